@@ -26,6 +26,8 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/db"
 	health "github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
@@ -53,6 +55,14 @@ func Run() {
 
 	// build logger
 	logger := logger.New("wormhole-explorer-analytics", logger.WithLevel(config.LogLevel))
+
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(rootCtx, "wormhole-explorer-analytics")
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	logger.Info("starting analytics service...")
 
 	// setup DB connection
@@ -95,7 +105,8 @@ func Run() {
 	// create and start a consumer.
 	logger.Info("initializing metrics consumer...")
 	vaaConsumeFunc := newVAAConsume(rootCtx, config, logger)
-	consumer := consumer.New(vaaConsumeFunc, metric.Push, logger, config.P2pNetwork)
+	tracker := reprocess.NewTracker(db.Database, logger)
+	consumer := consumer.New(vaaConsumeFunc, metric.Push, tracker, logger, config.P2pNetwork)
 	consumer.Start(rootCtx)
 
 	// create and start server.
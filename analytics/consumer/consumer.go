@@ -5,6 +5,8 @@ import (
 
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/metric"
 	"github.com/wormhole-foundation/wormhole-explorer/analytics/queue"
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
@@ -13,13 +15,14 @@ import (
 type Consumer struct {
 	consume    queue.VAAConsumeFunc
 	pushMetric metric.MetricPushFunc
+	tracker    *reprocess.Tracker
 	logger     *zap.Logger
 	p2pNetwork string
 }
 
 // New creates a new vaa consumer.
-func New(consume queue.VAAConsumeFunc, pushMetric metric.MetricPushFunc, logger *zap.Logger, p2pNetwork string) *Consumer {
-	return &Consumer{consume: consume, pushMetric: pushMetric, logger: logger, p2pNetwork: p2pNetwork}
+func New(consume queue.VAAConsumeFunc, pushMetric metric.MetricPushFunc, tracker *reprocess.Tracker, logger *zap.Logger, p2pNetwork string) *Consumer {
+	return &Consumer{consume: consume, pushMetric: pushMetric, tracker: tracker, logger: logger, p2pNetwork: p2pNetwork}
 }
 
 // Start consumes messages from VAA queue, parse and store those messages in a repository.
@@ -31,20 +34,35 @@ func (c *Consumer) Start(ctx context.Context) {
 			// check id message is expired.
 			if msg.IsExpired() {
 				c.logger.Warn("Message with vaa expired", zap.String("id", event.ID))
+				if event.Reprocess.Includes(reprocess.StageAnalytics) {
+					c.tracker.Track(ctx, event.Reprocess, reprocess.StageAnalytics, event.ID, reprocess.ErrEventExpired)
+				}
 				msg.Failed()
 				continue
 			}
 
+			// skip the events of reprocessing jobs that do not include the analytics.
+			if !event.Reprocess.Includes(reprocess.StageAnalytics) {
+				msg.Done()
+				continue
+			}
+
+			msgCtx, span := telemetry.StartVaaSpan(msg.Context(), "analytics.PushMetric", event.ID)
+
 			// unmarshal vaa.
 			vaa, err := sdk.Unmarshal(event.Vaa)
 			if err != nil {
+				telemetry.EndSpan(span, err)
 				c.logger.Error("Invalid vaa", zap.String("id", event.ID), zap.Error(err))
+				c.tracker.Track(ctx, event.Reprocess, reprocess.StageAnalytics, event.ID, err)
 				msg.Failed()
 				continue
 			}
 
 			// push vaa metrics.
-			err = c.pushMetric(ctx, vaa)
+			err = c.pushMetric(msgCtx, vaa)
+			telemetry.EndSpan(span, err)
+			c.tracker.Track(ctx, event.Reprocess, reprocess.StageAnalytics, event.ID, err)
 			if err != nil {
 				msg.Failed()
 				continue
@@ -3,17 +3,94 @@ package cacheable
 import (
 	"context"
 	"encoding/json"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
-// GetOrLoad is a function that tries to get the result from the cache, if it is not found or it is expired, then it loads the result.
+// lockExpiration is the maximum time a key is locked while it is loaded.
+const lockExpiration = 30 * time.Second
+
+// Cache is a cache whose keys can be invalidated before they expire.
+//
+// The expired or invalidated keys are loaded by a single caller: the concurrent callers of the
+// same instance share the load, and the callers of other instances return the cached value
+// while the key is locked.
+//
+// The invalidations are versioned with the timestamp of the publisher of the event, so the
+// instances compare versions set by the same clock instead of their own clocks.
+type Cache struct {
+	cache.Cache
+	locker   cache.Locker
+	group    singleflight.Group
+	mu       sync.RWMutex
+	versions map[string]time.Time
+}
+
+// NewCache creates a new Cache. The keys are locked only if the cache implements cache.Locker.
+func NewCache(c cache.Cache) *Cache {
+	locker, _ := c.(cache.Locker)
+	return &Cache{Cache: c, locker: locker, versions: make(map[string]time.Time)}
+}
+
+// Invalidate marks as stale the values cached before the version for the keys with any of the prefixes.
+// The version is the timestamp of the invalidation event, older versions are ignored.
+func (c *Cache) Invalidate(version time.Time, prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, prefix := range prefixes {
+		if version.After(c.versions[prefix]) {
+			c.versions[prefix] = version
+		}
+	}
+}
+
+// version returns the version of the last invalidation of a key, zero if it was never invalidated.
+func (c *Cache) version(key string) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var version time.Time
+	for prefix, v := range c.versions {
+		if strings.HasPrefix(key, prefix) && v.After(version) {
+			version = v
+		}
+	}
+	return version
+}
+
+// lock acquires the lock of a key, if the cache does not support locks the key is always acquired.
+func (c *Cache) lock(ctx context.Context, log *zap.Logger, key string) bool {
+	if c.locker == nil {
+		return true
+	}
+	acquired, err := c.locker.Lock(ctx, key, lockExpiration)
+	if err != nil {
+		if err != cache.ErrCacheNotEnabled {
+			log.Warn("locking key", zap.Error(err))
+		}
+		return true
+	}
+	return acquired
+}
+
+func (c *Cache) unlock(ctx context.Context, log *zap.Logger, key string) {
+	if c.locker == nil {
+		return
+	}
+	if err := c.locker.Unlock(ctx, key); err != nil && err != cache.ErrCacheNotEnabled {
+		log.Warn("unlocking key", zap.Error(err))
+	}
+}
+
+// GetOrLoad is a function that tries to get the result from the cache, if it is not found, expired or invalidated, then it loads the result.
 func GetOrLoad[T any](
 	ctx context.Context,
 	logger *zap.Logger,
-	cacheClient cache.Cache,
+	cacheClient *Cache,
 	expirations time.Duration,
 	key string,
 	load func() (T, error),
@@ -33,42 +110,65 @@ func GetOrLoad[T any](
 	}
 
 	var cached CachedResult[T]
-	//If the result is found in the cache and it is not expired, then return the result.
+	//If the result is found in the cache and it is not expired nor invalidated, then return the result.
 	if foundCache {
 		err = json.Unmarshal([]byte(value), &cached)
 		if err != nil {
+			foundCache = false
 			log.Warn("unmarshal cache", zap.Error(err))
-		} else if cached.Timestamp.Add(expirations).After(time.Now()) {
+		} else if cached.Timestamp.Add(expirations).After(time.Now()) && !cached.Version.Before(cacheClient.version(key)) {
 			return cached.Result, nil
 		}
 	}
 
-	//If the result is not found in the cache or it is expired, then load the result.
-	result, err := load()
+	//If the result is not found in the cache or it is stale, then load the result once for all the concurrent callers.
+	v, err, _ := cacheClient.group.Do(key, func() (interface{}, error) {
+
+		//If another instance is loading the key, the stale value is returned meanwhile.
+		if !cacheClient.lock(ctx, log, key) {
+			if foundCache {
+				return cached.Result, nil
+			}
+		} else {
+			defer cacheClient.unlock(ctx, log, key)
+		}
+
+		// the version is taken before loading, so an invalidation during the load marks the result as stale.
+		version := cacheClient.version(key)
+		result, err := load()
+		if err != nil {
+			return result, err
+		}
+
+		//Saves the result of the execution of the load function in cache.
+		newValue := CachedResult[T]{Timestamp: time.Now(), Version: version, Result: result}
+		err = cacheClient.Set(ctx, key, newValue, 10*expirations)
+		if err != nil {
+			log.Warn("saving the result in the cache", zap.Error(err))
+		}
+		return result, nil
+	})
 	if err != nil {
-		//If the load function fails and the cache was found and is expired, the cache value is returned anyway.
+		//If the load function fails and the cache was found and is stale, the cache value is returned anyway.
 		if foundCache {
 			log.Warn("load function fails but returns cached result",
 				zap.Error(err), zap.String("cacheTime", cached.Timestamp.String()))
 			return cached.Result, nil
 		}
-		return result, err
-	}
-
-	//Saves the result of the execution of the load function in cache.
-	newValue := CachedResult[T]{Timestamp: time.Now(), Result: result}
-	err = cacheClient.Set(ctx, key, newValue, 10*expirations)
-	if err != nil {
-		log.Warn("saving the result in the cache", zap.Error(err))
+		var zero T
+		return zero, err
 	}
 
 	//Returns the result of the execution of the function load
+	result, _ := v.(T)
 	return result, nil
 }
 
 type CachedResult[T any] struct {
 	Timestamp time.Time `json:"timestamp"`
-	Result    T         `json:"result"`
+	// Version is the version of the last invalidation of the key when the result was loaded.
+	Version time.Time `json:"version"`
+	Result  T         `json:"result"`
 }
 
 func (c CachedResult[T]) MarshalBinary() ([]byte, error) {
@@ -0,0 +1,152 @@
+package cacheable
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+	"go.uber.org/zap"
+)
+
+// memoryCache is an in-memory cache with locks, shared by the instances of the tests.
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string]string
+	locks  map[string]bool
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string]string), locks: make(map[string]bool)}
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	if !ok {
+		return "", cache.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	b, err := value.(CachedResult[int]).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = string(b)
+	return nil
+}
+
+func (m *memoryCache) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks[key] {
+		return false, nil
+	}
+	m.locks[key] = true
+	return true, nil
+}
+
+func (m *memoryCache) Unlock(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.locks, key)
+	return nil
+}
+
+func (m *memoryCache) Close() error {
+	return nil
+}
+
+func TestGetOrLoad_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	c := NewCache(newMemoryCache())
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	v, err := GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	// the cached value is returned until it is invalidated.
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.Equal(t, 1, v)
+
+	version := time.Now()
+	c.Invalidate(version, "wormscan:other")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.Equal(t, 1, v)
+
+	c.Invalidate(version, "wormscan:score")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 2, loads)
+
+	// an older or repeated version does not invalidate the value again.
+	c.Invalidate(version.Add(-time.Hour), "wormscan:scorecards")
+	c.Invalidate(version, "wormscan:scorecards")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.Equal(t, 2, v)
+
+	// a failed load returns the stale value.
+	c.Invalidate(version.Add(time.Second), "wormscan:scorecards")
+	v, err = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", func() (int, error) {
+		return 0, errors.New("load failed")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestGetOrLoad_Locked(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryCache()
+	c := NewCache(m)
+	load := func() (int, error) { return 1, nil }
+
+	// another instance is loading the key.
+	m.locks["wormscan:scorecards"] = true
+
+	// without a cached value the key is loaded anyway.
+	v, err := GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	// with a stale value, the stale value is returned while the key is locked.
+	c.Invalidate(time.Now(), "wormscan:scorecards")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", func() (int, error) { return 2, nil })
+	assert.Equal(t, 1, v)
+
+	// once the key is unlocked, the value is refreshed.
+	delete(m.locks, "wormscan:scorecards")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c, time.Minute, "wormscan:scorecards", func() (int, error) { return 2, nil })
+	assert.Equal(t, 2, v)
+}
+
+func TestGetOrLoad_InvalidatedByOtherInstance(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryCache()
+	c1 := NewCache(m)
+	c2 := NewCache(m)
+	version := time.Now()
+
+	// the first instance loads the value after the invalidation event.
+	c1.Invalidate(version, "wormscan:last-txs")
+	v, _ := GetOrLoad(ctx, zap.NewNop(), c1, time.Minute, "wormscan:last-txs", func() (int, error) { return 1, nil })
+	assert.Equal(t, 1, v)
+
+	// the second instance receives the same event later, the value has the same version
+	// so it is not stale regardless of the clocks of the instances.
+	c2.Invalidate(version, "wormscan:last-txs")
+	v, _ = GetOrLoad(ctx, zap.NewNop(), c2, time.Minute, "wormscan:last-txs", func() (int, error) { return 2, nil })
+	assert.Equal(t, 1, v)
+}
@@ -1,7 +1,12 @@
 package address
 
-import "github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+import (
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+)
 
 type AddressOverview struct {
-	Vaas []*vaa.VaaDoc `json:"vaas"`
+	// Labels contains the labels of the address, on any chain.
+	Labels []*labels.Label `json:"labels,omitempty"`
+	Vaas   []*vaa.VaaDoc   `json:"vaas"`
 }
@@ -3,6 +3,7 @@ package address
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"github.com/wormhole-foundation/wormhole-explorer/api/types"
@@ -10,15 +11,17 @@ import (
 )
 
 type Service struct {
-	repo   *Repository
-	logger *zap.Logger
+	repo          *Repository
+	labelsService *labels.Service
+	logger        *zap.Logger
 }
 
-func NewService(r *Repository, logger *zap.Logger) *Service {
+func NewService(r *Repository, labelsService *labels.Service, logger *zap.Logger) *Service {
 
 	srv := Service{
-		repo:   r,
-		logger: logger.With(zap.String("module", "AddressService")),
+		repo:          r,
+		labelsService: labelsService,
+		logger:        logger.With(zap.String("module", "AddressService")),
 	}
 
 	return &srv
@@ -42,6 +45,12 @@ func (s *Service) GetAddressOverview(
 		return response, err
 	}
 
+	// set the labels of the address and of the emitters of the VAAs
+	overview.Labels = s.labelsService.FindByAddress(address.Hex())
+	for _, v := range overview.Vaas {
+		v.EmitterLabel = s.labelsService.Find(v.EmitterChain, v.EmitterAddr)
+	}
+
 	response.Data = overview
 	return response, nil
 }
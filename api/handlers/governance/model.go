@@ -0,0 +1,39 @@
+package governance
+
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// GovernanceVaaDoc is a VAA of the governance emitter, classified by module and action by the parser.
+type GovernanceVaaDoc struct {
+	ID               string      `bson:"_id" json:"id"`
+	Sequence         string      `bson:"sequence" json:"sequence"`
+	GuardianSetIndex uint32      `bson:"guardianSetIndex" json:"guardianSetIndex"`
+	Module           string      `bson:"module" json:"module"`
+	ActionID         uint8       `bson:"actionId" json:"actionId"`
+	Action           string      `bson:"action" json:"action"`
+	TargetChain      sdk.ChainID `bson:"targetChain" json:"targetChain"`
+	// Chains are the chains the action applies to: the target chain, if any, and the chains referenced by the action.
+	Chains []sdk.ChainID `bson:"chains" json:"chains"`
+	// Fields are the decoded fields of the action. It is empty if the action is unknown.
+	Fields    map[string]interface{} `bson:"fields" json:"fields,omitempty"`
+	Payload   []byte                 `bson:"payload" json:"payload"`
+	Timestamp *time.Time             `bson:"timestamp" json:"timestamp"`
+	UpdatedAt *time.Time             `bson:"updatedAt" json:"updatedAt"`
+}
+
+// GovernanceQuery is a search over the governance VAAs.
+type GovernanceQuery struct {
+	pagination.Pagination
+	// Module filters by governance module, e.g. Core or TokenBridge.
+	Module string
+	// Action filters by action name, e.g. ContractUpgrade.
+	Action string
+	// TargetChain filters by the target chain of the header. Zero means all chains.
+	TargetChain *sdk.ChainID
+	// Chain filters by the chains the action applies to.
+	Chain *sdk.ChainID
+}
@@ -0,0 +1,72 @@
+package governance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definition.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		governanceVaas *mongo.Collection
+	}
+}
+
+// NewRepository create a new Repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db: db,
+		logger: logger.With(zap.String("module", "GovernanceRepository")),
+		collections: struct {
+			governanceVaas *mongo.Collection
+		}{
+			governanceVaas: db.Collection("governanceVaas"),
+		},
+	}
+}
+
+// Find returns the governance VAAs matching a query, sorted by timestamp.
+func (r *Repository) Find(ctx context.Context, q *GovernanceQuery) ([]*GovernanceVaaDoc, error) {
+
+	filter := bson.D{}
+	if q.Module != "" {
+		filter = append(filter, bson.E{Key: "module", Value: q.Module})
+	}
+	if q.Action != "" {
+		filter = append(filter, bson.E{Key: "action", Value: q.Action})
+	}
+	if q.TargetChain != nil {
+		filter = append(filter, bson.E{Key: "targetChain", Value: *q.TargetChain})
+	}
+	if q.Chain != nil {
+		filter = append(filter, bson.E{Key: "chains", Value: *q.Chain})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: q.GetSortInt()}, {Key: "_id", Value: q.GetSortInt()}}).
+		SetSkip(q.Skip).
+		SetLimit(q.Limit)
+	cur, err := r.collections.governanceVaas.Find(ctx, filter, opts)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get governance vaas",
+			zap.Error(err), zap.Any("q", q), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	docs := make([]*GovernanceVaaDoc, 0)
+	if err := cur.All(ctx, &docs); err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to []*GovernanceVaaDoc",
+			zap.Error(err), zap.Any("q", q), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	return docs, nil
+}
@@ -0,0 +1,28 @@
+package governance
+
+import (
+	"context"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"go.uber.org/zap"
+)
+
+// Service definition.
+type Service struct {
+	repo   *Repository
+	logger *zap.Logger
+}
+
+// NewService create a new governance.Service.
+func NewService(repo *Repository, logger *zap.Logger) *Service {
+	return &Service{repo: repo, logger: logger.With(zap.String("module", "GovernanceService"))}
+}
+
+// Find returns the governance VAAs matching a query.
+func (s *Service) Find(ctx context.Context, q *GovernanceQuery) (*response.Response[[]*GovernanceVaaDoc], error) {
+	docs, err := s.repo.Find(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &response.Response[[]*GovernanceVaaDoc]{Data: docs}, nil
+}
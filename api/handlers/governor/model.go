@@ -112,6 +112,8 @@ type EnqueuedVaa struct {
 	Sequence       string      `bson:"sequence" json:"sequence"`
 	NotionalValue  int64       `bson:"notionalValue" json:"notionalValue"`
 	TxHash         string      `bson:"txHash" json:"txHash"`
+	// ReleaseEstimate is the estimated release time of the VAA, if it could be computed.
+	ReleaseEstimate *ReleaseEstimate `bson:"-" json:"releaseEstimate,omitempty"`
 }
 
 // MarshalJSON interface implementation.
@@ -145,6 +147,8 @@ type EnqueuedVaaDetail struct {
 	NotionalValue  int64       `bson:"notionalValue" json:"notionalValue"`
 	TxHash         string      `bson:"txHash" json:"txHash"`
 	ReleaseTime    int64       `bson:"releaseTime" json:"releaseTime"`
+	// ReleaseEstimate is the estimated release time of the VAA, if it could be computed.
+	ReleaseEstimate *ReleaseEstimate `bson:"-" json:"releaseEstimate,omitempty"`
 }
 
 // MarshalJSON interface implementation.
@@ -196,4 +200,33 @@ type EnqueuedVaaItem struct {
 	ReleaseTime    int64        `bson:"releasetime" json:"releaseTime"`
 	NotionalValue  mongo.Uint64 `bson:"notionalvalue" json:"notionalValue"`
 	TxHash         string       `bson:"txhash" json:"txHash"`
+	// ReleaseEstimate is the estimated release time of the VAA, if it could be computed.
+	ReleaseEstimate *ReleaseEstimate `bson:"-" json:"releaseEstimate,omitempty"`
+}
+
+// GuardianEnqueuedVaa definition.
+// Represents a VAA enqueued in the governor of a single guardian.
+type GuardianEnqueuedVaa struct {
+	GuardianID         string       `bson:"guardianId"`
+	ChainID            vaa.ChainID  `bson:"chainid"`
+	EmitterAddress     string       `bson:"emitteraddress"`
+	Sequence           string       `bson:"sequence"`
+	ReleaseTime        int64        `bson:"releasetime"`
+	NotionalValue      mongo.Uint64 `bson:"notionalvalue"`
+	AvailableNotional  mongo.Uint64 `bson:"availablenotional"`
+	BigTransactionSize mongo.Uint64 `bson:"bigtransactionsize"`
+}
+
+// TransferNotional definition.
+// Represents the notional value of a transfer emitted from a chain.
+type TransferNotional struct {
+	Timestamp time.Time `bson:"timestamp"`
+	UsdAmount string    `bson:"usdAmount"`
+}
+
+// ReleaseEstimate definition.
+type ReleaseEstimate struct {
+	EstimatedReleaseTime time.Time     `json:"estimatedReleaseTime"`
+	Source               ReleaseSource `json:"source"`
+	EnqueuedGuardians    int           `json:"enqueuedGuardians"`
 }
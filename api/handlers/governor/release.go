@@ -0,0 +1,148 @@
+package governor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// ReleaseSource indicates which governor rule determines the estimated release time of an enqueued VAA.
+type ReleaseSource string
+
+const (
+	// ReleaseSourceReleaseTime means the VAA is released when the release time reported by the guardians is reached.
+	ReleaseSourceReleaseTime ReleaseSource = "releaseTime"
+	// ReleaseSourceNotionalWindow means the VAA is released when the 24h sliding window frees enough notional.
+	ReleaseSourceNotionalWindow ReleaseSource = "notionalWindow"
+	// ReleaseSourceReleased means a quorum of guardians no longer has the VAA enqueued.
+	ReleaseSourceReleased ReleaseSource = "released"
+)
+
+// governorWindow is the duration of the sliding window used by the governor to limit the outgoing notional.
+const governorWindow = 24 * time.Hour
+
+// guardianRelease is the release time estimated for a VAA enqueued in a single guardian.
+type guardianRelease struct {
+	releaseTime time.Time
+	source      ReleaseSource
+}
+
+// enqueuedVaaKey returns the VAA ID of an enqueued VAA reported in the governor status.
+func enqueuedVaaKey(chainID vaa.ChainID, emitterAddress, sequence string) string {
+	emitter := strings.TrimPrefix(strings.ToLower(emitterAddress), "0x")
+	return fmt.Sprintf("%d/%s/%s", chainID, emitter, sequence)
+}
+
+// estimateGuardianReleases estimates the release time of the VAAs enqueued in the governor
+// of a single guardian for a single chain.
+//
+// The governor releases a VAA when its release time is reached or, for VAAs below the big
+// transaction size, as soon as the sliding window has enough available notional.
+// The enqueued VAAs are assumed to be released in order of release time, so the notional of the
+// VAAs that are ahead in the queue must be freed before a VAA can be released.
+// The transfers must be sorted by timestamp in ascending order.
+func estimateGuardianReleases(
+	now time.Time,
+	enqueued []*GuardianEnqueuedVaa,
+	transfers []*TransferNotional,
+) map[string]guardianRelease {
+
+	queue := make([]*GuardianEnqueuedVaa, len(enqueued))
+	copy(queue, enqueued)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].ReleaseTime < queue[j].ReleaseTime
+	})
+
+	releases := make(map[string]guardianRelease, len(queue))
+	var pendingNotional float64
+	for _, v := range queue {
+		release := guardianRelease{
+			releaseTime: time.Unix(v.ReleaseTime, 0).UTC(),
+			source:      ReleaseSourceReleaseTime,
+		}
+
+		// big transactions are only released when the release time is reached.
+		isBigTransaction := v.BigTransactionSize != 0 && v.NotionalValue >= v.BigTransactionSize
+		if !isBigTransaction {
+			needed := pendingNotional + float64(v.NotionalValue) - float64(v.AvailableNotional)
+			if t, ok := notionalWindowRelease(now, needed, transfers); ok && t.Before(release.releaseTime) {
+				release = guardianRelease{releaseTime: t, source: ReleaseSourceNotionalWindow}
+			}
+			pendingNotional += float64(v.NotionalValue)
+		}
+
+		releases[enqueuedVaaKey(v.ChainID, v.EmitterAddress, v.Sequence)] = release
+	}
+
+	return releases
+}
+
+// notionalWindowRelease returns the moment at which the sliding window frees the needed notional.
+// The second return value is false if the transfers in the window are not enough to free it.
+func notionalWindowRelease(now time.Time, needed float64, transfers []*TransferNotional) (time.Time, bool) {
+	if needed <= 0 {
+		return now, true
+	}
+
+	var freed float64
+	for _, t := range transfers {
+		amount, err := strconv.ParseFloat(t.UsdAmount, 64)
+		if err != nil {
+			continue
+		}
+		freed += amount
+		if freed >= needed {
+			releaseTime := t.Timestamp.Add(governorWindow)
+			if releaseTime.Before(now) {
+				return now, true
+			}
+			return releaseTime.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// combineGuardianReleases combines the release times estimated by each guardian into the
+// release time of the VAA, which is the quorum-th earliest release time.
+//
+// Guardians that report governor status but do not have the VAA enqueued are considered to have
+// released it already.
+func combineGuardianReleases(
+	now time.Time,
+	releases []guardianRelease,
+	reportingGuardians int,
+	quorum int,
+) *ReleaseEstimate {
+
+	if len(releases) == 0 {
+		return nil
+	}
+
+	all := make([]guardianRelease, 0, reportingGuardians)
+	all = append(all, releases...)
+	for i := len(releases); i < reportingGuardians; i++ {
+		all = append(all, guardianRelease{releaseTime: now, source: ReleaseSourceReleased})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].releaseTime.Before(all[j].releaseTime)
+	})
+
+	idx := quorum - 1
+	if idx >= len(all) {
+		idx = len(all) - 1
+	}
+	if idx < 0 {
+		idx = 0
+	}
+
+	return &ReleaseEstimate{
+		EstimatedReleaseTime: all[idx].releaseTime,
+		Source:               all[idx].source,
+		EnqueuedGuardians:    len(releases),
+	}
+}
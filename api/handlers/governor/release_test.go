@@ -0,0 +1,123 @@
+package governor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestRelease_estimateGuardianReleases(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	releaseTime := now.Add(20 * time.Hour)
+
+	transfers := []*TransferNotional{
+		{Timestamp: now.Add(-23 * time.Hour), UsdAmount: "400000.5"},
+		{Timestamp: now.Add(-22 * time.Hour), UsdAmount: "600000"},
+		{Timestamp: now.Add(-10 * time.Hour), UsdAmount: "5000000"},
+	}
+
+	enqueued := []*GuardianEnqueuedVaa{
+		{
+			ChainID:            vaa.ChainIDEthereum,
+			EmitterAddress:     "0x0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585",
+			Sequence:           "2",
+			ReleaseTime:        releaseTime.Add(time.Hour).Unix(),
+			NotionalValue:      500_000,
+			AvailableNotional:  100_000,
+			BigTransactionSize: 10_000_000,
+		},
+		{
+			ChainID:            vaa.ChainIDEthereum,
+			EmitterAddress:     "0x0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585",
+			Sequence:           "1",
+			ReleaseTime:        releaseTime.Unix(),
+			NotionalValue:      300_000,
+			AvailableNotional:  100_000,
+			BigTransactionSize: 10_000_000,
+		},
+		{
+			ChainID:            vaa.ChainIDEthereum,
+			EmitterAddress:     "0x0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585",
+			Sequence:           "3",
+			ReleaseTime:        releaseTime.Add(2 * time.Hour).Unix(),
+			NotionalValue:      20_000_000,
+			AvailableNotional:  100_000,
+			BigTransactionSize: 10_000_000,
+		},
+	}
+
+	releases := estimateGuardianReleases(now, enqueued, transfers)
+	assert.Len(t, releases, 3)
+
+	// sequence 1 needs 200000 of notional, freed by the first transfer of the window.
+	r1 := releases["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1"]
+	assert.Equal(t, ReleaseSourceNotionalWindow, r1.source)
+	assert.Equal(t, now.Add(time.Hour), r1.releaseTime)
+
+	// sequence 2 needs 700000 of notional because sequence 1 is ahead in the queue.
+	r2 := releases["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/2"]
+	assert.Equal(t, ReleaseSourceNotionalWindow, r2.source)
+	assert.Equal(t, now.Add(2*time.Hour), r2.releaseTime)
+
+	// sequence 3 is a big transaction, so it is only released at its release time.
+	r3 := releases["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/3"]
+	assert.Equal(t, ReleaseSourceReleaseTime, r3.source)
+	assert.Equal(t, releaseTime.Add(2*time.Hour), r3.releaseTime)
+}
+
+func TestRelease_estimateGuardianReleasesWithoutEnoughNotional(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	releaseTime := now.Add(20 * time.Hour)
+
+	transfers := []*TransferNotional{
+		{Timestamp: now.Add(-23 * time.Hour), UsdAmount: "1000"},
+	}
+	enqueued := []*GuardianEnqueuedVaa{
+		{
+			ChainID:           vaa.ChainIDSolana,
+			EmitterAddress:    "0xec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5",
+			Sequence:          "10",
+			ReleaseTime:       releaseTime.Unix(),
+			NotionalValue:     500_000,
+			AvailableNotional: 0,
+		},
+	}
+
+	releases := estimateGuardianReleases(now, enqueued, transfers)
+	r := releases["1/ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5/10"]
+	assert.Equal(t, ReleaseSourceReleaseTime, r.source)
+	assert.Equal(t, releaseTime, r.releaseTime)
+}
+
+func TestRelease_combineGuardianReleases(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+
+	releases := make([]guardianRelease, 0, 19)
+	for i := 0; i < 19; i++ {
+		releases = append(releases, guardianRelease{
+			releaseTime: now.Add(time.Duration(i) * time.Hour),
+			source:      ReleaseSourceNotionalWindow,
+		})
+	}
+
+	// all guardians have the vaa enqueued, the 13th earliest release time is used.
+	estimate := combineGuardianReleases(now, releases, 19, minGuardianNum)
+	assert.Equal(t, now.Add(12*time.Hour), estimate.EstimatedReleaseTime)
+	assert.Equal(t, ReleaseSourceNotionalWindow, estimate.Source)
+	assert.Equal(t, 19, estimate.EnqueuedGuardians)
+
+	// a quorum of guardians already released the vaa.
+	estimate = combineGuardianReleases(now, releases[:5], 19, minGuardianNum)
+	assert.Equal(t, now, estimate.EstimatedReleaseTime)
+	assert.Equal(t, ReleaseSourceReleased, estimate.Source)
+	assert.Equal(t, 5, estimate.EnqueuedGuardians)
+
+	// fewer guardians than the quorum report governor status.
+	estimate = combineGuardianReleases(now, releases[:1], 1, minGuardianNum)
+	assert.Equal(t, now, estimate.EstimatedReleaseTime)
+
+	// no guardian has the vaa enqueued.
+	assert.Nil(t, combineGuardianReleases(now, nil, 19, minGuardianNum))
+}
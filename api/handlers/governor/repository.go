@@ -4,7 +4,9 @@ package governor
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,6 +29,7 @@ type Repository struct {
 	collections struct {
 		governorConfig *mongo.Collection
 		governorStatus *mongo.Collection
+		transferPrices *mongo.Collection
 	}
 }
 
@@ -37,9 +40,11 @@ func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
 		collections: struct {
 			governorConfig *mongo.Collection
 			governorStatus *mongo.Collection
+			transferPrices *mongo.Collection
 		}{
 			governorConfig: db.Collection("governorConfig"),
 			governorStatus: db.Collection("governorStatus"),
+			transferPrices: db.Collection("transferPrices"),
 		},
 	}
 }
@@ -1725,3 +1730,185 @@ func (r *Repository) IsVaaEnqueued(
 
 	return true, nil
 }
+
+// GetGuardianEnqueuedVaas get the enqueued vaas of each guardian along with the
+// governor state of the chain (available notional and big transaction size).
+//
+// If chainID is nil, the enqueued vaas of all chains are returned.
+func (r *Repository) GetGuardianEnqueuedVaas(
+	ctx context.Context,
+	chainID *vaa.ChainID,
+) ([]*GuardianEnqueuedVaa, error) {
+
+	projectStage1 := bson.D{
+		{Key: "$project", Value: bson.D{
+			{Key: "chains", Value: "$parsedStatus.chains"},
+		}},
+	}
+
+	unwindStage2 := bson.D{
+		{Key: "$unwind", Value: "$chains"},
+	}
+
+	matchStage3 := bson.D{
+		{Key: "$match", Value: bson.D{}},
+	}
+	if chainID != nil {
+		matchStage3 = bson.D{
+			{Key: "$match", Value: bson.D{
+				{Key: "chains.chainid", Value: *chainID},
+			}},
+		}
+	}
+
+	unwindStage4 := bson.D{
+		{Key: "$unwind", Value: "$chains.emitters"},
+	}
+
+	unwindStage5 := bson.D{
+		{Key: "$unwind", Value: "$chains.emitters.enqueuedvaas"},
+	}
+
+	lookupStage6 := bson.D{
+		{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "governorConfig"},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "config"},
+		}},
+	}
+
+	projectStage7 := bson.D{
+		{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "guardianId", Value: "$_id"},
+			{Key: "chainid", Value: "$chains.chainid"},
+			{Key: "availablenotional", Value: "$chains.remainingavailablenotional"},
+			{Key: "emitteraddress", Value: "$chains.emitters.emitteraddress"},
+			{Key: "sequence", Value: "$chains.emitters.enqueuedvaas.sequence"},
+			{Key: "releasetime", Value: "$chains.emitters.enqueuedvaas.releasetime"},
+			{Key: "notionalvalue", Value: "$chains.emitters.enqueuedvaas.notionalvalue"},
+			{Key: "bigtransactionsize", Value: bson.D{
+				{Key: "$let", Value: bson.D{
+					{Key: "vars", Value: bson.D{
+						{Key: "chainConfig", Value: bson.M{
+							"$arrayElemAt": []interface{}{
+								bson.D{
+									{Key: "$filter", Value: bson.D{
+										{Key: "input", Value: bson.M{
+											"$arrayElemAt": []interface{}{"$config.parsedConfig.chains", 0},
+										}},
+										{Key: "as", Value: "chain"},
+										{Key: "cond", Value: bson.D{
+											{Key: "$eq", Value: bson.A{"$$chain.chainid", "$chains.chainid"}},
+										}},
+									}},
+								},
+								0,
+							},
+						}},
+					}},
+					{Key: "in", Value: "$$chainConfig.bigtransactionsize"},
+				}},
+			}},
+		}},
+	}
+
+	// define aggregate pipeline
+	pipeLine := mongo.Pipeline{
+		projectStage1,
+		unwindStage2,
+		matchStage3,
+		unwindStage4,
+		unwindStage5,
+		lookupStage6,
+		projectStage7,
+	}
+
+	// execute aggregate operations.
+	cur, err := r.collections.governorStatus.Aggregate(ctx, pipeLine)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to execute Aggregate command to get guardian enqueued vaas",
+			zap.Error(err),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+
+	// decodes to []*GuardianEnqueuedVaa.
+	var enqueuedVaas []*GuardianEnqueuedVaa
+	err = cur.All(ctx, &enqueuedVaas)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor into []*GuardianEnqueuedVaa",
+			zap.Error(err),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+
+	return enqueuedVaas, nil
+}
+
+// CountGovernorStatus get the number of guardians reporting governor status.
+func (r *Repository) CountGovernorStatus(ctx context.Context) (int, error) {
+	count, err := r.collections.governorStatus.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to count governor status documents",
+			zap.Error(err),
+			zap.String("requestID", requestID),
+		)
+		return 0, errors.WithStack(err)
+	}
+	return int(count), nil
+}
+
+// GetTransferNotionalsSince get the notional value of the transfers emitted from
+// a chain by the governed emitters since a given time, sorted by timestamp in ascending order.
+func (r *Repository) GetTransferNotionalsSince(
+	ctx context.Context,
+	chainID vaa.ChainID,
+	emitters []string,
+	since time.Time,
+) ([]*TransferNotional, error) {
+
+	// the governor only counts the transfers of the governed emitters (i.e. the token bridge).
+	quoted := make([]string, 0, len(emitters))
+	for _, emitter := range emitters {
+		quoted = append(quoted, regexp.QuoteMeta(emitter))
+	}
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$regex", Value: fmt.Sprintf("^%d/(%s)/", chainID, strings.Join(quoted, "|"))}}},
+		{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: since}}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}}).
+		SetProjection(bson.D{{Key: "timestamp", Value: 1}, {Key: "usdAmount", Value: 1}})
+
+	cur, err := r.collections.transferPrices.Find(ctx, filter, opts)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Find command to get transfer notionals",
+			zap.Error(err),
+			zap.Stringer("chainId", chainID),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+
+	var transfers []*TransferNotional
+	err = cur.All(ctx, &transfers)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor into []*TransferNotional",
+			zap.Error(err),
+			zap.Stringer("chainId", chainID),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+
+	return transfers, nil
+}
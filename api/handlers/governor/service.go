@@ -4,6 +4,7 @@ package governor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
@@ -144,8 +145,19 @@ func (s *Service) GetEnqueueVass(ctx context.Context, p *pagination.Pagination)
 	}
 	query := QueryEnqueuedVaa().SetPagination(p)
 	enqueuedVaaResponse, err := s.repo.GetEnqueueVass(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	estimates := s.getReleaseEstimatesOrEmpty(ctx, nil)
+	for _, e := range enqueuedVaaResponse {
+		for _, v := range e.EnqueuedVaa {
+			v.ReleaseEstimate = estimates[enqueuedVaaKey(v.ChainID, v.EmitterAddress, v.Sequence)]
+		}
+	}
+
 	res := response.Response[[]*EnqueuedVaas]{Data: enqueuedVaaResponse}
-	return &res, err
+	return &res, nil
 }
 
 // GetEnqueueVassByChainID get enequeued vaa by chainID.
@@ -155,8 +167,17 @@ func (s *Service) GetEnqueueVassByChainID(ctx context.Context, p *pagination.Pag
 	}
 	query := QueryEnqueuedVaa().SetPagination(p).SetChain(chainID)
 	enqueuedVaaRecord, err := s.repo.GetEnqueueVassByChainID(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	estimates := s.getReleaseEstimatesOrEmpty(ctx, &chainID)
+	for _, v := range enqueuedVaaRecord {
+		v.ReleaseEstimate = estimates[enqueuedVaaKey(v.ChainID, v.EmitterAddress, v.Sequence)]
+	}
+
 	res := response.Response[[]*EnqueuedVaaDetail]{Data: enqueuedVaaRecord}
-	return &res, err
+	return &res, nil
 }
 
 // GetGovernorLimit get governor limit.
@@ -191,10 +212,12 @@ func (s *Service) GetEnqueuedVaas(ctx context.Context) ([]*EnqueuedVaaItem, erro
 	}
 	result := make([]*EnqueuedVaaItem, 0)
 	existingEnqueuedVaa := map[string]bool{}
+	estimates := s.getReleaseEstimatesOrEmpty(ctx, nil)
 	for _, e := range entries {
 		// remove duplicates
 		key := fmt.Sprintf("%s/%s/%s", e.EmitterChain, e.EmitterAddress, e.Sequence)
 		if _, exists := existingEnqueuedVaa[key]; !exists {
+			e.ReleaseEstimate = estimates[enqueuedVaaKey(e.EmitterChain, e.EmitterAddress, e.Sequence)]
 			result = append(result, e)
 			existingEnqueuedVaa[key] = true
 		}
@@ -208,3 +231,85 @@ func (s *Service) IsVaaEnqueued(ctx context.Context, chainID vaa.ChainID, emitte
 	isEnqueued, err := s.repo.IsVaaEnqueued(ctx, chainID, emitter, seq)
 	return isEnqueued, err
 }
+
+// GetVaaReleaseEstimate get the estimated release time of an enqueued vaa.
+// Returns nil if the vaa is not enqueued.
+func (s *Service) GetVaaReleaseEstimate(ctx context.Context, chainID vaa.ChainID, emitter *types.Address, seq string) (*ReleaseEstimate, error) {
+	estimates, err := s.GetReleaseEstimates(ctx, &chainID)
+	if err != nil {
+		return nil, err
+	}
+	return estimates[fmt.Sprintf("%d/%s/%s", chainID, emitter.Hex(), seq)], nil
+}
+
+// GetReleaseEstimates get the estimated release time of the enqueued vaas, keyed by vaa ID.
+//
+// For each guardian, the release time is the explicit release time reported in the governor status
+// or, for transfers limited by notional, the moment the 24h sliding window frees enough notional.
+// The estimates of all guardians are combined into the quorum-th earliest release time.
+// If chainID is nil, the enqueued vaas of all chains are estimated.
+func (s *Service) GetReleaseEstimates(ctx context.Context, chainID *vaa.ChainID) (map[string]*ReleaseEstimate, error) {
+	enqueued, err := s.repo.GetGuardianEnqueuedVaas(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if len(enqueued) == 0 {
+		return map[string]*ReleaseEstimate{}, nil
+	}
+
+	reportingGuardians, err := s.repo.CountGovernorStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// group enqueued vaas by chain and guardian, and collect the governed emitters of each chain.
+	enqueuedByChain := map[vaa.ChainID]map[string][]*GuardianEnqueuedVaa{}
+	emittersByChain := map[vaa.ChainID]map[string]bool{}
+	for _, e := range enqueued {
+		if _, ok := enqueuedByChain[e.ChainID]; !ok {
+			enqueuedByChain[e.ChainID] = map[string][]*GuardianEnqueuedVaa{}
+		}
+		enqueuedByChain[e.ChainID][e.GuardianID] = append(enqueuedByChain[e.ChainID][e.GuardianID], e)
+		if _, ok := emittersByChain[e.ChainID]; !ok {
+			emittersByChain[e.ChainID] = map[string]bool{}
+		}
+		emittersByChain[e.ChainID][e.EmitterAddress] = true
+	}
+
+	// estimate the release time of each vaa for each guardian.
+	now := time.Now().UTC()
+	releases := map[string][]guardianRelease{}
+	for chain, enqueuedByGuardian := range enqueuedByChain {
+		emitters := make([]string, 0, len(emittersByChain[chain]))
+		for emitter := range emittersByChain[chain] {
+			emitters = append(emitters, emitter)
+		}
+		transfers, err := s.repo.GetTransferNotionalsSince(ctx, chain, emitters, now.Add(-governorWindow))
+		if err != nil {
+			return nil, err
+		}
+		for _, guardianEnqueued := range enqueuedByGuardian {
+			for key, r := range estimateGuardianReleases(now, guardianEnqueued, transfers) {
+				releases[key] = append(releases[key], r)
+			}
+		}
+	}
+
+	// combine the estimates of all guardians.
+	estimates := make(map[string]*ReleaseEstimate, len(releases))
+	for key, r := range releases {
+		estimates[key] = combineGuardianReleases(now, r, reportingGuardians, minGuardianNum)
+	}
+	return estimates, nil
+}
+
+// getReleaseEstimatesOrEmpty get the estimated release times, logging the error and
+// returning an empty result if they cannot be computed.
+func (s *Service) getReleaseEstimatesOrEmpty(ctx context.Context, chainID *vaa.ChainID) map[string]*ReleaseEstimate {
+	estimates, err := s.GetReleaseEstimates(ctx, chainID)
+	if err != nil {
+		s.logger.Warn("failed to estimate release time of enqueued vaas", zap.Error(err))
+		return map[string]*ReleaseEstimate{}
+	}
+	return estimates
+}
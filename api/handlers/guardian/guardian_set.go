@@ -32,6 +32,14 @@ func (gs GuardianSet) IsValid(gsIx uint32, t time.Time) bool {
 	return gs.ExpirationTimeByIndex[gsIx].After(t)
 }
 
+// GetByIndex get the guardianset by index.
+func (gs GuardianSet) GetByIndex(index uint32) (common.GuardianSet, bool) {
+	if int(index) >= len(gs.GstByIndex) {
+		return common.GuardianSet{}, false
+	}
+	return gs.GstByIndex[index], true
+}
+
 // GetLatest get the lastest guardianset.
 func (gs GuardianSet) GetLatest() common.GuardianSet {
 	return gs.GstByIndex[len(gs.GstByIndex)-1]
@@ -0,0 +1,184 @@
+package heartbeats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// staleHeartbeatThreshold is the time after which the last heartbeat of a guardian is considered stale.
+// Guardians send a heartbeat every 15 seconds.
+const staleHeartbeatThreshold = time.Minute
+
+// maxLagTime is the time a guardian can lag behind the quorum height of a chain
+// to be considered up-to-date.
+const maxLagTime = time.Minute
+
+// defaultMaxLagBlocks is the number of blocks a guardian can lag behind the quorum height
+// of a chain to be considered up-to-date, for chains without a known block time.
+const defaultMaxLagBlocks = 100
+
+// chainBlockTimes contains the approximate block times of the chains.
+var chainBlockTimes = map[vaa.ChainID]time.Duration{
+	vaa.ChainIDSolana:    400 * time.Millisecond,
+	vaa.ChainIDEthereum:  12 * time.Second,
+	vaa.ChainIDBSC:       3 * time.Second,
+	vaa.ChainIDPolygon:   2 * time.Second,
+	vaa.ChainIDAvalanche: 2 * time.Second,
+	vaa.ChainIDAlgorand:  4 * time.Second,
+	vaa.ChainIDFantom:    time.Second,
+	vaa.ChainIDKlaytn:    time.Second,
+	vaa.ChainIDCelo:      5 * time.Second,
+	vaa.ChainIDMoonbeam:  12 * time.Second,
+	vaa.ChainIDArbitrum:  250 * time.Millisecond,
+	vaa.ChainIDOptimism:  2 * time.Second,
+	vaa.ChainIDBase:      2 * time.Second,
+}
+
+// GuardianHealth is the health of the guardians of the current guardian set.
+type GuardianHealth struct {
+	GuardianSetIndex uint32         `json:"guardianSetIndex"`
+	GuardianSetSize  int            `json:"guardianSetSize"`
+	Quorum           int            `json:"quorum"`
+	Chains           []*ChainHealth `json:"chains"`
+}
+
+// ChainHealth is the health of the guardians observing a chain.
+type ChainHealth struct {
+	ChainID vaa.ChainID `json:"chainId"`
+	// QuorumHeight is the highest height reached by a quorum of guardians.
+	// It is zero if less than a quorum of guardians report the chain.
+	QuorumHeight int64 `json:"quorumHeight"`
+	// HighestHeight is the highest height reported by a guardian.
+	HighestHeight int64 `json:"highestHeight"`
+	// UpToDateGuardians is the number of guardians that are up-to-date with the chain.
+	UpToDateGuardians int `json:"upToDateGuardians"`
+	// HasQuorum indicates whether a quorum of guardians is up-to-date with the chain.
+	HasQuorum bool                   `json:"hasQuorum"`
+	Guardians []*GuardianChainHealth `json:"guardians"`
+}
+
+// GuardianChainHealth is the health of a guardian observing a chain.
+type GuardianChainHealth struct {
+	GuardianAddress string `json:"guardianAddress"`
+	NodeName        string `json:"nodeName"`
+	Version         string `json:"version"`
+	Height          int64  `json:"height"`
+	// LagBlocks is the number of blocks the guardian lags behind the quorum height.
+	LagBlocks int64 `json:"lagBlocks"`
+	// LagSeconds is the estimated lag of the guardian in seconds, if the block time of the chain is known.
+	LagSeconds *float64 `json:"lagSeconds,omitempty"`
+	// StaleHeartbeat indicates whether the last heartbeat of the guardian is stale or missing.
+	StaleHeartbeat bool `json:"staleHeartbeat"`
+	// Missing indicates whether the guardian does not report the chain.
+	Missing  bool `json:"missing"`
+	UpToDate bool `json:"upToDate"`
+}
+
+// maxLagBlocks returns the number of blocks a guardian can lag behind the quorum height of a chain.
+func maxLagBlocks(chainID vaa.ChainID) int64 {
+	blockTime, ok := chainBlockTimes[chainID]
+	if !ok {
+		return defaultMaxLagBlocks
+	}
+	return int64(maxLagTime / blockTime)
+}
+
+// computeChainsHealth computes the health of each chain reported in the heartbeats
+// of the guardians.
+//
+// Guardians without heartbeats are reported as stale and missing for every chain.
+func computeChainsHealth(now time.Time, guardianAddrs []string, heartbeats []*HeartbeatDoc, quorum int) []*ChainHealth {
+
+	heartbeatsByAddr := make(map[string]*HeartbeatDoc, len(heartbeats))
+	chainIDs := make(map[vaa.ChainID]bool)
+	for _, hb := range heartbeats {
+		heartbeatsByAddr[hb.ID] = hb
+		for _, n := range hb.Networks {
+			chainIDs[vaa.ChainID(n.ID)] = true
+		}
+	}
+
+	chains := make([]*ChainHealth, 0, len(chainIDs))
+	for chainID := range chainIDs {
+		chains = append(chains, computeChainHealth(now, chainID, guardianAddrs, heartbeatsByAddr, quorum))
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].ChainID < chains[j].ChainID
+	})
+	return chains
+}
+
+// computeChainHealth computes the health of the guardians observing a chain.
+func computeChainHealth(
+	now time.Time,
+	chainID vaa.ChainID,
+	guardianAddrs []string,
+	heartbeatsByAddr map[string]*HeartbeatDoc,
+	quorum int,
+) *ChainHealth {
+
+	chain := ChainHealth{
+		ChainID:   chainID,
+		Guardians: make([]*GuardianChainHealth, 0, len(guardianAddrs)),
+	}
+
+	// build the row of each guardian and collect the heights of the guardians with recent heartbeats.
+	var heights []int64
+	for _, addr := range guardianAddrs {
+		row := GuardianChainHealth{GuardianAddress: addr, StaleHeartbeat: true, Missing: true}
+		chain.Guardians = append(chain.Guardians, &row)
+
+		hb, ok := heartbeatsByAddr[addr]
+		if !ok {
+			continue
+		}
+		row.NodeName = hb.NodeName
+		row.Version = hb.Version
+		row.StaleHeartbeat = hb.IsStale(now)
+		if height, ok := hb.Height(chainID); ok {
+			row.Missing = false
+			row.Height = height
+		}
+		if !row.StaleHeartbeat && !row.Missing && row.Height > 0 {
+			heights = append(heights, row.Height)
+		}
+	}
+
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	if len(heights) > 0 {
+		chain.HighestHeight = heights[0]
+	}
+	if quorum > 0 && len(heights) >= quorum {
+		chain.QuorumHeight = heights[quorum-1]
+	}
+
+	// the lag is measured from the quorum height, or from the highest height when there is no quorum.
+	reference := chain.QuorumHeight
+	if reference == 0 {
+		reference = chain.HighestHeight
+	}
+	blockTime, hasBlockTime := chainBlockTimes[chainID]
+	maxLag := maxLagBlocks(chainID)
+	for _, row := range chain.Guardians {
+		if row.Missing || row.Height <= 0 {
+			continue
+		}
+		if row.Height < reference {
+			row.LagBlocks = reference - row.Height
+		}
+		if hasBlockTime {
+			lagSeconds := (time.Duration(row.LagBlocks) * blockTime).Seconds()
+			row.LagSeconds = &lagSeconds
+		}
+		// a single guardian ahead of the others does not make the rest of them lag behind.
+		row.UpToDate = !row.StaleHeartbeat && row.LagBlocks <= maxLag
+		if row.UpToDate {
+			chain.UpToDateGuardians++
+		}
+	}
+	chain.HasQuorum = chain.UpToDateGuardians >= quorum
+
+	return &chain
+}
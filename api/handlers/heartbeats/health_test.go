@@ -0,0 +1,135 @@
+package heartbeats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestHealth_computeChainsHealth(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+	stale := now.Add(-10 * time.Minute)
+
+	guardianAddrs := []string{"0x01", "0x02", "0x03", "0x04"}
+	heartbeats := []*HeartbeatDoc{
+		{
+			ID:        "0x01",
+			NodeName:  "guardian-1",
+			Version:   "v2.18.0",
+			UpdatedAt: &recent,
+			Networks: []HeartbeatNetwork{
+				{ID: int64(vaa.ChainIDEthereum), Height: 1000},
+				{ID: int64(vaa.ChainIDSolana), Height: 5000},
+			},
+		},
+		{
+			ID:        "0x02",
+			NodeName:  "guardian-2",
+			Version:   "v2.18.0",
+			UpdatedAt: &recent,
+			Networks: []HeartbeatNetwork{
+				{ID: int64(vaa.ChainIDEthereum), Height: 998},
+				{ID: int64(vaa.ChainIDSolana), Height: 4000},
+			},
+		},
+		{
+			ID:        "0x03",
+			NodeName:  "guardian-3",
+			Version:   "v2.17.0",
+			UpdatedAt: &recent,
+			Networks: []HeartbeatNetwork{
+				{ID: int64(vaa.ChainIDEthereum), Height: 996},
+			},
+		},
+		{
+			ID:        "0x04",
+			NodeName:  "guardian-4",
+			Version:   "v2.18.0",
+			UpdatedAt: &stale,
+			Networks: []HeartbeatNetwork{
+				{ID: int64(vaa.ChainIDEthereum), Height: 1000},
+				{ID: int64(vaa.ChainIDSolana), Height: 5000},
+			},
+		},
+	}
+
+	chains := computeChainsHealth(now, guardianAddrs, heartbeats, 3)
+	assert.Len(t, chains, 2)
+
+	// solana is reported by two guardians with recent heartbeats and one of them lags behind.
+	solana := chains[0]
+	assert.Equal(t, vaa.ChainIDSolana, solana.ChainID)
+	assert.Equal(t, int64(0), solana.QuorumHeight)
+	assert.Equal(t, int64(5000), solana.HighestHeight)
+	assert.Equal(t, 1, solana.UpToDateGuardians)
+	assert.False(t, solana.HasQuorum)
+	assert.Equal(t, int64(1000), solana.Guardians[1].LagBlocks)
+	assert.Equal(t, 400.0, *solana.Guardians[1].LagSeconds)
+	assert.False(t, solana.Guardians[1].UpToDate)
+	assert.True(t, solana.Guardians[2].Missing)
+	assert.True(t, solana.Guardians[3].StaleHeartbeat)
+	assert.False(t, solana.Guardians[3].UpToDate)
+
+	// ethereum is reported by three guardians with recent heartbeats.
+	ethereum := chains[1]
+	assert.Equal(t, vaa.ChainIDEthereum, ethereum.ChainID)
+	assert.Equal(t, int64(996), ethereum.QuorumHeight)
+	assert.Equal(t, int64(1000), ethereum.HighestHeight)
+	assert.Equal(t, 3, ethereum.UpToDateGuardians)
+	assert.True(t, ethereum.HasQuorum)
+	assert.Equal(t, int64(0), ethereum.Guardians[0].LagBlocks)
+	assert.Equal(t, int64(0), ethereum.Guardians[2].LagBlocks)
+	assert.Equal(t, "v2.17.0", ethereum.Guardians[2].Version)
+	assert.Equal(t, "guardian-4", ethereum.Guardians[3].NodeName)
+	assert.True(t, ethereum.Guardians[3].StaleHeartbeat)
+}
+
+func TestHealth_computeChainsHealthWithoutHeartbeat(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	heartbeats := []*HeartbeatDoc{
+		{
+			ID:        "0x01",
+			Timestamp: now.UnixNano(),
+			Networks:  []HeartbeatNetwork{{ID: int64(vaa.ChainIDSui), Height: 10}},
+		},
+	}
+
+	chains := computeChainsHealth(now, []string{"0x01", "0x02"}, heartbeats, 2)
+	assert.Len(t, chains, 1)
+	assert.Equal(t, 1, chains[0].UpToDateGuardians)
+	assert.False(t, chains[0].HasQuorum)
+	assert.Nil(t, chains[0].Guardians[0].LagSeconds)
+	assert.True(t, chains[0].Guardians[1].StaleHeartbeat)
+	assert.True(t, chains[0].Guardians[1].Missing)
+}
+
+func TestHealth_computeChainsHealthGuardianAhead(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+
+	heights := []int64{5000, 1000, 1000, 999}
+	guardianAddrs := make([]string, 0, len(heights))
+	heartbeats := make([]*HeartbeatDoc, 0, len(heights))
+	for i, height := range heights {
+		addr := fmt.Sprintf("0x%02d", i+1)
+		guardianAddrs = append(guardianAddrs, addr)
+		heartbeats = append(heartbeats, &HeartbeatDoc{
+			ID:        addr,
+			UpdatedAt: &recent,
+			Networks:  []HeartbeatNetwork{{ID: int64(vaa.ChainIDEthereum), Height: height}},
+		})
+	}
+
+	// the guardians are compared with the quorum height, not with the guardian ahead of the others.
+	chains := computeChainsHealth(now, guardianAddrs, heartbeats, 3)
+	assert.Len(t, chains, 1)
+	assert.Equal(t, int64(1000), chains[0].QuorumHeight)
+	assert.Equal(t, int64(5000), chains[0].HighestHeight)
+	assert.Equal(t, 4, chains[0].UpToDateGuardians)
+	assert.True(t, chains[0].HasQuorum)
+	assert.Equal(t, int64(1), chains[0].Guardians[3].LagBlocks)
+}
@@ -1,6 +1,10 @@
 package heartbeats
 
-import "time"
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
 
 // HeartbeatDoc represent an heartbeat document.
 type HeartbeatDoc struct {
@@ -24,3 +28,26 @@ type HeartbeatNetwork struct {
 	ContractAddress string `bson:"contractaddress" json:"contractAddress"`
 	ErrorCount      int64  `bson:"errorcount" json:"errorCount"`
 }
+
+// LastHeartbeatTime returns the time of the last heartbeat of the guardian.
+func (hb *HeartbeatDoc) LastHeartbeatTime() time.Time {
+	if hb.UpdatedAt != nil {
+		return *hb.UpdatedAt
+	}
+	return time.Unix(0, hb.Timestamp)
+}
+
+// IsStale indicates whether the last heartbeat of the guardian is older than the stale threshold.
+func (hb *HeartbeatDoc) IsStale(now time.Time) bool {
+	return now.Sub(hb.LastHeartbeatTime()) > staleHeartbeatThreshold
+}
+
+// Height returns the height reported by the guardian for a chain, if the guardian reports it.
+func (hb *HeartbeatDoc) Height(chainID vaa.ChainID) (int64, bool) {
+	for _, n := range hb.Networks {
+		if vaa.ChainID(n.ID) == chainID {
+			return n.Height, true
+		}
+	}
+	return 0, false
+}
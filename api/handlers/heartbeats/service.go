@@ -2,22 +2,135 @@ package heartbeats
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
+	apiAlert "github.com/wormhole-foundation/wormhole-explorer/api/internal/alert"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
 // Service definition.
 type Service struct {
-	repo   *Repository
-	logger *zap.Logger
+	repo        *Repository
+	p2pNetwork  string
+	gs          guardian.GuardianSet
+	alertClient alert.AlertClient
+	logger      *zap.Logger
+	// lostQuorum contains the chains without a quorum of up-to-date guardians in the last health check.
+	lostQuorum   map[vaa.ChainID]bool
+	lostQuorumMu sync.Mutex
 }
 
 // NewService create a new Service.
-func NewService(dao *Repository, logger *zap.Logger) *Service {
-	return &Service{repo: dao, logger: logger.With(zap.String("module", "HearbeatsService"))}
+func NewService(dao *Repository, p2pNetwork string, alertClient alert.AlertClient, logger *zap.Logger) *Service {
+	return &Service{
+		repo:        dao,
+		p2pNetwork:  p2pNetwork,
+		gs:          guardian.GetByEnv(p2pNetwork),
+		alertClient: alertClient,
+		logger:      logger.With(zap.String("module", "HearbeatsService")),
+		lostQuorum:  make(map[vaa.ChainID]bool),
+	}
 }
 
 // GetHeartbeatsByIds get heartbeats by IDs.
 func (s *Service) GetHeartbeatsByIds(ctx context.Context, heartbeatsIDs []string) ([]*HeartbeatDoc, error) {
 	return s.repo.FindByIDs(ctx, heartbeatsIDs)
 }
+
+// GetGuardianHealth get the health of the guardians of the current guardian set for each chain.
+func (s *Service) GetGuardianHealth(ctx context.Context) (*GuardianHealth, error) {
+	if len(s.gs.GstByIndex) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	guardianSet := s.gs.GetLatest()
+	guardianAddrs := guardianSet.KeysAsHexStrings()
+	heartbeats, err := s.repo.FindByIDs(ctx, guardianAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	quorum := vaa.CalculateQuorum(len(guardianAddrs))
+	return &GuardianHealth{
+		GuardianSetIndex: guardianSet.Index,
+		GuardianSetSize:  len(guardianAddrs),
+		Quorum:           quorum,
+		Chains:           computeChainsHealth(time.Now(), guardianAddrs, heartbeats, quorum),
+	}, nil
+}
+
+// MonitorGuardianHealth checks the health of the guardians every interval and sends an alert
+// when a chain loses the quorum of up-to-date guardians. It blocks until the context is done.
+func (s *Service) MonitorGuardianHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkGuardianHealth(ctx)
+		}
+	}
+}
+
+// checkGuardianHealth sends an alert for each chain that lost the quorum of up-to-date guardians
+// since the last check.
+func (s *Service) checkGuardianHealth(ctx context.Context) {
+	health, err := s.GetGuardianHealth(ctx)
+	if err != nil {
+		s.logger.Error("failed to get guardian health", zap.Error(err))
+		return
+	}
+
+	s.lostQuorumMu.Lock()
+	defer s.lostQuorumMu.Unlock()
+
+	for _, chain := range health.Chains {
+		if chain.HasQuorum {
+			if s.lostQuorum[chain.ChainID] {
+				s.logger.Info("chain recovered quorum of up-to-date guardians", zap.Stringer("chainId", chain.ChainID))
+			}
+			delete(s.lostQuorum, chain.ChainID)
+			continue
+		}
+		if s.lostQuorum[chain.ChainID] {
+			continue
+		}
+
+		s.logger.Warn("chain lost quorum of up-to-date guardians",
+			zap.Stringer("chainId", chain.ChainID),
+			zap.Int("upToDateGuardians", chain.UpToDateGuardians),
+			zap.Int("quorum", health.Quorum))
+
+		alertContext := alert.AlertContext{
+			Details: map[string]string{
+				"network":           s.p2pNetwork,
+				"chainId":           chain.ChainID.String(),
+				"upToDateGuardians": fmt.Sprintf("%d", chain.UpToDateGuardians),
+				"quorum":            fmt.Sprintf("%d", health.Quorum),
+				"quorumHeight":      fmt.Sprintf("%d", chain.QuorumHeight),
+				"highestHeight":     fmt.Sprintf("%d", chain.HighestHeight),
+			},
+		}
+		a, err := s.alertClient.CreateAlert(apiAlert.ChainLostQuorum, alertContext)
+		if err != nil {
+			s.logger.Error("failed to create alert", zap.Error(err), zap.Stringer("chainId", chain.ChainID))
+			continue
+		}
+		// the chain is only marked once the alert is created, so that it is retried in the next check otherwise.
+		s.lostQuorum[chain.ChainID] = true
+		// alias by network and chain so that the alerts of different networks and chains are not deduplicated,
+		// the alert client is shared by the networks.
+		a.Alias = fmt.Sprintf("%s_%s_%d", a.Alias, s.p2pNetwork, chain.ChainID)
+		if err := s.alertClient.Send(ctx, a); err != nil {
+			s.logger.Error("failed to send alert", zap.Error(err), zap.Stringer("chainId", chain.ChainID))
+		}
+	}
+}
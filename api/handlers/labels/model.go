@@ -0,0 +1,152 @@
+package labels
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cosmos/btcutil/bech32"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Category is the kind of entity identified by a label.
+type Category string
+
+const (
+	CategoryBridgeContract Category = "bridge-contract"
+	CategoryRelayer        Category = "relayer"
+	CategoryExchange       Category = "exchange"
+	CategoryProtocol       Category = "protocol"
+)
+
+// ParseCategory parses a string and returns a `Category`.
+func ParseCategory(s string) (Category, error) {
+	switch Category(s) {
+	case CategoryBridgeContract, CategoryRelayer, CategoryExchange, CategoryProtocol:
+		return Category(s), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidCategory, s)
+}
+
+// Label sources.
+const (
+	// sourceSeed is the source of the labels loaded from the seed file.
+	sourceSeed = "seed"
+	// sourceAdmin is the source of the labels edited through the admin endpoints.
+	// These labels are never overwritten by the seed file.
+	sourceAdmin = "admin"
+)
+
+// Errors returned when editing a label.
+var (
+	ErrInvalidAddress  = errors.New("invalid address")
+	ErrInvalidCategory = errors.New("invalid category")
+	ErrMissingName     = errors.New("name is required")
+)
+
+// Label is a name assigned to a known address of a chain.
+type Label struct {
+	ChainID sdk.ChainID `bson:"chainId" json:"chainId"`
+	// Address is the 32-byte address, encoded as hex.
+	Address   string    `bson:"address" json:"address"`
+	Name      string    `bson:"name" json:"name"`
+	Category  Category  `bson:"category" json:"category"`
+	AppID     string    `bson:"appId,omitempty" json:"appId,omitempty"`
+	Source    string    `bson:"source" json:"-"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// labelDoc is a document of the addressLabels collection.
+type labelDoc struct {
+	ID          string `bson:"_id"`
+	Label       `bson:",inline"`
+	SeedVersion int  `bson:"seedVersion,omitempty"`
+	Deleted     bool `bson:"deleted,omitempty"`
+}
+
+// UpdateLabel contains the editable fields of a label.
+type UpdateLabel struct {
+	Name     string   `json:"name"`
+	Category Category `json:"category"`
+	AppID    string   `json:"appId"`
+}
+
+// SearchQuery is a query over the labels.
+// Empty fields are not used to filter the labels.
+type SearchQuery struct {
+	// Text is matched against the name and the address of the labels.
+	Text     string
+	ChainID  *sdk.ChainID
+	Category Category
+	AppID    string
+	Skip     int64
+	Limit    int64
+}
+
+// seedFile is the format of the file that contains the initial labels.
+type seedFile struct {
+	// Version must be incremented on every change of the file.
+	Version int `json:"version"`
+	Labels  []struct {
+		ChainID  sdk.ChainID `json:"chainId"`
+		Address  string      `json:"address"`
+		Name     string      `json:"name"`
+		Category Category    `json:"category"`
+		AppID    string      `json:"appId"`
+	} `json:"labels"`
+}
+
+// NormalizeAddress returns the 32-byte address, encoded as hex, of an address of a chain.
+//
+// The address can be a wormhole address (hex, with or without the 0x prefix) or a native address
+// of the chain (e.g. base58 for Solana, bech32 for Terra), so labels match regardless of the encoding.
+func NormalizeAddress(chainID sdk.ChainID, address string) (string, error) {
+
+	address = strings.TrimSpace(address)
+	if a := strings.ToLower(strings.TrimPrefix(address, "0x")); len(a) == 64 && isHex(a) {
+		return a, nil
+	}
+
+	decoded, err := decodeBech32(address)
+	if err != nil {
+		// chains without a native encoding use hex addresses.
+		decoded, err = domain.DecodeNativeAddressToHex(chainID, address)
+		if err != nil {
+			decoded = address
+		}
+	}
+
+	decoded = strings.ToLower(strings.TrimPrefix(decoded, "0x"))
+	if decoded == "" || len(decoded) > 64 || !isHex(decoded) {
+		return "", ErrInvalidAddress
+	}
+	return strings.Repeat("0", 64-len(decoded)) + decoded, nil
+}
+
+// decodeBech32 decodes a bech32 address, such as the addresses of Terra, to hex.
+func decodeBech32(address string) (string, error) {
+	_, data, err := bech32.Decode(address, bech32.MaxLengthBIP173)
+	if err != nil {
+		return "", err
+	}
+	b, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func isHex(s string) bool {
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func labelID(chainID sdk.ChainID, address string) string {
+	return fmt.Sprintf("%d/%s", chainID, address)
+}
@@ -0,0 +1,132 @@
+package labels
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definition.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		addressLabels *mongo.Collection
+	}
+}
+
+// NewRepository create a new Repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db: db,
+		logger: logger.With(zap.String("module", "LabelsRepository")),
+		collections: struct {
+			addressLabels *mongo.Collection
+		}{
+			addressLabels: db.Collection("addressLabels"),
+		},
+	}
+}
+
+// FindAll returns all the labels.
+func (r *Repository) FindAll(ctx context.Context) ([]*Label, error) {
+	cur, err := r.collections.addressLabels.Find(ctx, bson.D{{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}}})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var docs []labelDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	labels := make([]*Label, 0, len(docs))
+	for i := range docs {
+		labels = append(labels, &docs[i].Label)
+	}
+	return labels, nil
+}
+
+// Seed upserts the labels of the seed file and removes the labels of previous versions of the file.
+// The labels edited through the admin endpoints and the labels of a newer or the same version of the file,
+// seeded by another instance, are not modified.
+func (r *Repository) Seed(ctx context.Context, version int, labels []*Label) error {
+
+	now := time.Now()
+	for _, l := range labels {
+		id := labelID(l.ChainID, l.Address)
+		filter := bson.D{
+			{Key: "_id", Value: id},
+			{Key: "source", Value: bson.D{{Key: "$ne", Value: sourceAdmin}}},
+			{Key: "seedVersion", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: version}}}}},
+		}
+		update := bson.D{{Key: "$set", Value: bson.D{
+			{Key: "chainId", Value: l.ChainID},
+			{Key: "address", Value: l.Address},
+			{Key: "name", Value: l.Name},
+			{Key: "category", Value: l.Category},
+			{Key: "appId", Value: l.AppID},
+			{Key: "source", Value: sourceSeed},
+			{Key: "seedVersion", Value: version},
+			{Key: "updatedAt", Value: now},
+		}}}
+		_, err := r.collections.addressLabels.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		// the label exists and was edited by an admin or seeded from a newer version.
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	_, err := r.collections.addressLabels.DeleteMany(ctx, bson.D{
+		{Key: "source", Value: sourceSeed},
+		{Key: "seedVersion", Value: bson.D{{Key: "$lt", Value: version}}},
+	})
+	return errors.WithStack(err)
+}
+
+// Upsert creates or updates a label.
+func (r *Repository) Upsert(ctx context.Context, l *Label) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "chainId", Value: l.ChainID},
+			{Key: "address", Value: l.Address},
+			{Key: "name", Value: l.Name},
+			{Key: "category", Value: l.Category},
+			{Key: "appId", Value: l.AppID},
+			{Key: "source", Value: sourceAdmin},
+			{Key: "deleted", Value: false},
+			{Key: "updatedAt", Value: l.UpdatedAt},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "seedVersion", Value: ""}}},
+	}
+	_, err := r.collections.addressLabels.UpdateByID(ctx, labelID(l.ChainID, l.Address), update, options.Update().SetUpsert(true))
+	return errors.WithStack(err)
+}
+
+// Delete marks a label as deleted.
+//
+// The document is kept so that the seed file does not create the label again.
+func (r *Repository) Delete(ctx context.Context, chainID sdk.ChainID, address string) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: labelID(chainID, address)},
+		{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "source", Value: sourceAdmin},
+		{Key: "deleted", Value: true},
+		{Key: "updatedAt", Value: time.Now()},
+	}}}
+	res, err := r.collections.addressLabels.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return res.MatchedCount > 0, nil
+}
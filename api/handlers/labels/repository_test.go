@@ -0,0 +1,54 @@
+package labels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestRepository_Seed(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	labels := []*Label{
+		{ChainID: sdk.ChainIDEthereum, Address: "0001", Name: "A"},
+		{ChainID: sdk.ChainIDEthereum, Address: "0002", Name: "B"},
+	}
+
+	mt.Run("newer version", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			// the label was edited by an admin or seeded from a newer version.
+			mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+		)
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		err := repository.Seed(context.Background(), 3, labels)
+		assert.NoError(t, err)
+
+		events := mt.GetAllStartedEvents()
+		assert.Len(t, events, 3)
+		// the labels are only updated if their seed version is older.
+		filter := events[0].Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q")
+		var version bson.D
+		assert.NoError(t, filter.Document().Lookup("seedVersion").Unmarshal(&version))
+		assert.Equal(t, bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: int32(3)}}}}, version)
+		assert.Equal(t, "delete", events[2].CommandName)
+	})
+
+	mt.Run("update error", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "failed"}))
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		err := repository.Seed(context.Background(), 3, labels)
+		var cmdErr mongo.CommandError
+		assert.ErrorAs(t, err, &cmdErr)
+	})
+}
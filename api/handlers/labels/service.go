@@ -0,0 +1,243 @@
+package labels
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// labelsFile contains the initial labels. It is loaded into the database when the service starts.
+//
+//go:embed labels.json
+var labelsFile []byte
+
+// refreshInterval is the interval between reloads of the labels, to pick up the changes made by other instances.
+const refreshInterval = time.Minute
+
+// Service definition.
+type Service struct {
+	repo   *Repository
+	logger *zap.Logger
+
+	mu sync.RWMutex
+	// byID contains the labels indexed by chain and address.
+	byID map[string]*Label
+	// byAddress contains the labels indexed by address, since an address can be labeled on several chains.
+	byAddress map[string][]*Label
+	// sorted contains the labels sorted by name.
+	sorted []*Label
+}
+
+// NewService create a new Service.
+func NewService(repo *Repository, logger *zap.Logger) *Service {
+	s := &Service{
+		repo:   repo,
+		logger: logger.With(zap.String("module", "LabelsService")),
+	}
+	s.setLabels(nil)
+	return s
+}
+
+// Start loads the seed file into the database and starts reloading the labels periodically.
+func (s *Service) Start(ctx context.Context) error {
+
+	var seed seedFile
+	if err := json.Unmarshal(labelsFile, &seed); err != nil {
+		return fmt.Errorf("failed to parse labels file: %w", err)
+	}
+	labels, err := parseSeed(&seed)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Seed(ctx, seed.Version, labels); err != nil {
+		return fmt.Errorf("failed to seed labels: %w", err)
+	}
+	if err := s.reload(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.reload(ctx); err != nil {
+					s.logger.Error("failed to reload labels", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Find returns the label of an address of a chain, or nil if the address is not labeled.
+// The address can be encoded as hex or in the native format of the chain.
+func (s *Service) Find(chainID sdk.ChainID, address string) *Label {
+	if address == "" {
+		return nil
+	}
+	normalized, err := NormalizeAddress(chainID, address)
+	if err != nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.byID[labelID(chainID, normalized)]
+}
+
+// FindByAddress returns the labels of a 32-byte address, encoded as hex, on any chain.
+func (s *Service) FindByAddress(address string) []*Label {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.byAddress[strings.ToLower(address)]
+}
+
+// Search returns the labels that match the query, sorted by name.
+func (s *Service) Search(q *SearchQuery) []*Label {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return searchLabels(s.sorted, q)
+}
+
+// Upsert creates or updates the label of an address.
+func (s *Service) Upsert(ctx context.Context, chainID sdk.ChainID, address string, u *UpdateLabel) (*Label, error) {
+	normalized, err := NormalizeAddress(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	if u.Name == "" {
+		return nil, ErrMissingName
+	}
+	if _, err := ParseCategory(string(u.Category)); err != nil {
+		return nil, err
+	}
+
+	label := Label{
+		ChainID:   chainID,
+		Address:   normalized,
+		Name:      u.Name,
+		Category:  u.Category,
+		AppID:     u.AppID,
+		Source:    sourceAdmin,
+		UpdatedAt: time.Now(),
+	}
+	if err := s.repo.Upsert(ctx, &label); err != nil {
+		return nil, err
+	}
+	if err := s.reload(ctx); err != nil {
+		s.logger.Error("failed to reload labels", zap.Error(err))
+	}
+	return &label, nil
+}
+
+// Delete removes the label of an address.
+// It returns false if the address is not labeled.
+func (s *Service) Delete(ctx context.Context, chainID sdk.ChainID, address string) (bool, error) {
+	normalized, err := NormalizeAddress(chainID, address)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := s.repo.Delete(ctx, chainID, normalized)
+	if err != nil {
+		return false, err
+	}
+	if err := s.reload(ctx); err != nil {
+		s.logger.Error("failed to reload labels", zap.Error(err))
+	}
+	return deleted, nil
+}
+
+// reload reads all the labels from the database and replaces the in-memory index.
+func (s *Service) reload(ctx context.Context) error {
+	labels, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	s.setLabels(labels)
+	return nil
+}
+
+func (s *Service) setLabels(labels []*Label) {
+	byID := make(map[string]*Label, len(labels))
+	byAddress := make(map[string][]*Label, len(labels))
+	for _, l := range labels {
+		byID[labelID(l.ChainID, l.Address)] = l
+		byAddress[l.Address] = append(byAddress[l.Address], l)
+	}
+	sorted := make([]*Label, len(labels))
+	copy(sorted, labels)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byID = byID
+	s.byAddress = byAddress
+	s.sorted = sorted
+}
+
+// parseSeed validates and normalizes the labels of the seed file.
+func parseSeed(seed *seedFile) ([]*Label, error) {
+	labels := make([]*Label, 0, len(seed.Labels))
+	for _, l := range seed.Labels {
+		address, err := NormalizeAddress(l.ChainID, l.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address in labels file %d/%s: %w", l.ChainID, l.Address, err)
+		}
+		if _, err := ParseCategory(string(l.Category)); err != nil {
+			return nil, fmt.Errorf("invalid category in labels file %d/%s: %w", l.ChainID, l.Address, err)
+		}
+		labels = append(labels, &Label{
+			ChainID:  l.ChainID,
+			Address:  address,
+			Name:     l.Name,
+			Category: l.Category,
+			AppID:    l.AppID,
+			Source:   sourceSeed,
+		})
+	}
+	return labels, nil
+}
+
+// searchLabels filters and paginates a list of labels.
+func searchLabels(labels []*Label, q *SearchQuery) []*Label {
+	text := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(q.Text), "0x"))
+
+	result := make([]*Label, 0)
+	var skipped int64
+	for _, l := range labels {
+		if q.ChainID != nil && l.ChainID != *q.ChainID {
+			continue
+		}
+		if q.Category != "" && l.Category != q.Category {
+			continue
+		}
+		if q.AppID != "" && l.AppID != q.AppID {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(l.Name), text) && !strings.Contains(l.Address, text) {
+			continue
+		}
+		if skipped < q.Skip {
+			skipped++
+			continue
+		}
+		if q.Limit > 0 && int64(len(result)) >= q.Limit {
+			break
+		}
+		result = append(result, l)
+	}
+	return result
+}
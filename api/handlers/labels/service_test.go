@@ -0,0 +1,72 @@
+package labels
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	const ethereumTokenBridge = "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"
+	for _, address := range []string{
+		"0x3ee18B2214AFF97000D974cf647E7C347E8fa585",
+		"3ee18b2214aff97000d974cf647e7c347e8fa585",
+		ethereumTokenBridge,
+		"0x" + ethereumTokenBridge,
+	} {
+		normalized, err := NormalizeAddress(sdk.ChainIDEthereum, address)
+		assert.NoError(t, err)
+		assert.Equal(t, ethereumTokenBridge, normalized)
+	}
+
+	// native encodings are decoded.
+	normalized, err := NormalizeAddress(sdk.ChainIDSolana, "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk")
+	assert.NoError(t, err)
+	assert.Equal(t, "ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5", normalized)
+
+	normalized, err = NormalizeAddress(sdk.ChainIDTerra, "terra10nmmwe8r3g99a9newtqa7a75xfgs2e8z87r2sf")
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000000000000000007cf7b764e38a0a5e967972c1df77d432510564e2", normalized)
+
+	_, err = NormalizeAddress(sdk.ChainIDEthereum, "not an address")
+	assert.Equal(t, ErrInvalidAddress, err)
+}
+
+func TestService_parseSeed(t *testing.T) {
+	// the embedded labels file must be valid.
+	var seed seedFile
+	assert.NoError(t, json.Unmarshal(labelsFile, &seed))
+	assert.Greater(t, seed.Version, 0)
+
+	labels, err := parseSeed(&seed)
+	assert.NoError(t, err)
+	assert.Len(t, labels, len(seed.Labels))
+
+	ids := make(map[string]bool)
+	for _, l := range labels {
+		id := labelID(l.ChainID, l.Address)
+		assert.False(t, ids[id], "duplicated label %s", id)
+		ids[id] = true
+	}
+}
+
+func TestService_searchLabels(t *testing.T) {
+	ethereum := sdk.ChainIDEthereum
+	labels := []*Label{
+		{ChainID: sdk.ChainIDEthereum, Address: "00000000000000000000000098f3c9e6e3face36baad05fe09d375ef1464288b", Name: "Core Bridge", Category: CategoryBridgeContract},
+		{ChainID: sdk.ChainIDEthereum, Address: "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585", Name: "Portal Token Bridge", Category: CategoryBridgeContract, AppID: "PORTAL_TOKEN_BRIDGE"},
+		{ChainID: sdk.ChainIDSolana, Address: "ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5", Name: "Portal Token Bridge", Category: CategoryBridgeContract, AppID: "PORTAL_TOKEN_BRIDGE"},
+		{ChainID: sdk.ChainIDSolana, Address: "1dd48d0ee1fe7059b2866507b84f5f4259d7408c812e88bd6260a4914f7a2605", Name: "Relayer", Category: CategoryRelayer},
+	}
+
+	assert.Len(t, searchLabels(labels, &SearchQuery{Text: "bridge"}), 3)
+	assert.Len(t, searchLabels(labels, &SearchQuery{Text: "0x3EE18B"}), 1)
+	assert.Len(t, searchLabels(labels, &SearchQuery{Text: "portal", ChainID: &ethereum}), 1)
+	assert.Len(t, searchLabels(labels, &SearchQuery{Category: CategoryRelayer}), 1)
+	assert.Len(t, searchLabels(labels, &SearchQuery{AppID: "PORTAL_TOKEN_BRIDGE"}), 2)
+
+	page := searchLabels(labels, &SearchQuery{Skip: 1, Limit: 2})
+	assert.Equal(t, []*Label{labels[1], labels[2]}, page)
+}
@@ -21,14 +21,21 @@ type Repository struct {
 	logger      *zap.Logger
 	collections struct {
 		observations *mongo.Collection
+		vaas         *mongo.Collection
 	}
 }
 
 // NewRepository create a new Repository.
 func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
 	return &Repository{db: db,
-		logger:      logger.With(zap.String("module", "ObservationsRepository")),
-		collections: struct{ observations *mongo.Collection }{observations: db.Collection("observations")},
+		logger: logger.With(zap.String("module", "ObservationsRepository")),
+		collections: struct {
+			observations *mongo.Collection
+			vaas         *mongo.Collection
+		}{
+			observations: db.Collection("observations"),
+			vaas:         db.Collection("vaas"),
+		},
 	}
 }
 
@@ -81,6 +88,26 @@ func (r *Repository) FindOne(ctx context.Context, q *ObservationQuery) (*Observa
 	return &obs, err
 }
 
+// FindVaaGuardianSetIndex get the index of the guardian set that signed a VAA.
+// It returns false if the VAA has not been signed yet.
+func (r *Repository) FindVaaGuardianSetIndex(ctx context.Context, vaaID string) (uint32, bool, error) {
+	var doc struct {
+		GuardianSetIndex uint32 `bson:"guardianSetIndex"`
+	}
+	opts := options.FindOne().SetProjection(bson.D{{"guardianSetIndex", 1}})
+	err := r.collections.vaas.FindOne(ctx, bson.D{{"_id", vaaID}}, opts).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, false, nil
+		}
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute FindOne command to get the guardian set index of a vaa",
+			zap.Error(err), zap.String("vaaId", vaaID), zap.String("requestID", requestID))
+		return 0, false, errors.WithStack(err)
+	}
+	return doc.GuardianSetIndex, true, nil
+}
+
 // ObservationQuery respresent a query for the observation mongodb document.
 type ObservationQuery struct {
 	pagination.Pagination
@@ -3,7 +3,12 @@ package observations
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/types"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -12,13 +17,24 @@ import (
 
 // Service definition.
 type Service struct {
-	repo   *Repository
-	logger *zap.Logger
+	repo              *Repository
+	heartbeatsService *heartbeats.Service
+	gs                guardian.GuardianSet
+	logger            *zap.Logger
 }
 
+// maxObservationsByVAA is the maximum number of observations of a message read to find the missing signatures.
+// A message usually has one observation per guardian, but guardians can observe conflicting hashes.
+const maxObservationsByVAA = 1000
+
 // NewService create a new Service.
-func NewService(dao *Repository, logger *zap.Logger) *Service {
-	return &Service{repo: dao, logger: logger.With(zap.String("module", "ObservationsService"))}
+func NewService(dao *Repository, heartbeatsService *heartbeats.Service, p2pNetwork string, logger *zap.Logger) *Service {
+	return &Service{
+		repo:              dao,
+		heartbeatsService: heartbeatsService,
+		gs:                guardian.GetByEnv(p2pNetwork),
+		logger:            logger.With(zap.String("module", "ObservationsService")),
+	}
 }
 
 // FindAll get all the observations.
@@ -85,3 +101,55 @@ func (s *Service) FindOne(
 
 	return s.repo.FindOne(ctx, query)
 }
+
+// FindMissingSignatures get the guardians that have not signed a VAA (chainID, emitter addrress and sequence number).
+//
+// The guardian set of the message is the one that signed the VAA, or the latest guardian set
+// if the VAA has not been produced yet.
+func (s *Service) FindMissingSignatures(
+	ctx context.Context,
+	chain vaa.ChainID,
+	emitter *types.Address,
+	seq string,
+) (*MissingSignatures, error) {
+
+	if len(s.gs.GstByIndex) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	vaaID := fmt.Sprintf("%d/%s/%s", chain, emitter.Hex(), seq)
+	gsIndex, signed, err := s.repo.FindVaaGuardianSetIndex(ctx, vaaID)
+	if err != nil {
+		return nil, err
+	}
+	guardianSet := s.gs.GetLatest()
+	if signed {
+		if gs, ok := s.gs.GetByIndex(gsIndex); ok {
+			guardianSet = gs
+		}
+	}
+
+	query := Query().
+		SetChain(chain).
+		SetEmitter(emitter.Hex()).
+		SetSequence(seq).
+		SetPagination(pagination.Default().SetLimit(maxObservationsByVAA))
+	obs, err := s.repo.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(obs) == 0 && !signed {
+		return nil, errs.ErrNotFound
+	}
+
+	guardianAddrs := guardianSet.KeysAsHexStrings()
+	heartbeatDocs, err := s.heartbeatsService.GetHeartbeatsByIds(ctx, guardianAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := computeMissingSignatures(time.Now(), chain, guardianAddrs, vaa.CalculateQuorum(len(guardianAddrs)), obs, heartbeatDocs)
+	missing.GuardianSetIndex = guardianSet.Index
+	missing.Signed = signed
+	return missing, nil
+}
@@ -0,0 +1,137 @@
+package observations
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// MissingSignatures lists the guardians of the guardian set of a message that have not signed it yet.
+//
+// Guardians can observe conflicting digests of the same message, so the signatures are counted by digest.
+// The top-level fields describe the leading digest, the one with the most signatures.
+type MissingSignatures struct {
+	GuardianSetIndex uint32 `json:"guardianSetIndex"`
+	GuardianSetSize  int    `json:"guardianSetSize"`
+	Quorum           int    `json:"quorum"`
+	DigestSignatures
+	// Signed indicates whether the VAA of the message has been produced.
+	Signed bool `json:"signed"`
+	// Digests lists the signatures of each digest observed for the message, sorted by number of signatures.
+	Digests []*DigestSignatures `json:"digests"`
+}
+
+// DigestSignatures are the signatures of a digest of a message.
+type DigestSignatures struct {
+	Hash []byte `json:"hash,omitempty"`
+	// Signatures is the number of guardians of the guardian set that have signed the digest.
+	Signatures int `json:"signatures"`
+	// HasQuorum indicates whether a quorum of guardians has signed the digest.
+	HasQuorum bool                `json:"hasQuorum"`
+	Missing   []*MissingSignature `json:"missing"`
+}
+
+// MissingSignature is a guardian that has not signed a message.
+type MissingSignature struct {
+	GuardianAddress string `json:"guardianAddress"`
+	NodeName        string `json:"nodeName,omitempty"`
+	// LastHeartbeat is the time of the last heartbeat of the guardian. It is nil if the guardian has no heartbeats.
+	LastHeartbeat *time.Time `json:"lastHeartbeat,omitempty"`
+	// StaleHeartbeat indicates whether the last heartbeat of the guardian is stale or missing.
+	StaleHeartbeat bool `json:"staleHeartbeat"`
+	// Height is the height of the emitter chain reported by the guardian. It is nil if the guardian does not report the chain.
+	Height *int64 `json:"height,omitempty"`
+}
+
+// computeMissingSignatures returns, for each digest of the message, the guardians of a guardian set without an observation of it.
+func computeMissingSignatures(
+	now time.Time,
+	emitterChain vaa.ChainID,
+	guardianAddrs []string,
+	quorum int,
+	obs []*ObservationDoc,
+	heartbeatDocs []*heartbeats.HeartbeatDoc,
+) *MissingSignatures {
+
+	// group the signers by digest, a guardian that observed the same digest more than once is counted once.
+	hashes := make(map[string][]byte)
+	signersByHash := make(map[string]map[string]bool)
+	for _, o := range obs {
+		key := string(o.Hash)
+		if _, ok := signersByHash[key]; !ok {
+			hashes[key] = o.Hash
+			signersByHash[key] = make(map[string]bool)
+		}
+		signersByHash[key][strings.ToLower(o.GuardianAddr)] = true
+	}
+	heartbeatsByAddr := make(map[string]*heartbeats.HeartbeatDoc, len(heartbeatDocs))
+	for _, hb := range heartbeatDocs {
+		heartbeatsByAddr[strings.ToLower(hb.ID)] = hb
+	}
+
+	digests := make([]*DigestSignatures, 0, len(signersByHash))
+	for key, signers := range signersByHash {
+		digests = append(digests, computeDigestSignatures(now, emitterChain, guardianAddrs, quorum, hashes[key], signers, heartbeatsByAddr))
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		if digests[i].Signatures != digests[j].Signatures {
+			return digests[i].Signatures > digests[j].Signatures
+		}
+		return bytes.Compare(digests[i].Hash, digests[j].Hash) < 0
+	})
+
+	result := MissingSignatures{
+		GuardianSetSize: len(guardianAddrs),
+		Quorum:          quorum,
+		Digests:         digests,
+	}
+	if len(digests) > 0 {
+		result.DigestSignatures = *digests[0]
+	} else {
+		// without observations, every guardian is missing.
+		result.DigestSignatures = *computeDigestSignatures(now, emitterChain, guardianAddrs, quorum, nil, nil, heartbeatsByAddr)
+	}
+	return &result
+}
+
+// computeDigestSignatures returns the guardians of a guardian set that have not signed a digest.
+func computeDigestSignatures(
+	now time.Time,
+	emitterChain vaa.ChainID,
+	guardianAddrs []string,
+	quorum int,
+	hash []byte,
+	signers map[string]bool,
+	heartbeatsByAddr map[string]*heartbeats.HeartbeatDoc,
+) *DigestSignatures {
+
+	result := DigestSignatures{
+		Hash:    hash,
+		Missing: make([]*MissingSignature, 0),
+	}
+	for _, addr := range guardianAddrs {
+		if signers[strings.ToLower(addr)] {
+			result.Signatures++
+			continue
+		}
+
+		missing := MissingSignature{GuardianAddress: addr, StaleHeartbeat: true}
+		if hb, ok := heartbeatsByAddr[strings.ToLower(addr)]; ok {
+			lastHeartbeat := hb.LastHeartbeatTime()
+			missing.NodeName = hb.NodeName
+			missing.LastHeartbeat = &lastHeartbeat
+			missing.StaleHeartbeat = hb.IsStale(now)
+			if height, ok := hb.Height(emitterChain); ok {
+				missing.Height = &height
+			}
+		}
+		result.Missing = append(result.Missing, &missing)
+	}
+	result.HasQuorum = result.Signatures >= quorum
+
+	return &result
+}
@@ -0,0 +1,115 @@
+package observations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestSignatures_computeMissingSignatures(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+	stale := now.Add(-10 * time.Minute)
+
+	guardianAddrs := []string{"0xAA01", "0xAA02", "0xAA03", "0xAA04"}
+	obs := []*ObservationDoc{
+		{GuardianAddr: "0xAA01", Hash: []byte{1}},
+		// conflicting observations are counted in their own digest.
+		{GuardianAddr: "0xAA01", Hash: []byte{2}},
+		{GuardianAddr: "0xaa02", Hash: []byte{1}},
+	}
+	heartbeatDocs := []*heartbeats.HeartbeatDoc{
+		{
+			ID:        "0xAA03",
+			NodeName:  "guardian-3",
+			UpdatedAt: &recent,
+			Networks:  []heartbeats.HeartbeatNetwork{{ID: int64(vaa.ChainIDEthereum), Height: 1000}},
+		},
+		{
+			ID:        "0xAA04",
+			NodeName:  "guardian-4",
+			UpdatedAt: &stale,
+			Networks:  []heartbeats.HeartbeatNetwork{{ID: int64(vaa.ChainIDSolana), Height: 5000}},
+		},
+	}
+
+	missing := computeMissingSignatures(now, vaa.ChainIDEthereum, guardianAddrs, 3, obs, heartbeatDocs)
+	assert.Equal(t, 4, missing.GuardianSetSize)
+	assert.Equal(t, 3, missing.Quorum)
+	assert.Equal(t, 2, missing.Signatures)
+	assert.False(t, missing.HasQuorum)
+	assert.Len(t, missing.Missing, 2)
+	assert.Equal(t, []byte{1}, missing.Hash)
+	if assert.Len(t, missing.Digests, 2) {
+		assert.Equal(t, []byte{2}, missing.Digests[1].Hash)
+		assert.Equal(t, 1, missing.Digests[1].Signatures)
+		assert.Len(t, missing.Digests[1].Missing, 3)
+	}
+
+	g3 := missing.Missing[0]
+	assert.Equal(t, "0xAA03", g3.GuardianAddress)
+	assert.Equal(t, "guardian-3", g3.NodeName)
+	assert.Equal(t, &recent, g3.LastHeartbeat)
+	assert.False(t, g3.StaleHeartbeat)
+	if assert.NotNil(t, g3.Height) {
+		assert.Equal(t, int64(1000), *g3.Height)
+	}
+
+	// the guardian does not report the emitter chain.
+	g4 := missing.Missing[1]
+	assert.Equal(t, "0xAA04", g4.GuardianAddress)
+	assert.True(t, g4.StaleHeartbeat)
+	assert.Nil(t, g4.Height)
+}
+
+func TestSignatures_computeMissingSignaturesWithQuorum(t *testing.T) {
+	guardianAddrs := []string{"0xAA01", "0xAA02", "0xAA03"}
+	obs := []*ObservationDoc{{GuardianAddr: "0xAA01"}, {GuardianAddr: "0xAA02"}, {GuardianAddr: "0xAA03"}}
+
+	missing := computeMissingSignatures(time.Now(), vaa.ChainIDEthereum, guardianAddrs, 3, obs, nil)
+	assert.Equal(t, 3, missing.Signatures)
+	assert.True(t, missing.HasQuorum)
+	assert.Empty(t, missing.Missing)
+
+	// guardians without heartbeats are reported as stale.
+	missing = computeMissingSignatures(time.Now(), vaa.ChainIDEthereum, guardianAddrs, 3, obs[:1], nil)
+	assert.False(t, missing.HasQuorum)
+	assert.Len(t, missing.Missing, 2)
+	assert.True(t, missing.Missing[0].StaleHeartbeat)
+	assert.Nil(t, missing.Missing[0].LastHeartbeat)
+}
+
+func TestSignatures_computeMissingSignaturesConflictingDigests(t *testing.T) {
+	guardianAddrs := []string{"0xAA01", "0xAA02", "0xAA03", "0xAA04"}
+	// every guardian has signed a digest, but no digest has a quorum.
+	obs := []*ObservationDoc{
+		{GuardianAddr: "0xAA01", Hash: []byte{1}},
+		{GuardianAddr: "0xAA02", Hash: []byte{1}},
+		{GuardianAddr: "0xAA02", Hash: []byte{1}},
+		{GuardianAddr: "0xAA03", Hash: []byte{2}},
+		{GuardianAddr: "0xAA04", Hash: []byte{2}},
+	}
+
+	missing := computeMissingSignatures(time.Now(), vaa.ChainIDEthereum, guardianAddrs, 3, obs, nil)
+	assert.False(t, missing.HasQuorum)
+	assert.Equal(t, 2, missing.Signatures)
+	assert.Equal(t, []byte{1}, missing.Hash)
+	assert.Len(t, missing.Missing, 2)
+	if assert.Len(t, missing.Digests, 2) {
+		for _, d := range missing.Digests {
+			assert.Equal(t, 2, d.Signatures)
+			assert.False(t, d.HasQuorum)
+		}
+		assert.Equal(t, []byte{2}, missing.Digests[1].Hash)
+		assert.Equal(t, "0xAA01", missing.Digests[1].Missing[0].GuardianAddress)
+	}
+
+	// without observations, every guardian is missing.
+	missing = computeMissingSignatures(time.Now(), vaa.ChainIDEthereum, guardianAddrs, 3, nil, nil)
+	assert.Empty(t, missing.Digests)
+	assert.Equal(t, 0, missing.Signatures)
+	assert.Len(t, missing.Missing, 4)
+}
@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Token is a token supported by Portal Token Bridge, identified by its origin chain and address,
+// with its addresses on each chain.
+type Token struct {
+	TokenChain   sdk.ChainID    `json:"tokenChain"`
+	TokenAddress string         `json:"tokenAddress"`
+	Symbol       domain.Symbol  `json:"symbol"`
+	CoingeckoID  string         `json:"coingeckoId"`
+	Addresses    []TokenAddress `json:"addresses"`
+}
+
+// TokenAddress is the address of a token on a chain.
+type TokenAddress struct {
+	ChainID  sdk.ChainID `json:"chainId"`
+	Address  string      `json:"address"`
+	Decimals int64       `json:"decimals"`
+}
+
+// TokenStatsQuery defines the parameters of the statistics of a token.
+type TokenStatsQuery struct {
+	TokenChain   sdk.ChainID
+	TokenAddress string
+	TimeSpan     string
+	SampleRate   string
+}
+
+// TokenStats contains the statistics of the transfers of a token.
+type TokenStats struct {
+	TokenChain   sdk.ChainID   `json:"tokenChain"`
+	TokenAddress string        `json:"tokenAddress"`
+	Symbol       domain.Symbol `json:"symbol"`
+	CoingeckoID  string        `json:"coingeckoId"`
+	// Transfers is the number of transfers in the time span.
+	Transfers uint64 `json:"transfers"`
+	// Volume is the volume in USD of the transfers in the time span.
+	Volume               decimal.Decimal      `json:"volume"`
+	VolumeSeries         []TokenVolumeResult  `json:"volumeSeries"`
+	TopSourceChains      []TokenChainActivity `json:"topSourceChains"`
+	TopDestinationChains []TokenChainActivity `json:"topDestinationChains"`
+	Price                *TokenPrice          `json:"price,omitempty"`
+}
+
+// TokenVolumeResult is the number of transfers and volume of a token in a sample of the time span.
+type TokenVolumeResult struct {
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	Transfers uint64          `json:"transfers"`
+	Volume    decimal.Decimal `json:"volume"`
+}
+
+// TokenChainActivity is the number of transfers and volume of a token from or to a chain.
+type TokenChainActivity struct {
+	ChainID   sdk.ChainID     `json:"chainId"`
+	Transfers uint64          `json:"transfers"`
+	Volume    decimal.Decimal `json:"volume"`
+}
+
+// TokenPrice is the latest price of a token in the notional cache.
+type TokenPrice struct {
+	NotionalUsd decimal.Decimal `json:"notionalUsd"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
@@ -0,0 +1,197 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/mitchellh/mapstructure"
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// maxTopChains is the max number of source and destination chains in the statistics of a token.
+const maxTopChains = 7
+
+// queryTemplateTokenVolume is the query used to get the number of transfers and the volume of a token by sample.
+const queryTemplateTokenVolume = `
+data = from(bucket: "%s")
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == "vaa_volume" and r._field == "volume")
+  |> filter(fn: (r) => r.token_chain == "%d" and r.token_address == "%s")
+  |> group()
+
+data
+  |> aggregateWindow(every: %s, fn: sum, timeSrc: "_start", createEmpty: true)
+  |> yield(name: "volume")
+
+data
+  |> aggregateWindow(every: %s, fn: count, timeSrc: "_start", createEmpty: true)
+  |> yield(name: "transfers")
+`
+
+// queryTemplateTokenTopChains is the query used to get the chains with the highest volume of a token,
+// grouped by the source or destination chain.
+const queryTemplateTokenTopChains = `
+from(bucket: "%s")
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == "vaa_volume" and r._field == "volume")
+  |> filter(fn: (r) => r.token_chain == "%d" and r.token_address == "%s")
+  |> group(columns: ["%s"])
+  |> reduce(
+      identity: {transfers: 0, volume: uint(v: 0)},
+      fn: (r, accumulator) => ({transfers: accumulator.transfers + 1, volume: accumulator.volume + r._value}))
+  |> group()
+  |> sort(columns: ["volume"], desc: true)
+  |> limit(n: %d)
+`
+
+// Repository definition.
+type Repository struct {
+	queryAPI                api.QueryAPI
+	bucketInfiniteRetention string
+	logger                  *zap.Logger
+}
+
+// NewRepository create a new Repository.
+func NewRepository(client influxdb2.Client, org string, bucketInfiniteRetention string, logger *zap.Logger) *Repository {
+	return &Repository{
+		queryAPI:                client.QueryAPI(org),
+		bucketInfiniteRetention: bucketInfiniteRetention,
+		logger:                  logger.With(zap.String("module", "TokensRepository")),
+	}
+}
+
+// GetTokenVolume get the number of transfers and the volume of a token for each sample of the time span.
+func (r *Repository) GetTokenVolume(ctx context.Context, q *TokenStatsQuery) ([]TokenVolumeResult, error) {
+
+	query := buildTokenVolumeQuery(r.bucketInfiniteRetention, time.Now(), q)
+	result, err := r.queryAPI.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the volume and the number of transfers of each sample are returned in different tables.
+	sampleRate := sampleRateDuration(q.SampleRate)
+	samples := make(map[time.Time]*TokenVolumeResult)
+	for result.Next() {
+		from := result.Record().Time()
+		sample, ok := samples[from]
+		if !ok {
+			sample = &TokenVolumeResult{From: from, To: from.Add(sampleRate), Volume: decimal.Zero}
+			samples[from] = sample
+		}
+		switch result.Record().Result() {
+		case "volume":
+			sample.Volume = toDecimal(transactions.ToUint64(result.Record().Value()))
+		case "transfers":
+			sample.Transfers = transactions.ToUint64(result.Record().Value())
+		}
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	response := make([]TokenVolumeResult, 0, len(samples))
+	for _, sample := range samples {
+		response = append(response, *sample)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].From.After(response[j].From)
+	})
+	return response, nil
+}
+
+// GetTokenTopChains get the source or destination chains with the highest volume of a token.
+func (r *Repository) GetTokenTopChains(ctx context.Context, q *TokenStatsQuery, bySource bool) ([]TokenChainActivity, error) {
+
+	query := buildTokenTopChainsQuery(r.bucketInfiniteRetention, time.Now(), q, bySource)
+	result, err := r.queryAPI.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	type Row struct {
+		EmitterChain     string `mapstructure:"emitter_chain"`
+		DestinationChain string `mapstructure:"destination_chain"`
+		Transfers        int64  `mapstructure:"transfers"`
+		Volume           uint64 `mapstructure:"volume"`
+	}
+	response := make([]TokenChainActivity, 0, maxTopChains)
+	for result.Next() {
+		var row Row
+		if err := mapstructure.Decode(result.Record().Values(), &row); err != nil {
+			return nil, err
+		}
+
+		chain := row.DestinationChain
+		if bySource {
+			chain = row.EmitterChain
+		}
+		chainID, err := strconv.ParseUint(chain, 10, 16)
+		if err != nil {
+			r.logger.Warn("invalid chain in token volume", zap.String("chain", chain), zap.Error(err))
+			continue
+		}
+
+		// do not include invalid chain IDs in the response
+		if !domain.ChainIdIsValid(sdk.ChainID(chainID)) {
+			continue
+		}
+
+		response = append(response, TokenChainActivity{
+			ChainID:   sdk.ChainID(chainID),
+			Transfers: uint64(row.Transfers),
+			Volume:    toDecimal(row.Volume),
+		})
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+	return response, nil
+}
+
+func buildTokenVolumeQuery(bucket string, now time.Time, q *TokenStatsQuery) string {
+	start := startOfTimeSpan(now, q.TimeSpan).Format(time.RFC3339Nano)
+	return fmt.Sprintf(queryTemplateTokenVolume, bucket, start, q.TokenChain, q.TokenAddress, q.SampleRate, q.SampleRate)
+}
+
+func buildTokenTopChainsQuery(bucket string, now time.Time, q *TokenStatsQuery, bySource bool) string {
+	start := startOfTimeSpan(now, q.TimeSpan).Format(time.RFC3339Nano)
+	column := "destination_chain"
+	if bySource {
+		column = "emitter_chain"
+	}
+	return fmt.Sprintf(queryTemplateTokenTopChains, bucket, start, q.TokenChain, q.TokenAddress, column, maxTopChains)
+}
+
+// startOfTimeSpan returns the start of the time span, aligned to its sample rate.
+func startOfTimeSpan(now time.Time, timeSpan string) time.Time {
+	switch timeSpan {
+	case "1w":
+		return now.Truncate(24 * time.Hour).Add(-24 * time.Hour * 6)
+	case "1mo":
+		return now.Truncate(24 * time.Hour).Add(-24 * time.Hour * 29)
+	default:
+		return now.Truncate(time.Hour).Add(-time.Hour * 23)
+	}
+}
+
+func sampleRateDuration(sampleRate string) time.Duration {
+	if sampleRate == "1d" {
+		return 24 * time.Hour
+	}
+	return time.Hour
+}
+
+// toDecimal converts an integer amount with 8 decimals of precision to a decimal.
+func toDecimal(amount uint64) decimal.Decimal {
+	return decimal.RequireFromString(transactions.ConvertToDecimal(amount))
+}
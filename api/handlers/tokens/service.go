@@ -0,0 +1,168 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/cacheable"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"go.uber.org/zap"
+)
+
+const tokenStatsKey = "wormscan:token-stats"
+
+// Service definition.
+type Service struct {
+	repo          *Repository
+	notionalCache notional.NotionalLocalCacheReadable
+	cache         *cacheable.Cache
+	expiration    time.Duration
+	tokens        []Token
+	logger        *zap.Logger
+}
+
+// NewService create a new Service.
+func NewService(
+	repo *Repository,
+	notionalCache notional.NotionalLocalCacheReadable,
+	cache *cacheable.Cache,
+	expiration time.Duration,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:          repo,
+		notionalCache: notionalCache,
+		cache:         cache,
+		expiration:    expiration,
+		tokens:        groupTokens(domain.GetAllTokens()),
+		logger:        logger.With(zap.String("module", "TokensService")),
+	}
+}
+
+// ListTokens get all the tokens supported by Portal Token Bridge.
+func (s *Service) ListTokens(ctx context.Context) []Token {
+	return s.tokens
+}
+
+// GetTokenStats get the number of transfers, the volume and the latest price of a token.
+func (s *Service) GetTokenStats(ctx context.Context, q *TokenStatsQuery) (*TokenStats, error) {
+
+	tokenMetadata, ok := domain.GetTokenByAddress(q.TokenChain, q.TokenAddress)
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+
+	key := fmt.Sprintf("%s:%d:%s:%s:%s", tokenStatsKey, q.TokenChain, q.TokenAddress, q.TimeSpan, q.SampleRate)
+	stats, err := cacheable.GetOrLoad(ctx, s.logger, s.cache, s.expiration, key,
+		func() (*TokenStats, error) {
+			return s.loadTokenStats(ctx, q)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Symbol = tokenMetadata.Symbol
+	stats.CoingeckoID = tokenMetadata.CoingeckoID
+
+	// the price is read from the local notional cache, so it is always the latest one.
+	if tokenMetadata.Symbol != "" {
+		priceData, err := s.notionalCache.Get(tokenMetadata.Symbol)
+		if err == nil && !priceData.UpdatedAt.IsZero() {
+			stats.Price = &TokenPrice{NotionalUsd: priceData.NotionalUsd, UpdatedAt: priceData.UpdatedAt}
+		}
+	}
+
+	return stats, nil
+}
+
+// loadTokenStats executes the queries of the statistics of a token concurrently.
+func (s *Service) loadTokenStats(ctx context.Context, q *TokenStatsQuery) (*TokenStats, error) {
+
+	var wg sync.WaitGroup
+	var volumeSeries []TokenVolumeResult
+	var topSourceChains, topDestinationChains []TokenChainActivity
+	var volumeErr, sourceErr, destinationErr error
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		volumeSeries, volumeErr = s.repo.GetTokenVolume(ctx, q)
+	}()
+	go func() {
+		defer wg.Done()
+		topSourceChains, sourceErr = s.repo.GetTokenTopChains(ctx, q, true)
+	}()
+	go func() {
+		defer wg.Done()
+		topDestinationChains, destinationErr = s.repo.GetTokenTopChains(ctx, q, false)
+	}()
+	wg.Wait()
+
+	for _, err := range []error{volumeErr, sourceErr, destinationErr} {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats := TokenStats{
+		TokenChain:           q.TokenChain,
+		TokenAddress:         q.TokenAddress,
+		VolumeSeries:         volumeSeries,
+		TopSourceChains:      topSourceChains,
+		TopDestinationChains: topDestinationChains,
+	}
+	for _, sample := range volumeSeries {
+		stats.Transfers += sample.Transfers
+		stats.Volume = stats.Volume.Add(sample.Volume)
+	}
+	return &stats, nil
+}
+
+// groupTokens groups the entries of the token list by the origin chain and address of the token,
+// which identify the asset, since different assets can share a coingecko ID (e.g. the USDC of each chain).
+func groupTokens(metadata []domain.TokenMetadata) []Token {
+
+	tokensByOrigin := make(map[string]*Token)
+	var tokens []*Token
+	for _, m := range metadata {
+		address := TokenAddress{ChainID: m.TokenChain, Address: m.TokenAddress, Decimals: m.Decimals}
+
+		key := fmt.Sprintf("%d/%s", m.TokenChain, m.TokenAddress)
+		if t, ok := tokensByOrigin[key]; ok {
+			if t.Symbol == "" {
+				t.Symbol = m.Symbol
+			}
+			if t.CoingeckoID == "" {
+				t.CoingeckoID = m.CoingeckoID
+			}
+			continue
+		}
+
+		t := &Token{
+			TokenChain:   m.TokenChain,
+			TokenAddress: m.TokenAddress,
+			Symbol:       m.Symbol,
+			CoingeckoID:  m.CoingeckoID,
+			Addresses:    []TokenAddress{address},
+		}
+		tokensByOrigin[key] = t
+		tokens = append(tokens, t)
+	}
+
+	result := make([]Token, 0, len(tokens))
+	for _, t := range tokens {
+		result = append(result, *t)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].CoingeckoID != result[j].CoingeckoID {
+			return result[i].CoingeckoID < result[j].CoingeckoID
+		}
+		return result[i].TokenChain < result[j].TokenChain
+	})
+	return result
+}
@@ -0,0 +1,57 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestService_groupTokens(t *testing.T) {
+	metadata := []domain.TokenMetadata{
+		{TokenChain: sdk.ChainIDSolana, TokenAddress: "c6fa", Symbol: "USDC", CoingeckoID: "usd-coin", Decimals: 6},
+		{TokenChain: sdk.ChainIDEthereum, TokenAddress: "a0b8", Symbol: "USDC", CoingeckoID: "usd-coin", Decimals: 6},
+		{TokenChain: sdk.ChainIDSolana, TokenAddress: "e24b", Symbol: "", CoingeckoID: "genopets", Decimals: 9},
+		{TokenChain: sdk.ChainIDSolana, TokenAddress: "e24b", Symbol: "GENE", CoingeckoID: "genopets", Decimals: 9},
+		{TokenChain: sdk.ChainIDBSC, TokenAddress: "0001", Symbol: "A"},
+		{TokenChain: sdk.ChainIDBSC, TokenAddress: "0002", Symbol: "B"},
+	}
+
+	tokens := groupTokens(metadata)
+	assert.Len(t, tokens, 5)
+
+	// tokens without a coingecko ID are listed by their origin.
+	assert.Equal(t, domain.Symbol("A"), tokens[0].Symbol)
+	assert.Equal(t, domain.Symbol("B"), tokens[1].Symbol)
+
+	// the entries of the same origin are grouped.
+	genopets := tokens[2]
+	assert.Equal(t, "genopets", genopets.CoingeckoID)
+	assert.Equal(t, domain.Symbol("GENE"), genopets.Symbol)
+	assert.Len(t, genopets.Addresses, 1)
+
+	// the tokens of different origins are not grouped, even if they share a coingecko ID.
+	assert.Equal(t, sdk.ChainIDSolana, tokens[3].TokenChain)
+	assert.Equal(t, "c6fa", tokens[3].TokenAddress)
+	assert.Equal(t, sdk.ChainIDEthereum, tokens[4].TokenChain)
+	assert.Equal(t, []TokenAddress{{ChainID: sdk.ChainIDEthereum, Address: "a0b8", Decimals: 6}}, tokens[4].Addresses)
+}
+
+func TestRepository_startOfTimeSpan(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 25, 48, 0, time.UTC)
+	assert.Equal(t, time.Date(2023, 5, 3, 13, 0, 0, 0, time.UTC), startOfTimeSpan(now, "1d"))
+	assert.Equal(t, time.Date(2023, 4, 28, 0, 0, 0, 0, time.UTC), startOfTimeSpan(now, "1w"))
+	assert.Equal(t, time.Date(2023, 4, 5, 0, 0, 0, 0, time.UTC), startOfTimeSpan(now, "1mo"))
+}
+
+func TestRepository_buildTokenTopChainsQuery(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 25, 48, 0, time.UTC)
+	q := &TokenStatsQuery{TokenChain: sdk.ChainIDEthereum, TokenAddress: "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", TimeSpan: "1w", SampleRate: "1d"}
+
+	query := buildTokenTopChainsQuery("wormscan", now, q, true)
+	assert.Contains(t, query, `range(start: 2023-04-28T00:00:00Z)`)
+	assert.Contains(t, query, `r.token_chain == "2" and r.token_address == "000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"`)
+	assert.Contains(t, query, `group(columns: ["emitter_chain"])`)
+}
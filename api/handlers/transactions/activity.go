@@ -0,0 +1,288 @@
+package transactions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActivityGranularity is the size of the samples of an activity time series.
+type ActivityGranularity string
+
+const (
+	ActivityGranularity1h  ActivityGranularity = "1h"
+	ActivityGranularity1d  ActivityGranularity = "1d"
+	ActivityGranularity1mo ActivityGranularity = "1mo"
+)
+
+// ParseActivityGranularity parses a string and returns an `ActivityGranularity`.
+func ParseActivityGranularity(s string) (ActivityGranularity, error) {
+	switch ActivityGranularity(s) {
+	case ActivityGranularity1h, ActivityGranularity1d, ActivityGranularity1mo:
+		return ActivityGranularity(s), nil
+	}
+	return "", fmt.Errorf("invalid granularity: %s", s)
+}
+
+const (
+	// maxActivityRange is the longest time range supported by the activity queries.
+	maxActivityRange = 366 * 24 * time.Hour
+	// maxActivityCost is the max cost of an activity query.
+	//
+	// Each hour of raw `vaa_volume` points and each day of the daily rollup cost one unit,
+	// so an hourly time series, which can only be computed from raw points, is limited to 31 days.
+	maxActivityCost = 31 * 24
+)
+
+// Errors returned when creating an ActivityRange.
+var (
+	ErrInvalidActivityRange  = errors.New("from must be before to")
+	ErrActivityRangeTooLarge = fmt.Errorf("the time range can not be longer than %d days", int(maxActivityRange.Hours()/24))
+	ErrActivityCostExceeded  = errors.New("the time range is too large for the granularity")
+)
+
+const (
+	// rawVolumeMeasurement is the measurement written by the analytics service for each token bridge transfer.
+	rawVolumeMeasurement = "vaa_volume"
+	// dailyVolumeMeasurement is the daily rollup of `vaa_volume`, written by the task `analytics/scripts/vaa_volume_1d.flux`.
+	// It contains the fields `count` and `volume` grouped by all the tags of `vaa_volume`.
+	dailyVolumeMeasurement = "vaa_volume_1d"
+)
+
+// activityMetric is the value aggregated by an activity query.
+type activityMetric string
+
+const (
+	activityMetricCount  activityMetric = "count"
+	activityMetricVolume activityMetric = "volume"
+)
+
+// timeInterval is the half-open interval [start, stop).
+type timeInterval struct {
+	start time.Time
+	stop  time.Time
+}
+
+// ActivityRange is an arbitrary time range of an activity query.
+//
+// The range is answered by combining the daily rollup, for the whole days contained in the range,
+// with the raw `vaa_volume` points at the edges.
+type ActivityRange struct {
+	From        time.Time
+	To          time.Time
+	Granularity ActivityGranularity
+	// rollup is the interval answered from the daily rollup, if any.
+	rollup *timeInterval
+	// raw contains the intervals answered from the raw points.
+	raw []timeInterval
+	// Cost is the estimated cost of the query.
+	Cost int
+}
+
+// NewActivityRange normalizes and validates a time range.
+//
+// The range is expanded to whole hours, so that the results can be cached by the normalized range,
+// and the end of the range is capped at the current time.
+func NewActivityRange(from, to time.Time, granularity ActivityGranularity, now time.Time) (*ActivityRange, error) {
+
+	now = ceilHour(now.UTC())
+	from = from.UTC().Truncate(time.Hour)
+	to = ceilHour(to.UTC())
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidActivityRange
+	}
+	if to.Sub(from) > maxActivityRange {
+		return nil, ErrActivityRangeTooLarge
+	}
+
+	r := ActivityRange{From: from, To: to, Granularity: granularity}
+
+	// hourly samples can only be computed from the raw points.
+	firstDay := ceilDay(from)
+	lastDay := to.Truncate(24 * time.Hour)
+	if granularity == ActivityGranularity1h || !firstDay.Before(lastDay) {
+		r.raw = []timeInterval{{start: from, stop: to}}
+	} else {
+		r.rollup = &timeInterval{start: firstDay, stop: lastDay}
+		if from.Before(firstDay) {
+			r.raw = append(r.raw, timeInterval{start: from, stop: firstDay})
+		}
+		if lastDay.Before(to) {
+			r.raw = append(r.raw, timeInterval{start: lastDay, stop: to})
+		}
+	}
+
+	// compute the cost of the query.
+	if r.rollup != nil {
+		r.Cost += int(r.rollup.stop.Sub(r.rollup.start).Hours() / 24)
+	}
+	for _, i := range r.raw {
+		r.Cost += int(i.stop.Sub(i.start).Hours())
+	}
+	if r.Cost > maxActivityCost {
+		return nil, ErrActivityCostExceeded
+	}
+
+	return &r, nil
+}
+
+// Key returns a string that identifies the normalized range, to be used as a cache key.
+func (r *ActivityRange) Key() string {
+	return fmt.Sprintf("%s:%s:%s", r.From.Format(time.RFC3339), r.To.Format(time.RFC3339), r.Granularity)
+}
+
+// buildDataQuery returns the flux statements that define the table `name` with the points of
+// the metric in the range, combining the daily rollup and the raw points.
+//
+// The parameter `filter` is an optional flux filter applied to both sources.
+func (r *ActivityRange) buildDataQuery(name, bucket string, metric activityMetric, filter string) string {
+
+	const format = time.RFC3339Nano
+	var sb strings.Builder
+	var tables []string
+
+	if r.rollup != nil {
+		table := name + "Rollup"
+		tables = append(tables, table)
+		fmt.Fprintf(&sb, "%s = from(bucket: \"%s\")\n", table, bucket)
+		fmt.Fprintf(&sb, "  |> range(start: %s, stop: %s)\n", r.rollup.start.Format(format), r.rollup.stop.Format(format))
+		fmt.Fprintf(&sb, "  |> filter(fn: (r) => r._measurement == \"%s\" and r._field == \"%s\")\n", dailyVolumeMeasurement, metric)
+		if filter != "" {
+			fmt.Fprintf(&sb, "  |> %s\n", filter)
+		}
+	}
+
+	for i, interval := range r.raw {
+		table := fmt.Sprintf("%sRaw%d", name, i)
+		tables = append(tables, table)
+		fmt.Fprintf(&sb, "%s = from(bucket: \"%s\")\n", table, bucket)
+		fmt.Fprintf(&sb, "  |> range(start: %s, stop: %s)\n", interval.start.Format(format), interval.stop.Format(format))
+		fmt.Fprintf(&sb, "  |> filter(fn: (r) => r._measurement == \"%s\" and r._field == \"volume\")\n", rawVolumeMeasurement)
+		if filter != "" {
+			fmt.Fprintf(&sb, "  |> %s\n", filter)
+		}
+		// each raw point is a transfer, the rollup contains the count of transfers.
+		if metric == activityMetricCount {
+			sb.WriteString("  |> map(fn: (r) => ({r with _value: 1}))\n")
+		}
+	}
+
+	// the range is applied again to align the bounds of the tables.
+	source := tables[0]
+	if len(tables) > 1 {
+		source = fmt.Sprintf("union(tables: [%s])", strings.Join(tables, ", "))
+	}
+	fmt.Fprintf(&sb, "%s = %s\n", name, source)
+	fmt.Fprintf(&sb, "  |> range(start: %s, stop: %s)\n", r.From.Format(format), r.To.Format(format))
+
+	return sb.String()
+}
+
+// sampleEnd returns the end of the sample of the time series that starts at `start`.
+func (r *ActivityRange) sampleEnd(start time.Time) time.Time {
+	var end time.Time
+	switch r.Granularity {
+	case ActivityGranularity1mo:
+		end = start.AddDate(0, 1, 0)
+	case ActivityGranularity1d:
+		end = start.Add(24 * time.Hour)
+	default:
+		end = start.Add(time.Hour)
+	}
+	if end.After(r.To) {
+		return r.To
+	}
+	return end
+}
+
+func buildChainActivityByRangeQuery(bucket string, q *ChainActivityQuery, r *ActivityRange) string {
+	metric := activityMetricCount
+	if q.IsNotional {
+		metric = activityMetricVolume
+	}
+	var filter string
+	if q.HasAppIDS() {
+		filter = fmt.Sprintf(`filter(fn: (r) => contains(value: r.app_id, set: ["%s"]))`, strings.Join(q.GetAppIDs(), `","`))
+	}
+	return r.buildDataQuery("data", bucket, metric, filter) + `
+data
+  |> group(columns: ["emitter_chain", "destination_chain"])
+  |> sum()
+`
+}
+
+func buildTopAssetsByRangeQuery(bucket string, r *ActivityRange) string {
+	return r.buildDataQuery("data", bucket, activityMetricVolume, "") + `
+data
+  |> group(columns: ["emitter_chain", "token_address", "token_chain"])
+  |> sum()
+  |> group()
+  |> top(columns: ["_value"], n: 7)
+`
+}
+
+func buildTopChainPairsByRangeQuery(bucket string, r *ActivityRange) string {
+	return r.buildDataQuery("data", bucket, activityMetricCount, "") + `
+data
+  |> group(columns: ["emitter_chain", "destination_chain"])
+  |> sum()
+  |> group()
+  |> top(columns: ["_value"], n: 100)
+`
+}
+
+func buildActivityQuery(bucket string, r *ActivityRange) string {
+	const template = `
+%s
+  |> group()
+  |> aggregateWindow(every: %s, fn: sum, timeSrc: "_start", createEmpty: true)
+  |> yield(name: "%s")
+`
+	return r.buildDataQuery("transfers", bucket, activityMetricCount, "") +
+		r.buildDataQuery("volume", bucket, activityMetricVolume, "") +
+		fmt.Sprintf(template, "transfers", r.Granularity, activityMetricCount) +
+		fmt.Sprintf(template, "volume", r.Granularity, activityMetricVolume)
+}
+
+// ToUint64 converts a value of an influx record to uint64.
+// Empty samples have a nil value.
+func ToUint64(value interface{}) uint64 {
+	switch v := value.(type) {
+	case uint64:
+		return v
+	case int64:
+		if v < 0 {
+			return 0
+		}
+		return uint64(v)
+	default:
+		return 0
+	}
+}
+
+func ceilHour(t time.Time) time.Time {
+	if truncated := t.Truncate(time.Hour); !truncated.Equal(t) {
+		return truncated.Add(time.Hour)
+	}
+	return t
+}
+
+func ceilDay(t time.Time) time.Time {
+	if truncated := t.Truncate(24 * time.Hour); !truncated.Equal(t) {
+		return truncated.Add(24 * time.Hour)
+	}
+	return t
+}
+
+// ActivityResult is the number of transfers and the volume in a sample of an activity time series.
+type ActivityResult struct {
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Transfers uint64    `json:"transfers"`
+	// Volume in USD.
+	Volume string `json:"volume"`
+}
@@ -0,0 +1,61 @@
+package transactions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivity_NewActivityRange(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 25, 48, 0, time.UTC)
+
+	// month to date, the range is normalized to whole hours and capped at the current time.
+	r, err := NewActivityRange(time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), now.Add(time.Hour*24), ActivityGranularity1d, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 5, 4, 13, 0, 0, 0, time.UTC), r.To)
+	assert.Equal(t, &timeInterval{start: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), stop: time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC)}, r.rollup)
+	assert.Equal(t, []timeInterval{{start: time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC), stop: r.To}}, r.raw)
+	assert.Equal(t, 3+13, r.Cost)
+
+	// a range that starts and ends in the middle of a day.
+	r, err = NewActivityRange(time.Date(2023, 1, 1, 18, 30, 0, 0, time.UTC), time.Date(2023, 3, 31, 6, 0, 0, 0, time.UTC), ActivityGranularity1mo, now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 1, 18, 0, 0, 0, time.UTC), r.From)
+	assert.Len(t, r.raw, 2)
+	assert.Equal(t, 6+88+6, r.Cost)
+	assert.Equal(t, "2023-01-01T18:00:00Z:2023-03-31T06:00:00Z:1mo", r.Key())
+
+	// hourly samples are computed from the raw points.
+	r, err = NewActivityRange(now.Add(-48*time.Hour), now, ActivityGranularity1h, now)
+	assert.NoError(t, err)
+	assert.Nil(t, r.rollup)
+	assert.Equal(t, 49, r.Cost)
+}
+
+func TestActivity_NewActivityRangeErrors(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 25, 48, 0, time.UTC)
+
+	_, err := NewActivityRange(now, now.Add(-time.Hour*2), ActivityGranularity1d, now)
+	assert.Equal(t, ErrInvalidActivityRange, err)
+
+	_, err = NewActivityRange(now.AddDate(-2, 0, 0), now, ActivityGranularity1d, now)
+	assert.Equal(t, ErrActivityRangeTooLarge, err)
+
+	_, err = NewActivityRange(now.AddDate(0, -3, 0), now, ActivityGranularity1h, now)
+	assert.Equal(t, ErrActivityCostExceeded, err)
+}
+
+func TestActivity_buildTopChainPairsByRangeQuery(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 25, 48, 0, time.UTC)
+	r, err := NewActivityRange(time.Date(2023, 5, 1, 6, 0, 0, 0, time.UTC), now, ActivityGranularity1d, now)
+	assert.NoError(t, err)
+
+	query := buildTopChainPairsByRangeQuery("wormscan", r)
+	assert.Contains(t, query, `r._measurement == "vaa_volume_1d" and r._field == "count"`)
+	assert.Equal(t, 2, strings.Count(query, `map(fn: (r) => ({r with _value: 1}))`))
+	assert.Contains(t, query, "data = union(tables: [dataRollup, dataRaw0, dataRaw1])")
+	assert.Contains(t, query, "range(start: 2023-05-01T06:00:00Z, stop: 2023-05-04T13:00:00Z)")
+	assert.Contains(t, query, `top(columns: ["_value"], n: 100)`)
+}
@@ -3,6 +3,7 @@ package transactions
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +14,7 @@ import (
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/filter"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/tvl"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
@@ -128,6 +130,7 @@ type repositoryCollections struct {
 	vaas               *mongo.Collection
 	parsedVaa          *mongo.Collection
 	globalTransactions *mongo.Collection
+	observations       *mongo.Collection
 }
 
 type Repository struct {
@@ -164,6 +167,7 @@ func NewRepository(
 			vaas:               db.Collection("vaas"),
 			parsedVaa:          db.Collection("parsedVaa"),
 			globalTransactions: db.Collection("globalTransactions"),
+			observations:       db.Collection("observations"),
 		},
 		supportedChainIDs: domain.GetSupportedChainIDs(),
 		logger:            logger,
@@ -172,10 +176,21 @@ func NewRepository(
 	return &r
 }
 
+// GetTopAssets get the assets with the highest volume in a time span, from the summarized metric.
 func (r *Repository) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]AssetDTO, error) {
+	query := fmt.Sprintf(queryTemplateTopAssets, r.bucket30DaysRetention, *timeSpan, r.bucketInfiniteRetention)
+	return r.findTopAssets(ctx, query)
+}
+
+// GetTopAssetsByRange get the assets with the highest volume in an arbitrary time range.
+func (r *Repository) GetTopAssetsByRange(ctx context.Context, activityRange *ActivityRange) ([]AssetDTO, error) {
+	query := buildTopAssetsByRangeQuery(r.bucketInfiniteRetention, activityRange)
+	return r.findTopAssets(ctx, query)
+}
+
+func (r *Repository) findTopAssets(ctx context.Context, query string) ([]AssetDTO, error) {
 
 	// Submit the query to InfluxDB
-	query := fmt.Sprintf(queryTemplateTopAssets, r.bucket30DaysRetention, *timeSpan, r.bucketInfiniteRetention)
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -221,7 +236,7 @@ func (r *Repository) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTi
 			EmitterChain: sdk.ChainID(emitterChain),
 			TokenChain:   sdk.ChainID(tokenChain),
 			TokenAddress: rows[i].TokenAddress,
-			Volume:       convertToDecimal(rows[i].Volume),
+			Volume:       ConvertToDecimal(rows[i].Volume),
 		}
 		assets = append(assets, asset)
 	}
@@ -229,6 +244,7 @@ func (r *Repository) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTi
 	return assets, nil
 }
 
+// GetTopChainPairs get the chain pairs with the highest number of transfers in a time span, from the summarized metric.
 func (r *Repository) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]ChainPairDTO, error) {
 
 	if timeSpan == nil {
@@ -245,8 +261,19 @@ func (r *Repository) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisti
 		measurement = "chain_activity_30_days_3h"
 	}
 
-	// Submit the query to InfluxDB
 	query := fmt.Sprintf(queryTemplateTopChainPairs, r.bucket24HoursRetention, *timeSpan, measurement)
+	return r.findTopChainPairs(ctx, query)
+}
+
+// GetTopChainPairsByRange get the chain pairs with the highest number of transfers in an arbitrary time range.
+func (r *Repository) GetTopChainPairsByRange(ctx context.Context, activityRange *ActivityRange) ([]ChainPairDTO, error) {
+	query := buildTopChainPairsByRangeQuery(r.bucketInfiniteRetention, activityRange)
+	return r.findTopChainPairs(ctx, query)
+}
+
+func (r *Repository) findTopChainPairs(ctx context.Context, query string) ([]ChainPairDTO, error) {
+
+	// Submit the query to InfluxDB
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -309,8 +336,8 @@ func (r *Repository) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisti
 	return pairs, nil
 }
 
-// convertToDecimal converts an integer amount to a decimal string, with 8 decimals of precision.
-func convertToDecimal(amount uint64) string {
+// ConvertToDecimal converts an integer amount to a decimal string, with 8 decimals of precision.
+func ConvertToDecimal(amount uint64) string {
 
 	// If the amount is less than 1, just use a format mask.
 	if amount < 1_0000_0000 {
@@ -325,8 +352,19 @@ func convertToDecimal(amount uint64) string {
 	return result
 }
 
+// FindChainActivity get the activity between chain pairs in a time span, from its summarized metric.
 func (r *Repository) FindChainActivity(ctx context.Context, q *ChainActivityQuery) ([]ChainActivityResult, error) {
 	query := r.buildChainActivityQuery(q)
+	return r.findChainActivity(ctx, query)
+}
+
+// FindChainActivityByRange get the activity between chain pairs in an arbitrary time range.
+func (r *Repository) FindChainActivityByRange(ctx context.Context, q *ChainActivityQuery, activityRange *ActivityRange) ([]ChainActivityResult, error) {
+	query := buildChainActivityByRangeQuery(r.bucketInfiniteRetention, q, activityRange)
+	return r.findChainActivity(ctx, query)
+}
+
+func (r *Repository) findChainActivity(ctx context.Context, query string) ([]ChainActivityResult, error) {
 	result, err := r.queryAPI.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -532,7 +570,7 @@ func (r *Repository) getTotalTxVolume(ctx context.Context) (string, error) {
 	if err := mapstructure.Decode(result.Record().Values(), &row); err != nil {
 		return "", fmt.Errorf("failed to decode tx volume by portal bridge query response: %w", err)
 	}
-	return convertToDecimal(row.Value), nil
+	return ConvertToDecimal(row.Value), nil
 }
 
 func (r *Repository) getMessages24h(ctx context.Context) (string, error) {
@@ -617,10 +655,53 @@ func (r *Repository) getVolume24h(ctx context.Context) (string, error) {
 	}
 
 	// convert the volume to a string and return
-	volume := convertToDecimal(row.Value)
+	volume := ConvertToDecimal(row.Value)
 	return volume, nil
 }
 
+// GetActivity get the number of transfers and the volume for each sample of an arbitrary time range.
+func (r *Repository) GetActivity(ctx context.Context, activityRange *ActivityRange) ([]ActivityResult, error) {
+
+	query := buildActivityQuery(r.bucketInfiniteRetention, activityRange)
+	result, err := r.queryAPI.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// the number of transfers and the volume of each sample are returned in different tables.
+	samples := make(map[time.Time]*ActivityResult)
+	for result.Next() {
+		from := result.Record().Time()
+		sample, ok := samples[from]
+		if !ok {
+			sample = &ActivityResult{From: from, To: activityRange.sampleEnd(from), Volume: ConvertToDecimal(0)}
+			// the first sample could start before the range.
+			if sample.From.Before(activityRange.From) {
+				sample.From = activityRange.From
+			}
+			samples[from] = sample
+		}
+		switch result.Record().Result() {
+		case string(activityMetricCount):
+			sample.Transfers = ToUint64(result.Record().Value())
+		case string(activityMetricVolume):
+			sample.Volume = ConvertToDecimal(ToUint64(result.Record().Value()))
+		}
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	response := make([]ActivityResult, 0, len(samples))
+	for _, sample := range samples {
+		response = append(response, *sample)
+	}
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].From.Before(response[j].From)
+	})
+	return response, nil
+}
+
 // GetTransactionCount get the last transactions.
 func (r *Repository) GetTransactionCount(ctx context.Context, q *TransactionCountQuery) ([]TransactionCountResult, error) {
 	query := buildLastTrxQuery(r.bucket30DaysRetention, time.Now(), q)
@@ -760,6 +841,12 @@ func (r *Repository) findGlobalTransactionByID(ctx context.Context, q *GlobalTra
 type FindTransactionsInput struct {
 	// id specifies the VAA ID of the transaction to be found.
 	id string
+	// ids specifies the VAA IDs of the transactions to be found.
+	ids []string
+	// payloadFilter specifies the time range of the transactions to be found.
+	//
+	// The filters on the parsed payload are resolved to `ids` by `FindTransactionIDsByPayloadFilter`.
+	payloadFilter *filter.PayloadFilter
 	// sort specifies whether the results should be sorted
 	//
 	// If set to true, the results will be sorted by descending timestamp and ID.
@@ -794,6 +881,20 @@ func (r *Repository) FindTransactions(
 			})
 		}
 
+		// Filter by IDs
+		if len(input.ids) > 0 {
+			pipeline = append(pipeline, bson.D{
+				{"$match", bson.D{{"_id", bson.M{"$in": input.ids}}}},
+			})
+		}
+
+		// Filter by timestamp
+		if timestampMatch := input.payloadFilter.TimestampMatch(); timestampMatch != nil {
+			pipeline = append(pipeline, bson.D{
+				{"$match", timestampMatch},
+			})
+		}
+
 		// left outer join on the `transferPrices` collection
 		pipeline = append(pipeline, bson.D{
 			{"$lookup", bson.D{
@@ -884,6 +985,44 @@ func (r *Repository) FindTransactions(
 	return documents, nil
 }
 
+// FindTransactionIDsByPayloadFilter returns the IDs of the page of transactions whose parsed payload
+// matches the filter, sorted by descending timestamp.
+func (r *Repository) FindTransactionIDsByPayloadFilter(
+	ctx context.Context,
+	f *filter.PayloadFilter,
+	p *pagination.Pagination,
+) ([]string, error) {
+
+	// Transactions are always sorted by descending timestamp.
+	page := *p
+	page.SortOrder = "DESC"
+
+	cur, err := r.collections.parsedVaa.Aggregate(ctx, f.ParsedVaaIDsPipeline(&page))
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute aggregation pipeline to get parsedVaa ids",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	err = cur.All(ctx, &docs)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor to parsedVaa ids",
+			zap.Error(err), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+
+	ids := make([]string, 0, len(docs))
+	for i := range docs {
+		ids = append(ids, docs[i].ID)
+	}
+	return ids, nil
+}
+
 // ListTransactionsByAddress returns a sorted list of transactions for a given address.
 //
 // Pagination is implemented using a keyset cursor pattern, based on the (timestamp, ID) pair.
@@ -1016,3 +1155,79 @@ func (r *Repository) ListTransactionsByAddress(
 
 	return documents, nil
 }
+
+// FindMessageStatusRecords returns the documents used to resolve the status of a message.
+func (r *Repository) FindMessageStatusRecords(
+	ctx context.Context,
+	chainID sdk.ChainID,
+	emitter string,
+	seq string,
+) (*MessageStatusRecords, error) {
+
+	id := fmt.Sprintf("%d/%s/%s", chainID, emitter, seq)
+	var records MessageStatusRecords
+
+	// look up the VAA
+	var vaaRecord MessageStatusVaa
+	err := r.collections.vaas.
+		FindOne(ctx, bson.M{"_id": id}).
+		Decode(&vaaRecord)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute FindOne command to get vaa from `vaas` collection",
+			zap.Error(err),
+			zap.String("id", id),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+	if err == nil {
+		records.Vaa = &vaaRecord
+	}
+
+	// summarize the observations
+	pipeline := mongo.Pipeline{
+		{{"$match", bson.D{
+			{"emitterChain", chainID},
+			{"emitterAddr", emitter},
+			{"sequence", seq},
+		}}},
+		{{"$group", bson.D{
+			{"_id", nil},
+			{"signers", bson.D{{"$addToSet", "$guardianAddr"}}},
+			{"firstObservedAt", bson.D{{"$min", "$indexedAt"}}},
+		}}},
+	}
+	cur, err := r.collections.observations.Aggregate(ctx, pipeline)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute aggregation pipeline to summarize observations",
+			zap.Error(err),
+			zap.String("id", id),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+	var observations []MessageStatusObservations
+	err = cur.All(ctx, &observations)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed to decode cursor into []MessageStatusObservations",
+			zap.Error(err),
+			zap.String("id", id),
+			zap.String("requestID", requestID),
+		)
+		return nil, errors.WithStack(err)
+	}
+	if len(observations) > 0 {
+		records.Observations = &observations[0]
+	}
+
+	// look up the global transaction
+	records.GlobalTx, err = r.findGlobalTransactionByID(ctx, &GlobalTransactionQuery{id: id})
+	if err != nil && err != errs.ErrNotFound {
+		return nil, err
+	}
+
+	return &records, nil
+}
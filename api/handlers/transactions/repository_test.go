@@ -2,7 +2,7 @@ package transactions
 
 import "testing"
 
-func Test_convertToDecimal(t *testing.T) {
+func Test_ConvertToDecimal(t *testing.T) {
 
 	tcs := []struct {
 		input  uint64
@@ -29,7 +29,7 @@ func Test_convertToDecimal(t *testing.T) {
 	for i := range tcs {
 		tc := tcs[i]
 
-		result := convertToDecimal(tc.input)
+		result := ConvertToDecimal(tc.input)
 		if result != tc.output {
 			t.Errorf("expected %s, got %s", tc.output, result)
 		}
@@ -7,11 +7,13 @@ import (
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/api/cacheable"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/filter"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/types"
-	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
@@ -19,7 +21,9 @@ import (
 
 type Service struct {
 	repo              *Repository
-	cache             cache.Cache
+	govSrv            *governor.Service
+	guardianSet       guardian.GuardianSet
+	cache             *cacheable.Cache
 	expiration        time.Duration
 	supportedChainIDs map[vaa.ChainID]string
 	logger            *zap.Logger
@@ -31,12 +35,31 @@ const (
 	topAssetsByVolumeKey           = "wormscan:top-assets-by-volume"
 	topChainPairsByNumTransfersKey = "wormscan:top-chain-pairs-by-num-transfers"
 	chainActivityKey               = "wormscan:chain-activity"
+	activityKey                    = "wormscan:activity"
 )
 
+// CacheKeys are the prefixes of the cached keys that change with every new VAA.
+// The invalidated metrics are reloaded by a single caller, the others get the stale value meanwhile.
+var CacheKeys = []string{
+	lastTxsKey,
+	scorecardsKey,
+	topAssetsByVolumeKey,
+	topChainPairsByNumTransfersKey,
+	chainActivityKey,
+	activityKey,
+}
+
 // NewService create a new Service.
-func NewService(repo *Repository, cache cache.Cache, expiration time.Duration, logger *zap.Logger) *Service {
+func NewService(
+	repo *Repository,
+	govSrv *governor.Service,
+	p2pNetwork string,
+	cache *cacheable.Cache,
+	expiration time.Duration,
+	logger *zap.Logger,
+) *Service {
 	supportedChainIDs := domain.GetSupportedChainIDs()
-	return &Service{repo: repo, supportedChainIDs: supportedChainIDs,
+	return &Service{repo: repo, govSrv: govSrv, guardianSet: guardian.GetByEnv(p2pNetwork), supportedChainIDs: supportedChainIDs,
 		cache: cache, expiration: expiration, logger: logger.With(zap.String("module", "TransactionService"))}
 }
 
@@ -56,6 +79,9 @@ func (s *Service) GetScorecards(ctx context.Context) (*Scorecards, error) {
 		})
 }
 
+// GetTopAssets get the assets with the highest volume in a time span.
+//
+// The time spans are answered from their summarized metrics, the daily rollup only answers the arbitrary ranges.
 func (s *Service) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]AssetDTO, error) {
 	key := topAssetsByVolumeKey
 	if timeSpan != nil {
@@ -67,6 +93,7 @@ func (s *Service) GetTopAssets(ctx context.Context, timeSpan *TopStatisticsTimeS
 		})
 }
 
+// GetTopChainPairs get the chain pairs with the highest number of transfers in a time span.
 func (s *Service) GetTopChainPairs(ctx context.Context, timeSpan *TopStatisticsTimeSpan) ([]ChainPairDTO, error) {
 	key := topChainPairsByNumTransfersKey
 	if timeSpan != nil {
@@ -87,6 +114,46 @@ func (s *Service) GetChainActivity(ctx context.Context, q *ChainActivityQuery) (
 		})
 }
 
+// GetChainActivityByRange get chain activity in an arbitrary time range.
+func (s *Service) GetChainActivityByRange(ctx context.Context, q *ChainActivityQuery, activityRange *ActivityRange) ([]ChainActivityResult, error) {
+	key := fmt.Sprintf("%s:%s:%v:%s", chainActivityKey, activityRange.Key(), q.IsNotional, strings.Join(q.GetAppIDs(), ","))
+	return cacheable.GetOrLoad(ctx, s.logger, s.cache, s.expiration, key,
+		func() ([]ChainActivityResult, error) {
+			s.logger.Debug("querying chain activity by range", zap.String("key", key), zap.Int("cost", activityRange.Cost))
+			return s.repo.FindChainActivityByRange(ctx, q, activityRange)
+		})
+}
+
+// GetTopAssetsByRange get the assets with the highest volume in an arbitrary time range.
+func (s *Service) GetTopAssetsByRange(ctx context.Context, activityRange *ActivityRange) ([]AssetDTO, error) {
+	key := fmt.Sprintf("%s:%s", topAssetsByVolumeKey, activityRange.Key())
+	return cacheable.GetOrLoad(ctx, s.logger, s.cache, s.expiration, key,
+		func() ([]AssetDTO, error) {
+			s.logger.Debug("querying top assets by range", zap.String("key", key), zap.Int("cost", activityRange.Cost))
+			return s.repo.GetTopAssetsByRange(ctx, activityRange)
+		})
+}
+
+// GetTopChainPairsByRange get the chain pairs with the highest number of transfers in an arbitrary time range.
+func (s *Service) GetTopChainPairsByRange(ctx context.Context, activityRange *ActivityRange) ([]ChainPairDTO, error) {
+	key := fmt.Sprintf("%s:%s", topChainPairsByNumTransfersKey, activityRange.Key())
+	return cacheable.GetOrLoad(ctx, s.logger, s.cache, s.expiration, key,
+		func() ([]ChainPairDTO, error) {
+			s.logger.Debug("querying top chain pairs by range", zap.String("key", key), zap.Int("cost", activityRange.Cost))
+			return s.repo.GetTopChainPairsByRange(ctx, activityRange)
+		})
+}
+
+// GetActivity get the number of transfers and the volume for each sample of an arbitrary time range.
+func (s *Service) GetActivity(ctx context.Context, activityRange *ActivityRange) ([]ActivityResult, error) {
+	key := fmt.Sprintf("%s:%s", activityKey, activityRange.Key())
+	return cacheable.GetOrLoad(ctx, s.logger, s.cache, s.expiration, key,
+		func() ([]ActivityResult, error) {
+			s.logger.Debug("querying activity by range", zap.String("key", key), zap.Int("cost", activityRange.Cost))
+			return s.repo.GetActivity(ctx, activityRange)
+		})
+}
+
 // FindGlobalTransactionByID find a global transaction by id.
 func (s *Service) FindGlobalTransactionByID(ctx context.Context, chainID vaa.ChainID, emitter *types.Address, seq string) (*GlobalTransactionDoc, error) {
 
@@ -119,12 +186,31 @@ func (s *Service) GetTokenByChainAndAddress(ctx context.Context, chainID vaa.Cha
 func (s *Service) ListTransactions(
 	ctx context.Context,
 	pagination *pagination.Pagination,
+	payloadFilter *filter.PayloadFilter,
 ) ([]TransactionDto, error) {
 
 	input := FindTransactionsInput{
-		sort:       true,
-		pagination: pagination,
+		sort:          true,
+		pagination:    pagination,
+		payloadFilter: payloadFilter,
 	}
+
+	// The filters on the parsed payload are applied on the `parsedVaa` collection,
+	// which returns the IDs of the page of transactions.
+	if payloadFilter.HasPayloadFilters() {
+		ids, err := s.repo.FindTransactionIDsByPayloadFilter(ctx, payloadFilter, pagination)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return []TransactionDto{}, nil
+		}
+		input = FindTransactionsInput{
+			sort: true,
+			ids:  ids,
+		}
+	}
+
 	return s.repo.FindTransactions(ctx, &input)
 }
 
@@ -159,3 +245,64 @@ func (s *Service) GetTransactionByID(
 	// Return matching document
 	return &output[0], nil
 }
+
+// GetTransactionsByIDs returns the transactions of a list of VAA IDs (chain/emitter/sequence), indexed by ID.
+// The IDs without a transaction are not present in the result.
+func (s *Service) GetTransactionsByIDs(ctx context.Context, ids []string) (map[string]*TransactionDto, error) {
+
+	// Execute the database query
+	input := FindTransactionsInput{
+		ids: ids,
+	}
+	output, err := s.repo.FindTransactions(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*TransactionDto, len(output))
+	for i := range output {
+		result[output[i].ID] = &output[i]
+	}
+	return result, nil
+}
+
+// GetMessageStatus resolves the lifecycle status of a message from its observations,
+// VAA, governor status and global transaction.
+func (s *Service) GetMessageStatus(
+	ctx context.Context,
+	chain vaa.ChainID,
+	emitter *types.Address,
+	seq string,
+) (*MessageStatus, error) {
+
+	records, err := s.repo.FindMessageStatusRecords(ctx, chain, emitter.Hex(), seq)
+	if err != nil {
+		return nil, err
+	}
+
+	// the governor status is queried even if the VAA is stored, since the guardians
+	// report the release of an enqueued message after the VAA is signed.
+	release, err := s.govSrv.GetVaaReleaseEstimate(ctx, chain, emitter, seq)
+	if err != nil {
+		return nil, err
+	}
+
+	// count the signatures using the guardian set of the VAA, if it was signed.
+	guardianSet := s.guardianSet.GetLatest()
+	var vaaSignatures int
+	if records.Vaa != nil {
+		if int(records.Vaa.GuardianSetIndex) < len(s.guardianSet.GstByIndex) {
+			guardianSet = s.guardianSet.GstByIndex[records.Vaa.GuardianSetIndex]
+		}
+		if v, err := vaa.Unmarshal(records.Vaa.Vaa); err == nil {
+			vaaSignatures = len(v.Signatures)
+		}
+	}
+
+	id := fmt.Sprintf("%d/%s/%s", chain, emitter.Hex(), seq)
+	status := resolveMessageStatus(time.Now(), id, records, vaaSignatures, len(guardianSet.Keys), release)
+	if status == nil {
+		return nil, errs.ErrNotFound
+	}
+	return status, nil
+}
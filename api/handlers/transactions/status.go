@@ -0,0 +1,168 @@
+package transactions
+
+import (
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// MessageState is the lifecycle state of a cross-chain message.
+type MessageState string
+
+const (
+	// MessageStateSourceTxSeen indicates that the transaction that emitted the message was seen,
+	// but no guardian has signed an observation yet.
+	MessageStateSourceTxSeen MessageState = "sourceTxSeen"
+	// MessageStateObserving indicates that the guardians are signing observations of the message.
+	MessageStateObserving MessageState = "observing"
+	// MessageStateGovernorEnqueued indicates that the message was delayed by the governor.
+	MessageStateGovernorEnqueued MessageState = "governorEnqueued"
+	// MessageStateVaaSigned indicates that a quorum of guardians signed the VAA.
+	MessageStateVaaSigned MessageState = "vaaSigned"
+	// MessageStateRedeemFailed indicates that a transaction redeeming the VAA on the destination chain failed.
+	MessageStateRedeemFailed MessageState = "redeemFailed"
+	// MessageStateRedeemed indicates that the VAA was redeemed on the destination chain.
+	MessageStateRedeemed MessageState = "redeemed"
+	// MessageStateExpired indicates that the message did not reach quorum before the observations expired.
+	MessageStateExpired MessageState = "expired"
+)
+
+// observationExpiration is the time after the first observation of a message
+// after which the message is considered expired if the VAA was not signed.
+const observationExpiration = 24 * time.Hour
+
+// MessageStateTransition represents the transition of a message into a lifecycle state.
+type MessageStateTransition struct {
+	State MessageState `json:"state"`
+	// Timestamp is the moment of the transition, if it is known.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+// MessageStatus is the lifecycle status of a cross-chain message.
+type MessageStatus struct {
+	ID    string       `json:"id"`
+	State MessageState `json:"state"`
+	// Signatures is the number of guardians that signed the message.
+	Signatures int `json:"signatures"`
+	// GuardianSetSize is the number of guardians in the guardian set that observes the message.
+	GuardianSetSize int `json:"guardianSetSize"`
+	// Quorum is the number of signatures needed to sign the VAA.
+	Quorum int `json:"quorum"`
+	// GovernorRelease is the estimated release time of the VAA, if it is enqueued by the governor.
+	GovernorRelease *governor.ReleaseEstimate `json:"governorRelease,omitempty"`
+	// Transitions contains the states the message went through, in chronological order.
+	Transitions []MessageStateTransition `json:"transitions"`
+}
+
+// MessageStatusRecords contains the documents used to resolve the status of a message.
+type MessageStatusRecords struct {
+	Vaa          *MessageStatusVaa
+	Observations *MessageStatusObservations
+	GlobalTx     *GlobalTransactionDoc
+}
+
+// MessageStatusVaa is a summary of the VAA of a message.
+type MessageStatusVaa struct {
+	Timestamp        *time.Time `bson:"timestamp"`
+	IndexedAt        *time.Time `bson:"indexedAt"`
+	GuardianSetIndex uint32     `bson:"guardianSetIndex"`
+	Vaa              []byte     `bson:"vaas"`
+}
+
+// MessageStatusObservations is a summary of the observations of a message.
+type MessageStatusObservations struct {
+	Signers         []string   `bson:"signers"`
+	FirstObservedAt *time.Time `bson:"firstObservedAt"`
+}
+
+// resolveMessageStatus derives the lifecycle status of a message from the documents
+// stored for it, the governor release estimate and the number of signatures of the VAA.
+//
+// It returns nil if there is no evidence of the message.
+func resolveMessageStatus(
+	now time.Time,
+	id string,
+	records *MessageStatusRecords,
+	vaaSignatures int,
+	guardianSetSize int,
+	release *governor.ReleaseEstimate,
+) *MessageStatus {
+
+	status := MessageStatus{
+		ID:              id,
+		GuardianSetSize: guardianSetSize,
+		Quorum:          vaa.CalculateQuorum(guardianSetSize),
+		GovernorRelease: release,
+		Transitions:     []MessageStateTransition{},
+	}
+
+	var firstObservedAt *time.Time
+	if records.Observations != nil {
+		status.Signatures = len(records.Observations.Signers)
+		firstObservedAt = records.Observations.FirstObservedAt
+	}
+	if vaaSignatures > status.Signatures {
+		status.Signatures = vaaSignatures
+	}
+
+	hasOriginTx := records.GlobalTx != nil && records.GlobalTx.OriginTx != nil &&
+		records.GlobalTx.OriginTx.Status == string(domain.SourceTxStatusConfirmed)
+	if records.Vaa == nil && status.Signatures == 0 && !hasOriginTx {
+		return nil
+	}
+
+	addTransition := func(state MessageState, ts *time.Time) {
+		status.State = state
+		status.Transitions = append(status.Transitions, MessageStateTransition{State: state, Timestamp: ts})
+	}
+
+	// the source transaction is seen when the message is emitted.
+	sourceTxSeenAt := firstObservedAt
+	if records.Vaa != nil && records.Vaa.Timestamp != nil {
+		sourceTxSeenAt = records.Vaa.Timestamp
+	}
+	addTransition(MessageStateSourceTxSeen, sourceTxSeenAt)
+
+	// the governor delays the message before the guardians sign the observation.
+	// It does not report enqueue times, only the release estimate.
+	if release != nil {
+		addTransition(MessageStateGovernorEnqueued, nil)
+	}
+
+	if status.Signatures > 0 || records.Vaa != nil {
+		addTransition(MessageStateObserving, firstObservedAt)
+	}
+
+	if records.Vaa == nil {
+		switch {
+		case release != nil:
+			// guardians that already released the message may be signing it,
+			// but the message remains delayed until a quorum releases it.
+			status.State = MessageStateGovernorEnqueued
+		case firstObservedAt != nil && now.Sub(*firstObservedAt) > observationExpiration:
+			expiredAt := firstObservedAt.Add(observationExpiration)
+			addTransition(MessageStateExpired, &expiredAt)
+		}
+		return &status
+	}
+
+	addTransition(MessageStateVaaSigned, records.Vaa.IndexedAt)
+
+	if records.GlobalTx != nil && records.GlobalTx.DestinationTx != nil {
+		destinationTx := records.GlobalTx.DestinationTx
+		redeemedAt := destinationTx.Timestamp
+		if redeemedAt == nil {
+			redeemedAt = destinationTx.UpdatedAt
+		}
+		switch destinationTx.Status {
+		case domain.DstTxStatusConfirmed:
+			addTransition(MessageStateRedeemed, redeemedAt)
+		case domain.DstTxStatusFailedToProcess:
+			addTransition(MessageStateRedeemFailed, redeemedAt)
+		}
+	}
+
+	return &status
+}
@@ -0,0 +1,122 @@
+package transactions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+)
+
+func TestStatus_resolveMessageStatus(t *testing.T) {
+	now := time.Date(2023, 5, 4, 12, 0, 0, 0, time.UTC)
+	emittedAt := now.Add(-2 * time.Hour)
+	observedAt := emittedAt.Add(time.Minute)
+	signedAt := observedAt.Add(time.Minute)
+	redeemedAt := signedAt.Add(time.Hour)
+	release := &governor.ReleaseEstimate{EstimatedReleaseTime: now.Add(time.Hour)}
+
+	signed := &MessageStatusVaa{Timestamp: &emittedAt, IndexedAt: &signedAt}
+	observations := &MessageStatusObservations{Signers: []string{"a", "b", "c"}, FirstObservedAt: &observedAt}
+
+	tests := []struct {
+		name            string
+		now             time.Time
+		records         *MessageStatusRecords
+		vaaSignatures   int
+		release         *governor.ReleaseEstimate
+		wantState       MessageState
+		wantTransitions []MessageState
+		wantSignatures  int
+	}{
+		{
+			name: "source tx seen",
+			now:  now,
+			records: &MessageStatusRecords{
+				GlobalTx: &GlobalTransactionDoc{OriginTx: &OriginTx{Status: string(domain.SourceTxStatusConfirmed)}},
+			},
+			wantState:       MessageStateSourceTxSeen,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen},
+		},
+		{
+			name:            "observing",
+			now:             now,
+			records:         &MessageStatusRecords{Observations: observations},
+			wantState:       MessageStateObserving,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateObserving},
+			wantSignatures:  3,
+		},
+		{
+			name:            "governor enqueued",
+			now:             now,
+			records:         &MessageStatusRecords{Observations: observations},
+			release:         release,
+			wantState:       MessageStateGovernorEnqueued,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateGovernorEnqueued, MessageStateObserving},
+			wantSignatures:  3,
+		},
+		{
+			name:            "expired",
+			now:             now.Add(48 * time.Hour),
+			records:         &MessageStatusRecords{Observations: observations},
+			wantState:       MessageStateExpired,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateObserving, MessageStateExpired},
+			wantSignatures:  3,
+		},
+		{
+			name:            "vaa signed",
+			now:             now,
+			records:         &MessageStatusRecords{Vaa: signed, Observations: observations},
+			vaaSignatures:   13,
+			wantState:       MessageStateVaaSigned,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateObserving, MessageStateVaaSigned},
+			wantSignatures:  13,
+		},
+		{
+			name: "redeem failed",
+			now:  now,
+			records: &MessageStatusRecords{
+				Vaa:      signed,
+				GlobalTx: &GlobalTransactionDoc{DestinationTx: &DestinationTx{Status: domain.DstTxStatusFailedToProcess, UpdatedAt: &redeemedAt}},
+			},
+			vaaSignatures:   13,
+			wantState:       MessageStateRedeemFailed,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateObserving, MessageStateVaaSigned, MessageStateRedeemFailed},
+			wantSignatures:  13,
+		},
+		{
+			name: "redeemed",
+			now:  now,
+			records: &MessageStatusRecords{
+				Vaa:      signed,
+				GlobalTx: &GlobalTransactionDoc{DestinationTx: &DestinationTx{Status: domain.DstTxStatusConfirmed, Timestamp: &redeemedAt}},
+			},
+			vaaSignatures:   13,
+			wantState:       MessageStateRedeemed,
+			wantTransitions: []MessageState{MessageStateSourceTxSeen, MessageStateObserving, MessageStateVaaSigned, MessageStateRedeemed},
+			wantSignatures:  13,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := resolveMessageStatus(tt.now, "2/emitter/1", tt.records, tt.vaaSignatures, 19, tt.release)
+			assert.NotNil(t, status)
+			assert.Equal(t, tt.wantState, status.State)
+			assert.Equal(t, tt.wantSignatures, status.Signatures)
+			assert.Equal(t, 13, status.Quorum)
+
+			states := make([]MessageState, 0, len(status.Transitions))
+			for _, transition := range status.Transitions {
+				states = append(states, transition.State)
+			}
+			assert.Equal(t, tt.wantTransitions, states)
+		})
+	}
+}
+
+func TestStatus_resolveMessageStatusNotFound(t *testing.T) {
+	status := resolveMessageStatus(time.Now(), "2/emitter/1", &MessageStatusRecords{}, 0, 19, nil)
+	assert.Nil(t, status)
+}
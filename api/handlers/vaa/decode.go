@@ -0,0 +1,191 @@
+package vaa
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// ErrMalformedVaa is returned when the bytes of a VAA can not be decoded.
+var ErrMalformedVaa = errors.New("malformed vaa")
+
+// DecodedVaa is a VAA decoded from its raw bytes.
+type DecodedVaa struct {
+	ID     string           `json:"id"`
+	Digest string           `json:"digest"`
+	Header DecodedVaaHeader `json:"header"`
+	Body   DecodedVaaBody   `json:"body"`
+	// Verification is the result of verifying the signatures against the guardian set history.
+	Verification VaaVerification `json:"verification"`
+	// Stored compares the VAA with the one stored in the database.
+	Stored StoredVaa `json:"stored"`
+	// ParsedPayload is the payload parsed by the vaa-payload-parser, if the emitter is supported.
+	ParsedPayload          interface{}                              `json:"parsedPayload,omitempty"`
+	StandardizedProperties *vaaPayloadParser.StandardizedProperties `json:"standardizedProperties,omitempty"`
+}
+
+// DecodedVaaHeader is the header of a VAA.
+type DecodedVaaHeader struct {
+	Version          uint8               `json:"version"`
+	GuardianSetIndex uint32              `json:"guardianSetIndex"`
+	Signatures       []*DecodedSignature `json:"signatures"`
+}
+
+// DecodedSignature is a guardian signature of a VAA.
+type DecodedSignature struct {
+	Index uint8 `json:"index"`
+	// GuardianAddress is the address of the guardian at the index of the guardian set, if the guardian set is known.
+	GuardianAddress string `json:"guardianAddress,omitempty"`
+	Signature       string `json:"signature"`
+	// Valid indicates whether the signature was produced by the guardian at the index of the guardian set.
+	Valid bool `json:"valid"`
+}
+
+// DecodedVaaBody is the body of a VAA.
+type DecodedVaaBody struct {
+	Timestamp         time.Time   `json:"timestamp"`
+	Nonce             uint32      `json:"nonce"`
+	EmitterChain      vaa.ChainID `json:"emitterChain"`
+	EmitterAddr       string      `json:"emitterAddr"`
+	EmitterNativeAddr string      `json:"emitterNativeAddr,omitempty"`
+	Sequence          uint64      `json:"sequence"`
+	ConsistencyLevel  uint8       `json:"consistencyLevel"`
+	Payload           string      `json:"payload"`
+}
+
+// VaaVerification is the result of verifying the signatures of a VAA.
+type VaaVerification struct {
+	// GuardianSetKnown indicates whether the guardian set of the VAA is in the guardian set history.
+	GuardianSetKnown bool `json:"guardianSetKnown"`
+	GuardianSetSize  int  `json:"guardianSetSize,omitempty"`
+	Quorum           int  `json:"quorum,omitempty"`
+	ValidSignatures  int  `json:"validSignatures"`
+	// Verified indicates whether all the signatures are valid and they reach the quorum.
+	Verified bool `json:"verified"`
+}
+
+// StoredVaa compares a VAA with the one stored in the database.
+type StoredVaa struct {
+	Exists bool `json:"exists"`
+	// BytesMatch indicates whether the stored bytes are the same as the decoded ones.
+	BytesMatch bool `json:"bytesMatch"`
+	// DigestMatch indicates whether the stored VAA signs the same body, possibly with a different set of signatures.
+	DigestMatch bool `json:"digestMatch"`
+}
+
+// Encodings of the VAAs to decode.
+const (
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+)
+
+// DecodeVaaBytes parses the bytes of a VAA in an encoding, base64 if it is empty, or hex with or without 0x prefix.
+//
+// The encoding is explicit since a string can be valid in both encodings.
+func DecodeVaaBytes(s, encoding string) ([]byte, error) {
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("empty vaa")
+	}
+
+	switch encoding {
+	case "", EncodingBase64:
+		if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return b, nil
+		}
+		if b, err := base64.URLEncoding.DecodeString(s); err == nil {
+			return b, nil
+		}
+		return nil, errors.New("vaa is not base64 encoded")
+	case EncodingHex:
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, errors.New("vaa is not hex encoded")
+		}
+		return b, nil
+	default:
+		return nil, errors.Errorf("encoding must be %s or %s", EncodingBase64, EncodingHex)
+	}
+}
+
+// decodeVaa decodes the bytes of a VAA and verifies its signatures against the guardian set history.
+func decodeVaa(raw []byte, gs guardian.GuardianSet) (*vaa.VAA, *DecodedVaa, error) {
+
+	v, err := vaa.Unmarshal(raw)
+	if err != nil {
+		return nil, nil, errors.Wrap(ErrMalformedVaa, err.Error())
+	}
+
+	digest := v.SigningDigest()
+	decoded := DecodedVaa{
+		ID:     v.MessageID(),
+		Digest: hex.EncodeToString(digest.Bytes()),
+		Header: DecodedVaaHeader{
+			Version:          v.Version,
+			GuardianSetIndex: v.GuardianSetIndex,
+			Signatures:       make([]*DecodedSignature, 0, len(v.Signatures)),
+		},
+		Body: DecodedVaaBody{
+			Timestamp:        v.Timestamp,
+			Nonce:            v.Nonce,
+			EmitterChain:     v.EmitterChain,
+			EmitterAddr:      v.EmitterAddress.String(),
+			Sequence:         v.Sequence,
+			ConsistencyLevel: v.ConsistencyLevel,
+			Payload:          hex.EncodeToString(v.Payload),
+		},
+	}
+	if nativeAddr, err := domain.TranslateEmitterAddress(v.EmitterChain, v.EmitterAddress.String()); err == nil {
+		decoded.Body.EmitterNativeAddr = nativeAddr
+	}
+
+	set, ok := gs.GetByIndex(v.GuardianSetIndex)
+	decoded.Verification.GuardianSetKnown = ok
+	if ok {
+		decoded.Verification.GuardianSetSize = len(set.Keys)
+		decoded.Verification.Quorum = vaa.CalculateQuorum(len(set.Keys))
+	}
+
+	// a guardian can only sign once, and the signatures must be sorted by index.
+	signed := make(map[uint8]bool, len(v.Signatures))
+	ordered := true
+	for i, sig := range v.Signatures {
+		s := DecodedSignature{
+			Index:     sig.Index,
+			Signature: hex.EncodeToString(sig.Signature[:]),
+		}
+		if ok && int(sig.Index) < len(set.Keys) {
+			addr := set.Keys[sig.Index]
+			s.GuardianAddress = addr.Hex()
+			if pubKey, err := crypto.Ecrecover(digest.Bytes(), sig.Signature[:]); err == nil {
+				signer := eth_common.BytesToAddress(crypto.Keccak256(pubKey[1:])[12:])
+				s.Valid = signer == addr && !signed[sig.Index]
+			}
+		}
+		if i > 0 && sig.Index <= v.Signatures[i-1].Index {
+			ordered = false
+		}
+		if s.Valid {
+			signed[sig.Index] = true
+			decoded.Verification.ValidSignatures++
+		}
+		decoded.Header.Signatures = append(decoded.Header.Signatures, &s)
+	}
+
+	decoded.Verification.Verified = ok &&
+		ordered &&
+		decoded.Verification.ValidSignatures == len(v.Signatures) &&
+		decoded.Verification.ValidSignatures >= decoded.Verification.Quorum
+
+	return v, &decoded, nil
+}
@@ -0,0 +1,104 @@
+package vaa
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/certusone/wormhole/node/pkg/common"
+	eth_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestDecode_decodeVaa(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	gs := guardian.GuardianSet{
+		GstByIndex: []common.GuardianSet{{
+			Index: 0,
+			Keys:  []eth_common.Address{crypto.PubkeyToAddress(key1.PublicKey), crypto.PubkeyToAddress(key2.PublicKey)},
+		}},
+	}
+
+	v := &vaa.VAA{
+		Version:          vaa.SupportedVAAVersion,
+		GuardianSetIndex: 0,
+		Timestamp:        time.Unix(1683000000, 0),
+		Nonce:            1,
+		Sequence:         10,
+		EmitterChain:     vaa.ChainIDEthereum,
+		EmitterAddress:   vaa.Address{0x01},
+		ConsistencyLevel: 1,
+		Payload:          []byte{0xca, 0xfe},
+	}
+	v.AddSignature(key1, 0)
+	v.AddSignature(key2, 1)
+	raw, err := v.Marshal()
+	assert.NoError(t, err)
+
+	_, decoded, err := decodeVaa(raw, gs)
+	assert.NoError(t, err)
+	assert.Equal(t, v.MessageID(), decoded.ID)
+	assert.Equal(t, "cafe", decoded.Body.Payload)
+	assert.Equal(t, uint64(10), decoded.Body.Sequence)
+	assert.Len(t, decoded.Header.Signatures, 2)
+	assert.Equal(t, crypto.PubkeyToAddress(key2.PublicKey).Hex(), decoded.Header.Signatures[1].GuardianAddress)
+	assert.True(t, decoded.Verification.GuardianSetKnown)
+	assert.Equal(t, 2, decoded.Verification.ValidSignatures)
+	assert.True(t, decoded.Verification.Verified)
+
+	// a signature of the wrong guardian is not valid.
+	v.Signatures[1].Index = 0
+	raw, _ = v.Marshal()
+	_, decoded, err = decodeVaa(raw, gs)
+	assert.NoError(t, err)
+	assert.True(t, decoded.Header.Signatures[0].Valid)
+	assert.False(t, decoded.Header.Signatures[1].Valid)
+	assert.False(t, decoded.Verification.Verified)
+
+	// the guardian set is unknown.
+	v.GuardianSetIndex = 1
+	raw, _ = v.Marshal()
+	_, decoded, err = decodeVaa(raw, gs)
+	assert.NoError(t, err)
+	assert.False(t, decoded.Verification.GuardianSetKnown)
+	assert.Equal(t, 0, decoded.Verification.ValidSignatures)
+	assert.False(t, decoded.Verification.Verified)
+
+	_, _, err = decodeVaa([]byte{0x01, 0x02}, gs)
+	assert.ErrorIs(t, err, ErrMalformedVaa)
+}
+
+func TestDecode_DecodeVaaBytes(t *testing.T) {
+	raw := []byte{0x01, 0x00, 0x00, 0x00, 0x03}
+
+	for _, tt := range []struct{ s, encoding string }{
+		{hex.EncodeToString(raw), EncodingHex},
+		{"0x" + hex.EncodeToString(raw), EncodingHex},
+		{base64.StdEncoding.EncodeToString(raw), EncodingBase64},
+		{base64.StdEncoding.EncodeToString(raw), ""},
+	} {
+		b, err := DecodeVaaBytes(tt.s, tt.encoding)
+		assert.NoError(t, err, tt.s)
+		assert.Equal(t, raw, b, tt.s)
+	}
+
+	// a string valid in both encodings is decoded with the requested one.
+	b, err := DecodeVaaBytes("01000000", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xd3, 0x5d, 0x34, 0xd3, 0x4d, 0x34}, b)
+	b, err = DecodeVaaBytes("01000000", EncodingHex)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x00, 0x00, 0x00}, b)
+
+	_, err = DecodeVaaBytes("", "")
+	assert.Error(t, err)
+	_, err = DecodeVaaBytes("not a vaa!", "")
+	assert.Error(t, err)
+	_, err = DecodeVaaBytes("0x0100", "base58")
+	assert.Error(t, err)
+}
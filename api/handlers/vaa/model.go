@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
@@ -32,6 +33,8 @@ type VaaDoc struct {
 	AppId string `bson:"appId" json:"appId,omitempty"`
 	// Payload is an extension field - it is not present in the guardian API.
 	Payload map[string]interface{} `bson:"payload" json:"payload,omitempty"`
+	// EmitterLabel is an extension field - it is not present in the guardian API.
+	EmitterLabel *labels.Label `bson:"-" json:"emitterLabel,omitempty"`
 
 	// NativeTxHash is an internal field.
 	//
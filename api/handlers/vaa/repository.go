@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/filter"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -25,6 +26,7 @@ type Repository struct {
 		invalidVaas        *mongo.Collection
 		vaaCount           *mongo.Collection
 		globalTransactions *mongo.Collection
+		parsedVaa          *mongo.Collection
 	}
 }
 
@@ -38,12 +40,14 @@ func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
 			invalidVaas        *mongo.Collection
 			vaaCount           *mongo.Collection
 			globalTransactions *mongo.Collection
+			parsedVaa          *mongo.Collection
 		}{
 			vaas:               db.Collection("vaas"),
 			vaasPythnet:        db.Collection("vaasPythnet"),
 			invalidVaas:        db.Collection("invalid_vaas"),
 			vaaCount:           db.Collection("vaaCounts"),
 			globalTransactions: db.Collection("globalTransactions"),
+			parsedVaa:          db.Collection("parsedVaa"),
 		},
 	}
 }
@@ -114,6 +118,52 @@ func (r *Repository) FindVaasByTxHashWorkaround(
 	return r.FindVaas(ctx, &q)
 }
 
+// FindVaasByPayloadFilter searches the database for VAAs whose parsed payload matches `q.payloadFilter`.
+//
+// The filter is executed against the `parsedVaa` collection to get the IDs of the page of VAAs,
+// and then the VAAs are looked up by ID in the `vaas` collection.
+func (r *Repository) FindVaasByPayloadFilter(
+	ctx context.Context,
+	query *VaaQuery,
+) ([]*VaaDoc, error) {
+
+	// Find the IDs of the VAAs that match the filter
+	pipeline := query.payloadFilter.ParsedVaaIDsPipeline(&query.Pagination)
+	cur, err := r.collections.parsedVaa.Aggregate(ctx, pipeline)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed execute Aggregate command to get parsedVaa ids",
+			zap.Error(err), zap.Any("q", query), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	err = cur.All(ctx, &docs)
+	if err != nil {
+		requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+		r.logger.Error("failed decoding cursor to parsedVaa ids",
+			zap.Error(err), zap.Any("q", query), zap.String("requestID", requestID))
+		return nil, errors.WithStack(err)
+	}
+	if len(docs) == 0 {
+		return make([]*VaaDoc, 0), nil
+	}
+
+	// Find VAAs that match the given VAA IDs
+	q := *query // making a copy to avoid modifying the struct passed by the caller
+	ids := make([]string, 0, len(docs))
+	for i := range docs {
+		ids = append(ids, docs[i].ID)
+	}
+	q.SetIDs(ids)
+	// The page was already applied on the `parsedVaa` collection.
+	q.Pagination.Skip = 0
+	q.Pagination.Limit = int64(len(ids))
+	q.payloadFilter = nil
+	return r.FindVaas(ctx, &q)
+}
+
 // FindVaas searches the database for VAAs matching the given filters.
 //
 // When the `q.txHash` field is set, this function will look up transaction hashes in the `vaas` collection.
@@ -134,13 +184,8 @@ func (r *Repository) FindVaas(
 
 		// filter by VAA ids (potentially more than one)
 		if len(q.ids) > 0 {
-			var array bson.A
-			for _, id := range q.ids {
-				predicate := bson.D{bson.E{"_id", id}}
-				array = append(array, predicate)
-			}
 			pipeline = append(pipeline, bson.D{
-				{"$match", bson.D{{"$or", array}}},
+				{"$match", bson.D{{"_id", bson.M{"$in": q.ids}}}},
 			})
 		}
 
@@ -172,6 +217,13 @@ func (r *Repository) FindVaas(
 			})
 		}
 
+		// filter by timestamp
+		if timestampMatch := q.payloadFilter.TimestampMatch(); timestampMatch != nil {
+			pipeline = append(pipeline, bson.D{
+				{"$match", timestampMatch},
+			})
+		}
+
 		// left outer join on the `parsedVaa` collection
 		pipeline = append(pipeline, bson.D{
 			{"$lookup", bson.D{
@@ -331,6 +383,7 @@ type VaaQuery struct {
 	txHash               string
 	appId                string
 	includeParsedPayload bool
+	payloadFilter        *filter.PayloadFilter
 }
 
 // Query create a new VaaQuery with default pagination vaues.
@@ -379,6 +432,12 @@ func (q *VaaQuery) SetAppId(appId string) *VaaQuery {
 	return q
 }
 
+// SetPayloadFilter set the payloadFilter field of the VaaQuery struct.
+func (q *VaaQuery) SetPayloadFilter(f *filter.PayloadFilter) *VaaQuery {
+	q.payloadFilter = f
+	return q
+}
+
 func (q *VaaQuery) IncludeParsedPayload(val bool) *VaaQuery {
 	q.includeParsedPayload = val
 	return q
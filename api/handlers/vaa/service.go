@@ -1,34 +1,57 @@
 package vaa
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/cacheable"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/guardian"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/filter"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"github.com/wormhole-foundation/wormhole-explorer/api/types"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
 // Service definition.
 type Service struct {
-	repo         *Repository
-	getCacheFunc cache.CacheGetFunc
-	logger       *zap.Logger
+	repo          *Repository
+	cache         *cacheable.Cache
+	labelsService *labels.Service
+	gs            guardian.GuardianSet
+	parser        *payload.Registry
+	logger        *zap.Logger
 }
 
 // NewService creates a new VAA Service.
-func NewService(r *Repository, getCacheFunc cache.CacheGetFunc, logger *zap.Logger) *Service {
+//
+// The payload parser is used to parse the payload of the decoded VAAs. It can be nil.
+func NewService(
+	r *Repository,
+	cache *cacheable.Cache,
+	labelsService *labels.Service,
+	parser *payload.Registry,
+	p2pNetwork string,
+	logger *zap.Logger,
+) *Service {
 
 	s := Service{
-		repo:         r,
-		getCacheFunc: getCacheFunc,
-		logger:       logger.With(zap.String("module", "VaaService")),
+		repo:          r,
+		cache:         cache,
+		labelsService: labelsService,
+		gs:            guardian.GetByEnv(p2pNetwork),
+		parser:        parser,
+		logger:        logger.With(zap.String("module", "VaaService")),
 	}
 
 	return &s
@@ -40,6 +63,7 @@ type FindAllParams struct {
 	TxHash               *types.TxHash
 	IncludeParsedPayload bool
 	AppId                string
+	PayloadFilter        *filter.PayloadFilter
 }
 
 // FindAll returns all VAAs.
@@ -60,6 +84,9 @@ func (s *Service) FindAll(
 	if params.AppId != "" {
 		query.SetAppId(params.AppId)
 	}
+	if !params.PayloadFilter.IsEmpty() {
+		query.SetPayloadFilter(params.PayloadFilter)
+	}
 
 	// Execute the database query
 	//
@@ -71,6 +98,8 @@ func (s *Service) FindAll(
 	var vaas []*VaaDoc
 	if query.txHash != "" {
 		vaas, err = s.repo.FindVaasByTxHashWorkaround(ctx, query)
+	} else if query.payloadFilter.HasPayloadFilters() {
+		vaas, err = s.repo.FindVaasByPayloadFilter(ctx, query)
 	} else {
 		vaas, err = s.repo.FindVaas(ctx, query)
 	}
@@ -79,6 +108,7 @@ func (s *Service) FindAll(
 	}
 
 	// Return the matching documents
+	s.setEmitterLabels(vaas)
 	res := response.Response[[]*VaaDoc]{Data: vaas}
 	return &res, nil
 }
@@ -96,6 +126,7 @@ func (s *Service) FindByChain(
 		IncludeParsedPayload(false)
 
 	vaas, err := s.repo.FindVaas(ctx, query)
+	s.setEmitterLabels(vaas)
 
 	res := response.Response[[]*VaaDoc]{Data: vaas}
 	return &res, err
@@ -116,6 +147,7 @@ func (s *Service) FindByEmitter(
 		IncludeParsedPayload(false)
 
 	vaas, err := s.repo.FindVaas(ctx, query)
+	s.setEmitterLabels(vaas)
 
 	res := response.Response[[]*VaaDoc]{Data: vaas}
 	return &res, err
@@ -143,6 +175,7 @@ func (s *Service) FindById(
 	}
 
 	// return matching documents
+	s.setEmitterLabels([]*VaaDoc{vaa})
 	resp := response.Response[*VaaDoc]{Data: vaa}
 	return &resp, err
 }
@@ -182,6 +215,106 @@ func (s *Service) findById(
 	return docs[0], nil
 }
 
+// FindByIDs returns the VAAs of a list of VAA IDs (chain/emitter/sequence), indexed by ID.
+// The IDs without a VAA are not present in the result.
+func (s *Service) FindByIDs(ctx context.Context, ids []string, includeParsedPayload bool) (map[string]*VaaDoc, error) {
+
+	// pythnet VAAs are stored in a different collection.
+	pythnetPrefix := fmt.Sprintf("%d/", vaa.ChainIDPythNet)
+	var vaaIDs, pythnetIDs []string
+	for _, id := range ids {
+		if strings.HasPrefix(id, pythnetPrefix) {
+			pythnetIDs = append(pythnetIDs, id)
+		} else {
+			vaaIDs = append(vaaIDs, id)
+		}
+	}
+
+	result := make(map[string]*VaaDoc, len(ids))
+	for _, group := range []struct {
+		chain vaa.ChainID
+		ids   []string
+	}{{vaa.ChainIDUnset, vaaIDs}, {vaa.ChainIDPythNet, pythnetIDs}} {
+		if len(group.ids) == 0 {
+			continue
+		}
+
+		// query all the VAAs of the group with a single aggregation
+		p := pagination.Default()
+		p.Limit = int64(len(group.ids))
+		query := Query().
+			SetChain(group.chain).
+			SetIDs(group.ids).
+			SetPagination(p).
+			IncludeParsedPayload(includeParsedPayload)
+		docs, err := s.repo.FindVaas(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		s.setEmitterLabels(docs)
+		for _, doc := range docs {
+			result[doc.ID] = doc
+		}
+	}
+
+	return result, nil
+}
+
+// Decode decodes the raw bytes of a VAA, verifies its signatures against the guardian set history,
+// compares it with the stored VAA and parses its payload.
+func (s *Service) Decode(ctx context.Context, raw []byte) (*DecodedVaa, error) {
+
+	v, decoded, err := decodeVaa(raw, s.gs)
+	if err != nil {
+		return nil, err
+	}
+
+	// compare with the stored VAA
+	emitter, err := types.BytesToAddress(v.EmitterAddress[:])
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.findById(ctx, v.EmitterChain, emitter, strconv.FormatUint(v.Sequence, 10), false)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return nil, err
+	}
+	if stored != nil {
+		decoded.Stored.Exists = true
+		decoded.Stored.BytesMatch = bytes.Equal(stored.Vaa, raw)
+		if storedVaa, err := vaa.Unmarshal(stored.Vaa); err == nil {
+			decoded.Stored.DigestMatch = storedVaa.SigningDigest() == v.SigningDigest()
+		}
+	}
+
+	// parse the payload with the same parsers used by the parser service
+	if s.parser != nil {
+		parsed, _, err := s.parser.Parse(v)
+		if err == nil {
+			// the amounts are transformed as the parser stores them.
+			standardizedProperties := vaaPayloadParser.TransformStandarizedProperties(decoded.ID, parsed.StandardizedProperties, s.logger)
+			decoded.ParsedPayload = parsed.ParsedPayload
+			decoded.StandardizedProperties = &standardizedProperties
+		} else if !errors.Is(err, vaaPayloadParser.ErrNotFound) {
+			requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
+			s.logger.Warn("failed to parse the payload of a decoded vaa",
+				zap.String("vaaId", decoded.ID),
+				zap.Error(err),
+				zap.String("requestID", requestID),
+			)
+		}
+	}
+
+	return decoded, nil
+}
+
+// setEmitterLabels sets the label of the emitter of each VAA, if it is a known address.
+func (s *Service) setEmitterLabels(vaas []*VaaDoc) {
+	for _, v := range vaas {
+		v.EmitterLabel = s.labelsService.Find(v.EmitterChain, v.EmitterAddr)
+	}
+}
+
 // GetVaaCount get a list a list of vaa count grouped by chainID.
 func (s *Service) GetVaaCount(ctx context.Context) (*response.Response[[]*VaaStats], error) {
 	q := Query()
@@ -194,8 +327,10 @@ func (s *Service) GetVaaCount(ctx context.Context) (*response.Response[[]*VaaSta
 // the cached value of the sequence for this chainID, address.
 // If the sequence does not exist we can not discard the request.
 func (s *Service) discardVaaNotIndexed(ctx context.Context, chain vaa.ChainID, emitter *types.Address, seq string) bool {
+	// the max sequence is updated by fly for every new VAA, so it is read from the shared cache
+	// without a local copy to invalidate.
 	key := fmt.Sprintf("%s:%d:%s", "wormscan:vaa-max-sequence", chain, emitter.Hex())
-	sequence, err := s.getCacheFunc(ctx, key)
+	sequence, err := s.cache.Get(ctx, key)
 	if err != nil {
 		if errors.Is(err, cache.ErrInternal) {
 			requestID := fmt.Sprintf("%v", ctx.Value("requestid"))
@@ -0,0 +1,29 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+)
+
+// alert key constants definition.
+const (
+	ChainLostQuorum = "CHAIN_LOST_QUORUM"
+)
+
+func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
+	alerts := make(map[string]alert.Alert)
+
+	// Alert chain without a quorum of up-to-date guardians.
+	alerts[ChainLostQuorum] = alert.Alert{
+		Alias:       ChainLostQuorum,
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Chain lost quorum of up-to-date guardians"),
+		Description: "Less than a quorum of guardians are up-to-date with the chain, VAAs of the chain can not be signed.",
+		Actions:     []string{"check guardians health in /api/v1/guardians/health", "check guardians heartbeats"},
+		Tags:        []string{cfg.Environment, "api", "guardian", "heartbeat"},
+		Entity:      "api",
+		Priority:    alert.CRITICAL,
+	}
+
+	return alerts
+}
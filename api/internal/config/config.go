@@ -6,6 +6,7 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	ipfslog "github.com/ipfs/go-log/v2"
@@ -38,6 +39,10 @@ type AppConfig struct {
 		Enabled          bool
 		MetricExpiration int
 		Prefix           string
+		// NotionalChannel is the pubsub channel used by the notional job to notify price updates.
+		NotionalChannel string
+		// InvalidationChannel is the pubsub channel used by the pipeline to notify new VAAs.
+		InvalidationChannel string
 	}
 	PORT         int
 	LogLevel     string
@@ -53,6 +58,20 @@ type AppConfig struct {
 		Bucket30Days   string
 		BucketInfinite string
 	}
+	Alert struct {
+		Enabled bool
+		ApiKey  string
+	}
+	Admin struct {
+		// ApiKey is the bearer token required by the admin endpoints.
+		// The admin endpoints are disabled when it is empty.
+		ApiKey string
+	}
+	VaaPayloadParser struct {
+		// URL of the vaa-payload-parser service used by the parser, to parse the payload of the decoded VAAs
+		// without a native parser as the parser does. Only the native parsers are used when it is empty.
+		URL string
+	}
 	RateLimit struct {
 		Enabled bool
 		// Max number of requests per minute
@@ -60,6 +79,72 @@ type AppConfig struct {
 		// Prefix for redis keys
 		Prefix string
 	}
+	// Networks defines the networks served in addition to P2pNetwork, the gRPC API only serves P2pNetwork.
+	// It is loaded from the WORMSCAN_NETWORKS environment variable as a JSON array.
+	Networks []NetworkConfig `mapstructure:"-"`
+}
+
+// NetworkConfig defines the configuration of a network served by the API.
+//
+// Empty fields are taken from the AppConfig, except the cache prefix which defaults to the network name,
+// so an additional network usually only needs to define its database name and influx buckets.
+type NetworkConfig struct {
+	P2pNetwork string `json:"p2pNetwork"`
+	DB         struct {
+		URL  string `json:"url"`
+		Name string `json:"name"`
+	} `json:"db"`
+	Cache struct {
+		Prefix string `json:"prefix"`
+		TvlKey string `json:"tvlKey"`
+	} `json:"cache"`
+	Influx struct {
+		URL            string `json:"url"`
+		Token          string `json:"token"`
+		Organization   string `json:"organization"`
+		Bucket24Hours  string `json:"bucket24Hours"`
+		Bucket30Days   string `json:"bucket30Days"`
+		BucketInfinite string `json:"bucketInfinite"`
+	} `json:"influx"`
+}
+
+// GetNetworks returns the configuration of all the networks served by the API.
+// The first element is always the default network, defined by P2pNetwork.
+func (cfg *AppConfig) GetNetworks() []NetworkConfig {
+
+	var defaultNetwork NetworkConfig
+	defaultNetwork.P2pNetwork = cfg.P2pNetwork
+	defaultNetwork.DB.URL = cfg.DB.URL
+	defaultNetwork.DB.Name = cfg.DB.Name
+	defaultNetwork.Cache.Prefix = cfg.Cache.Prefix
+	defaultNetwork.Cache.TvlKey = cfg.Cache.TvlKey
+	defaultNetwork.Influx.URL = cfg.Influx.URL
+	defaultNetwork.Influx.Token = cfg.Influx.Token
+	defaultNetwork.Influx.Organization = cfg.Influx.Organization
+	defaultNetwork.Influx.Bucket24Hours = cfg.Influx.Bucket24Hours
+	defaultNetwork.Influx.Bucket30Days = cfg.Influx.Bucket30Days
+	defaultNetwork.Influx.BucketInfinite = cfg.Influx.BucketInfinite
+
+	networks := []NetworkConfig{defaultNetwork}
+	for _, n := range cfg.Networks {
+		if n.DB.URL == "" {
+			n.DB.URL = cfg.DB.URL
+		}
+		if n.Cache.TvlKey == "" {
+			n.Cache.TvlKey = cfg.Cache.TvlKey
+		}
+		if n.Influx.URL == "" {
+			n.Influx.URL = cfg.Influx.URL
+		}
+		if n.Influx.Token == "" {
+			n.Influx.Token = cfg.Influx.Token
+		}
+		if n.Influx.Organization == "" {
+			n.Influx.Organization = cfg.Influx.Organization
+		}
+		networks = append(networks, n)
+	}
+	return networks
 }
 
 // GetLogLevel get zapcore.Level define in the configuraion.
@@ -70,14 +155,18 @@ func (cfg *AppConfig) GetLogLevel() (ipfslog.LogLevel, error) {
 func defaulConfig() *AppConfig {
 	return &AppConfig{
 		Cache: struct {
-			URL              string
-			TvlKey           string
-			TvlExpiration    int
-			Enabled          bool
-			MetricExpiration int
-			Prefix           string
+			URL                 string
+			TvlKey              string
+			TvlExpiration       int
+			Enabled             bool
+			MetricExpiration    int
+			Prefix              string
+			NotionalChannel     string
+			InvalidationChannel string
 		}{
-			MetricExpiration: 10,
+			MetricExpiration:    10,
+			NotionalChannel:     "WORMSCAN:NOTIONAL",
+			InvalidationChannel: "WORMSCAN:CACHE_INVALIDATION",
 		},
 	}
 }
@@ -117,5 +206,40 @@ func init() {
 func Get() (*AppConfig, error) {
 	var cfg AppConfig
 	err := viper.Unmarshal(&cfg)
-	return &cfg, err
+	if err != nil {
+		return &cfg, err
+	}
+
+	// load the additional networks
+	if networks := viper.GetString("networks"); networks != "" {
+		if err := json.Unmarshal([]byte(networks), &cfg.Networks); err != nil {
+			return &cfg, fmt.Errorf("failed to parse networks configuration: %w", err)
+		}
+	}
+	networks := map[string]bool{cfg.P2pNetwork: true}
+	prefixes := map[string]bool{cfg.Cache.Prefix: true}
+	for i := range cfg.Networks {
+		n := &cfg.Networks[i]
+		if n.P2pNetwork == "" || networks[n.P2pNetwork] {
+			return &cfg, fmt.Errorf("invalid or duplicated p2p network for additional network: %q", n.P2pNetwork)
+		}
+		networks[n.P2pNetwork] = true
+		if n.DB.Name == "" {
+			return &cfg, fmt.Errorf("missing database name for network %s", n.P2pNetwork)
+		}
+		// the metrics of each network are stored in its own buckets.
+		if n.Influx.Bucket24Hours == "" || n.Influx.Bucket30Days == "" || n.Influx.BucketInfinite == "" {
+			return &cfg, fmt.Errorf("missing influx buckets for network %s", n.P2pNetwork)
+		}
+		// the networks can not share the cache keys.
+		if n.Cache.Prefix == "" {
+			n.Cache.Prefix = n.P2pNetwork
+		}
+		if prefixes[n.Cache.Prefix] {
+			return &cfg, fmt.Errorf("duplicated cache prefix %q for network %s", n.Cache.Prefix, n.P2pNetwork)
+		}
+		prefixes[n.Cache.Prefix] = true
+	}
+
+	return &cfg, nil
 }
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testnet = `{"p2pNetwork":"testnet","db":{"name":"wormscan-testnet"},` +
+	`"influx":{"bucket24Hours":"24h-testnet","bucket30Days":"30d-testnet","bucketInfinite":"inf-testnet"}}`
+
+func TestGet_Networks(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks string
+		prefixes []string
+		err      string
+	}{
+		{name: "no additional networks", prefixes: []string{"mainnet"}},
+		{name: "additional network", networks: "[" + testnet + "]", prefixes: []string{"mainnet", "testnet"}},
+		{name: "invalid json", networks: "[", err: "failed to parse networks configuration"},
+		{name: "missing p2p network", networks: `[{"db":{"name":"wormscan"}}]`, err: "invalid or duplicated p2p network"},
+		{name: "duplicated p2p network", networks: `[{"p2pNetwork":"mainnet","db":{"name":"wormscan"}}]`, err: "invalid or duplicated p2p network"},
+		{name: "missing database name", networks: `[{"p2pNetwork":"testnet"}]`, err: "missing database name"},
+		{name: "missing influx buckets", networks: `[{"p2pNetwork":"testnet","db":{"name":"wormscan-testnet"}}]`, err: "missing influx buckets"},
+		{name: "duplicated cache prefix", networks: "[" + testnet + `,{"p2pNetwork":"devnet","db":{"name":"wormscan-devnet"},"cache":{"prefix":"testnet"},` +
+			`"influx":{"bucket24Hours":"24h-devnet","bucket30Days":"30d-devnet","bucketInfinite":"inf-devnet"}}]`, err: "duplicated cache prefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WORMSCAN_P2PNETWORK", "mainnet")
+			t.Setenv("WORMSCAN_CACHE_PREFIX", "mainnet")
+			t.Setenv("WORMSCAN_NETWORKS", tt.networks)
+
+			cfg, err := Get()
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			var prefixes []string
+			for _, n := range cfg.GetNetworks() {
+				prefixes = append(prefixes, n.Cache.Prefix)
+			}
+			assert.Equal(t, tt.prefixes, prefixes)
+		})
+	}
+}
+
+func TestGetNetworks_Defaults(t *testing.T) {
+	var cfg AppConfig
+	cfg.P2pNetwork = "mainnet"
+	cfg.DB.URL = "mongodb://localhost:27017"
+	cfg.DB.Name = "wormscan"
+	cfg.Cache.TvlKey = "tvl"
+	cfg.Influx.URL = "http://localhost:8086"
+	cfg.Influx.Token = "token"
+	cfg.Influx.Organization = "xlabs"
+	cfg.Influx.Bucket24Hours = "24h"
+	cfg.Networks = []NetworkConfig{{P2pNetwork: "testnet"}}
+	cfg.Networks[0].DB.Name = "wormscan-testnet"
+	cfg.Networks[0].Cache.Prefix = "testnet"
+	cfg.Networks[0].Influx.Bucket24Hours = "24h-testnet"
+
+	networks := cfg.GetNetworks()
+	assert.Len(t, networks, 2)
+	assert.Equal(t, "mainnet", networks[0].P2pNetwork)
+	assert.Equal(t, "wormscan", networks[0].DB.Name)
+	assert.Equal(t, "24h", networks[0].Influx.Bucket24Hours)
+
+	// the empty fields of the additional networks are taken from the default network, except the buckets.
+	testnet := networks[1]
+	assert.Equal(t, "mongodb://localhost:27017", testnet.DB.URL)
+	assert.Equal(t, "wormscan-testnet", testnet.DB.Name)
+	assert.Equal(t, "tvl", testnet.Cache.TvlKey)
+	assert.Equal(t, "http://localhost:8086", testnet.Influx.URL)
+	assert.Equal(t, "token", testnet.Influx.Token)
+	assert.Equal(t, "xlabs", testnet.Influx.Organization)
+	assert.Equal(t, "24h-testnet", testnet.Influx.Bucket24Hours)
+}
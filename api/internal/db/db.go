@@ -4,13 +4,14 @@ package db
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Connect create a new mongo db client for the options defined in the input param url.
 func Connect(ctx context.Context, url string) (*mongo.Client, error) {
-	cli, err := mongo.NewClient(options.Client().ApplyURI(url))
+	cli, err := mongo.NewClient(options.Client().ApplyURI(url).SetMonitor(telemetry.NewMongoMonitor()))
 	if err != nil {
 		return cli, err
 	}
@@ -0,0 +1,212 @@
+// Package filter implements filters on the standardized properties of parsed VAAs.
+package filter
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Validation errors of a PayloadFilter.
+var (
+	ErrTokenAddressWithoutChain = errors.New("tokenAddress requires tokenChain")
+	ErrAmountWithoutToken       = errors.New("minAmount and maxAmount require tokenChain and tokenAddress")
+	ErrUsdAmountWithoutIndex    = errors.New("minUsdAmount and maxUsdAmount require fromChain, toChain, tokenChain or payloadType")
+	ErrInvalidAmountRange       = errors.New("minAmount is greater than maxAmount")
+	ErrInvalidUsdAmountRange    = errors.New("minUsdAmount is greater than maxUsdAmount")
+	ErrInvalidTimeRange         = errors.New("startTime is after endTime")
+)
+
+// PayloadFilter contains filters on the standardized properties of a parsed VAA.
+//
+// The filters are executed against the `parsedVaa` collection, and the valid combinations
+// are the ones supported by the indexes created in the `fly` migration:
+//   - standardizedProperties.fromChain, timestamp
+//   - standardizedProperties.toChain, timestamp
+//   - standardizedProperties.tokenChain, timestamp, standardizedProperties.tokenAddress
+//   - parsedPayload.payloadId, timestamp
+//   - timestamp
+type PayloadFilter struct {
+	FromChain    *sdk.ChainID
+	ToChain      *sdk.ChainID
+	TokenChain   *sdk.ChainID
+	TokenAddress string
+	// MinAmount and MaxAmount are compared against the normalized amount of the token (8 decimals).
+	MinAmount    *decimal.Decimal
+	MaxAmount    *decimal.Decimal
+	MinUsdAmount *decimal.Decimal
+	MaxUsdAmount *decimal.Decimal
+	StartTime    *time.Time
+	EndTime      *time.Time
+	PayloadType  *int
+}
+
+// IsEmpty returns true if no filter is set.
+func (f *PayloadFilter) IsEmpty() bool {
+	return f == nil || (!f.HasPayloadFilters() && f.StartTime == nil && f.EndTime == nil)
+}
+
+// HasPayloadFilters returns true if a filter other than the time range is set.
+//
+// The time range can be applied directly on the `vaas` collection, while the other
+// filters require a query on the `parsedVaa` collection.
+func (f *PayloadFilter) HasPayloadFilters() bool {
+	if f == nil {
+		return false
+	}
+	return f.FromChain != nil || f.ToChain != nil || f.TokenChain != nil || f.TokenAddress != "" ||
+		f.MinAmount != nil || f.MaxAmount != nil || f.MinUsdAmount != nil || f.MaxUsdAmount != nil ||
+		f.PayloadType != nil
+}
+
+// hasIndexedField returns true if a filter that is the prefix of an index is set.
+func (f *PayloadFilter) hasIndexedField() bool {
+	return f.FromChain != nil || f.ToChain != nil || f.TokenChain != nil || f.PayloadType != nil
+}
+
+// Validate rejects the combinations of filters that can not use an index.
+func (f *PayloadFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	if f.TokenAddress != "" && f.TokenChain == nil {
+		return ErrTokenAddressWithoutChain
+	}
+	// amounts are comparable only for the same token.
+	if (f.MinAmount != nil || f.MaxAmount != nil) && (f.TokenChain == nil || f.TokenAddress == "") {
+		return ErrAmountWithoutToken
+	}
+	// usd amounts are stored in another collection, so they are applied after an indexed filter.
+	if (f.MinUsdAmount != nil || f.MaxUsdAmount != nil) && !f.hasIndexedField() {
+		return ErrUsdAmountWithoutIndex
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && f.MinAmount.GreaterThan(*f.MaxAmount) {
+		return ErrInvalidAmountRange
+	}
+	if f.MinUsdAmount != nil && f.MaxUsdAmount != nil && f.MinUsdAmount.GreaterThan(*f.MaxUsdAmount) {
+		return ErrInvalidUsdAmountRange
+	}
+	if f.StartTime != nil && f.EndTime != nil && f.StartTime.After(*f.EndTime) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+// TimestampMatch returns the predicate of the time range on the `timestamp` field.
+// It returns nil if the time range is not set.
+func (f *PayloadFilter) TimestampMatch() bson.D {
+	if f == nil || (f.StartTime == nil && f.EndTime == nil) {
+		return nil
+	}
+	timestamp := bson.D{}
+	if f.StartTime != nil {
+		timestamp = append(timestamp, bson.E{Key: "$gte", Value: *f.StartTime})
+	}
+	if f.EndTime != nil {
+		timestamp = append(timestamp, bson.E{Key: "$lte", Value: *f.EndTime})
+	}
+	return bson.D{{Key: "timestamp", Value: timestamp}}
+}
+
+// ParsedVaaIDsPipeline returns an aggregation pipeline on the `parsedVaa` collection
+// that returns the IDs of the page of VAAs matching the filter, sorted by timestamp.
+func (f *PayloadFilter) ParsedVaaIDsPipeline(p *pagination.Pagination) mongo.Pipeline {
+
+	// filter by the indexed fields
+	match := bson.D{}
+	if f.FromChain != nil {
+		match = append(match, bson.E{Key: "standardizedProperties.fromChain", Value: *f.FromChain})
+	}
+	if f.ToChain != nil {
+		match = append(match, bson.E{Key: "standardizedProperties.toChain", Value: *f.ToChain})
+	}
+	if f.TokenChain != nil {
+		match = append(match, bson.E{Key: "standardizedProperties.tokenChain", Value: *f.TokenChain})
+	}
+	if f.TokenAddress != "" {
+		match = append(match, bson.E{Key: "standardizedProperties.tokenAddress", Value: bson.M{"$in": addressVariants(f.TokenAddress)}})
+	}
+	if f.PayloadType != nil {
+		match = append(match, bson.E{Key: "parsedPayload.payloadId", Value: *f.PayloadType})
+	}
+	match = append(match, f.TimestampMatch()...)
+
+	// amounts are stored as strings, so they are compared after the indexed filters.
+	if amountRange := decimalRange("$standardizedProperties.amount", f.MinAmount, f.MaxAmount); amountRange != nil {
+		match = append(match, bson.E{Key: "$expr", Value: amountRange})
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: p.GetSortInt()}}}},
+	}
+
+	// filter by the usd amount of the `transferPrices` collection
+	if usdAmountRange := decimalRange("$usdAmount", f.MinUsdAmount, f.MaxUsdAmount); usdAmountRange != nil {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.D{
+				{Key: "from", Value: "transferPrices"},
+				{Key: "localField", Value: "_id"},
+				{Key: "foreignField", Value: "_id"},
+				{Key: "as", Value: "transferPrices"},
+			}}},
+			bson.D{{Key: "$addFields", Value: bson.D{
+				{Key: "usdAmount", Value: bson.M{"$arrayElemAt": []interface{}{"$transferPrices.usdAmount", 0}}},
+			}}},
+			bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: usdAmountRange}}}},
+		)
+	}
+
+	return append(pipeline,
+		bson.D{{Key: "$skip", Value: p.Skip}},
+		bson.D{{Key: "$limit", Value: p.Limit}},
+		bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+	)
+}
+
+// decimalRange returns an expression that checks that the decimal value of a string field
+// is in the range [min, max]. It returns nil if the range is not set.
+func decimalRange(field string, min, max *decimal.Decimal) bson.D {
+	if min == nil && max == nil {
+		return nil
+	}
+	value := bson.D{{Key: "$convert", Value: bson.D{
+		{Key: "input", Value: field},
+		{Key: "to", Value: "decimal"},
+		{Key: "onError", Value: nil},
+		{Key: "onNull", Value: nil},
+	}}}
+	conditions := bson.A{bson.D{{Key: "$ne", Value: bson.A{value, nil}}}}
+	if min != nil {
+		conditions = append(conditions, bson.D{{Key: "$gte", Value: bson.A{value, toDecimal128(*min)}}})
+	}
+	if max != nil {
+		conditions = append(conditions, bson.D{{Key: "$lte", Value: bson.A{value, toDecimal128(*max)}}})
+	}
+	return bson.D{{Key: "$and", Value: conditions}}
+}
+
+func toDecimal128(d decimal.Decimal) primitive.Decimal128 {
+	// the string representation of a decimal is always a valid Decimal128.
+	d128, _ := primitive.ParseDecimal128(d.String())
+	return d128
+}
+
+// addressVariants returns the formats in which an address could be stored.
+//
+// Non-EVM addresses could be case sensitive (i.e. Solana), so they are kept as is.
+// EVM addresses are stored with a 0x prefix and all lowercase characters.
+func addressVariants(address string) []string {
+	if utils.StartsWith0x(address) {
+		return []string{address, strings.ToLower(address)}
+	}
+	return []string{address, "0x" + strings.ToLower(address)}
+}
@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/pagination"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPayloadFilter_Validate(t *testing.T) {
+	chain := sdk.ChainIDEthereum
+	ten := decimal.NewFromInt(10)
+	one := decimal.NewFromInt(1)
+	start := time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		filter *PayloadFilter
+		err    error
+	}{
+		{name: "empty", filter: &PayloadFilter{}},
+		{name: "nil", filter: nil},
+		{name: "token", filter: &PayloadFilter{TokenChain: &chain, TokenAddress: "0xdac17f958d2ee523a2206206994597c13d831ec7", MinAmount: &one, MaxAmount: &ten}},
+		{name: "usd amount with chain", filter: &PayloadFilter{ToChain: &chain, MinUsdAmount: &one}},
+		{name: "token address without chain", filter: &PayloadFilter{TokenAddress: "0x01"}, err: ErrTokenAddressWithoutChain},
+		{name: "amount without token", filter: &PayloadFilter{TokenChain: &chain, MinAmount: &ten}, err: ErrAmountWithoutToken},
+		{name: "usd amount without index", filter: &PayloadFilter{MinUsdAmount: &ten, StartTime: &start}, err: ErrUsdAmountWithoutIndex},
+		{name: "invalid usd amount range", filter: &PayloadFilter{FromChain: &chain, MinUsdAmount: &ten, MaxUsdAmount: &one}, err: ErrInvalidUsdAmountRange},
+		{name: "invalid time range", filter: &PayloadFilter{StartTime: &start, EndTime: &end}, err: ErrInvalidTimeRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.filter.Validate())
+		})
+	}
+}
+
+func TestPayloadFilter_HasPayloadFilters(t *testing.T) {
+	start := time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC)
+	payloadType := 3
+
+	var nilFilter *PayloadFilter
+	assert.True(t, nilFilter.IsEmpty())
+	assert.Nil(t, nilFilter.TimestampMatch())
+
+	timeRange := &PayloadFilter{StartTime: &start}
+	assert.False(t, timeRange.IsEmpty())
+	assert.False(t, timeRange.HasPayloadFilters())
+	assert.Equal(t, bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: start}}}}, timeRange.TimestampMatch())
+
+	byPayloadType := &PayloadFilter{PayloadType: &payloadType}
+	assert.True(t, byPayloadType.HasPayloadFilters())
+}
+
+func TestPayloadFilter_ParsedVaaIDsPipeline(t *testing.T) {
+	chain := sdk.ChainIDSolana
+	min := decimal.NewFromInt(1000)
+	f := &PayloadFilter{ToChain: &chain, MinUsdAmount: &min}
+
+	pipeline := f.ParsedVaaIDsPipeline(pagination.Default())
+
+	// match, sort, lookup, addFields, match, skip, limit and project stages.
+	assert.Len(t, pipeline, 8)
+	assert.Equal(t, bson.D{{Key: "$match", Value: bson.D{{Key: "standardizedProperties.toChain", Value: chain}}}}, pipeline[0])
+	assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: "timestamp", Value: -1}}}}, pipeline[1])
+	assert.Equal(t, "$lookup", pipeline[2][0].Key)
+	assert.Equal(t, bson.D{{Key: "$limit", Value: int64(50)}}, pipeline[6])
+}
@@ -17,34 +17,32 @@ import (
 	"github.com/gofiber/adaptor/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/address"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/infrastructure"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/observations"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
-	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	apiAlert "github.com/wormhole-foundation/wormhole-explorer/api/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/config"
-	"github.com/wormhole-foundation/wormhole-explorer/api/internal/db"
-	"github.com/wormhole-foundation/wormhole-explorer/api/internal/tvl"
 	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/guardian"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan"
 	rpcApi "github.com/wormhole-foundation/wormhole-explorer/api/rpc"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	wormscanCache "github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/invalidation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
 	xlogger "github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/common/utils"
 	"go.uber.org/zap"
 )
 
+// guardianHealthCheckInterval is the interval between checks of the health of the guardians.
+const guardianHealthCheckInterval = time.Minute
+
 //go:embed docs/swagger.json
 var swagger []byte
 
@@ -101,57 +99,35 @@ func main() {
 	rootLogger := xlogger.New("wormhole-api", xlogger.WithLevel(cfg.LogLevel))
 	defer rootLogger.Sync()
 
-	// Setup DB
-	rootLogger.Info("connecting to MongoDB")
-	cli, err := db.Connect(appCtx, cfg.DB.URL)
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(appCtx, "wormscan-api")
 	if err != nil {
-		rootLogger.Fatal("failed to connect to MongoDB", zap.Error(err))
+		rootLogger.Fatal("failed to set up tracing", zap.Error(err))
 	}
-	db := cli.Database(cfg.DB.Name)
+	defer shutdownTracing(context.Background())
 
-	// Get cache get function
-	rootLogger.Info("initializing cache")
-	cache, err := NewCache(appCtx, cfg, rootLogger)
+	// Set up alert client
+	alertClient, err := newAlertClient(cfg)
 	if err != nil {
-		rootLogger.Fatal("failed to initialize cache", zap.Error(err))
+		rootLogger.Fatal("failed to create alert client", zap.Error(err))
 	}
 
-	// cfg.Cache.Expiration
-	rootLogger.Info("initializing TVL cache")
-	tvl := tvl.NewTVL(cfg.P2pNetwork, cache, cfg.Cache.TvlKey, cfg.Cache.TvlExpiration, rootLogger)
-
-	//InfluxDB client
-	rootLogger.Info("initializing InfluxDB client")
-	influxCli := newInfluxClient(cfg.Influx.URL, cfg.Influx.Token)
-
-	// Set up repositories
-	rootLogger.Info("initializing repositories")
-	addressRepo := address.NewRepository(db, rootLogger)
-	vaaRepo := vaa.NewRepository(db, rootLogger)
-	obsRepo := observations.NewRepository(db, rootLogger)
-	governorRepo := governor.NewRepository(db, rootLogger)
-	infrastructureRepo := infrastructure.NewRepository(db, rootLogger)
-	heartbeatsRepo := heartbeats.NewRepository(db, rootLogger)
-	transactionsRepo := transactions.NewRepository(
-		tvl,
-		influxCli,
-		cfg.Influx.Organization,
-		cfg.Influx.Bucket24Hours,
-		cfg.Influx.Bucket30Days,
-		cfg.Influx.BucketInfinite,
-		db,
-		rootLogger,
-	)
-
-	// Set up services
-	rootLogger.Info("initializing services")
-	addressService := address.NewService(addressRepo, rootLogger)
-	vaaService := vaa.NewService(vaaRepo, cache.Get, rootLogger)
-	obsService := observations.NewService(obsRepo, rootLogger)
-	governorService := governor.NewService(governorRepo, rootLogger)
-	infrastructureService := infrastructure.NewService(infrastructureRepo, rootLogger)
-	heartbeatsService := heartbeats.NewService(heartbeatsRepo, rootLogger)
-	transactionsService := transactions.NewService(transactionsRepo, cache, time.Duration(cfg.Cache.MetricExpiration)*time.Second, rootLogger)
+	// Set up the clients and services of each network
+	networks := make([]*networkContext, 0, len(cfg.Networks)+1)
+	for _, n := range cfg.GetNetworks() {
+		rootLogger.Info("initializing network", zap.String("network", n.P2pNetwork))
+		network, err := newNetworkContext(appCtx, cfg, n, alertClient, rootLogger)
+		if err != nil {
+			rootLogger.Fatal("failed to initialize network", zap.String("network", n.P2pNetwork), zap.Error(err))
+		}
+		networks = append(networks, network)
+	}
+	defaultNetwork := networks[0]
+
+	// Monitor the health of the guardians of each network
+	for _, n := range networks {
+		go n.heartbeatsService.MonitorGuardianHealth(appCtx, guardianHealthCheckInterval)
+	}
 
 	// Set up a custom error handler
 	response.SetEnableStackTrace(*cfg)
@@ -167,6 +143,7 @@ func main() {
 	app.Use(prometheus.Middleware)
 
 	app.Use(requestid.New())
+	app.Use(middleware.Tracing())
 	app.Use(logger.New(logger.Config{
 		Format: "level=info timestamp=${time} method=${method} path=${path} status${status} request_id=${locals:requestid}\n",
 	}))
@@ -184,13 +161,24 @@ func main() {
 		app.Use(rl)
 	}
 
+	// Select the network by header for clients that don't use the network path prefix
+	p2pNetworks := make([]string, 0, len(networks))
+	for _, n := range networks {
+		p2pNetworks = append(p2pNetworks, n.p2pNetwork)
+	}
+	app.Use(middleware.SelectNetwork([]string{"/api/v1", "/v1"}, p2pNetworks))
+
 	// Set up route handlers
 	app.Get("/swagger.json", GetSwagger)
-	wormscan.RegisterRoutes(app, rootLogger, addressService, vaaService, obsService, governorService, infrastructureService, transactionsService)
-	guardian.RegisterRoutes(cfg, app, rootLogger, vaaService, governorService, heartbeatsService)
+	for _, n := range networks {
+		wormscan.RegisterRoutes(app, "/api/v1/"+n.p2pNetwork, n.p2pNetwork, p2pNetworks, rootLogger, n.addressService, n.vaaService, n.obsService, n.governorService, n.infrastructureService, n.transactionsService, n.heartbeatsService, n.tokensService, n.labelsService, n.governanceService, cfg.Admin.ApiKey)
+		guardian.RegisterRoutes(app, "/v1/"+n.p2pNetwork, n.p2pNetwork, rootLogger, n.vaaService, n.governorService, n.heartbeatsService)
+	}
+	wormscan.RegisterRoutes(app, "/api/v1", defaultNetwork.p2pNetwork, p2pNetworks, rootLogger, defaultNetwork.addressService, defaultNetwork.vaaService, defaultNetwork.obsService, defaultNetwork.governorService, defaultNetwork.infrastructureService, defaultNetwork.transactionsService, defaultNetwork.heartbeatsService, defaultNetwork.tokensService, defaultNetwork.labelsService, defaultNetwork.governanceService, cfg.Admin.ApiKey)
+	guardian.RegisterRoutes(app, "/v1", defaultNetwork.p2pNetwork, rootLogger, defaultNetwork.vaaService, defaultNetwork.governorService, defaultNetwork.heartbeatsService)
 
-	// Set up gRPC handlers
-	handler := rpcApi.NewHandler(vaaService, heartbeatsService, governorService, rootLogger, cfg.P2pNetwork)
+	// Set up gRPC handlers, only the default network is served over gRPC.
+	handler := rpcApi.NewHandler(defaultNetwork.vaaService, defaultNetwork.heartbeatsService, defaultNetwork.governorService, rootLogger, defaultNetwork.p2pNetwork)
 	grpcServer := rpcApi.NewServer(handler, rootLogger)
 	grpcWebServer := grpcweb.WrapServer(grpcServer)
 	app.Use(
@@ -225,13 +213,15 @@ func main() {
 	rootLogger.Info("cleanup tasks...")
 	rootLogger.Info("shutting down server...")
 	app.Shutdown()
-	rootLogger.Info("closing cache...")
-	cache.Close()
+	rootLogger.Info("closing network clients...")
+	for _, n := range networks {
+		n.Close(context.Background())
+	}
 	rootLogger.Info("terminated API service successfully")
 }
 
 // NewCache get a CacheGetFunc to get a value by a Key from cache and a CacheReadable to get a value by a Key from notional local cache.
-func NewCache(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (wormscanCache.Cache, error) {
+func NewCache(ctx context.Context, cfg *config.AppConfig, prefix string, logger *zap.Logger) (wormscanCache.Cache, error) {
 
 	// if run mode is development with cache is disabled, return a dummy cache client and a dummy notional cache client.
 	if cfg.RunMode == config.RunModeDevelopmernt && !cfg.Cache.Enabled {
@@ -243,7 +233,7 @@ func NewCache(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (w
 	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Cache.URL})
 
 	// get cache client
-	cacheClient, err := wormscanCache.NewCacheClient(redisClient, cfg.Cache.Enabled, cfg.Cache.Prefix, logger)
+	cacheClient, err := wormscanCache.NewCacheClient(redisClient, cfg.Cache.Enabled, prefix, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize cache client: %w", err)
 	}
@@ -251,10 +241,56 @@ func NewCache(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (w
 	return cacheClient, nil
 }
 
+// NewNotionalCache get a local cache of the token prices, synchronized with the notional job by pubsub.
+func NewNotionalCache(ctx context.Context, cfg *config.AppConfig, prefix string, logger *zap.Logger) (notional.NotionalLocalCacheReadable, error) {
+
+	// if run mode is development with cache is disabled, return a dummy notional cache client.
+	if cfg.RunMode == config.RunModeDevelopmernt && !cfg.Cache.Enabled {
+		return notional.NewDummyNotionalCache(), nil
+	}
+
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Cache.URL})
+	notionalCache, err := notional.NewNotionalCache(ctx, redisClient, prefix, cfg.Cache.NotionalChannel, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize notional cache: %w", err)
+	}
+	if err := notionalCache.Init(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load notional cache: %w", err)
+	}
+
+	return notionalCache, nil
+}
+
+// NewInvalidationSubscriber subscribes to the cache invalidation events that the pipeline publishes when new VAAs are stored.
+// It returns nil if run mode is development with cache disabled.
+func NewInvalidationSubscriber(ctx context.Context, cfg *config.AppConfig, p2pNetwork string, logger *zap.Logger) (*invalidation.Subscriber, error) {
+
+	if cfg.RunMode == config.RunModeDevelopmernt && !cfg.Cache.Enabled {
+		return nil, nil
+	}
+
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.Cache.URL})
+	return invalidation.NewSubscriber(ctx, redisClient, cfg.Cache.InvalidationChannel, p2pNetwork, logger)
+}
+
 func newInfluxClient(url, token string) influxdb2.Client {
 	return influxdb2.NewClient(url, token)
 }
 
+func newAlertClient(cfg *config.AppConfig) (alert.AlertClient, error) {
+	if !cfg.Alert.Enabled {
+		return alert.NewDummyClient(), nil
+	}
+
+	alertConfig := alert.AlertConfig{
+		Environment: cfg.Environment,
+		ApiKey:      cfg.Alert.ApiKey,
+		Enabled:     cfg.Alert.Enabled,
+	}
+
+	return alert.NewAlertService(alertConfig, apiAlert.LoadAlerts)
+}
+
 func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logger) (func(*fiber.Ctx) error, error) {
 
 	if cfg.RateLimit.Prefix != "" {
@@ -281,7 +317,8 @@ func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logg
 
 	logger.Info("rate limit enabled", zap.Int("max requests per minute", cfg.RateLimit.Max))
 
-	router := limiter.New(limiter.Config{
+	// batch requests count against the limit proportionally to the number of IDs.
+	router := middleware.RateLimit(middleware.RateLimitConfig{
 		Next: func(c *fiber.Ctx) bool {
 
 			ip := utils.GetRealIp(c)
@@ -292,9 +329,7 @@ func NewRateLimiter(ctx context.Context, cfg *config.AppConfig, logger *zap.Logg
 		KeyGenerator: func(c *fiber.Ctx) string {
 			return utils.GetRealIp(c)
 		},
-		LimitReached: func(c *fiber.Ctx) error {
-			return c.SendStatus(fiber.StatusTooManyRequests)
-		},
+		Cost:    middleware.BatchRequestCost,
 		Storage: store,
 	})
 
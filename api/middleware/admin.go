@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+)
+
+// AdminAuth returns a middleware that only accepts requests with the header `Authorization: Bearer <apiKey>`.
+func AdminAuth(apiKey string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if apiKey == "" || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
+			return response.NewApiError(c, fiber.StatusUnauthorized, response.Unauthenticated, "UNAUTHORIZED", nil)
+		}
+		return c.Next()
+	}
+}
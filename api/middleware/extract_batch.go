@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"github.com/wormhole-foundation/wormhole-explorer/api/types"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// MaxBatchSize is the maximum number of IDs of a batch request.
+const MaxBatchSize = 50
+
+// BatchRequest is the body of a batch request.
+type BatchRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchID is a VAA ID of a batch request.
+type BatchID struct {
+	// Key is the ID as it was sent by the client, used to index the results.
+	Key      string
+	ChainID  sdk.ChainID
+	Emitter  *types.Address
+	Sequence uint64
+}
+
+// ID returns the VAA ID in the format stored in the database.
+func (b *BatchID) ID() string {
+	return fmt.Sprintf("%d/%s/%d", b.ChainID, b.Emitter.Hex(), b.Sequence)
+}
+
+// ExtractBatchIDs parses the VAA IDs (chain/emitter/sequence) of the body of a batch request.
+// Duplicated IDs are returned once.
+func ExtractBatchIDs(c *fiber.Ctx, l *zap.Logger) ([]*BatchID, error) {
+
+	var req BatchRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return nil, response.NewInvalidParamError(c, "INVALID BODY", errors.WithStack(err))
+	}
+	if len(req.IDs) == 0 {
+		return nil, response.NewInvalidParamError(c, "MISSING IDS", nil)
+	}
+	if len(req.IDs) > MaxBatchSize {
+		return nil, response.NewInvalidParamError(c, fmt.Sprintf("TOO MANY IDS, MAX %d", MaxBatchSize), nil)
+	}
+
+	ids := make([]*BatchID, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+	for _, key := range req.IDs {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		id, err := parseBatchID(key)
+		if err != nil {
+			requestID := fmt.Sprintf("%v", c.Locals("requestid"))
+			l.Error("failed to parse batch id",
+				zap.Error(err),
+				zap.String("id", key),
+				zap.String("requestID", requestID),
+			)
+			return nil, response.NewInvalidParamError(c, fmt.Sprintf("MALFORMED ID %s", key), errors.WithStack(err))
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func parseBatchID(key string) (*BatchID, error) {
+
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		return nil, errors.New("id must have the format chain/emitter/sequence")
+	}
+
+	chain, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	chainID := sdk.ChainID(chain)
+
+	emitter, err := types.StringToAddress(parts[1], chainID == sdk.ChainIDSolana)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BatchID{Key: key, ChainID: chainID, Emitter: emitter, Sequence: seq}, nil
+}
+
+// BatchRequestCost returns the number of requests a batch request counts as for the rate limiter,
+// which is the number of IDs of the batch. Other requests count as one.
+func BatchRequestCost(c *fiber.Ctx) int {
+
+	if c.Method() != http.MethodPost || !strings.HasSuffix(c.Path(), "/batch") {
+		return 1
+	}
+
+	// malformed requests are rejected by the handler.
+	var req BatchRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil || len(req.IDs) == 0 || len(req.IDs) > MaxBatchSize {
+		return 1
+	}
+	return len(req.IDs)
+}
@@ -10,7 +10,11 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/filter"
 	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"github.com/wormhole-foundation/wormhole-explorer/api/types"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -360,6 +364,42 @@ func ExtractTopStatisticsTimeSpan(ctx *fiber.Ctx) (*transactions.TopStatisticsTi
 	return timeSpan, nil
 }
 
+// ExtractActivityRange parses the `from`, `to` and `granularity` query parameters of an arbitrary time range.
+//
+// It returns nil if neither `from` nor `to` are set. When only `from` is set, the range ends at the current time.
+func ExtractActivityRange(c *fiber.Ctx, l *zap.Logger) (*transactions.ActivityRange, error) {
+
+	from, err := ExtractTime(c, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := ExtractTime(c, "to")
+	if err != nil {
+		return nil, err
+	}
+	if from == nil && to == nil {
+		return nil, nil
+	}
+	if from == nil {
+		return nil, response.NewInvalidQueryParamError(c, "MISSING <from> QUERY PARAMETER", nil)
+	}
+
+	granularity, err := transactions.ParseActivityGranularity(c.Query("granularity", string(transactions.ActivityGranularity1d)))
+	if err != nil {
+		return nil, response.NewInvalidQueryParamError(c, "INVALID <granularity> QUERY PARAMETER", nil)
+	}
+
+	now := time.Now()
+	if to == nil {
+		to = &now
+	}
+	activityRange, err := transactions.NewActivityRange(*from, *to, granularity, now)
+	if err != nil {
+		return nil, response.NewInvalidQueryParamError(c, strings.ToUpper(err.Error()), errors.WithStack(err))
+	}
+	return activityRange, nil
+}
+
 // ExtractTokenAddress get token address from route path.
 func ExtractTokenAddress(c *fiber.Ctx, l *zap.Logger) (*types.Address, error) {
 	strTokenAddress := c.Params("token_address")
@@ -375,3 +415,140 @@ func ExtractTokenAddress(c *fiber.Ctx, l *zap.Logger) (*types.Address, error) {
 	}
 	return tokenAddress, nil
 }
+
+// ExtractPayloadFilter parses the filters on the standardized properties of the VAA payload from query params.
+//
+// The combinations of filters that can not use an index are rejected.
+func ExtractPayloadFilter(c *fiber.Ctx, l *zap.Logger) (*filter.PayloadFilter, error) {
+
+	var f filter.PayloadFilter
+	var err error
+
+	if f.FromChain, err = extractChainIDFromQueryParams(c, "fromChain"); err != nil {
+		return nil, err
+	}
+	if f.ToChain, err = extractChainIDFromQueryParams(c, "toChain"); err != nil {
+		return nil, err
+	}
+	if f.TokenChain, err = extractChainIDFromQueryParams(c, "tokenChain"); err != nil {
+		return nil, err
+	}
+	f.TokenAddress = c.Query("tokenAddress")
+
+	if f.MinAmount, err = extractDecimalFromQueryParams(c, "minAmount"); err != nil {
+		return nil, err
+	}
+	if f.MaxAmount, err = extractDecimalFromQueryParams(c, "maxAmount"); err != nil {
+		return nil, err
+	}
+	if f.MinUsdAmount, err = extractDecimalFromQueryParams(c, "minUsdAmount"); err != nil {
+		return nil, err
+	}
+	if f.MaxUsdAmount, err = extractDecimalFromQueryParams(c, "maxUsdAmount"); err != nil {
+		return nil, err
+	}
+	if f.StartTime, err = ExtractTime(c, "startTime"); err != nil {
+		return nil, err
+	}
+	if f.EndTime, err = ExtractTime(c, "endTime"); err != nil {
+		return nil, err
+	}
+
+	if payloadType := c.Query("payloadType"); payloadType != "" {
+		value, err := strconv.Atoi(payloadType)
+		if err != nil {
+			return nil, response.NewInvalidQueryParamError(c, "INVALID <payloadType> QUERY PARAMETER", errors.WithStack(err))
+		}
+		f.PayloadType = &value
+	}
+
+	if err := f.Validate(); err != nil {
+		requestID := fmt.Sprintf("%v", c.Locals("requestid"))
+		l.Info("invalid combination of payload filters",
+			zap.Error(err),
+			zap.String("requestID", requestID),
+		)
+		return nil, response.NewInvalidQueryParamError(c, strings.ToUpper(err.Error()), nil)
+	}
+
+	return &f, nil
+}
+
+func extractChainIDFromQueryParams(c *fiber.Ctx, queryParam string) (*sdk.ChainID, error) {
+	value := c.Query(queryParam)
+	if value == "" {
+		return nil, nil
+	}
+	chain, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return nil, response.NewInvalidQueryParamError(c, fmt.Sprintf("INVALID <%s> QUERY PARAMETER", queryParam), errors.WithStack(err))
+	}
+	chainID := sdk.ChainID(chain)
+	return &chainID, nil
+}
+
+func extractDecimalFromQueryParams(c *fiber.Ctx, queryParam string) (*decimal.Decimal, error) {
+	value := c.Query(queryParam)
+	if value == "" {
+		return nil, nil
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil || d.IsNegative() {
+		return nil, response.NewInvalidQueryParamError(c, fmt.Sprintf("INVALID <%s> QUERY PARAMETER", queryParam), nil)
+	}
+	return &d, nil
+}
+
+// ExtractLabelsQuery parses the query parameters of a search over the address labels.
+func ExtractLabelsQuery(c *fiber.Ctx, l *zap.Logger) (*labels.SearchQuery, error) {
+
+	pagination, err := ExtractPagination(c)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID, err := extractChainIDFromQueryParams(c, "chain")
+	if err != nil {
+		return nil, err
+	}
+
+	var category labels.Category
+	if value := c.Query("category"); value != "" {
+		category, err = labels.ParseCategory(value)
+		if err != nil {
+			return nil, response.NewInvalidQueryParamError(c, "INVALID <category> QUERY PARAMETER", errors.WithStack(err))
+		}
+	}
+
+	q := labels.SearchQuery{
+		Text:     c.Query("q"),
+		ChainID:  chainID,
+		Category: category,
+		AppID:    c.Query("appId"),
+		Skip:     pagination.Skip,
+		Limit:    pagination.Limit,
+	}
+	return &q, nil
+}
+
+// ExtractGovernanceQuery parses the query parameters of a search over the governance VAAs.
+func ExtractGovernanceQuery(c *fiber.Ctx, l *zap.Logger) (*governance.GovernanceQuery, error) {
+
+	pagination, err := ExtractPagination(c)
+	if err != nil {
+		return nil, err
+	}
+
+	targetChain, err := extractChainIDFromQueryParams(c, "targetChain")
+	if err != nil {
+		return nil, err
+	}
+
+	q := governance.GovernanceQuery{
+		Pagination:  *pagination,
+		Module:      c.Query("module"),
+		Action:      c.Query("action"),
+		TargetChain: targetChain,
+	}
+	return &q, nil
+}
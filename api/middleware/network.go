@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+)
+
+// NetworkHeader is the request header used to select the network that serves a request.
+const NetworkHeader = "X-Wormscan-Network"
+
+// NetworkQuery is the query parameter used to select the network, if the NetworkHeader header is not set.
+const NetworkQuery = "network"
+
+// SelectNetwork routes the request to the network defined in the NetworkHeader header or the NetworkQuery parameter.
+//
+// The network routes are mounted under a path prefix (e.g.: `/api/v1/testnet/...`),
+// so the request path is rewritten to include the prefix of the selected network.
+// Requests without a selected network, or that already have a network prefix, are not modified.
+func SelectNetwork(basePaths []string, networks []string) fiber.Handler {
+
+	supported := make(map[string]bool, len(networks))
+	for _, n := range networks {
+		supported[n] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		network := c.Get(NetworkHeader)
+		if network == "" {
+			network = c.Query(NetworkQuery)
+		}
+		if network == "" {
+			return c.Next()
+		}
+		if !supported[network] {
+			return response.NewInvalidParamError(c, fmt.Sprintf("UNSUPPORTED NETWORK %s", network), nil)
+		}
+
+		path := c.Path()
+		for _, basePath := range basePaths {
+			if !strings.HasPrefix(path, basePath+"/") {
+				continue
+			}
+			rest := strings.TrimPrefix(path, basePath)
+			if hasNetworkPrefix(rest, supported) {
+				break
+			}
+			c.Path(basePath + "/" + network + rest)
+			break
+		}
+
+		return c.Next()
+	}
+}
+
+// hasNetworkPrefix returns true if the path starts with a network segment.
+func hasNetworkPrefix(path string, networks map[string]bool) bool {
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	return networks[segments[0]]
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectNetwork(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(SelectNetwork([]string{"/api/v1", "/v1"}, []string{"mainnet", "testnet"}))
+	app.Get("/*", func(c *fiber.Ctx) error { return c.SendString(c.Path()) })
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		status int
+		path   string
+	}{
+		{name: "no network", target: "/api/v1/vaas", status: fiber.StatusOK, path: "/api/v1/vaas"},
+		{name: "header", target: "/api/v1/vaas", header: "testnet", status: fiber.StatusOK, path: "/api/v1/testnet/vaas"},
+		{name: "query", target: "/v1/governor?network=testnet", status: fiber.StatusOK, path: "/v1/testnet/governor"},
+		{name: "header before query", target: "/api/v1/vaas?network=testnet", header: "mainnet", status: fiber.StatusOK, path: "/api/v1/mainnet/vaas"},
+		{name: "network prefix", target: "/api/v1/testnet/vaas", header: "mainnet", status: fiber.StatusOK, path: "/api/v1/testnet/vaas"},
+		{name: "path without network routes", target: "/version", header: "testnet", status: fiber.StatusOK, path: "/version"},
+		{name: "unknown network header", target: "/api/v1/vaas", header: "devnet", status: fiber.StatusBadRequest},
+		{name: "unknown network query", target: "/api/v1/vaas?network=devnet", status: fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(NetworkHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status == fiber.StatusOK {
+				body, err := io.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.path, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"encoding/binary"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+)
+
+// headers of the rate limiter, the same used by the fiber limiter.
+const (
+	xRateLimitLimit     = "X-RateLimit-Limit"
+	xRateLimitRemaining = "X-RateLimit-Remaining"
+	xRateLimitReset     = "X-RateLimit-Reset"
+)
+
+// RateLimitConfig defines the config of the rate limiter middleware.
+type RateLimitConfig struct {
+	// Max is the number of requests allowed per client within the expiration window.
+	Max int
+	// Expiration is the duration of the window.
+	Expiration time.Duration
+	// Storage keeps the counters of the clients.
+	Storage fiber.Storage
+	// Next defines a function to skip the middleware when it returns true.
+	Next func(c *fiber.Ctx) bool
+	// KeyGenerator returns the key of the client of a request.
+	KeyGenerator func(c *fiber.Ctx) string
+	// Cost returns the number of requests a request counts as. By default every request counts as one.
+	Cost func(c *fiber.Ctx) int
+}
+
+// RateLimit limits the number of requests of each client within a fixed window.
+//
+// Unlike the fiber limiter, a request can count as more than one request, so batch requests
+// count against the limit proportionally to their size.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+
+	var mu sync.Mutex
+	return func(c *fiber.Ctx) error {
+
+		if cfg.Next != nil && cfg.Next(c) {
+			return c.Next()
+		}
+
+		cost := 1
+		if cfg.Cost != nil {
+			cost = cfg.Cost(c)
+		}
+		// a request that costs more than the whole window could never be served.
+		if cost > cfg.Max {
+			cost = cfg.Max
+		}
+		if cost < 1 {
+			cost = 1
+		}
+
+		key := cfg.KeyGenerator(c)
+		now := time.Now()
+
+		mu.Lock()
+		w, err := getRateLimitWindow(cfg.Storage, key)
+		if err != nil {
+			mu.Unlock()
+			return err
+		}
+		if !now.Before(w.expiresAt) {
+			w = rateLimitWindow{expiresAt: now.Add(cfg.Expiration)}
+		}
+
+		// rejected requests do not consume the remaining requests of the window.
+		allowed := w.hits+cost <= cfg.Max
+		if allowed {
+			w.hits += cost
+			err = cfg.Storage.Set(key, w.encode(), w.expiresAt.Sub(now))
+		}
+		mu.Unlock()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		resetIn := strconv.FormatInt(int64(w.expiresAt.Sub(now).Seconds()), 10)
+		c.Set(xRateLimitLimit, strconv.Itoa(cfg.Max))
+		c.Set(xRateLimitRemaining, strconv.Itoa(cfg.Max-w.hits))
+		c.Set(xRateLimitReset, resetIn)
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, resetIn)
+			return c.SendStatus(fiber.StatusTooManyRequests)
+		}
+
+		return c.Next()
+	}
+}
+
+// rateLimitWindow is the number of requests of a client within a window.
+type rateLimitWindow struct {
+	hits      int
+	expiresAt time.Time
+}
+
+func getRateLimitWindow(storage fiber.Storage, key string) (rateLimitWindow, error) {
+	b, err := storage.Get(key)
+	if err != nil {
+		return rateLimitWindow{}, errors.WithStack(err)
+	}
+	if len(b) != 16 {
+		return rateLimitWindow{}, nil
+	}
+	return rateLimitWindow{
+		hits:      int(binary.BigEndian.Uint64(b[:8])),
+		expiresAt: time.Unix(0, int64(binary.BigEndian.Uint64(b[8:]))),
+	}, nil
+}
+
+func (w rateLimitWindow) encode() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], uint64(w.hits))
+	binary.BigEndian.PutUint64(b[8:], uint64(w.expiresAt.UnixNano()))
+	return b
+}
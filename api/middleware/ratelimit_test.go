@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStorage is a fiber.Storage that ignores the expiration of the keys.
+type memoryStorage map[string][]byte
+
+func (m memoryStorage) Get(key string) ([]byte, error)                    { return m[key], nil }
+func (m memoryStorage) Set(key string, val []byte, _ time.Duration) error { m[key] = val; return nil }
+func (m memoryStorage) Delete(key string) error                           { delete(m, key); return nil }
+func (m memoryStorage) Reset() error                                      { return nil }
+func (m memoryStorage) Close() error                                      { return nil }
+
+func TestRateLimit_BatchCost(t *testing.T) {
+	app := fiber.New()
+	app.Use(RateLimit(RateLimitConfig{
+		Max:          5,
+		Expiration:   time.Minute,
+		Storage:      memoryStorage{},
+		KeyGenerator: func(c *fiber.Ctx) string { return "client" },
+		Cost:         BatchRequestCost,
+	}))
+	app.Get("/vaas", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Post("/vaas/batch", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	batch := func(ids ...string) int {
+		body := `{"ids": ["` + strings.Join(ids, `","`) + `"]}`
+		req := httptest.NewRequest(fiber.MethodPost, "/vaas/batch", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// a batch of 3 IDs counts as 3 requests.
+	assert.Equal(t, fiber.StatusOK, batch("2/a/1", "2/a/2", "2/a/3"))
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/vaas", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "1", resp.Header.Get(xRateLimitRemaining))
+
+	// a rejected batch does not consume the remaining requests.
+	assert.Equal(t, fiber.StatusTooManyRequests, batch("2/a/1", "2/a/2"))
+	assert.Equal(t, fiber.StatusOK, batch("2/a/1"))
+	assert.Equal(t, fiber.StatusTooManyRequests, batch("2/a/1"))
+}
+
+func TestExtractBatchIDs_parseBatchID(t *testing.T) {
+	id, err := parseBatchID("2/0x0000000000000000000000003ee18B2214AFF97000D974cf647E7C347E8fa585/10")
+	assert.NoError(t, err)
+	assert.Equal(t, "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/10", id.ID())
+
+	for _, key := range []string{"2/0x01", "x/0x01/1", "2/0x01/-1", "2/zz/1"} {
+		_, err := parseBatchID(key)
+		assert.Error(t, err, key)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Tracing starts a span for each request, continuing the trace propagated in the request headers.
+//
+// The span context is stored in the request context, so the database commands executed
+// by the handlers are traced as children of the request span.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := telemetry.ExtractHeaders(c.UserContext(), func(key string) string { return c.Get(key) })
+		ctx, span := telemetry.StartSpan(ctx, c.Method()+" "+c.Path())
+		c.SetUserContext(ctx)
+		c.Locals(telemetry.SpanContextKey, telemetry.SpanContext(ctx))
+
+		err := c.Next()
+
+		// the route is only known after the request is routed.
+		span.SetName(c.Method() + " " + c.Route().Path)
+		span.SetAttributes(
+			attribute.String("http.method", c.Method()),
+			attribute.String("http.route", c.Route().Path),
+			attribute.Int("http.status_code", c.Response().StatusCode()),
+			attribute.String("request.id", fmt.Sprintf("%v", c.Locals("requestid"))),
+		)
+		telemetry.EndSpan(span, err)
+		return err
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/cacheable"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/address"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/infrastructure"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/observations"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/tokens"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/config"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/db"
+	"github.com/wormhole-foundation/wormhole-explorer/api/internal/tvl"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	wormscanCache "github.com/wormhole-foundation/wormhole-explorer/common/client/cache"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/invalidation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/notional"
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// networkContext contains the clients and services that serve the data of a single network.
+type networkContext struct {
+	p2pNetwork            string
+	db                    *mongo.Client
+	cache                 wormscanCache.Cache
+	notionalCache         notional.NotionalLocalCacheReadable
+	invalidations         *invalidation.Subscriber
+	influxCli             influxdb2.Client
+	addressService        *address.Service
+	vaaService            *vaa.Service
+	obsService            *observations.Service
+	governorService       *governor.Service
+	infrastructureService *infrastructure.Service
+	heartbeatsService     *heartbeats.Service
+	transactionsService   *transactions.Service
+	tokensService         *tokens.Service
+	labelsService         *labels.Service
+	governanceService     *governance.Service
+}
+
+// newNetworkContext connects to the database, cache and influx of a network and sets up its services.
+func newNetworkContext(
+	ctx context.Context,
+	cfg *config.AppConfig,
+	network config.NetworkConfig,
+	alertClient alert.AlertClient,
+	rootLogger *zap.Logger,
+) (*networkContext, error) {
+
+	logger := rootLogger.With(zap.String("network", network.P2pNetwork))
+
+	// Setup DB
+	logger.Info("connecting to MongoDB")
+	cli, err := db.Connect(ctx, network.DB.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	database := cli.Database(network.DB.Name)
+
+	// Get cache get function
+	logger.Info("initializing cache")
+	cache, err := NewCache(ctx, cfg, network.Cache.Prefix, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	logger.Info("initializing notional cache")
+	notionalCache, err := NewNotionalCache(ctx, cfg, network.Cache.Prefix, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize notional cache: %w", err)
+	}
+
+	// the transaction metrics are cached until they expire or the pipeline notifies new VAAs.
+	logger.Info("subscribing to cache invalidations")
+	invalidations, err := NewInvalidationSubscriber(ctx, cfg, network.P2pNetwork, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+	metricsCache := cacheable.NewCache(cache)
+	if invalidations != nil {
+		invalidations.Start(func(e invalidation.Event) {
+			if e.Event == invalidation.EventVaaStored {
+				metricsCache.Invalidate(e.Timestamp, transactions.CacheKeys...)
+			}
+		})
+	}
+
+	// cfg.Cache.Expiration
+	logger.Info("initializing TVL cache")
+	tvl := tvl.NewTVL(network.P2pNetwork, cache, network.Cache.TvlKey, cfg.Cache.TvlExpiration, logger)
+
+	//InfluxDB client
+	logger.Info("initializing InfluxDB client")
+	influxCli := newInfluxClient(network.Influx.URL, network.Influx.Token)
+
+	// Set up repositories
+	logger.Info("initializing repositories")
+	addressRepo := address.NewRepository(database, logger)
+	vaaRepo := vaa.NewRepository(database, logger)
+	obsRepo := observations.NewRepository(database, logger)
+	governorRepo := governor.NewRepository(database, logger)
+	infrastructureRepo := infrastructure.NewRepository(database, logger)
+	heartbeatsRepo := heartbeats.NewRepository(database, logger)
+	transactionsRepo := transactions.NewRepository(
+		tvl,
+		influxCli,
+		network.Influx.Organization,
+		network.Influx.Bucket24Hours,
+		network.Influx.Bucket30Days,
+		network.Influx.BucketInfinite,
+		database,
+		logger,
+	)
+	tokensRepo := tokens.NewRepository(influxCli, network.Influx.Organization, network.Influx.BucketInfinite, logger)
+
+	labelsRepo := labels.NewRepository(database, logger)
+	governanceRepo := governance.NewRepository(database, logger)
+
+	// Set up services
+	logger.Info("initializing services")
+	governorService := governor.NewService(governorRepo, logger)
+	heartbeatsService := heartbeats.NewService(heartbeatsRepo, network.P2pNetwork, alertClient, logger)
+	labelsService := labels.NewService(labelsRepo, logger)
+	if err := labelsService.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load labels: %w", err)
+	}
+	// the payloads are parsed natively, with the vaa-payload-parser service as fallback if it is configured.
+	payloadParser, err := payload.NewRegistryFromConfig(payload.Config{
+		P2pNetwork:    network.P2pNetwork,
+		RemoteURL:     cfg.VaaPayloadParser.URL,
+		RemoteTimeout: vaaPayloadParser.DefaultTimeout,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize payload parser: %w", err)
+	}
+	metricExpiration := time.Duration(cfg.Cache.MetricExpiration) * time.Second
+	return &networkContext{
+		p2pNetwork:            network.P2pNetwork,
+		db:                    cli,
+		cache:                 cache,
+		notionalCache:         notionalCache,
+		invalidations:         invalidations,
+		influxCli:             influxCli,
+		addressService:        address.NewService(addressRepo, labelsService, logger),
+		vaaService:            vaa.NewService(vaaRepo, metricsCache, labelsService, payloadParser, network.P2pNetwork, logger),
+		obsService:            observations.NewService(obsRepo, heartbeatsService, network.P2pNetwork, logger),
+		governorService:       governorService,
+		infrastructureService: infrastructure.NewService(infrastructureRepo, logger),
+		heartbeatsService:     heartbeatsService,
+		transactionsService:   transactions.NewService(transactionsRepo, governorService, network.P2pNetwork, metricsCache, metricExpiration, logger),
+		tokensService:         tokens.NewService(tokensRepo, notionalCache, metricsCache, metricExpiration, logger),
+		labelsService:         labelsService,
+		governanceService:     governance.NewService(governanceRepo, logger),
+	}, nil
+}
+
+// Close releases the clients of the network.
+func (n *networkContext) Close(ctx context.Context) {
+	n.cache.Close()
+	n.notionalCache.Close()
+	if n.invalidations != nil {
+		n.invalidations.Close()
+	}
+	n.influxCli.Close()
+	n.db.Disconnect(ctx)
+}
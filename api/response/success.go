@@ -11,3 +11,10 @@ type Response[T any] struct {
 	Data       T                  `json:"data"`
 	Pagination ResponsePagination `json:"pagination"`
 }
+
+// BatchItem is the result of the lookup of one of the IDs of a batch request.
+// IDs that were not found have an explicit entry with `found` set to false.
+type BatchItem[T any] struct {
+	Found bool `json:"found"`
+	Data  T    `json:"data,omitempty"`
+}
@@ -83,6 +83,8 @@ type EnqueuedVaaItemResponse struct {
 	ReleaseTime    int64       `json:"releaseTime"`
 	NotionalValue  string      `json:"notionalValue"`
 	TxHash         string      `json:"txHash"`
+	// ReleaseEstimate is not part of the node grpc api, it is an extension of the explorer.
+	ReleaseEstimate *governor.ReleaseEstimate `json:"releaseEstimate,omitempty"`
 }
 
 // GetEnqueuedVaas godoc
@@ -107,12 +109,13 @@ func (c *Controller) GetEnqueuedVaas(ctx *fiber.Ctx) error {
 			return err
 		}
 		r := EnqueuedVaaItemResponse{
-			EmitterChain:   v.EmitterChain,
-			EmitterAddress: v.EmitterAddress,
-			Sequence:       seqUint64,
-			ReleaseTime:    v.ReleaseTime,
-			NotionalValue:  v.NotionalValue.String(),
-			TxHash:         v.TxHash,
+			EmitterChain:    v.EmitterChain,
+			EmitterAddress:  v.EmitterAddress,
+			Sequence:        seqUint64,
+			ReleaseTime:     v.ReleaseTime,
+			NotionalValue:   v.NotionalValue.String(),
+			TxHash:          v.TxHash,
+			ReleaseEstimate: v.ReleaseEstimate,
 		}
 		entries = append(entries, &r)
 	}
@@ -123,6 +126,13 @@ func (c *Controller) GetEnqueuedVaas(ctx *fiber.Ctx) error {
 	return ctx.JSON(response)
 }
 
+// IsVaaEnqueuedResponse response compatible with grpc api.
+type IsVaaEnqueuedResponse struct {
+	IsEnqueued bool `json:"isEnqueued"`
+	// ReleaseEstimate is not part of the node grpc api, it is an extension of the explorer.
+	ReleaseEstimate *governor.ReleaseEstimate `json:"releaseEstimate,omitempty"`
+}
+
 // IsVaaEnqueued godoc
 // @Description Check if vaa is enqueued
 // @Tags Guardian
@@ -130,7 +140,7 @@ func (c *Controller) GetEnqueuedVaas(ctx *fiber.Ctx) error {
 // @Param chain_id path integer true "id of the blockchain"
 // @Param emitter path string true "address of the emitter"
 // @Param seq path integer true "sequence of the vaa"
-// @Success 200 {object} EnqueuedVaaResponse
+// @Success 200 {object} IsVaaEnqueuedResponse
 // @Failure 400
 // @Failure 500
 // @Router /v1/governor/is_vaa_enqueued/{chain_id}/{emitter}/{seq} [get]
@@ -145,11 +155,15 @@ func (c *Controller) IsVaaEnqueued(ctx *fiber.Ctx) error {
 	}
 
 	// build reponse compatible with node grpc api.
-	response := struct {
-		IsEnqueued bool `json:"isEnqueued"`
-	}{
+	response := IsVaaEnqueuedResponse{
 		IsEnqueued: isEnqueued,
 	}
+	if isEnqueued {
+		response.ReleaseEstimate, err = c.srv.GetVaaReleaseEstimate(ctx.Context(), chainID, emitter, strconv.FormatUint(seq, 10))
+		if err != nil {
+			c.logger.Warn("failed to estimate release time of enqueued vaa", zap.Error(err))
+		}
+	}
 	return ctx.JSON(response)
 }
 
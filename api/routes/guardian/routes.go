@@ -5,7 +5,6 @@ import (
 	govsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
 	heartbeatssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
 	vaasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
-	"github.com/wormhole-foundation/wormhole-explorer/api/internal/config"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/guardian/governor"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/guardian/guardian"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/guardian/heartbeats"
@@ -13,10 +12,11 @@ import (
 	"go.uber.org/zap"
 )
 
-// RegisterRoutes sets up the handlers for the Guardian API.
+// RegisterRoutes sets up the handlers for the Guardian API of a network under basePath.
 func RegisterRoutes(
-	cfg *config.AppConfig,
 	app *fiber.App,
+	basePath string,
+	p2pNetwork string,
 	rootLogger *zap.Logger,
 	vaaService *vaasvc.Service,
 	governorService *govsvc.Service,
@@ -26,11 +26,11 @@ func RegisterRoutes(
 	// Set up controllers
 	vaaCtrl := vaa.NewController(vaaService, rootLogger)
 	governorCtrl := governor.NewController(governorService, rootLogger)
-	guardianCtrl := guardian.NewController(rootLogger, cfg.P2pNetwork)
-	heartbeatsCtrl := heartbeats.NewController(heartbeatsService, rootLogger, cfg.P2pNetwork)
+	guardianCtrl := guardian.NewController(rootLogger, p2pNetwork)
+	heartbeatsCtrl := heartbeats.NewController(heartbeatsService, rootLogger, p2pNetwork)
 
 	// Set up route handlers
-	apiV1 := app.Group("/v1")
+	apiV1 := app.Group(basePath)
 
 	// signedVAA resource
 	signedVAA := apiV1.Group("/signed_vaa")
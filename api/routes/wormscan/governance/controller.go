@@ -0,0 +1,85 @@
+// Package governance handles the requests of the governance VAAs.
+package governance
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *governance.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *governance.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "GovernanceController")),
+	}
+}
+
+// FindGovernanceVaas godoc
+// @Description Returns the VAAs of the governance emitter, classified by module and action, with the decoded fields of each action.
+// @Tags Wormscan
+// @ID find-governance-vaas
+// @Param module query string false "filter by module, e.g. Core, TokenBridge, NFTBridge, WormholeRelayer, CircleIntegration or GlobalAccountant"
+// @Param action query string false "filter by action, e.g. ContractUpgrade, GuardianSetUpgrade, SetMessageFee, RegisterChain or RecoverChainId"
+// @Param targetChain query integer false "filter by the target chain of the action, 0 for the actions that target all chains"
+// @Param page query integer false "Page number."
+// @Param pageSize query integer false "Number of elements per page."
+// @Param sortOrder query string false "Sort results in ascending or descending order." Enums(ASC, DESC)
+// @Success 200 {object} response.Response[[]governance.GovernanceVaaDoc]
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/governance [get]
+func (c *Controller) FindGovernanceVaas(ctx *fiber.Ctx) error {
+	q, err := middleware.ExtractGovernanceQuery(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	vaas, err := c.srv.Find(ctx.Context(), q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(vaas)
+}
+
+// FindGovernanceVaasByChain godoc
+// @Description Returns the governance actions that apply to a chain: the actions that target the chain
+// @Description and the actions that reference it, such as the registration of its emitters.
+// @Description The actions that target all chains, such as guardian set upgrades, are not included.
+// @Tags Wormscan
+// @ID find-governance-vaas-by-chain
+// @Param chain path integer true "id of the blockchain"
+// @Param module query string false "filter by module"
+// @Param action query string false "filter by action"
+// @Param page query integer false "Page number."
+// @Param pageSize query integer false "Number of elements per page."
+// @Param sortOrder query string false "Sort results in ascending or descending order." Enums(ASC, DESC)
+// @Success 200 {object} response.Response[[]governance.GovernanceVaaDoc]
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/governance/{chain} [get]
+func (c *Controller) FindGovernanceVaasByChain(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	q, err := middleware.ExtractGovernanceQuery(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	q.Chain = &chainID
+
+	vaas, err := c.srv.Find(ctx.Context(), q)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(vaas)
+}
@@ -0,0 +1,39 @@
+package guardians
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
+	_ "github.com/wormhole-foundation/wormhole-explorer/api/response" // required by swaggo
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *heartbeats.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *heartbeats.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "GuardiansController")),
+	}
+}
+
+// GetGuardiansHealth godoc
+// @Description Get the health of the guardians for each chain, computed from the last heartbeats.
+// @Description For each chain, it returns the height reached by a quorum of guardians and the lag of each guardian behind it.
+// @Tags Wormscan
+// @ID guardians-health
+// @Success 200 {object} heartbeats.GuardianHealth
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/guardians/health [get]
+func (c *Controller) GetGuardiansHealth(ctx *fiber.Ctx) error {
+	health, err := c.srv.GetGuardianHealth(ctx.Context())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(health)
+}
@@ -8,12 +8,15 @@ import (
 
 // Controller definition.
 type Controller struct {
-	srv *infrastructure.Service
+	srv         *infrastructure.Service
+	p2pNetwork  string
+	p2pNetworks []string
 }
 
 // NewController creates a Controller instance.
-func NewController(serv *infrastructure.Service) *Controller {
-	return &Controller{srv: serv}
+// p2pNetwork is the network served by the controller and p2pNetworks are all the networks served by the API.
+func NewController(serv *infrastructure.Service, p2pNetwork string, p2pNetworks []string) *Controller {
+	return &Controller{srv: serv, p2pNetwork: p2pNetwork, p2pNetworks: p2pNetworks}
 }
 
 // HealthCheck is the HTTP route handler for the endpoint `GET /api/v1/health`.
@@ -21,14 +24,15 @@ func NewController(serv *infrastructure.Service) *Controller {
 // @Description Health check
 // @Tags Wormscan
 // @ID health-check
-// @Success 200 {object} object{status=string}
+// @Success 200 {object} object{status=string,network=string}
 // @Failure 400
 // @Failure 500
 // @Router /api/v1/health [get]
 func (c *Controller) HealthCheck(ctx *fiber.Ctx) error {
 	return ctx.JSON(struct {
-		Status string `json:"status"`
-	}{Status: "OK"})
+		Status  string `json:"status"`
+		Network string `json:"network"`
+	}{Status: "OK", Network: c.p2pNetwork})
 }
 
 // ReadyCheck is the HTTP handler for the endpoint `GET /api/v1/ready`.
@@ -36,20 +40,20 @@ func (c *Controller) HealthCheck(ctx *fiber.Ctx) error {
 // @Description Ready check
 // @Tags Wormscan
 // @ID ready-check
-// @Success 200 {object} object{ready=string}
+// @Success 200 {object} object{ready=string,network=string}
 // @Failure 400
 // @Failure 500
 // @Router /api/v1/ready [get]
 func (c *Controller) ReadyCheck(ctx *fiber.Ctx) error {
+	type readyResponse struct {
+		Ready   string `json:"ready"`
+		Network string `json:"network"`
+	}
 	ready, _ := c.srv.CheckMongoServerStatus(ctx.Context())
 	if ready {
-		return ctx.Status(fiber.StatusOK).JSON(struct {
-			Ready string `json:"ready"`
-		}{Ready: "OK"})
+		return ctx.Status(fiber.StatusOK).JSON(readyResponse{Ready: "OK", Network: c.p2pNetwork})
 	}
-	return ctx.Status(fiber.StatusInternalServerError).JSON(struct {
-		Ready string `json:"ready"`
-	}{Ready: "NO"})
+	return ctx.Status(fiber.StatusInternalServerError).JSON(readyResponse{Ready: "NO", Network: c.p2pNetwork})
 }
 
 // VersionResponse is the JSON model for the 200 OK response in `GET /api/v1/version`.
@@ -59,6 +63,10 @@ type VersionResponse struct {
 	Branch    string `json:"branch"`
 	Machine   string `json:"machine"`
 	User      string `json:"user"`
+	// Network is the network served by the endpoint.
+	Network string `json:"network"`
+	// Networks are all the networks served by the API.
+	Networks []string `json:"networks"`
 }
 
 // Version is the HTTP route handler for the endpoint `GET /api/v1/version`.
@@ -77,5 +85,7 @@ func (c *Controller) Version(ctx *fiber.Ctx) error {
 		Build:     build.Build,
 		Machine:   build.Machine,
 		User:      build.User,
+		Network:   c.p2pNetwork,
+		Networks:  c.p2pNetworks,
 	})
 }
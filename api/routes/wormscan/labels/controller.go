@@ -0,0 +1,152 @@
+package labels
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
+	errs "github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *labels.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *labels.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "LabelsController")),
+	}
+}
+
+// SearchLabelsResponse is the "200 OK" response model for `GET /api/v1/labels`.
+type SearchLabelsResponse struct {
+	Labels []*labels.Label `json:"labels"`
+}
+
+// SearchLabels godoc
+// @Description Search the labels of known addresses (bridge contracts, relayers, exchanges and protocols).
+// @Tags Wormscan
+// @ID search-labels
+// @Param q query string false "text contained in the name or the address of the label"
+// @Param chain query integer false "filter by chain"
+// @Param category query string false "filter by category, supported values: bridge-contract, relayer, exchange and protocol"
+// @Param appId query string false "filter by app id"
+// @Param page query integer false "Page number. Starts at 0."
+// @Param pageSize query integer false "Number of elements per page."
+// @Success 200 {object} SearchLabelsResponse
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/labels [get]
+func (c *Controller) SearchLabels(ctx *fiber.Ctx) error {
+	q, err := middleware.ExtractLabelsQuery(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(SearchLabelsResponse{Labels: c.srv.Search(q)})
+}
+
+// GetLabel godoc
+// @Description Returns the label of an address of a chain.
+// @Description The address can be encoded as hex or in the native format of the chain.
+// @Tags Wormscan
+// @ID get-label
+// @Param chain path integer true "id of the blockchain"
+// @Param address path string true "address"
+// @Success 200 {object} labels.Label
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/labels/{chain}/{address} [get]
+func (c *Controller) GetLabel(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	if _, err := labels.NormalizeAddress(chainID, ctx.Params("address")); err != nil {
+		return response.NewInvalidParamError(ctx, "MALFORMED ADDR", errors.WithStack(err))
+	}
+
+	label := c.srv.Find(chainID, ctx.Params("address"))
+	if label == nil {
+		return errs.ErrNotFound
+	}
+	return ctx.JSON(label)
+}
+
+// UpsertLabel godoc
+// @Description Creates or updates the label of an address of a chain. Requires the admin api key.
+// @Tags Wormscan
+// @ID upsert-label
+// @Param chain path integer true "id of the blockchain"
+// @Param address path string true "address"
+// @Param label body labels.UpdateLabel true "label"
+// @Success 200 {object} labels.Label
+// @Failure 400
+// @Failure 401
+// @Failure 500
+// @Router /api/v1/admin/labels/{chain}/{address} [put]
+func (c *Controller) UpsertLabel(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	var u labels.UpdateLabel
+	if err := ctx.BodyParser(&u); err != nil {
+		return response.NewInvalidParamError(ctx, "INVALID BODY", errors.WithStack(err))
+	}
+
+	label, err := c.srv.Upsert(ctx.Context(), chainID, ctx.Params("address"), &u)
+	if err != nil {
+		if isValidationError(err) {
+			return response.NewInvalidParamError(ctx, strings.ToUpper(err.Error()), errors.WithStack(err))
+		}
+		return err
+	}
+	return ctx.JSON(label)
+}
+
+// DeleteLabel godoc
+// @Description Deletes the label of an address of a chain. Requires the admin api key.
+// @Tags Wormscan
+// @ID delete-label
+// @Param chain path integer true "id of the blockchain"
+// @Param address path string true "address"
+// @Success 204
+// @Failure 400
+// @Failure 401
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/admin/labels/{chain}/{address} [delete]
+func (c *Controller) DeleteLabel(ctx *fiber.Ctx) error {
+	chainID, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := c.srv.Delete(ctx.Context(), chainID, ctx.Params("address"))
+	if err != nil {
+		if isValidationError(err) {
+			return response.NewInvalidParamError(ctx, strings.ToUpper(err.Error()), errors.WithStack(err))
+		}
+		return err
+	}
+	if !deleted {
+		return errs.ErrNotFound
+	}
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func isValidationError(err error) bool {
+	return errors.Is(err, labels.ErrInvalidAddress) ||
+		errors.Is(err, labels.ErrInvalidCategory) ||
+		errors.Is(err, labels.ErrMissingName)
+}
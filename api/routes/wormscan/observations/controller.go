@@ -143,6 +143,35 @@ func (c *Controller) FindAllByVAA(ctx *fiber.Ctx) error {
 	return ctx.JSON(obs)
 }
 
+// FindMissingSignatures godoc
+// @Description Returns the guardians of the guardian set of a message that have not signed it yet,
+// @Description with their last heartbeat and the height of the emitter chain they report,
+// @Description and whether the message still lacks a quorum of signatures.
+// @Tags Wormscan
+// @ID find-missing-signatures
+// @Param chain_id path integer true "id of the blockchain"
+// @Param emitter path string true "address of the emitter"
+// @Param seq path integer true "sequence of the VAA"
+// @Success 200 {object} observations.MissingSignatures
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/observations/{chain_id}/{emitter}/{seq}/missing-signatures [get]
+func (c *Controller) FindMissingSignatures(ctx *fiber.Ctx) error {
+
+	chainID, addr, seq, err := middleware.ExtractVAAParams(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	missing, err := c.srv.FindMissingSignatures(ctx.Context(), chainID, addr, strconv.FormatUint(seq, 10))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(missing)
+}
+
 // FindOne godoc
 // @Description Find a specific observation.
 // @Tags Wormscan
@@ -7,15 +7,24 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cache"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	addrsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/address"
+	governancesvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/governance"
 	govsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	heartbeatssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/heartbeats"
 	infrasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/infrastructure"
+	labelssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	obssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/observations"
+	tokenssvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/tokens"
 	trxsvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
 	vaasvc "github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/address"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/governance"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/guardians"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/infrastructure"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/labels"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/observations"
+	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/tokens"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/transactions"
 	"github.com/wormhole-foundation/wormhole-explorer/api/routes/wormscan/vaa"
 	"go.uber.org/zap"
@@ -30,9 +39,12 @@ var cacheConfig = cache.Config{
 	StoreResponseHeaders: true,
 }
 
-// RegisterRoutes sets up the handlers for the Wormscan API.
+// RegisterRoutes sets up the handlers for the Wormscan API of a network under basePath.
 func RegisterRoutes(
 	app *fiber.App,
+	basePath string,
+	p2pNetwork string,
+	p2pNetworks []string,
 	rootLogger *zap.Logger,
 	addressService *addrsvc.Service,
 	vaaService *vaasvc.Service,
@@ -40,6 +52,11 @@ func RegisterRoutes(
 	governorService *govsvc.Service,
 	infrastructureService *infrasvc.Service,
 	transactionsService *trxsvc.Service,
+	heartbeatsService *heartbeatssvc.Service,
+	tokensService *tokenssvc.Service,
+	labelsService *labelssvc.Service,
+	governanceService *governancesvc.Service,
+	adminApiKey string,
 ) {
 
 	// Set up controllers
@@ -47,11 +64,15 @@ func RegisterRoutes(
 	vaaCtrl := vaa.NewController(vaaService, rootLogger)
 	observationsCtrl := observations.NewController(obsService, rootLogger)
 	governorCtrl := governor.NewController(governorService, rootLogger)
-	infrastructureCtrl := infrastructure.NewController(infrastructureService)
-	transactionCtrl := transactions.NewController(transactionsService, rootLogger)
+	infrastructureCtrl := infrastructure.NewController(infrastructureService, p2pNetwork, p2pNetworks)
+	guardiansCtrl := guardians.NewController(heartbeatsService, rootLogger)
+	transactionCtrl := transactions.NewController(transactionsService, labelsService, rootLogger)
+	tokensCtrl := tokens.NewController(tokensService, rootLogger)
+	labelsCtrl := labels.NewController(labelsService, rootLogger)
+	governanceCtrl := governance.NewController(governanceService, rootLogger)
 
 	// Set up route handlers
-	api := app.Group("/api/v1")
+	api := app.Group(basePath)
 	api.Use(cors.New()) // TODO CORS restrictions?
 
 	// monitoring
@@ -67,17 +88,41 @@ func RegisterRoutes(
 	api.Get("/last-txs", transactionCtrl.GetLastTransactions)
 	api.Get("/scorecards", transactionCtrl.GetScorecards)
 	api.Get("/x-chain-activity", transactionCtrl.GetChainActivity)
+	api.Get("/activity", transactionCtrl.GetActivity)
 	api.Get("/top-assets-by-volume", transactionCtrl.GetTopAssets)
 	api.Get("/top-chain-pairs-by-num-transfers", transactionCtrl.GetTopChainPairs)
 	api.Get("token/:chain/:token_address", transactionCtrl.GetTokenByChainAndAddress)
 	api.Get("/transactions", transactionCtrl.ListTransactions)
+	api.Post("/transactions/batch", transactionCtrl.GetTransactionsByIDs)
 	api.Get("/transactions/:chain/:emitter/:sequence", transactionCtrl.GetTransactionByID)
+	api.Get("/transactions/:chain/:emitter/:sequence/status", transactionCtrl.GetTransactionStatus)
+
+	// tokens resource
+	api.Get("/tokens", tokensCtrl.ListTokens)
+	api.Get("/tokens/:chain/:token_address/stats", tokensCtrl.GetTokenStats)
+
+	// labels resource
+	api.Get("/labels", labelsCtrl.SearchLabels)
+	api.Get("/labels/:chain/:address", labelsCtrl.GetLabel)
+
+	// governance resource
+	api.Get("/governance", governanceCtrl.FindGovernanceVaas)
+	api.Get("/governance/:chain", governanceCtrl.FindGovernanceVaasByChain)
+
+	// admin resources, only available when an api key is configured
+	if adminApiKey != "" {
+		admin := api.Group("/admin", middleware.AdminAuth(adminApiKey))
+		admin.Put("/labels/:chain/:address", labelsCtrl.UpsertLabel)
+		admin.Delete("/labels/:chain/:address", labelsCtrl.DeleteLabel)
+	}
 
 	// vaas resource
 	vaas := api.Group("/vaas")
 	vaas.Use(cache.New(cacheConfig))
 	vaas.Get("/vaa-counts", vaaCtrl.GetVaaCount)
 	vaas.Get("/", vaaCtrl.FindAll)
+	vaas.Post("/batch", vaaCtrl.FindByIDs)
+	vaas.Post("/decode", vaaCtrl.Decode)
 	vaas.Get("/:chain", vaaCtrl.FindByChain)
 	vaas.Get("/:chain/:emitter", vaaCtrl.FindByEmitter)
 	vaas.Get("/:chain/:emitter/:sequence", vaaCtrl.FindById)
@@ -88,8 +133,12 @@ func RegisterRoutes(
 	observations.Get("/:chain", observationsCtrl.FindAllByChain)
 	observations.Get("/:chain/:emitter", observationsCtrl.FindAllByEmitter)
 	observations.Get("/:chain/:emitter/:sequence", observationsCtrl.FindAllByVAA)
+	observations.Get("/:chain/:emitter/:sequence/missing-signatures", observationsCtrl.FindMissingSignatures)
 	observations.Get("/:chain/:emitter/:sequence/:signer/:hash", observationsCtrl.FindOne)
 
+	// guardians resource
+	api.Get("/guardians/health", guardiansCtrl.GetGuardiansHealth)
+
 	// governor resources
 	governor := api.Group("/governor")
 	governorLimit := governor.Group("/limit")
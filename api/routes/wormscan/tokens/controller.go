@@ -0,0 +1,79 @@
+package tokens
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/tokens"
+	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	_ "github.com/wormhole-foundation/wormhole-explorer/api/response" // required by swaggo
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *tokens.Service
+	logger *zap.Logger
+}
+
+// NewController create a new controler.
+func NewController(srv *tokens.Service, logger *zap.Logger) *Controller {
+	return &Controller{
+		srv:    srv,
+		logger: logger.With(zap.String("module", "TokensController")),
+	}
+}
+
+// ListTokens godoc
+// @Description Returns the tokens supported by Portal Token Bridge.
+// @Description The addresses of the same asset on each chain are grouped by coingecko id.
+// @Tags Wormscan
+// @ID list-tokens
+// @Success 200 {object} []tokens.Token
+// @Failure 500
+// @Router /api/v1/tokens [get]
+func (c *Controller) ListTokens(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.srv.ListTokens(ctx.Context()))
+}
+
+// GetTokenStats godoc
+// @Description Returns the number of transfers, the volume by sample, the top source and destination chains
+// @Description and the latest price of a token, identified by its original chain and address.
+// @Tags Wormscan
+// @ID get-token-stats
+// @Param chain path integer true "id of the original chain of the token"
+// @Param token_address path string true "original address of the token"
+// @Param timeSpan query string false "Time span, supported values: 1d, 1w and 1mo (default is 1d)."
+// @Param sampleRate query string false "Sample rate, supported values: 1h and 1d (default is 1h)."
+// @Success 200 {object} tokens.TokenStats
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/tokens/{chain}/{token_address}/stats [get]
+func (c *Controller) GetTokenStats(ctx *fiber.Ctx) error {
+	chain, err := middleware.ExtractChainID(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	tokenAddress, err := middleware.ExtractTokenAddress(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	timeSpan, sampleRate, err := middleware.ExtractTimeSpanAndSampleRate(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	q := tokens.TokenStatsQuery{
+		TokenChain:   chain,
+		TokenAddress: tokenAddress.Hex(),
+		TimeSpan:     timeSpan,
+		SampleRate:   sampleRate,
+	}
+	stats, err := c.srv.GetTokenStats(ctx.Context(), &q)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(stats)
+}
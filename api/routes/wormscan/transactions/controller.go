@@ -5,9 +5,11 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/shopspring/decimal"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
 	"github.com/wormhole-foundation/wormhole-explorer/api/internal/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
@@ -15,15 +17,17 @@ import (
 
 // Controller is the controller for the transactions resource.
 type Controller struct {
-	srv    *transactions.Service
-	logger *zap.Logger
+	srv       *transactions.Service
+	labelsSrv *labels.Service
+	logger    *zap.Logger
 }
 
 // NewController create a new controler.
-func NewController(transactionsService *transactions.Service, logger *zap.Logger) *Controller {
+func NewController(transactionsService *transactions.Service, labelsService *labels.Service, logger *zap.Logger) *Controller {
 	return &Controller{
-		srv:    transactionsService,
-		logger: logger.With(zap.String("module", "TransactionsController")),
+		srv:       transactionsService,
+		labelsSrv: labelsService,
+		logger:    logger.With(zap.String("module", "TransactionsController")),
 	}
 }
 
@@ -96,20 +100,33 @@ func (c *Controller) GetScorecards(ctx *fiber.Ctx) error {
 // @Description Returns a list of the emitter_chain and destination_chain pair ordered by transfer count.
 // @Tags Wormscan
 // @ID get-top-chain-pairs-by-num-transfers
-// @Param timeSpan query string true "Time span, supported values: 7d, 15d, 30d."
+// @Param timeSpan query string false "Time span, supported values: 7d, 15d, 30d. Required if from is not set."
+// @Param from query string false "Start of an arbitrary time range, format: 20060102T150405Z."
+// @Param to query string false "End of an arbitrary time range, format: 20060102T150405Z (default is now)."
 // @Success 200 {object} TopChainPairsResponse
+// @Failure 400
 // @Failure 500
 // @Router /api/v1/top-chain-pairs-by-num-transfers [get]
 func (c *Controller) GetTopChainPairs(ctx *fiber.Ctx) error {
 
 	// Extract query parameters
-	timeSpan, err := middleware.ExtractTopStatisticsTimeSpan(ctx)
+	activityRange, err := middleware.ExtractActivityRange(ctx, c.logger)
 	if err != nil {
 		return err
 	}
 
 	// Query chain pairs from the database
-	chainPairDTOs, err := c.srv.GetTopChainPairs(ctx.Context(), timeSpan)
+	var chainPairDTOs []transactions.ChainPairDTO
+	if activityRange != nil {
+		chainPairDTOs, err = c.srv.GetTopChainPairsByRange(ctx.Context(), activityRange)
+	} else {
+		var timeSpan *transactions.TopStatisticsTimeSpan
+		timeSpan, err = middleware.ExtractTopStatisticsTimeSpan(ctx)
+		if err != nil {
+			return err
+		}
+		chainPairDTOs, err = c.srv.GetTopChainPairs(ctx.Context(), timeSpan)
+	}
 	if err != nil {
 		c.logger.Error("failed to get top chain pairs by number of transfers", zap.Error(err))
 		return err
@@ -136,20 +153,33 @@ func (c *Controller) GetTopChainPairs(ctx *fiber.Ctx) error {
 // @Description The volume is calculated using the notional price of the symbol at the day the VAA was emitted.
 // @Tags Wormscan
 // @ID get-top-assets-by-volume
-// @Param timeSpan query string true "Time span, supported values: 7d, 15d, 30d."
+// @Param timeSpan query string false "Time span, supported values: 7d, 15d, 30d. Required if from is not set."
+// @Param from query string false "Start of an arbitrary time range, format: 20060102T150405Z."
+// @Param to query string false "End of an arbitrary time range, format: 20060102T150405Z (default is now)."
 // @Success 200 {object} TopAssetsResponse
+// @Failure 400
 // @Failure 500
 // @Router /api/v1/top-assets-by-volume [get]
 func (c *Controller) GetTopAssets(ctx *fiber.Ctx) error {
 
 	// Extract query parameters
-	timeSpan, err := middleware.ExtractTopStatisticsTimeSpan(ctx)
+	activityRange, err := middleware.ExtractActivityRange(ctx, c.logger)
 	if err != nil {
 		return err
 	}
 
 	// Query assets from the database
-	assetDTOs, err := c.srv.GetTopAssets(ctx.Context(), timeSpan)
+	var assetDTOs []transactions.AssetDTO
+	if activityRange != nil {
+		assetDTOs, err = c.srv.GetTopAssetsByRange(ctx.Context(), activityRange)
+	} else {
+		var timeSpan *transactions.TopStatisticsTimeSpan
+		timeSpan, err = middleware.ExtractTopStatisticsTimeSpan(ctx)
+		if err != nil {
+			return err
+		}
+		assetDTOs, err = c.srv.GetTopAssets(ctx.Context(), timeSpan)
+	}
 	if err != nil {
 		c.logger.Error("failed to get top assets by volume", zap.Error(err))
 		return err
@@ -180,6 +210,37 @@ func (c *Controller) GetTopAssets(ctx *fiber.Ctx) error {
 	return ctx.JSON(response)
 }
 
+// GetActivity godoc
+// @Description Returns the number of token bridge transfers and their volume for each sample of an arbitrary time range.
+// @Description The range is normalized to whole hours and can not be longer than 366 days. Hourly samples are limited to 31 days.
+// @Tags Wormscan
+// @ID get-activity
+// @Param from query string true "Start of the time range, format: 20060102T150405Z."
+// @Param to query string false "End of the time range, format: 20060102T150405Z (default is now)."
+// @Param granularity query string false "Size of the samples, supported values: 1h, 1d and 1mo (default is 1d)."
+// @Success 200 {object} []transactions.ActivityResult
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/activity [get]
+func (c *Controller) GetActivity(ctx *fiber.Ctx) error {
+
+	activityRange, err := middleware.ExtractActivityRange(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	if activityRange == nil {
+		return response.NewInvalidQueryParamError(ctx, "MISSING <from> QUERY PARAMETER", nil)
+	}
+
+	activity, err := c.srv.GetActivity(ctx.Context(), activityRange)
+	if err != nil {
+		c.logger.Error("failed to get activity", zap.Error(err))
+		return err
+	}
+
+	return ctx.JSON(activity)
+}
+
 // GetChainActivity godoc
 // @Description Returns a list of chain pairs by origin chain and destination chain.
 // @Description The list could be rendered by notional or transaction count.
@@ -189,6 +250,8 @@ func (c *Controller) GetTopAssets(ctx *fiber.Ctx) error {
 // @Param timeSpan query string false "Time span, supported values: 7d, 30d, 90d, 1y and all-time (default is 7d)."
 // @Param by query string false "Renders the results using notional or tx count (default is notional)."
 // @Param apps query string false "List of apps separated by comma (default is all apps)."
+// @Param from query string false "Start of an arbitrary time range, format: 20060102T150405Z. Overrides timeSpan."
+// @Param to query string false "End of an arbitrary time range, format: 20060102T150405Z (default is now)."
 // @Success 200 {object} transactions.ChainActivity
 // @Failure 400
 // @Failure 500
@@ -207,6 +270,10 @@ func (c *Controller) GetChainActivity(ctx *fiber.Ctx) error {
 	if err != nil {
 		return err
 	}
+	activityRange, err := middleware.ExtractActivityRange(ctx, c.logger)
+	if err != nil {
+		return err
+	}
 
 	q := &transactions.ChainActivityQuery{
 		TimeSpan:   timeSpan,
@@ -215,7 +282,12 @@ func (c *Controller) GetChainActivity(ctx *fiber.Ctx) error {
 	}
 
 	// Get the chain activity.
-	activity, err := c.srv.GetChainActivity(ctx.Context(), q)
+	var activity []transactions.ChainActivityResult
+	if activityRange != nil {
+		activity, err = c.srv.GetChainActivityByRange(ctx.Context(), q, activityRange)
+	} else {
+		activity, err = c.srv.GetChainActivity(ctx.Context(), q)
+	}
 	if err != nil {
 		c.logger.Error("Error getting chain activity", zap.Error(err))
 		return err
@@ -354,6 +426,18 @@ func (c *Controller) GetTokenByChainAndAddress(ctx *fiber.Ctx) error {
 // @ID list-transactions
 // @Param page query integer false "Page number. Starts at 0."
 // @Param pageSize query integer false "Number of elements per page."
+// @Param address query string false "filter by the destination address"
+// @Param fromChain query integer false "filter by the source chain of the payload"
+// @Param toChain query integer false "filter by the destination chain of the payload"
+// @Param tokenChain query integer false "filter by the chain of the token"
+// @Param tokenAddress query string false "filter by the address of the token, requires tokenChain"
+// @Param minAmount query string false "minimum normalized amount of tokens, requires tokenChain and tokenAddress"
+// @Param maxAmount query string false "maximum normalized amount of tokens, requires tokenChain and tokenAddress"
+// @Param minUsdAmount query string false "minimum amount in USD, requires fromChain, toChain, tokenChain or payloadType"
+// @Param maxUsdAmount query string false "maximum amount in USD, requires fromChain, toChain, tokenChain or payloadType"
+// @Param startTime query string false "filter transactions emitted since this time, format 20060102T150405Z"
+// @Param endTime query string false "filter transactions emitted until this time, format 20060102T150405Z"
+// @Param payloadType query integer false "filter by the payload type"
 // @Success 200 {object} ListTransactionsResponse
 // @Failure 400
 // @Failure 500
@@ -366,13 +450,20 @@ func (c *Controller) ListTransactions(ctx *fiber.Ctx) error {
 		return err
 	}
 	address := middleware.ExtractAddressFromQueryParams(ctx, c.logger)
+	payloadFilter, err := middleware.ExtractPayloadFilter(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	if address != "" && !payloadFilter.IsEmpty() {
+		return response.NewInvalidQueryParamError(ctx, "PAYLOAD FILTERS CAN NOT BE COMBINED WITH <address>", nil)
+	}
 
 	// Query transactions from the database
 	var dtos []transactions.TransactionDto
 	if address != "" {
 		dtos, err = c.srv.ListTransactionsByAddress(ctx.Context(), address, pagination)
 	} else {
-		dtos, err = c.srv.ListTransactions(ctx.Context(), pagination)
+		dtos, err = c.srv.ListTransactions(ctx.Context(), pagination, payloadFilter)
 	}
 	if err != nil {
 		return err
@@ -439,9 +530,59 @@ func (c *Controller) makeTransactionDetail(input *transactions.TransactionDto) *
 		tx.GlobalTx = &input.GlobalTransations[0]
 	}
 
+	// Set the labels of the known addresses
+	tx.Labels = c.makeTransactionLabels(&tx)
+
 	return &tx
 }
 
+// makeTransactionLabels looks up the labels of the emitter, sender and receiver of a transaction.
+//
+// The sender and receiver are taken from the standardized properties of the payload and,
+// if not available, from the origin and destination transactions.
+func (c *Controller) makeTransactionLabels(tx *TransactionDetail) *TransactionLabels {
+
+	var labels TransactionLabels
+	labels.Emitter = c.labelsSrv.Find(tx.EmitterChain, tx.EmitterAddress)
+
+	if chainID, ok := chainIDFromProperty(tx.StandardizedProperties["fromChain"]); ok {
+		if address, ok := tx.StandardizedProperties["fromAddress"].(string); ok {
+			labels.From = c.labelsSrv.Find(chainID, address)
+		}
+	}
+	if labels.From == nil && tx.GlobalTx != nil && tx.GlobalTx.OriginTx != nil {
+		labels.From = c.labelsSrv.Find(tx.EmitterChain, tx.GlobalTx.OriginTx.From)
+	}
+
+	if chainID, ok := chainIDFromProperty(tx.StandardizedProperties["toChain"]); ok {
+		if address, ok := tx.StandardizedProperties["toAddress"].(string); ok {
+			labels.To = c.labelsSrv.Find(chainID, address)
+		}
+	}
+	if labels.To == nil && tx.GlobalTx != nil && tx.GlobalTx.DestinationTx != nil {
+		labels.To = c.labelsSrv.Find(tx.GlobalTx.DestinationTx.ChainID, tx.GlobalTx.DestinationTx.To)
+	}
+
+	if labels.Emitter == nil && labels.From == nil && labels.To == nil {
+		return nil
+	}
+	return &labels
+}
+
+// chainIDFromProperty converts a chain ID decoded from a standardized property.
+func chainIDFromProperty(value interface{}) (sdk.ChainID, bool) {
+	switch v := value.(type) {
+	case int32:
+		return sdk.ChainID(v), v > 0
+	case int64:
+		return sdk.ChainID(v), v > 0
+	case float64:
+		return sdk.ChainID(v), v > 0
+	default:
+		return 0, false
+	}
+}
+
 // GetTransactionByID godoc
 // @Description Find VAA metadata by ID.
 // @Tags Wormscan
@@ -468,13 +609,112 @@ func (c *Controller) GetTransactionByID(ctx *fiber.Ctx) error {
 		emitter,
 		strconv.FormatUint(seq, 10),
 	)
-	if err != nil {
+	if err != nil && err != errors.ErrNotFound {
 		return err
 	}
+
+	// A VAA enqueued by the governor is not signed until it is released, so its transaction is not found.
+	// In that case, the response only contains the lifecycle status and the governor release estimate.
 	if dto == nil {
-		return errors.ErrNotFound
+		status, err := c.srv.GetMessageStatus(ctx.Context(), chainID, emitter, strconv.FormatUint(seq, 10))
+		if err != nil || status.GovernorRelease == nil {
+			return errors.ErrNotFound
+		}
+		return ctx.JSON(&TransactionDetail{
+			ID:              status.ID,
+			EmitterChain:    chainID,
+			EmitterAddress:  emitter.Hex(),
+			GovernorRelease: status.GovernorRelease,
+			Status:          status,
+		})
 	}
 
 	tx := c.makeTransactionDetail(dto)
+
+	// Set the lifecycle status and the governor release estimate, if the VAA is enqueued
+	status, err := c.srv.GetMessageStatus(ctx.Context(), chainID, emitter, strconv.FormatUint(seq, 10))
+	if err != nil {
+		c.logger.Warn("failed to resolve message status",
+			zap.String("vaaId", tx.ID),
+			zap.Error(err),
+		)
+	} else {
+		tx.Status = status
+		tx.GovernorRelease = status.GovernorRelease
+	}
+
 	return ctx.JSON(tx)
 }
+
+// GetTransactionsByIDs godoc
+// @Description Find a batch of transactions by VAA ID. The IDs have the format chain/emitter/sequence.
+// @Description The response has an entry for each ID, with `found` set to false when the transaction does not exist.
+// @Description The lifecycle status is not included, use the status endpoint of each transaction.
+// @Description Each ID counts as one request for the rate limiter.
+// @Tags Wormscan
+// @ID get-transactions-by-ids
+// @Param ids body middleware.BatchRequest true "VAA IDs of the transactions, up to 50"
+// @Success 200 {object} response.Response[map[string]response.BatchItem[TransactionDetail]]
+// @Failure 400
+// @Failure 429
+// @Failure 500
+// @Router /api/v1/transactions/batch [post]
+func (c *Controller) GetTransactionsByIDs(ctx *fiber.Ctx) error {
+
+	ids, err := middleware.ExtractBatchIDs(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	vaaIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		vaaIDs = append(vaaIDs, id.ID())
+	}
+	dtos, err := c.srv.GetTransactionsByIDs(ctx.Context(), vaaIDs)
+	if err != nil {
+		return err
+	}
+
+	// index the results by the IDs sent by the client
+	items := make(map[string]*response.BatchItem[*TransactionDetail], len(ids))
+	for _, id := range ids {
+		item := response.BatchItem[*TransactionDetail]{}
+		if dto, ok := dtos[id.ID()]; ok {
+			item.Found = true
+			item.Data = c.makeTransactionDetail(dto)
+		}
+		items[id.Key] = &item
+	}
+
+	return ctx.JSON(response.Response[map[string]*response.BatchItem[*TransactionDetail]]{Data: items})
+}
+
+// GetTransactionStatus godoc
+// @Description Returns the lifecycle status of a message: source tx seen, observing, governor enqueued,
+// @Description VAA signed, redeem failed, redeemed or expired, with the timestamp of each state transition.
+// @Tags Wormscan
+// @ID get-transaction-status
+// @Param chain_id path integer true "id of the blockchain"
+// @Param emitter path string true "address of the emitter"
+// @Param seq path integer true "sequence of the VAA"
+// @Success 200 {object} transactions.MessageStatus
+// @Failure 400
+// @Failure 404
+// @Failure 500
+// @Router /api/v1/transactions/{chain_id}/{emitter}/{seq}/status [get]
+func (c *Controller) GetTransactionStatus(ctx *fiber.Ctx) error {
+
+	// Extract query params
+	chainID, emitter, seq, err := middleware.ExtractVAAParams(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	// Resolve the status of the message
+	status, err := c.srv.GetMessageStatus(ctx.Context(), chainID, emitter, strconv.FormatUint(seq, 10))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(status)
+}
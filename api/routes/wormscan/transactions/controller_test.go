@@ -952,7 +952,7 @@ func Test_Controller_createChainActivityResponse(t *testing.T) {
 	err := json.Unmarshal([]byte(activityJSON), &activity)
 	assert.NoError(t, err)
 
-	controller := NewController(nil, zap.NewExample())
+	controller := NewController(nil, nil, zap.NewExample())
 	result, err := controller.createChainActivityResponse(activity, false)
 	assert.NoError(t, err)
 
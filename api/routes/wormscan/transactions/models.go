@@ -3,6 +3,8 @@ package transactions
 import (
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/governor"
+	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/labels"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/transactions"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
@@ -23,6 +25,19 @@ type TransactionDetail struct {
 	Payload                map[string]interface{}             `json:"payload,omitempty"`
 	StandardizedProperties map[string]interface{}             `json:"standardizedProperties,omitempty"`
 	GlobalTx               *transactions.GlobalTransactionDoc `json:"globalTx,omitempty"`
+	// GovernorRelease contains the estimated release time of the VAA, if it is enqueued by the governor.
+	GovernorRelease *governor.ReleaseEstimate `json:"governorRelease,omitempty"`
+	// Status contains the lifecycle status of the message.
+	Status *transactions.MessageStatus `json:"status,omitempty"`
+	// Labels contains the labels of the known addresses involved in the transaction.
+	Labels *TransactionLabels `json:"labels,omitempty"`
+}
+
+// TransactionLabels contains the labels of the emitter, sender and receiver of a transaction.
+type TransactionLabels struct {
+	Emitter *labels.Label `json:"emitter,omitempty"`
+	From    *labels.Label `json:"from,omitempty"`
+	To      *labels.Label `json:"to,omitempty"`
 }
 
 // ListTransactionsResponse is the "200 OK" response model for `GET /api/v1/transactions`.
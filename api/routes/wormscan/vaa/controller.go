@@ -5,9 +5,10 @@ import (
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
 	"github.com/wormhole-foundation/wormhole-explorer/api/handlers/vaa"
 	"github.com/wormhole-foundation/wormhole-explorer/api/middleware"
-	_ "github.com/wormhole-foundation/wormhole-explorer/api/response" // required by swaggo
+	"github.com/wormhole-foundation/wormhole-explorer/api/response"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +33,17 @@ func NewController(serv *vaa.Service, logger *zap.Logger) *Controller {
 // @Param txHash query string false "Transaction hash of the VAA"
 // @Param parsedPayload query bool false "include the parsed contents of the VAA, if available"
 // @Param appId query string false "filter by application ID"
+// @Param fromChain query integer false "filter by the source chain of the payload"
+// @Param toChain query integer false "filter by the destination chain of the payload"
+// @Param tokenChain query integer false "filter by the chain of the token"
+// @Param tokenAddress query string false "filter by the address of the token, requires tokenChain"
+// @Param minAmount query string false "minimum normalized amount of tokens, requires tokenChain and tokenAddress"
+// @Param maxAmount query string false "maximum normalized amount of tokens, requires tokenChain and tokenAddress"
+// @Param minUsdAmount query string false "minimum amount in USD, requires fromChain, toChain, tokenChain or payloadType"
+// @Param maxUsdAmount query string false "maximum amount in USD, requires fromChain, toChain, tokenChain or payloadType"
+// @Param startTime query string false "filter VAAs emitted since this time, format 20060102T150405Z"
+// @Param endTime query string false "filter VAAs emitted until this time, format 20060102T150405Z"
+// @Param payloadType query integer false "filter by the payload type"
 // @Success 200 {object} response.Response[[]vaa.VaaDoc]
 // @Failure 400
 // @Failure 500
@@ -58,11 +70,20 @@ func (c *Controller) FindAll(ctx *fiber.Ctx) error {
 		includeParsedPayload = true
 	}
 
+	payloadFilter, err := middleware.ExtractPayloadFilter(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+	if payloadFilter.HasPayloadFilters() && (txHash != nil || appId != "") {
+		return response.NewInvalidQueryParamError(ctx, "PAYLOAD FILTERS CAN NOT BE COMBINED WITH <txHash> OR <appId>", nil)
+	}
+
 	p := vaa.FindAllParams{
 		Pagination:           pagination,
 		TxHash:               txHash,
 		IncludeParsedPayload: includeParsedPayload,
 		AppId:                appId,
+		PayloadFilter:        payloadFilter,
 	}
 	vaas, err := c.srv.FindAll(ctx.Context(), &p)
 	if err != nil {
@@ -190,3 +211,89 @@ func (c *Controller) GetVaaCount(ctx *fiber.Ctx) error {
 
 	return ctx.JSON(vaas)
 }
+
+// FindByIDs godoc
+// @Description Find a batch of VAAs by ID. The IDs have the format chain/emitter/sequence.
+// @Description The response has an entry for each ID, with `found` set to false when the VAA does not exist.
+// @Description Each ID counts as one request for the rate limiter.
+// @Tags Wormscan
+// @ID find-vaas-by-ids
+// @Param ids body middleware.BatchRequest true "IDs of the VAAs, up to 50"
+// @Param parsedPayload query bool false "include the parsed contents of the VAAs, if available"
+// @Success 200 {object} response.Response[map[string]response.BatchItem[vaa.VaaDoc]]
+// @Failure 400
+// @Failure 429
+// @Failure 500
+// @Router /api/v1/vaas/batch [post]
+func (c *Controller) FindByIDs(ctx *fiber.Ctx) error {
+
+	ids, err := middleware.ExtractBatchIDs(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	includeParsedPayload, err := middleware.ExtractParsedPayload(ctx, c.logger)
+	if err != nil {
+		return err
+	}
+
+	vaaIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		vaaIDs = append(vaaIDs, id.ID())
+	}
+	vaas, err := c.srv.FindByIDs(ctx.Context(), vaaIDs, includeParsedPayload)
+	if err != nil {
+		return err
+	}
+
+	// index the results by the IDs sent by the client
+	items := make(map[string]*response.BatchItem[*vaa.VaaDoc], len(ids))
+	for _, id := range ids {
+		v, ok := vaas[id.ID()]
+		items[id.Key] = &response.BatchItem[*vaa.VaaDoc]{Found: ok, Data: v}
+	}
+
+	return ctx.JSON(response.Response[map[string]*response.BatchItem[*vaa.VaaDoc]]{Data: items})
+}
+
+// DecodeRequest is the body of a decode request.
+type DecodeRequest struct {
+	// Vaa is the encoded VAA.
+	Vaa string `json:"vaa"`
+	// Encoding is the encoding of the VAA, base64 (default) or hex.
+	Encoding string `json:"encoding"`
+}
+
+// Decode godoc
+// @Description Decodes a VAA encoded as base64 or hex. Returns its header and body, the guardians that signed it,
+// @Description whether the signatures verify against the guardian set history, whether the VAA is stored
+// @Description (and whether the stored bytes match) and the parsed payload.
+// @Tags Wormscan
+// @ID decode-vaa
+// @Param vaa body DecodeRequest true "VAA encoded as base64 (default) or hex"
+// @Success 200 {object} vaa.DecodedVaa
+// @Failure 400
+// @Failure 500
+// @Router /api/v1/vaas/decode [post]
+func (c *Controller) Decode(ctx *fiber.Ctx) error {
+
+	var req DecodeRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.NewInvalidParamError(ctx, "INVALID BODY", errors.WithStack(err))
+	}
+
+	raw, err := vaa.DecodeVaaBytes(req.Vaa, req.Encoding)
+	if err != nil {
+		return response.NewInvalidParamError(ctx, "MALFORMED VAA ENCODING", errors.WithStack(err))
+	}
+
+	decoded, err := c.srv.Decode(ctx.Context(), raw)
+	if err != nil {
+		if errors.Is(err, vaa.ErrMalformedVaa) {
+			return response.NewInvalidParamError(ctx, "MALFORMED VAA", err)
+		}
+		return err
+	}
+
+	return ctx.JSON(decoded)
+}
@@ -44,6 +44,12 @@ type CacheReadable interface {
 	Close() error
 }
 
+// Locker is the interface for distributed locks, used to coordinate the clients of the cache.
+type Locker interface {
+	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
 type CacheGetFunc func(ctx context.Context, key string) (string, error)
 
 // NewCacheClient init a new cache client.
@@ -102,6 +108,23 @@ func (c *CacheClient) Set(ctx context.Context, key string, value interface{}, ex
 	return nil
 }
 
+// Lock acquires the lock of a key, it returns false if the lock is held by another client.
+// The lock is released after the expiration if it is not unlocked.
+func (c *CacheClient) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	if !c.Enabled {
+		return false, ErrCacheNotEnabled
+	}
+	return c.Client.SetNX(ctx, c.renderKey(key)+":lock", "1", expiration).Result()
+}
+
+// Unlock releases the lock of a key.
+func (c *CacheClient) Unlock(ctx context.Context, key string) error {
+	if !c.Enabled {
+		return ErrCacheNotEnabled
+	}
+	return c.Client.Del(ctx, c.renderKey(key)+":lock").Err()
+}
+
 func (c *CacheClient) renderKey(key string) string {
 	if c.Prefix != "" {
 		return fmt.Sprintf("%s:%s", c.Prefix, key)
@@ -25,6 +25,16 @@ func (d *DummyCacheClient) Set(ctx context.Context, key string, value interface{
 	return nil
 }
 
+// Lock dummy method that always acquires the lock.
+func (d *DummyCacheClient) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return true, nil
+}
+
+// Unlock dummy method that does nothing.
+func (d *DummyCacheClient) Unlock(ctx context.Context, key string) error {
+	return nil
+}
+
 // Close dummy cache client.
 func (d *DummyCacheClient) Close() error {
 	return nil
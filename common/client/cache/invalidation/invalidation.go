@@ -0,0 +1,153 @@
+// Package invalidation notifies the changes of the indexed data by redis pubsub,
+// so the consumers of the data can invalidate the values they have cached.
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultChannel is the default pubsub channel of the invalidation events.
+	DefaultChannel = "WORMSCAN:CACHE_INVALIDATION"
+	// EventVaaStored is notified when new VAAs are stored in the database.
+	EventVaaStored = "VAA_STORED"
+)
+
+// Event is a change of the indexed data of a network.
+type Event struct {
+	Event      string    `json:"event"`
+	P2pNetwork string    `json:"p2pNetwork"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Notifier is the interface to notify invalidation events.
+type Notifier interface {
+	Notify(event string)
+}
+
+// RedisNotifier publishes the invalidation events in a redis channel.
+//
+// The events are batched and published at most once per interval, so a burst of
+// changes results in a single invalidation of the consumers caches.
+type RedisNotifier struct {
+	client     *redis.Client
+	channel    string
+	p2pNetwork string
+	interval   time.Duration
+	mu         sync.Mutex
+	pending    map[string]bool
+	logger     *zap.Logger
+}
+
+// NewRedisNotifier creates a new RedisNotifier.
+// After create a RedisNotifier use the Run method to publish the events.
+func NewRedisNotifier(client *redis.Client, channel, p2pNetwork string, interval time.Duration, logger *zap.Logger) *RedisNotifier {
+	return &RedisNotifier{
+		client:     client,
+		channel:    channel,
+		p2pNetwork: p2pNetwork,
+		interval:   interval,
+		pending:    make(map[string]bool),
+		logger:     logger.With(zap.String("module", "CacheInvalidationNotifier")),
+	}
+}
+
+// Notify adds an event to be published in the next interval.
+func (n *RedisNotifier) Notify(event string) {
+	n.mu.Lock()
+	n.pending[event] = true
+	n.mu.Unlock()
+}
+
+// Run publishes the pending events every interval until the context is cancelled.
+func (n *RedisNotifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n.flush(ctx)
+		}
+	}
+}
+
+func (n *RedisNotifier) flush(ctx context.Context) {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = make(map[string]bool)
+	n.mu.Unlock()
+
+	for event := range pending {
+		msg, err := json.Marshal(Event{Event: event, P2pNetwork: n.p2pNetwork, Timestamp: time.Now()})
+		if err != nil {
+			n.logger.Error("failed to marshal invalidation event", zap.Error(err), zap.String("event", event))
+			continue
+		}
+		if err := n.client.Publish(ctx, n.channel, msg).Err(); err != nil {
+			n.logger.Error("failed to publish invalidation event", zap.Error(err), zap.String("event", event))
+		}
+	}
+}
+
+// DummyNotifier is a notifier that discards the events.
+type DummyNotifier struct{}
+
+// NewDummyNotifier creates a new DummyNotifier.
+func NewDummyNotifier() *DummyNotifier {
+	return &DummyNotifier{}
+}
+
+// Notify discards the event.
+func (d *DummyNotifier) Notify(event string) {}
+
+// Subscriber receives the invalidation events of a network.
+type Subscriber struct {
+	pubSub     *redis.PubSub
+	p2pNetwork string
+	logger     *zap.Logger
+}
+
+// NewSubscriber subscribes to the invalidation events of a network.
+// After create a Subscriber use the Start method to handle the events.
+func NewSubscriber(ctx context.Context, client *redis.Client, channel, p2pNetwork string, logger *zap.Logger) (*Subscriber, error) {
+	if client == nil {
+		return nil, errors.New("redis client is nil")
+	}
+	return &Subscriber{
+		pubSub:     client.Subscribe(ctx, channel),
+		p2pNetwork: p2pNetwork,
+		logger:     logger.With(zap.String("module", "CacheInvalidationSubscriber")),
+	}, nil
+}
+
+// Start calls the handler with each event of the network, until the subscriber is closed.
+func (s *Subscriber) Start(handler func(Event)) {
+	ch := s.pubSub.Channel()
+	go func() {
+		for msg := range ch {
+			var e Event
+			if err := json.Unmarshal([]byte(msg.Payload), &e); err != nil {
+				s.logger.Warn("invalid invalidation event", zap.Error(err), zap.String("payload", msg.Payload))
+				continue
+			}
+			if e.P2pNetwork != s.p2pNetwork {
+				continue
+			}
+			handler(e)
+		}
+	}()
+}
+
+// Close unsubscribes from the invalidation events.
+func (s *Subscriber) Close() error {
+	return s.pubSub.Close()
+}
@@ -1,3 +1,4 @@
+// Package parser is a client of the vaa-payload-parser service, which parses the payload of the VAAs.
 package parser
 
 import (
@@ -7,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -15,6 +17,9 @@ import (
 
 const DefaultTimeout = 10
 
+// VersionHeader is the header of the parse responses with the version of the vaa-payload-parser service.
+const VersionHeader = "X-Parser-Version"
+
 var (
 	ErrCallEndpoint        = errors.New("ERROR CALL ENPOINT")
 	ErrNotFound            = errors.New("NOT FOUND")
@@ -32,6 +37,21 @@ type ParseVaaResponse struct {
 	Result         interface{} `json:"result"`
 }
 
+// StandardizedProperties represent a standardized properties.
+type StandardizedProperties struct {
+	AppIds       []string    `json:"appIds" bson:"appIds"`
+	FromChain    sdk.ChainID `json:"fromChain" bson:"fromChain"`
+	FromAddress  string      `json:"fromAddress" bson:"fromAddress"`
+	ToChain      sdk.ChainID `json:"toChain" bson:"toChain"`
+	ToAddress    string      `json:"toAddress" bson:"toAddress"`
+	TokenChain   sdk.ChainID `json:"tokenChain" bson:"tokenChain"`
+	TokenAddress string      `json:"tokenAddress" bson:"tokenAddress"`
+	Amount       string      `json:"amount" bson:"amount"`
+	FeeAddress   string      `json:"feeAddress" bson:"feeAddress"`
+	FeeChain     sdk.ChainID `json:"feeChain" bson:"feeChain"`
+	Fee          string      `json:"fee" bson:"fee"`
+}
+
 // ParserVAAAPIClient parse vaa api client.
 type ParserVAAAPIClient struct {
 	Client  http.Client
@@ -109,6 +129,8 @@ func (c ParserVAAAPIClient) ParsePayload(chainID uint16, address, sequence strin
 type ParseVaaWithStandarizedPropertiesdResponse struct {
 	ParsedPayload          interface{}            `json:"parsedPayload"`
 	StandardizedProperties StandardizedProperties `json:"standardizedProperties"`
+	// Version is the version of the service that parsed the VAA, reported in the VersionHeader, or 0 if it is not reported.
+	Version int `json:"-"`
 }
 
 // ParseVaaWithStandarizedProperties invoke the endpoint to parse a VAA from the VAAParserAPI.
@@ -133,6 +155,7 @@ func (c *ParserVAAAPIClient) ParseVaaWithStandarizedProperties(vaa *sdk.VAA) (*P
 	case http.StatusCreated:
 		var parsedVAA ParseVaaWithStandarizedPropertiesdResponse
 		json.NewDecoder(response.Body).Decode(&parsedVAA)
+		parsedVAA.Version, _ = strconv.Atoi(response.Header.Get(VersionHeader))
 		return &parsedVAA, nil
 	case http.StatusNotFound:
 		return nil, ErrNotFound
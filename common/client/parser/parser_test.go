@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
@@ -135,3 +136,30 @@ func TestInternalErrorVaaParser(t *testing.T) {
 		t.Error("expected parserVaaResponse zero value, got %w", parserVaaResponse)
 	}
 }
+
+// TestParseVaaWithStandarizedPropertiesVersion test the version reported by the vaa parser.
+func TestParseVaaWithStandarizedPropertiesVersion(t *testing.T) {
+	version := ""
+	parserVaaClient := NewParserVAAAPITestClient(func(request *http.Request) *http.Response {
+		header := http.Header{}
+		if version != "" {
+			header.Set(VersionHeader, version)
+		}
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{"parsedPayload": {}, "standardizedProperties": {"appIds": ["PORTAL_TOKEN_BRIDGE"]}}`)),
+		}
+	})
+
+	for expected, header := range map[int]string{0: "", 3: "3"} {
+		version = header
+		parsed, err := parserVaaClient.ParseVaaWithStandarizedProperties(&sdk.VAA{})
+		if err != nil {
+			t.Fatalf("expected err zero value, got %v", err)
+		}
+		if parsed.Version != expected {
+			t.Errorf("expected version %d, got %d", expected, parsed.Version)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"math/big"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// TransformStandarizedProperties transform amount and fee amount to 8 decimals, as the parser stores them.
+func TransformStandarizedProperties(vaaID string, sp StandardizedProperties, logger *zap.Logger) StandardizedProperties {
+	// transform amount.
+	amount := transformAmount(logger, sp.TokenChain, sp.TokenAddress, sp.Amount, vaaID)
+	// transform fee amount.
+	feeAmount := transformAmount(logger, sp.FeeChain, sp.FeeAddress, sp.Fee, vaaID)
+	// create StandardizedProperties.
+	return StandardizedProperties{
+		AppIds:       sp.AppIds,
+		FromChain:    sp.FromChain,
+		FromAddress:  sp.FromAddress,
+		ToChain:      sp.ToChain,
+		ToAddress:    sp.ToAddress,
+		TokenChain:   sp.TokenChain,
+		TokenAddress: sp.TokenAddress,
+		Amount:       amount,
+		FeeAddress:   sp.FeeAddress,
+		FeeChain:     sp.FeeChain,
+		Fee:          feeAmount,
+	}
+}
+
+// transformAmount transform amount and fee amount.
+func transformAmount(logger *zap.Logger, chainID sdk.ChainID, nativeAddress, amount, vaaID string) string {
+
+	if chainID == sdk.ChainIDUnset || nativeAddress == "" || amount == "" {
+		return ""
+	}
+
+	nativeHex, err := domain.DecodeNativeAddressToHex(sdk.ChainID(chainID), nativeAddress)
+	if err != nil {
+		logger.Warn("Native address cannot be transformed to hex",
+			zap.String("vaaId", vaaID),
+			zap.String("nativeAddress", nativeAddress),
+			zap.Uint16("chain", uint16(chainID)))
+		return ""
+	}
+
+	addr, err := sdk.StringToAddress(nativeHex)
+	if err != nil {
+		logger.Warn("Address cannot be parsed",
+			zap.String("vaaId", vaaID),
+			zap.String("nativeAddress", nativeAddress),
+			zap.Uint16("chain", uint16(chainID)))
+		return ""
+	}
+	// Get the token metadata
+	//
+	// This is complementary data about the token that is not present in the VAA itself.
+	tokenMeta, ok := domain.GetTokenByAddress(sdk.ChainID(chainID), addr.String())
+	if !ok {
+		logger.Warn("Token metadata not found",
+			zap.String("vaaId", vaaID),
+			zap.String("nativeAddress", nativeAddress),
+			zap.Uint16("chain", uint16(chainID)))
+		return ""
+	}
+
+	bigAmount := new(big.Int)
+	bigAmount, ok = bigAmount.SetString(amount, 10)
+	if !ok {
+		logger.Error("Cannot parse amount",
+			zap.String("vaaId", vaaID),
+			zap.String("amount", amount),
+			zap.String("nativeAddress", nativeAddress),
+			zap.Uint16("chain", uint16(chainID)))
+		return ""
+	}
+
+	if tokenMeta.Decimals < 8 {
+		// factor = 10 ^ (8 - tokenMeta.Decimals)
+		var factor big.Int
+		factor.Exp(big.NewInt(10), big.NewInt(int64(8-tokenMeta.Decimals)), nil)
+
+		bigAmount = bigAmount.Mul(bigAmount, &factor)
+	}
+
+	return bigAmount.String()
+}
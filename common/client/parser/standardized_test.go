@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"testing"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+func TestTransformStandarizedProperties(t *testing.T) {
+	sp := StandardizedProperties{
+		AppIds:       []string{"PORTAL_TOKEN_BRIDGE"},
+		TokenChain:   sdk.ChainIDEthereum,
+		TokenAddress: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		Amount:       "1500000",
+		FeeChain:     sdk.ChainIDEthereum,
+		FeeAddress:   "0x0000000000000000000000000000000000000001",
+		Fee:          "10",
+	}
+
+	transformed := TransformStandarizedProperties("2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1", sp, zap.NewNop())
+	// USDC has 6 decimals, the amounts are normalized to 8 decimals.
+	if transformed.Amount != "150000000" {
+		t.Errorf("expected amount 150000000, got %s", transformed.Amount)
+	}
+	// the amounts of unknown tokens are discarded.
+	if transformed.Fee != "" {
+		t.Errorf("expected empty fee, got %s", transformed.Fee)
+	}
+	if transformed.TokenAddress != sp.TokenAddress || len(transformed.AppIds) != 1 {
+		t.Errorf("unexpected properties %+v", transformed)
+	}
+}
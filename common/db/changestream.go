@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// resumeTokensCollection is the collection of the resume tokens of the change streams.
+const resumeTokensCollection = "changeStreamResumeTokens"
+
+// catchUpOverlap is the margin of the catch-up queries, since the documents are indexed
+// by other services whose clocks may be skewed.
+const catchUpOverlap = time.Minute
+
+// Mongo error codes of a resume token that can not be used anymore.
+const (
+	errCodeInvalidResumeToken      = 260
+	errCodeChangeStreamFatalError  = 280
+	errCodeChangeStreamHistoryLost = 286
+)
+
+// ResumeToken is the last position of a change stream.
+type ResumeToken struct {
+	ID    string   `bson:"_id"`
+	Token bson.Raw `bson:"token"`
+	// Timestamp is the cluster time of the last event received.
+	Timestamp time.Time `bson:"timestamp"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// CatchUpFunc handles the documents inserted since a time, when the change stream can not be resumed.
+type CatchUpFunc func(ctx context.Context, since time.Time) error
+
+// ChangeStreamHandler handles an event of a change stream.
+type ChangeStreamHandler func(ctx context.Context, event bson.Raw)
+
+// ChangeStream is a database change stream that resumes from its last position after a restart or an error.
+//
+// The resume token is saved periodically, so the events received after the last save are handled again
+// when the stream is resumed.
+type ChangeStream struct {
+	db           *mongo.Database
+	tokens       *mongo.Collection
+	id           string
+	pipeline     []bson.D
+	catchUp      CatchUpFunc
+	saveInterval time.Duration
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	logger       *zap.Logger
+}
+
+// NewChangeStream creates a new change stream of a database.
+// The id identifies the resume token of the stream, and catchUp handles the documents inserted while the stream was
+// stopped if its resume token is no longer in the oplog.
+func NewChangeStream(db *mongo.Database, id string, pipeline []bson.D, catchUp CatchUpFunc, saveInterval time.Duration, logger *zap.Logger) *ChangeStream {
+	return &ChangeStream{
+		db:           db,
+		tokens:       db.Collection(resumeTokensCollection),
+		id:           id,
+		pipeline:     pipeline,
+		catchUp:      catchUp,
+		saveInterval: saveInterval,
+		minBackoff:   time.Second,
+		maxBackoff:   time.Minute,
+		logger:       logger.With(zap.String("changeStream", id)),
+	}
+}
+
+// Watch handles the events of the change stream until the context is cancelled,
+// reconnecting with backoff when the stream fails.
+func (c *ChangeStream) Watch(ctx context.Context, handler ChangeStreamHandler) {
+	backoff := c.minBackoff
+	for {
+		start := time.Now()
+		err := c.watch(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+		// the backoff is reset when the stream was healthy for a while.
+		if time.Since(start) > c.maxBackoff {
+			backoff = c.minBackoff
+		}
+		c.logger.Error("Change stream failed, reconnecting", zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = nextBackoff(backoff, c.maxBackoff)
+	}
+}
+
+func nextBackoff(backoff, max time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
+
+func (c *ChangeStream) watch(ctx context.Context, handler ChangeStreamHandler) error {
+	token, err := c.loadToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := options.ChangeStream()
+	if token != nil {
+		opts.SetResumeAfter(token.Token)
+	}
+	stream, err := c.db.Watch(ctx, c.pipeline, opts)
+	if err != nil && token != nil && isResumeTokenLost(err) {
+		since := token.Timestamp.Add(-catchUpOverlap)
+		c.logger.Error("Resume token lost, catching up the documents inserted meanwhile",
+			zap.Time("since", since), zap.Error(err))
+		// the stream is opened before the catch-up, so the documents inserted meanwhile are not missed.
+		stream, err = c.db.Watch(ctx, c.pipeline)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if err := c.catchUp(ctx, since); err != nil {
+			stream.Close(ctx)
+			return errors.Wrap(err, "catching up change stream")
+		}
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+	defer stream.Close(ctx)
+
+	c.logger.Info("Change stream started", zap.Bool("resumed", token != nil))
+	lastSave := time.Now()
+	var lastTimestamp time.Time
+	for stream.Next(ctx) {
+		if t, _, ok := stream.Current.Lookup("clusterTime").TimestampOK(); ok {
+			lastTimestamp = time.Unix(int64(t), 0)
+		}
+		handler(ctx, stream.Current)
+		if time.Since(lastSave) >= c.saveInterval {
+			c.saveToken(ctx, stream.ResumeToken(), lastTimestamp)
+			lastSave = time.Now()
+		}
+	}
+	// the last position is saved even if the context was cancelled.
+	if !lastTimestamp.IsZero() {
+		saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		c.saveToken(saveCtx, stream.ResumeToken(), lastTimestamp)
+		cancel()
+	}
+	return errors.WithStack(stream.Err())
+}
+
+func (c *ChangeStream) loadToken(ctx context.Context) (*ResumeToken, error) {
+	var token ResumeToken
+	err := c.tokens.FindOne(ctx, bson.D{{Key: "_id", Value: c.id}}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &token, nil
+}
+
+func (c *ChangeStream) saveToken(ctx context.Context, token bson.Raw, timestamp time.Time) {
+	if token == nil {
+		return
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "token", Value: token},
+		{Key: "timestamp", Value: timestamp},
+		{Key: "updatedAt", Value: time.Now()},
+	}}}
+	_, err := c.tokens.UpdateByID(ctx, c.id, update, options.Update().SetUpsert(true))
+	if err != nil {
+		c.logger.Error("Saving resume token", zap.Error(err))
+	}
+}
+
+// isResumeTokenLost returns whether an error is caused by a resume token that is no longer in the oplog.
+func isResumeTokenLost(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	return serverErr.HasErrorCode(errCodeChangeStreamHistoryLost) ||
+		serverErr.HasErrorCode(errCodeInvalidResumeToken) ||
+		serverErr.HasErrorCode(errCodeChangeStreamFatalError)
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/test-go/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 2*time.Second, nextBackoff(time.Second, time.Minute))
+	assert.Equal(t, time.Minute, nextBackoff(40*time.Second, time.Minute))
+}
+
+func TestIsResumeTokenLost(t *testing.T) {
+	assert.True(t, isResumeTokenLost(mongo.CommandError{Code: errCodeChangeStreamHistoryLost}))
+	assert.True(t, isResumeTokenLost(errors.WithStack(mongo.CommandError{Code: errCodeInvalidResumeToken})))
+	assert.False(t, isResumeTokenLost(mongo.CommandError{Code: 11000}))
+	assert.False(t, isResumeTokenLost(errors.New("connection refused")))
+}
+
+func TestChangeStream_ResumeFromSavedToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("resume", func(mt *mtest.T) {
+		saved := bson.D{{Key: "_data", Value: "saved"}}
+		ns := mt.DB.Name() + "." + resumeTokensCollection
+		mt.AddMockResponses(
+			// the saved resume token.
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: "test"},
+				{Key: "token", Value: saved},
+				{Key: "timestamp", Value: time.Now()},
+			}),
+			// the resumed stream returns an event and ends.
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".$cmd.aggregate", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: bson.D{{Key: "_data", Value: "next"}}},
+				{Key: "operationType", Value: "insert"},
+				{Key: "clusterTime", Value: primitive.Timestamp{T: uint32(time.Now().Unix())}},
+			}),
+			// the position of the event is saved.
+			mtest.CreateSuccessResponse(),
+		)
+
+		catchUp := func(ctx context.Context, since time.Time) error {
+			mt.Fatal("unexpected catch-up")
+			return nil
+		}
+		stream := NewChangeStream(mt.DB, "test", []bson.D{}, catchUp, time.Hour, zap.NewNop())
+		events := 0
+		err := stream.watch(context.Background(), func(ctx context.Context, event bson.Raw) { events++ })
+		assert.Nil(t, err)
+		assert.Equal(t, 1, events)
+
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		aggregate := mt.GetStartedEvent()
+		assert.Equal(t, "aggregate", aggregate.CommandName)
+		resumeAfter := aggregate.Command.Lookup("pipeline", "0", "$changeStream", "resumeAfter", "_data")
+		assert.Equal(t, "saved", resumeAfter.StringValue())
+		update := mt.GetStartedEvent()
+		assert.Equal(t, "update", update.CommandName)
+		token := update.Command.Lookup("updates", "0", "u", "$set", "token", "_data")
+		assert.Equal(t, "next", token.StringValue())
+	})
+}
+
+func TestChangeStream_CatchUpAfterTokenLost(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("catch up", func(mt *mtest.T) {
+		savedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ns := mt.DB.Name() + "." + resumeTokensCollection
+		mt.AddMockResponses(
+			// the saved resume token.
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: "test"},
+				{Key: "token", Value: bson.D{{Key: "_data", Value: "lost"}}},
+				{Key: "timestamp", Value: savedAt},
+			}),
+			// the resume token is no longer in the oplog.
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code: errCodeChangeStreamHistoryLost, Name: "ChangeStreamHistoryLost", Message: "history lost"}),
+			// the new stream has no events.
+			mtest.CreateCursorResponse(0, mt.DB.Name()+".$cmd.aggregate", mtest.FirstBatch),
+		)
+
+		var caughtUpSince time.Time
+		catchUp := func(ctx context.Context, since time.Time) error {
+			caughtUpSince = since
+			return nil
+		}
+		stream := NewChangeStream(mt.DB, "test", []bson.D{}, catchUp, time.Hour, zap.NewNop())
+		err := stream.watch(context.Background(), func(ctx context.Context, event bson.Raw) {})
+		assert.Nil(t, err)
+		assert.Equal(t, savedAt.Add(-catchUpOverlap), caughtUpSince.UTC())
+
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.Equal(t, "aggregate", mt.GetStartedEvent().CommandName)
+		// the new stream starts from the current position.
+		aggregate := mt.GetStartedEvent()
+		assert.Equal(t, "aggregate", aggregate.CommandName)
+		_, err = aggregate.Command.LookupErr("pipeline", "0", "$changeStream", "resumeAfter")
+		assert.NotNil(t, err)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
@@ -17,7 +18,7 @@ type Database struct {
 
 // New connects to DB and returns a client that will disconnect when the passed in context is cancelled.
 func New(appCtx context.Context, log *zap.Logger, uri, databaseName string) (*Database, error) {
-	cli, err := mongo.Connect(appCtx, options.Client().ApplyURI(uri))
+	cli, err := mongo.Connect(appCtx, options.Client().ApplyURI(uri).SetMonitor(telemetry.NewMongoMonitor()))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,75 @@
+package payload
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	wormhole "github.com/wormhole-foundation/wormhole/sdk"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// relayerAddresses are the addresses of the generic relayer contract, deployed with the same address in the EVM chains of each network.
+var relayerAddresses = map[string]string{
+	domain.P2pMainNet: "00000000000000000000000027428dd2d3dd32a4d7f7c497eaaa23130d894911",
+	domain.P2pTestNet: "00000000000000000000000080ac94316391752a193c1c47e27d382b507c93f3",
+}
+
+// relayerChains are the chains where the generic relayer is deployed.
+var relayerChains = []sdk.ChainID{
+	sdk.ChainIDEthereum,
+	sdk.ChainIDBSC,
+	sdk.ChainIDPolygon,
+	sdk.ChainIDAvalanche,
+	sdk.ChainIDFantom,
+	sdk.ChainIDCelo,
+	sdk.ChainIDMoonbeam,
+	sdk.ChainIDArbitrum,
+	sdk.ChainIDOptimism,
+	sdk.ChainIDBase,
+}
+
+// DefaultEmitters returns the known emitters of each native parser type in a network, with the format of NewRegistry.
+// It returns no emitters for an unknown network.
+func DefaultEmitters(p2pNetwork string) map[string][]string {
+	var tokenBridge, nftBridge map[sdk.ChainID][]byte
+	switch p2pNetwork {
+	case domain.P2pMainNet:
+		tokenBridge, nftBridge = wormhole.KnownTokenbridgeEmitters, wormhole.KnownNFTBridgeEmitters
+	case domain.P2pTestNet:
+		tokenBridge, nftBridge = wormhole.KnownTestnetTokenbridgeEmitters, wormhole.KnownTestnetNFTBridgeEmitters
+	default:
+		return map[string][]string{}
+	}
+
+	relayerAddress, _ := sdk.StringToAddress(relayerAddresses[p2pNetwork])
+	relayer := make(map[sdk.ChainID][]byte, len(relayerChains))
+	for _, chainID := range relayerChains {
+		relayer[chainID] = relayerAddress.Bytes()
+	}
+
+	return map[string][]string{
+		TypeTokenBridge: formatEmitters(tokenBridge),
+		TypeNFTBridge:   formatEmitters(nftBridge),
+		TypeRelayer:     formatEmitters(relayer),
+	}
+}
+
+// formatEmitters formats the emitters by chain as chainID/emitterAddress, sorted by chain.
+func formatEmitters(emitters map[sdk.ChainID][]byte) []string {
+	chains := make([]sdk.ChainID, 0, len(emitters))
+	for chainID := range emitters {
+		chains = append(chains, chainID)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i] < chains[j] })
+
+	formatted := make([]string, 0, len(chains))
+	for _, chainID := range chains {
+		addr, err := sdk.BytesToAddress(emitters[chainID])
+		if err != nil {
+			continue
+		}
+		formatted = append(formatted, fmt.Sprintf("%d/%s", chainID, addr.String()))
+	}
+	return formatted
+}
@@ -0,0 +1,57 @@
+package payload
+
+import (
+	"fmt"
+
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// nftBridgeTransfer is the payload ID of the NFT bridge transfers.
+const nftBridgeTransfer = 1
+
+// parseNFTBridge parses the transfers of the NFT bridge.
+func parseNFTBridge(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error) {
+	if len(vaa.Payload) == 0 {
+		return nil, fmt.Errorf("empty NFT bridge payload")
+	}
+	r := NewReader(vaa.Payload)
+	payloadID := r.Uint8()
+	if payloadID != nftBridgeTransfer {
+		return nil, fmt.Errorf("unknown NFT bridge payload %d", payloadID)
+	}
+
+	tokenAddress := r.Address()
+	tokenChain := r.Chain()
+	symbol := r.String32()
+	name := r.String32()
+	tokenID := r.Uint256()
+	uri := string(r.Bytes(int(r.Uint8())))
+	toAddress := r.Address()
+	toChain := r.Chain()
+	if err := r.End(); err != nil {
+		return nil, err
+	}
+
+	return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+		ParsedPayload: map[string]interface{}{
+			"payloadId":    payloadID,
+			"tokenAddress": hexAddress(tokenAddress),
+			"tokenChain":   tokenChain,
+			"symbol":       symbol,
+			"name":         name,
+			"tokenId":      tokenID,
+			"uri":          uri,
+			"toAddress":    hexAddress(toAddress),
+			"toChain":      toChain,
+		},
+		StandardizedProperties: vaaPayloadParser.StandardizedProperties{
+			AppIds:       []string{AppIdPortalNFTBridge},
+			FromChain:    vaa.EmitterChain,
+			ToChain:      toChain,
+			ToAddress:    nativeAddress(toChain, toAddress),
+			TokenChain:   tokenChain,
+			TokenAddress: nativeAddress(tokenChain, tokenAddress),
+		},
+	}, nil
+}
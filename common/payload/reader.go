@@ -0,0 +1,107 @@
+package payload
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Reader reads the big-endian fields of a VAA payload. The first error is kept, and the fields read after it are zero.
+type Reader struct {
+	buf *bytes.Reader
+	err error
+}
+
+// NewReader creates a new Reader of a payload.
+func NewReader(payload []byte) *Reader {
+	return &Reader{buf: bytes.NewReader(payload)}
+}
+
+// Bytes returns the next n bytes.
+func (r *Reader) Bytes(n int) []byte {
+	b := make([]byte, n)
+	if r.err != nil {
+		return b
+	}
+	if _, err := io.ReadFull(r.buf, b); err != nil {
+		r.err = errors.New("unexpected end of payload")
+	}
+	return b
+}
+
+func (r *Reader) Uint8() uint8 {
+	return r.Bytes(1)[0]
+}
+
+func (r *Reader) Uint32() uint32 {
+	return binary.BigEndian.Uint32(r.Bytes(4))
+}
+
+func (r *Reader) Uint64() uint64 {
+	return binary.BigEndian.Uint64(r.Bytes(8))
+}
+
+func (r *Reader) Chain() sdk.ChainID {
+	return sdk.ChainID(binary.BigEndian.Uint16(r.Bytes(2)))
+}
+
+func (r *Reader) Address() sdk.Address {
+	var addr sdk.Address
+	copy(addr[:], r.Bytes(32))
+	return addr
+}
+
+// Uint256 returns a 256-bit unsigned integer as a decimal string.
+func (r *Reader) Uint256() string {
+	return new(big.Int).SetBytes(r.Bytes(32)).String()
+}
+
+// String32 returns a string padded with zeros to 32 bytes.
+func (r *Reader) String32() string {
+	return strings.TrimRight(string(r.Bytes(32)), "\x00")
+}
+
+// Rest returns the remaining bytes of the payload.
+func (r *Reader) Rest() []byte {
+	return r.Bytes(r.buf.Len())
+}
+
+// Err returns the first error.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// End returns the first error, or an error if the payload has unexpected bytes at the end.
+func (r *Reader) End() error {
+	if r.err == nil && r.buf.Len() > 0 {
+		return errors.New("unexpected bytes at the end of payload")
+	}
+	return r.err
+}
+
+// hexAddress returns an address as 0x-prefixed hex, as the vaa-payload-parser service.
+func hexAddress(addr sdk.Address) string {
+	return "0x" + hex.EncodeToString(addr[:])
+}
+
+// nativeAddress returns an address in the native format of its chain, as expected in the standardized properties.
+//
+// The addresses of the chains without a native format known from the address alone are returned as 0x-prefixed hex.
+func nativeAddress(chainID sdk.ChainID, addr sdk.Address) string {
+	switch chainID {
+	case sdk.ChainIDNear, sdk.ChainIDSui, sdk.ChainIDAptos:
+		return hexAddress(addr)
+	}
+	native, err := domain.TranslateEmitterAddress(chainID, hex.EncodeToString(addr[:]))
+	if err != nil {
+		return hexAddress(addr)
+	}
+	return native
+}
@@ -0,0 +1,209 @@
+// Package payload parses the payloads of the VAAs natively, with a parser registered by chain and emitter.
+//
+// The emitters without a native parser are parsed by the vaa-payload-parser service, if it is configured.
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Native parser types, configured by emitter.
+const (
+	TypeTokenBridge = "tokenBridge"
+	TypeNFTBridge   = "nftBridge"
+	TypeRelayer     = "relayer"
+)
+
+// App IDs of the standardized properties.
+const (
+	AppIdPortalTokenBridge = "PORTAL_TOKEN_BRIDGE"
+	AppIdPortalNFTBridge   = "PORTAL_NFT_BRIDGE"
+	AppIdGenericRelayer    = "GENERIC_RELAYER"
+)
+
+// Types of the parsers of the VAAs.
+const (
+	ParserTypeNative = "native"
+	ParserTypeRemote = "remote"
+)
+
+// ParserVersion is the parser that produced a parsed vaa, to find the vaas parsed by an outdated parser.
+type ParserVersion struct {
+	// Type is the native parser or the remote vaa-payload-parser service.
+	Type string `bson:"type" json:"type"`
+	// Version of the native parsers or of the remote service, increased when the parsing logic changes.
+	Version int `bson:"version" json:"version"`
+}
+
+// NativeParserVersion is the version of the native parsers, stamped on the VAAs they parse.
+// It must be increased when the parsing logic changes, so the VAAs parsed by the previous version can be reparsed.
+const NativeParserVersion = 1
+
+// ParseFunc parses the payload of a VAA into the same response of the vaa-payload-parser service.
+type ParseFunc func(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error)
+
+// parsers contains the native parsers of each type.
+var parsers = map[string]ParseFunc{
+	TypeTokenBridge: parseTokenBridge,
+	TypeNFTBridge:   parseNFTBridge,
+	TypeRelayer:     parseRelayer,
+}
+
+type emitterKey struct {
+	chainID sdk.ChainID
+	address sdk.Address
+}
+
+// Registry parses the VAAs with the native parser of their emitter.
+type Registry struct {
+	parsers       map[emitterKey]ParseFunc
+	remote        *vaaPayloadParser.ParserVAAAPIClient
+	remoteVersion int
+	logger        *zap.Logger
+}
+
+// NewRegistry creates a new Registry with the emitters of each native parser type, with the format chainID/emitterAddress:
+//
+//	{"tokenBridge": ["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"]}
+//
+// If remote is not nil, it parses the VAAs of the other emitters, and the VAAs that the native parsers
+// do not support, stamped with remoteVersion.
+func NewRegistry(emitters map[string][]string, remote *vaaPayloadParser.ParserVAAAPIClient, remoteVersion int, logger *zap.Logger) (*Registry, error) {
+	r := Registry{
+		parsers:       make(map[emitterKey]ParseFunc),
+		remote:        remote,
+		remoteVersion: remoteVersion,
+		logger:        logger,
+	}
+	for parserType, addresses := range emitters {
+		parse, ok := parsers[parserType]
+		if !ok {
+			return nil, fmt.Errorf("unknown parser type %s: must be %s, %s or %s", parserType, TypeTokenBridge, TypeNFTBridge, TypeRelayer)
+		}
+		for _, a := range addresses {
+			key, err := parseEmitterKey(a)
+			if err != nil {
+				return nil, fmt.Errorf("invalid emitter %s of parser %s: %v", a, parserType, err)
+			}
+			if _, exists := r.parsers[key]; exists {
+				return nil, fmt.Errorf("emitter %s has more than one parser", a)
+			}
+			r.parsers[key] = parse
+		}
+	}
+	return &r, nil
+}
+
+// Config is the configuration of a Registry.
+type Config struct {
+	// P2pNetwork selects the default emitters of the native parsers, see DefaultEmitters.
+	P2pNetwork string
+	// Emitters of each native parser type encoded as JSON, see NewRegistry.
+	// The emitters of a type replace its default emitters, so a type with no emitters disables its native parser.
+	Emitters string
+	// RemoteURL is the vaa-payload-parser service, it is used as fallback only if it is not empty.
+	RemoteURL     string
+	RemoteTimeout int64
+	// RemoteVersion is stamped on the VAAs parsed by the remote service when it does not report its version.
+	RemoteVersion int
+}
+
+// NewRegistryFromConfig creates a new Registry with the default emitters of the network and the configured emitters.
+func NewRegistryFromConfig(cfg Config, logger *zap.Logger) (*Registry, error) {
+	parserEmitters := DefaultEmitters(cfg.P2pNetwork)
+	if cfg.Emitters != "" {
+		var configured map[string][]string
+		if err := json.Unmarshal([]byte(cfg.Emitters), &configured); err != nil {
+			return nil, fmt.Errorf("invalid native parser emitters: %w", err)
+		}
+		for parserType, addresses := range configured {
+			parserEmitters[parserType] = addresses
+		}
+	}
+	var remote *vaaPayloadParser.ParserVAAAPIClient
+	if cfg.RemoteURL != "" {
+		client, err := vaaPayloadParser.NewParserVAAAPIClient(cfg.RemoteTimeout, cfg.RemoteURL, logger)
+		if err != nil {
+			return nil, err
+		}
+		remote = &client
+	}
+	return NewRegistry(parserEmitters, remote, cfg.RemoteVersion, logger)
+}
+
+func parseEmitterKey(s string) (emitterKey, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return emitterKey{}, fmt.Errorf("must be chainID/emitterAddress")
+	}
+	chainID, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return emitterKey{}, err
+	}
+	addr, err := sdk.StringToAddress(parts[1])
+	if err != nil {
+		return emitterKey{}, err
+	}
+	return emitterKey{chainID: sdk.ChainID(chainID), address: addr}, nil
+}
+
+// Parse parses a VAA with the native parser of its emitter, or with the remote service,
+// and returns the version of the parser that parsed it.
+//
+// The errors are the same of the remote service: ErrNotFound if no parser supports the emitter,
+// and ErrUnproceesableEntity if the payload can not be parsed.
+func (r *Registry) Parse(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, ParserVersion, error) {
+	parse, ok := r.nativeParser(vaa)
+	if ok {
+		parsed, err := parse(vaa)
+		if err == nil {
+			return parsed, ParserVersion{Type: ParserTypeNative, Version: NativeParserVersion}, nil
+		}
+		r.logger.Debug("VAA cannot be parsed natively", zap.String("id", vaa.MessageID()), zap.Error(err))
+		if r.remote == nil {
+			return nil, ParserVersion{}, fmt.Errorf("%w: %v", vaaPayloadParser.ErrUnproceesableEntity, err)
+		}
+	}
+	if r.remote == nil {
+		return nil, ParserVersion{}, vaaPayloadParser.ErrNotFound
+	}
+	parsed, err := r.remote.ParseVaaWithStandarizedProperties(vaa)
+	if err != nil {
+		return nil, ParserVersion{}, err
+	}
+	// the version reported by the remote service is stamped, the configured version is used if it does not report it.
+	version := r.remoteVersion
+	if parsed.Version > 0 {
+		version = parsed.Version
+	}
+	return parsed, ParserVersion{Type: ParserTypeRemote, Version: version}, nil
+}
+
+// Version returns the version of the parser that parses a VAA, or false if no parser supports its emitter.
+func (r *Registry) Version(vaa *sdk.VAA) (ParserVersion, bool) {
+	if _, ok := r.nativeParser(vaa); ok {
+		return ParserVersion{Type: ParserTypeNative, Version: NativeParserVersion}, true
+	}
+	if r.remote != nil {
+		return ParserVersion{Type: ParserTypeRemote, Version: r.remoteVersion}, true
+	}
+	return ParserVersion{}, false
+}
+
+// Register sets the native parser of an emitter, replacing its parser of the configured types.
+func (r *Registry) Register(chainID sdk.ChainID, address sdk.Address, parse ParseFunc) {
+	r.parsers[emitterKey{chainID: chainID, address: address}] = parse
+}
+
+// nativeParser returns the native parser of the emitter of a VAA.
+func (r *Registry) nativeParser(vaa *sdk.VAA) (ParseFunc, bool) {
+	parse, ok := r.parsers[emitterKey{chainID: vaa.EmitterChain, address: vaa.EmitterAddress}]
+	return parse, ok
+}
@@ -0,0 +1,219 @@
+package payload
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+const tokenBridgeEmitter = "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	r, err := NewRegistry(map[string][]string{TypeTokenBridge: {"2/" + tokenBridgeEmitter}}, nil, 0, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func newTestVaa(t *testing.T, emitter string, payload []byte) *sdk.VAA {
+	addr, err := sdk.StringToAddress(emitter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &sdk.VAA{EmitterChain: sdk.ChainIDEthereum, EmitterAddress: addr, Sequence: 1, Payload: payload}
+}
+
+func TestRegistry_TokenBridgeTransfer(t *testing.T) {
+	payload := decodeHex(t, "01"+
+		"0000000000000000000000000000000000000000000000000000000005f5e100"+
+		"000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"+"0002"+
+		"000000000000000000000000b1731c586ca89a23809861c6103f0b96b3f57d92"+"0006"+
+		"0000000000000000000000000000000000000000000000000000000000000064")
+
+	parsed, version, err := newTestRegistry(t).Parse(newTestVaa(t, tokenBridgeEmitter, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version.Type != ParserTypeNative || version.Version != NativeParserVersion {
+		t.Errorf("unexpected parser version %+v", version)
+	}
+	fields := parsed.ParsedPayload.(map[string]interface{})
+	if fields["payloadId"] != uint8(1) || fields["amount"] != "100000000" || fields["fee"] != "100" {
+		t.Errorf("unexpected parsed payload %v", fields)
+	}
+	expected := vaaPayloadParser.StandardizedProperties{
+		AppIds:       []string{AppIdPortalTokenBridge},
+		FromChain:    sdk.ChainIDEthereum,
+		ToChain:      sdk.ChainIDAvalanche,
+		ToAddress:    "0xb1731c586ca89a23809861c6103f0b96b3f57d92",
+		TokenChain:   sdk.ChainIDEthereum,
+		TokenAddress: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		Amount:       "100000000",
+		FeeChain:     sdk.ChainIDEthereum,
+		FeeAddress:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		Fee:          "100",
+	}
+	if !reflect.DeepEqual(expected, parsed.StandardizedProperties) {
+		t.Errorf("unexpected standardized properties %+v", parsed.StandardizedProperties)
+	}
+}
+
+func TestRegistry_TokenBridgeAttestMeta(t *testing.T) {
+	symbol := hex.EncodeToString([]byte("WETH")) + "00000000000000000000000000000000000000000000000000000000"
+	name := hex.EncodeToString([]byte("Wrapped Ether")) + "00000000000000000000000000000000000000"
+	payload := decodeHex(t, "02"+"000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"+"0002"+"12"+symbol+name)
+
+	parsed, _, err := newTestRegistry(t).Parse(newTestVaa(t, tokenBridgeEmitter, payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := parsed.ParsedPayload.(map[string]interface{})
+	if fields["symbol"] != "WETH" || fields["name"] != "Wrapped Ether" || fields["decimals"] != uint8(18) {
+		t.Errorf("unexpected parsed payload %v", fields)
+	}
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	// a truncated transfer can not be parsed.
+	_, _, err := r.Parse(newTestVaa(t, tokenBridgeEmitter, decodeHex(t, "0100")))
+	if !errors.Is(err, vaaPayloadParser.ErrUnproceesableEntity) {
+		t.Errorf("expected ErrUnproceesableEntity, got %v", err)
+	}
+
+	// an emitter without parser is not found when there is no remote parser.
+	_, _, err = r.Parse(newTestVaa(t, "01", []byte{1}))
+	if !errors.Is(err, vaaPayloadParser.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	if _, err := NewRegistry(map[string][]string{"unknown": {"2/" + tokenBridgeEmitter}}, nil, 0, zap.NewNop()); err == nil {
+		t.Error("expected error for unknown parser type")
+	}
+	if _, err := NewRegistry(map[string][]string{TypeNFTBridge: {tokenBridgeEmitter}}, nil, 0, zap.NewNop()); err == nil {
+		t.Error("expected error for emitter without chain")
+	}
+	if _, err := NewRegistry(map[string][]string{
+		TypeTokenBridge: {"2/" + tokenBridgeEmitter},
+		TypeNFTBridge:   {"2/" + tokenBridgeEmitter},
+	}, nil, 0, zap.NewNop()); err == nil {
+		t.Error("expected error for emitter with two parsers")
+	}
+}
+
+func TestRegistry_Version(t *testing.T) {
+	r := newTestRegistry(t)
+
+	version, ok := r.Version(newTestVaa(t, tokenBridgeEmitter, nil))
+	if !ok || version.Type != ParserTypeNative || version.Version != NativeParserVersion {
+		t.Errorf("unexpected version %+v", version)
+	}
+	if _, ok := r.Version(newTestVaa(t, "01", nil)); ok {
+		t.Error("expected no version for an emitter without parser")
+	}
+}
+
+func TestRegistry_RemoteVersion(t *testing.T) {
+	reported := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reported != "" {
+			w.Header().Set(vaaPayloadParser.VersionHeader, reported)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"parsedPayload":{}}`))
+	}))
+	defer server.Close()
+	remote, err := vaaPayloadParser.NewParserVAAAPIClient(1, server.URL, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRegistry(map[string][]string{}, &remote, 2, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the configured version is used when the service does not report its version.
+	_, version, err := r.Parse(newTestVaa(t, tokenBridgeEmitter, nil))
+	if err != nil || version != (ParserVersion{Type: ParserTypeRemote, Version: 2}) {
+		t.Errorf("unexpected version %+v, err %v", version, err)
+	}
+
+	reported = "3"
+	_, version, err = r.Parse(newTestVaa(t, tokenBridgeEmitter, nil))
+	if err != nil || version != (ParserVersion{Type: ParserTypeRemote, Version: 3}) {
+		t.Errorf("unexpected version %+v, err %v", version, err)
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	vaa := &sdk.VAA{EmitterChain: sdk.ChainIDSolana, EmitterAddress: sdk.GovernanceEmitter, Sequence: 1, Payload: []byte{1}}
+
+	// the emitters are parsed natively only once their parser is registered.
+	r := newTestRegistry(t)
+	if _, ok := r.Version(vaa); ok {
+		t.Error("expected no version for the governance emitter")
+	}
+	r.Register(sdk.ChainIDSolana, sdk.GovernanceEmitter, func(*sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error) {
+		return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{}, nil
+	})
+	if version, ok := r.Version(vaa); !ok || version.Type != ParserTypeNative {
+		t.Errorf("unexpected version %+v", version)
+	}
+}
+
+func TestNewRegistryFromConfig(t *testing.T) {
+	ethereumTokenBridge := newTestVaa(t, tokenBridgeEmitter, nil)
+	ethereumRelayer := newTestVaa(t, "00000000000000000000000027428dd2d3dd32a4d7f7c497eaaa23130d894911", nil)
+
+	// the known emitters of the network are parsed natively by default.
+	r, err := NewRegistryFromConfig(Config{P2pNetwork: "mainnet"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Version(ethereumTokenBridge); !ok {
+		t.Error("expected the token bridge emitter to be parsed natively")
+	}
+	if _, ok := r.Version(ethereumRelayer); !ok {
+		t.Error("expected the relayer emitter to be parsed natively")
+	}
+
+	// the configured emitters replace the default emitters of their type.
+	r, err = NewRegistryFromConfig(Config{P2pNetwork: "mainnet", Emitters: `{"relayer": []}`}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Version(ethereumTokenBridge); !ok {
+		t.Error("expected the token bridge emitter to be parsed natively")
+	}
+	if _, ok := r.Version(ethereumRelayer); ok {
+		t.Error("expected the relayer emitter to not be parsed natively")
+	}
+
+	// the known emitters of other networks are not parsed natively.
+	r, err = NewRegistryFromConfig(Config{P2pNetwork: "testnet"}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Version(ethereumTokenBridge); ok {
+		t.Error("expected the mainnet token bridge emitter to not be parsed natively in testnet")
+	}
+}
@@ -0,0 +1,122 @@
+package payload
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Standard relayer payload IDs.
+const (
+	relayerDeliveryInstruction   = 1
+	relayerRedeliveryInstruction = 2
+)
+
+// relayerVaaKey is the type of the message keys that reference a VAA.
+const relayerVaaKey = 1
+
+// parseRelayer parses the delivery and redelivery instructions of the standard relayer.
+func parseRelayer(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error) {
+	if len(vaa.Payload) == 0 {
+		return nil, fmt.Errorf("empty relayer payload")
+	}
+	r := NewReader(vaa.Payload)
+	payloadID := r.Uint8()
+
+	switch payloadID {
+	case relayerDeliveryInstruction:
+		targetChain := r.Chain()
+		targetAddress := r.Address()
+		payload := r.Bytes(int(r.Uint32()))
+		requestedReceiverValue := r.Uint256()
+		extraReceiverValue := r.Uint256()
+		executionInfo := r.Bytes(int(r.Uint32()))
+		refundChain := r.Chain()
+		refundAddress := r.Address()
+		refundDeliveryProvider := r.Address()
+		sourceDeliveryProvider := r.Address()
+		senderAddress := r.Address()
+		n := r.Uint8()
+		messageKeys := make([]map[string]interface{}, 0, n)
+		for i := 0; i < int(n) && r.err == nil; i++ {
+			messageKeys = append(messageKeys, r.messageKey())
+		}
+		if err := r.End(); err != nil {
+			return nil, err
+		}
+		return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+			ParsedPayload: map[string]interface{}{
+				"payloadId":              payloadID,
+				"targetChainId":          targetChain,
+				"targetAddress":          hexAddress(targetAddress),
+				"payload":                hex.EncodeToString(payload),
+				"requestedReceiverValue": requestedReceiverValue,
+				"extraReceiverValue":     extraReceiverValue,
+				"encodedExecutionInfo":   hex.EncodeToString(executionInfo),
+				"refundChainId":          refundChain,
+				"refundAddress":          hexAddress(refundAddress),
+				"refundDeliveryProvider": hexAddress(refundDeliveryProvider),
+				"sourceDeliveryProvider": hexAddress(sourceDeliveryProvider),
+				"senderAddress":          hexAddress(senderAddress),
+				"messageKeys":            messageKeys,
+			},
+			StandardizedProperties: vaaPayloadParser.StandardizedProperties{
+				AppIds:      []string{AppIdGenericRelayer},
+				FromChain:   vaa.EmitterChain,
+				FromAddress: nativeAddress(vaa.EmitterChain, senderAddress),
+				ToChain:     targetChain,
+				ToAddress:   nativeAddress(targetChain, targetAddress),
+			},
+		}, nil
+
+	case relayerRedeliveryInstruction:
+		deliveryVaaKey := r.messageKey()
+		targetChain := r.Chain()
+		newRequestedReceiverValue := r.Uint256()
+		newExecutionInfo := r.Bytes(int(r.Uint32()))
+		newSourceDeliveryProvider := r.Address()
+		newSenderAddress := r.Address()
+		if err := r.End(); err != nil {
+			return nil, err
+		}
+		return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+			ParsedPayload: map[string]interface{}{
+				"payloadId":                 payloadID,
+				"deliveryVaaKey":            deliveryVaaKey,
+				"targetChainId":             targetChain,
+				"newRequestedReceiverValue": newRequestedReceiverValue,
+				"newEncodedExecutionInfo":   hex.EncodeToString(newExecutionInfo),
+				"newSourceDeliveryProvider": hexAddress(newSourceDeliveryProvider),
+				"newSenderAddress":          hexAddress(newSenderAddress),
+			},
+			StandardizedProperties: vaaPayloadParser.StandardizedProperties{
+				AppIds:      []string{AppIdGenericRelayer},
+				FromChain:   vaa.EmitterChain,
+				FromAddress: nativeAddress(vaa.EmitterChain, newSenderAddress),
+				ToChain:     targetChain,
+			},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown relayer payload %d", payloadID)
+	}
+}
+
+// messageKey reads a message key of the standard relayer: a VAA key, or the encoded key of other types.
+func (r *Reader) messageKey() map[string]interface{} {
+	keyType := r.Uint8()
+	if keyType == relayerVaaKey {
+		return map[string]interface{}{
+			"type":           keyType,
+			"chainId":        r.Chain(),
+			"emitterAddress": hexAddress(r.Address()),
+			"sequence":       r.Uint64(),
+		}
+	}
+	return map[string]interface{}{
+		"type":       keyType,
+		"encodedKey": hex.EncodeToString(r.Bytes(int(r.Uint32()))),
+	}
+}
@@ -0,0 +1,104 @@
+package payload
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// Token bridge payload IDs.
+const (
+	tokenBridgeTransfer            = 1
+	tokenBridgeAttestMeta          = 2
+	tokenBridgeTransferWithPayload = 3
+)
+
+// parseTokenBridge parses the Transfer, AttestMeta and TransferWithPayload payloads of the token bridge.
+func parseTokenBridge(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error) {
+	if len(vaa.Payload) == 0 {
+		return nil, fmt.Errorf("empty token bridge payload")
+	}
+	r := NewReader(vaa.Payload)
+	payloadID := r.Uint8()
+
+	switch payloadID {
+	case tokenBridgeTransfer, tokenBridgeTransferWithPayload:
+		amount := r.Uint256()
+		tokenAddress := r.Address()
+		tokenChain := r.Chain()
+		toAddress := r.Address()
+		toChain := r.Chain()
+		parsed := map[string]interface{}{
+			"payloadId":    payloadID,
+			"amount":       amount,
+			"tokenAddress": hexAddress(tokenAddress),
+			"tokenChain":   tokenChain,
+			"toAddress":    hexAddress(toAddress),
+			"toChain":      toChain,
+		}
+		sp := vaaPayloadParser.StandardizedProperties{
+			AppIds:       []string{AppIdPortalTokenBridge},
+			FromChain:    vaa.EmitterChain,
+			ToChain:      toChain,
+			ToAddress:    nativeAddress(toChain, toAddress),
+			TokenChain:   tokenChain,
+			TokenAddress: nativeAddress(tokenChain, tokenAddress),
+			Amount:       amount,
+		}
+
+		if payloadID == tokenBridgeTransfer {
+			fee := r.Uint256()
+			if err := r.End(); err != nil {
+				return nil, err
+			}
+			parsed["fee"] = fee
+			sp.FeeChain = tokenChain
+			sp.FeeAddress = sp.TokenAddress
+			sp.Fee = fee
+		} else {
+			fromAddress := r.Address()
+			payload := r.Rest()
+			if r.err != nil {
+				return nil, r.err
+			}
+			parsed["fromAddress"] = hexAddress(fromAddress)
+			parsed["payload"] = hex.EncodeToString(payload)
+			sp.FromAddress = nativeAddress(vaa.EmitterChain, fromAddress)
+		}
+		return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+			ParsedPayload:          parsed,
+			StandardizedProperties: sp,
+		}, nil
+
+	case tokenBridgeAttestMeta:
+		tokenAddress := r.Address()
+		tokenChain := r.Chain()
+		decimals := r.Uint8()
+		symbol := r.String32()
+		name := r.String32()
+		if err := r.End(); err != nil {
+			return nil, err
+		}
+		return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+			ParsedPayload: map[string]interface{}{
+				"payloadId":    payloadID,
+				"tokenAddress": hexAddress(tokenAddress),
+				"tokenChain":   tokenChain,
+				"decimals":     decimals,
+				"symbol":       symbol,
+				"name":         name,
+			},
+			StandardizedProperties: vaaPayloadParser.StandardizedProperties{
+				AppIds:       []string{AppIdPortalTokenBridge},
+				FromChain:    vaa.EmitterChain,
+				TokenChain:   tokenChain,
+				TokenAddress: nativeAddress(tokenChain, tokenAddress),
+			},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown token bridge payload %d", payloadID)
+	}
+}
@@ -0,0 +1,150 @@
+package reprocess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// JobsCollection is the name of the collection that stores the reprocessing jobs.
+const JobsCollection = "reprocessJobs"
+
+// JobEventsCollection is the name of the collection that stores the outcome of each event of a
+// reprocessing job in each stage, so redelivered events are not counted twice.
+const JobEventsCollection = "reprocessJobEvents"
+
+// Stage is a service of the pipeline that consumes the vaa events.
+type Stage string
+
+const (
+	StageParser    Stage = "parser"
+	StageTxTracker Stage = "tx-tracker"
+	StageAnalytics Stage = "analytics"
+)
+
+// AllStages contains all the stages that can be reprocessed.
+var AllStages = []Stage{StageParser, StageTxTracker, StageAnalytics}
+
+// ErrInvalidStage is returned when parsing an unknown stage.
+var ErrInvalidStage = errors.New("invalid stage")
+
+// ErrEventExpired is tracked when a stage drops an expired event of a reprocessing job.
+var ErrEventExpired = errors.New("event expired")
+
+// ParseStage parses a string and returns a `Stage`.
+func ParseStage(s string) (Stage, error) {
+	for _, stage := range AllStages {
+		if string(stage) == s {
+			return stage, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidStage, s)
+}
+
+// Request is attached to the events published by a reprocessing job.
+type Request struct {
+	JobID  string  `json:"jobId"`
+	Reason string  `json:"reason"`
+	Stages []Stage `json:"stages"`
+}
+
+// Includes returns true if the stage has to process the event.
+//
+// Events that are not part of a reprocessing job are processed by all the stages.
+func (r *Request) Includes(stage Stage) bool {
+	if r == nil {
+		return true
+	}
+	for _, s := range r.Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Tracker records the progress of the reprocessing jobs.
+type Tracker struct {
+	jobs   *mongo.Collection
+	events *mongo.Collection
+	logger *zap.Logger
+}
+
+// NewTracker creates a new reprocessing job tracker.
+func NewTracker(db *mongo.Database, logger *zap.Logger) *Tracker {
+	return &Tracker{
+		jobs:   db.Collection(JobsCollection),
+		events: db.Collection(JobEventsCollection),
+		logger: logger.With(zap.String("module", "ReprocessTracker")),
+	}
+}
+
+// jobEvent is a document of the reprocessJobEvents collection.
+type jobEvent struct {
+	ID      string `bson:"_id"`
+	Outcome string `bson:"outcome"`
+}
+
+// Track records the result of processing an event of a reprocessing job in a stage.
+// Events that are not part of a reprocessing job are ignored.
+//
+// An event is counted once per stage: a redelivered event with the same outcome does not change the
+// counters, and one with a different outcome moves the event from the previous counter to the new one.
+func (t *Tracker) Track(ctx context.Context, r *Request, stage Stage, vaaID string, err error) {
+	if t == nil || r == nil {
+		return
+	}
+	logger := t.logger.With(
+		zap.String("jobId", r.JobID),
+		zap.String("stage", string(stage)),
+		zap.String("id", vaaID))
+
+	outcome := "processed"
+	now := time.Now()
+	set := bson.D{{Key: "updatedAt", Value: now}}
+	if err != nil {
+		outcome = "failed"
+		set = append(set, bson.E{Key: fmt.Sprintf("stages.%s.lastError", stage), Value: fmt.Sprintf("%s: %s", vaaID, err)})
+	}
+
+	// store the outcome of the event and get the previous one.
+	eventID := fmt.Sprintf("%s/%s/%s", r.JobID, stage, vaaID)
+	eventUpdate := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "outcome", Value: outcome},
+		{Key: "updatedAt", Value: now},
+	}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+	var previous jobEvent
+	findErr := t.events.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: eventID}}, eventUpdate, opts).Decode(&previous)
+	if findErr != nil && !errors.Is(findErr, mongo.ErrNoDocuments) {
+		logger.Error("Error updating reprocessing job event", zap.Error(findErr))
+		return
+	}
+
+	update := bson.D{{Key: "$set", Value: set}}
+	if inc := counterUpdates(stage, previous.Outcome, outcome); len(inc) > 0 {
+		update = append(update, bson.E{Key: "$inc", Value: inc})
+	}
+	if _, updateErr := t.jobs.UpdateByID(ctx, r.JobID, update); updateErr != nil {
+		logger.Error("Error updating reprocessing job", zap.Error(updateErr))
+	}
+}
+
+// counterUpdates returns the increments of the stage counters when the outcome of an event changes
+// from previous, empty if the event was not tracked yet, to current.
+func counterUpdates(stage Stage, previous, current string) bson.D {
+	if previous == current {
+		return nil
+	}
+	inc := bson.D{{Key: fmt.Sprintf("stages.%s.%s", stage, current), Value: 1}}
+	if previous != "" {
+		inc = append(inc, bson.E{Key: fmt.Sprintf("stages.%s.%s", stage, previous), Value: -1})
+	}
+	return inc
+}
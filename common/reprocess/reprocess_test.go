@@ -0,0 +1,23 @@
+package reprocess
+
+import (
+	"testing"
+
+	"github.com/test-go/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCounterUpdates(t *testing.T) {
+	// first delivery of the event.
+	assert.Equal(t, bson.D{{Key: "stages.parser.processed", Value: 1}},
+		counterUpdates(StageParser, "", "processed"))
+
+	// redelivery with the same outcome.
+	assert.Empty(t, counterUpdates(StageParser, "failed", "failed"))
+
+	// redelivery after a failure.
+	assert.Equal(t, bson.D{
+		{Key: "stages.analytics.processed", Value: 1},
+		{Key: "stages.analytics.failed", Value: -1},
+	}, counterUpdates(StageAnalytics, "failed", "processed"))
+}
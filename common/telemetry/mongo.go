@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// mongoMonitor creates a span for each database command executed within a trace.
+type mongoMonitor struct {
+	// spans contains the spans of the running commands, indexed by request ID.
+	spans sync.Map
+}
+
+// NewMongoMonitor creates a command monitor that traces the database commands.
+func NewMongoMonitor() *event.CommandMonitor {
+	m := &mongoMonitor{}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *mongoMonitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+
+	// the request context of the fiber handlers only supports string keys.
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if sc, ok := ctx.Value(SpanContextKey).(trace.SpanContext); ok {
+			ctx = trace.ContextWithSpanContext(ctx, sc)
+		}
+	}
+	// commands executed outside of a trace are not traced.
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", "mongodb"),
+		attribute.String("db.name", evt.DatabaseName),
+		attribute.String("db.operation", evt.CommandName),
+	}
+	if collection, ok := evt.Command.Lookup(evt.CommandName).StringValueOK(); ok {
+		attrs = append(attrs, attribute.String("db.mongodb.collection", collection))
+	}
+	_, span := StartSpan(ctx, "mongo."+evt.CommandName, attrs...)
+	m.spans.Store(evt.RequestID, span)
+}
+
+func (m *mongoMonitor) succeeded(_ context.Context, evt *event.CommandSucceededEvent) {
+	m.end(evt.RequestID, nil)
+}
+
+func (m *mongoMonitor) failed(_ context.Context, evt *event.CommandFailedEvent) {
+	m.end(evt.RequestID, errors.New(evt.Failure))
+}
+
+func (m *mongoMonitor) end(requestID int64, err error) {
+	if span, ok := m.spans.LoadAndDelete(requestID); ok {
+		EndSpan(span.(trace.Span), err)
+	}
+}
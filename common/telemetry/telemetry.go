@@ -0,0 +1,130 @@
+// Package telemetry sets up the distributed tracing of the services.
+//
+// The traces are exported to the OTLP endpoint defined by the standard OpenTelemetry environment
+// variables (OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT). When no endpoint is
+// configured, tracing is a no-op.
+package telemetry
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/wormhole-foundation/wormhole-explorer"
+
+// SpanContextKey is the key of the span context in contexts that only support string keys,
+// such as the request context of the fiber handlers.
+const SpanContextKey = "otelspancontext"
+
+// ShutdownFunc flushes the pending spans and stops the exporter.
+type ShutdownFunc func(context.Context) error
+
+// Init sets up the global tracer provider of a service.
+func Init(ctx context.Context, serviceName string) (ShutdownFunc, error) {
+
+	// the trace context is propagated even if the service does not export its spans.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// the exporter reads the endpoint and the rest of its options from the environment.
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartVaaSpan starts the span of a stage of the processing of a VAA.
+func StartVaaSpan(ctx context.Context, name, vaaID string) (context.Context, trace.Span) {
+	return StartSpan(ctx, name, attribute.String("vaa.id", vaaID))
+}
+
+// EndSpan records the error, if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SpanContext returns the span context of the span of a context.
+func SpanContext(ctx context.Context) trace.SpanContext {
+	return trace.SpanContextFromContext(ctx)
+}
+
+// Inject returns the trace context of a context, to propagate it in a message.
+// It returns nil if the context has no trace.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns a copy of the context with the trace context propagated in a message.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// ExtractHeaders returns a copy of the context with the trace context propagated in the headers of a request.
+func ExtractHeaders(ctx context.Context, get func(key string) string) context.Context {
+	carrier := make(map[string]string)
+	for _, field := range otel.GetTextMapPropagator().Fields() {
+		if value := get(field); value != "" {
+			carrier[field] = value
+		}
+	}
+	return Extract(ctx, carrier)
+}
+
+// NotificationAttributes are the message attributes of a SNS notification delivered to SQS.
+type NotificationAttributes map[string]struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// Carrier returns the attributes as a trace context carrier.
+func (a NotificationAttributes) Carrier() map[string]string {
+	carrier := make(map[string]string, len(a))
+	for k, v := range a {
+		carrier[k] = v.Value
+	}
+	return carrier
+}
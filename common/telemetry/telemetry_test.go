@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/test-go/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTelemetry_InjectExtract(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := Init(context.Background(), "test")
+	assert.NoError(t, err)
+	defer shutdown(context.Background())
+
+	// a context without a trace is not propagated.
+	assert.Nil(t, Inject(context.Background()))
+	assert.Equal(t, context.Background(), Extract(context.Background(), nil))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+	})
+	carrier := Inject(trace.ContextWithRemoteSpanContext(context.Background(), sc))
+	assert.Contains(t, carrier, "traceparent")
+
+	extracted := SpanContext(Extract(context.Background(), carrier))
+	assert.Equal(t, sc.TraceID(), extracted.TraceID())
+	assert.Equal(t, sc.SpanID(), extracted.SpanID())
+
+	headers := ExtractHeaders(context.Background(), func(key string) string { return carrier[key] })
+	assert.Equal(t, sc.TraceID(), SpanContext(headers).TraceID())
+}
+
+func TestTelemetry_NotificationAttributes(t *testing.T) {
+	body := `{"traceparent": {"Type": "String", "Value": "00-01020300000000000000000000000000-0405000000000000-01"}}`
+	var attrs NotificationAttributes
+	assert.NoError(t, json.Unmarshal([]byte(body), &attrs))
+	assert.Equal(t, map[string]string{"traceparent": "00-01020300000000000000000000000000-0405000000000000-01"}, attrs.Carrier())
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_sqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 )
 
 type Producer struct {
@@ -28,7 +30,21 @@ func (p *Producer) SendMessage(ctx context.Context, groupID, deduplicationID, bo
 			MessageGroupId:         aws.String(groupID),
 			MessageDeduplicationId: aws.String(deduplicationID),
 			MessageBody:            aws.String(body),
+			MessageAttributes:      messageAttributes(ctx),
 			QueueUrl:               aws.String(p.url),
 		})
 	return err
 }
+
+// messageAttributes returns the trace context of a context as message attributes.
+func messageAttributes(ctx context.Context) map[string]aws_sqs_types.MessageAttributeValue {
+	carrier := telemetry.Inject(ctx)
+	if carrier == nil {
+		return nil
+	}
+	attrs := make(map[string]aws_sqs_types.MessageAttributeValue, len(carrier))
+	for k, v := range carrier {
+		attrs[k] = aws_sqs_types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(v)}
+	}
+	return attrs
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/config"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/deduplicator"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/guardiansets"
@@ -239,6 +240,13 @@ func main() {
 
 	logger := logger.New("wormhole-fly", logger.WithLevel(logLevel))
 
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(rootCtx, "wormhole-fly")
+	if err != nil {
+		logger.Fatal("could not set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	isLocal := flag.Bool("local", false, "a bool")
 	flag.Parse()
 
@@ -114,9 +114,125 @@ func Run(db *mongo.Database) error {
 		return err
 	}
 
+	// create indexes in parsedVaa collection to filter by standardized properties.
+	indexesParsedVaa := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "standardizedProperties.fromChain", Value: 1},
+			{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "standardizedProperties.toChain", Value: 1},
+			{Key: "timestamp", Value: -1}}},
+		// the timestamp is before the token address, so a query by token chain alone can sort by timestamp
+		// on the index, and a query by token chain and address is sorted on the index for each address variant.
+		{Keys: bson.D{
+			{Key: "standardizedProperties.tokenChain", Value: 1},
+			{Key: "timestamp", Value: -1},
+			{Key: "standardizedProperties.tokenAddress", Value: 1}}},
+		{Keys: bson.D{
+			{Key: "parsedPayload.payloadId", Value: 1},
+			{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "emitterChain", Value: 1},
+			{Key: "emitterAddr", Value: 1},
+			{Key: "_id", Value: 1}}},
+	}
+	_, err = db.Collection("parsedVaa").Indexes().CreateMany(context.TODO(), indexesParsedVaa)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// drop the previous index by token chain and address, replaced by the index above.
+	_, err = db.Collection("parsedVaa").Indexes().DropOne(context.TODO(),
+		"standardizedProperties.tokenChain_1_standardizedProperties.tokenAddress_1_timestamp_-1")
+	if err != nil && isNotIndexNotFoundError(err) {
+		return err
+	}
+
+	// the parsedVaa documents stored before the timestamp was added are backfilled by the job JOB_PARSED_VAA_TIMESTAMP.
+
+	// create indexes in governanceVaas collection to filter by module, action and chain.
+	indexesGovernanceVaas := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "module", Value: 1},
+			{Key: "action", Value: 1},
+			{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "chains", Value: 1},
+			{Key: "timestamp", Value: -1}}},
+	}
+	_, err = db.Collection("governanceVaas").Indexes().CreateMany(context.TODO(), indexesGovernanceVaas)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create indexes in parserFailures collection to find the due retries and list the failures.
+	indexesParserFailures := []mongo.IndexModel{
+		{Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "nextRetryAt", Value: 1}}},
+		{Keys: bson.D{{Key: "lastFailedAt", Value: -1}}},
+		{Keys: bson.D{
+			{Key: "emitterChain", Value: 1},
+			{Key: "lastFailedAt", Value: -1}}},
+	}
+	_, err = db.Collection("parserFailures").Indexes().CreateMany(context.TODO(), indexesParserFailures)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create ttl index in parserFailures collection, the failures store the VAA and are removed
+	// 90 days after their last failed attempt, the pending ones are retried at least once a day.
+	indexParserFailuresTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "lastFailedAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(90 * 24 * 60 * 60)}
+	_, err = db.Collection("parserFailures").Indexes().CreateOne(context.TODO(), indexParserFailuresTTL)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in parserReparseJobs collection to find the jobs to run.
+	indexParserReparseJobsByStatus := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "createdAt", Value: 1}}}
+	_, err = db.Collection("parserReparseJobs").Indexes().CreateOne(context.TODO(), indexParserReparseJobsByStatus)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create index in reprocessJobs collection to find the enqueuing jobs to resume.
+	indexReprocessJobsByStatus := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "createdAt", Value: 1}}}
+	_, err = db.Collection("reprocessJobs").Indexes().CreateOne(context.TODO(), indexReprocessJobsByStatus)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
+	// create ttl index in reprocessJobEvents collection, the outcomes are only needed while the job is running.
+	indexReprocessJobEventsTTL := mongo.IndexModel{
+		Keys:    bson.D{{Key: "updatedAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60)}
+	_, err = db.Collection("reprocessJobEvents").Indexes().CreateOne(context.TODO(), indexReprocessJobEventsTTL)
+	if err != nil && isNotAlreadyExistsError(err) {
+		return err
+	}
+
 	return nil
 }
 
+func isNotIndexNotFoundError(err error) bool {
+	target := &mongo.CommandError{}
+	isCommandError := errors.As(err, target)
+	if !isCommandError || err.(mongo.CommandError).Code != 27 {
+		return true
+	}
+	return false
+}
+
 func isNotAlreadyExistsError(err error) bool {
 	target := &mongo.CommandError{}
 	isCommandError := errors.As(err, target)
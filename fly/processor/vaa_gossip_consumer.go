@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/deduplicator"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/guardiansets"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
@@ -41,14 +42,18 @@ func NewVAAGossipConsumer(
 }
 
 // Push handles incoming VAAs depending on whether it is a pyth or non pyth.
-func (p *vaaGossipConsumer) Push(ctx context.Context, v *vaa.VAA, serializedVaa []byte) error {
+func (p *vaaGossipConsumer) Push(ctx context.Context, v *vaa.VAA, serializedVaa []byte) (err error) {
+
+	// the trace of a VAA starts when it is received from the gossip network.
+	ctx, span := telemetry.StartVaaSpan(ctx, "fly.ReceiveVaa", v.MessageID())
+	defer func() { telemetry.EndSpan(span, err) }()
 
 	if err := p.guardianSetHistory.Verify(ctx, v); err != nil {
 		p.logger.Error("Received invalid vaa", zap.String("id", v.MessageID()))
 		return err
 	}
 
-	err := p.deduplicator.Apply(ctx, v.MessageID(), func() error {
+	err = p.deduplicator.Apply(ctx, v.MessageID(), func() error {
 		p.metrics.IncVaaUnfiltered(v.EmitterChain)
 		if vaa.ChainIDPythNet == v.EmitterChain {
 			return p.pythProcess(ctx, v, serializedVaa)
@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/queue"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/storage"
@@ -58,8 +59,10 @@ func (c *VAAQueueConsumer) Start(ctx context.Context) {
 
 			c.metrics.IncVaaConsumedFromQueue(v.EmitterChain)
 
-			err = c.repository.UpsertVaa(ctx, v, msg.Data())
+			msgCtx, span := telemetry.StartVaaSpan(msg.Context(), "fly.StoreVaa", v.MessageID())
+			err = c.repository.UpsertVaa(msgCtx, v, msg.Data())
 			if err != nil {
+				telemetry.EndSpan(span, err)
 				c.logger.Error("Error inserting vaa in repository",
 					zap.String("id", v.MessageID()),
 					zap.Error(err))
@@ -67,7 +70,8 @@ func (c *VAAQueueConsumer) Start(ctx context.Context) {
 				continue
 			}
 
-			err = c.notifyFunc(ctx, v, msg.Data())
+			err = c.notifyFunc(msgCtx, v, msg.Data())
+			telemetry.EndSpan(span, err)
 			if err != nil {
 				c.metrics.IncMaxSequenceCacheError(v.EmitterChain)
 				c.logger.Error("Error notifying vaa",
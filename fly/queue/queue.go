@@ -5,6 +5,8 @@ import "context"
 // Message represents a message from a queue.
 type Message interface {
 	Data() []byte
+	// Context returns the context of the message, with the trace context propagated by the publisher.
+	Context() context.Context
 	Done(context.Context)
 	Failed()
 	IsExpired() bool
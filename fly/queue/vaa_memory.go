@@ -33,9 +33,10 @@ func WithSize(v int) VAAInMemoryOption {
 }
 
 // Publish sends the message to a channel.
-func (i *VAAInMemory) Publish(_ context.Context, v *vaa.VAA, data []byte) error {
+func (i *VAAInMemory) Publish(ctx context.Context, v *vaa.VAA, data []byte) error {
 	i.ch <- &memoryConsumerMessage{
 		data: data,
+		ctx:  ctx,
 	}
 	return nil
 }
@@ -47,12 +48,17 @@ func (i *VAAInMemory) Consume(_ context.Context) <-chan Message {
 
 type memoryConsumerMessage struct {
 	data []byte
+	ctx  context.Context
 }
 
 func (m *memoryConsumerMessage) Data() []byte {
 	return m.data
 }
 
+func (m *memoryConsumerMessage) Context() context.Context {
+	return m.ctx
+}
+
 func (m *memoryConsumerMessage) Done(_ context.Context) {}
 
 func (m *memoryConsumerMessage) Failed() {}
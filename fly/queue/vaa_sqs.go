@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	aws_sqs_types "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/fly/internal/sqs"
 
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -80,7 +82,7 @@ func (q *SQS) Consume(ctx context.Context) <-chan Message {
 					logger:    q.logger,
 					consumer:  q.consumer,
 					expiredAt: expiredAt,
-					ctx:       ctx,
+					ctx:       telemetry.Extract(ctx, traceCarrier(msg)),
 				}
 			}
 			q.wg.Wait()
@@ -108,6 +110,10 @@ func (m *sqsConsumerMessage) Data() []byte {
 	return m.data
 }
 
+func (m *sqsConsumerMessage) Context() context.Context {
+	return m.ctx
+}
+
 func (m *sqsConsumerMessage) Done(ctx context.Context) {
 	if err := m.consumer.DeleteMessage(ctx, m.id); err != nil {
 		m.logger.Error("Error deleting message from SQS", zap.Error(err))
@@ -122,3 +128,14 @@ func (m *sqsConsumerMessage) Failed() {
 func (m *sqsConsumerMessage) IsExpired() bool {
 	return m.expiredAt.Before(time.Now())
 }
+
+// traceCarrier returns the trace context propagated in the attributes of a message.
+func traceCarrier(msg aws_sqs_types.Message) map[string]string {
+	carrier := make(map[string]string, len(msg.MessageAttributes))
+	for k, v := range msg.MessageAttributes {
+		if v.StringValue != nil {
+			carrier[k] = *v.StringValue
+		}
+	}
+	return carrier
+}
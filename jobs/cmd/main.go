@@ -6,11 +6,13 @@ import (
 	"os"
 
 	"github.com/go-redis/redis"
+	"github.com/wormhole-foundation/wormhole-explorer/common/db"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/config"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/internal/coingecko"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs"
 	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/notional"
+	"github.com/wormhole-foundation/wormhole-explorer/jobs/jobs/parsedvaa"
 	"go.uber.org/zap"
 )
 
@@ -32,8 +34,12 @@ func main() {
 	var err error
 	switch cfg.JobID {
 	case jobs.JobIDNotional:
-		notionalJob := initNotionalJob(context, cfg, logger)
+		notionalJob := initNotionalJob(context, logger)
 		err = notionalJob.Run()
+	case jobs.JobIDParsedVaaTimestamp:
+		timestampJob, database := initParsedVaaTimestampJob(context, logger)
+		defer database.Close()
+		err = timestampJob.Run()
 	default:
 		logger.Fatal("Invalid job id", zap.String("job_id", cfg.JobID))
 	}
@@ -47,7 +53,11 @@ func main() {
 }
 
 // initNotionalJob initializes notional job.
-func initNotionalJob(ctx context.Context, cfg *config.Configuration, logger *zap.Logger) *notional.NotionalJob {
+func initNotionalJob(ctx context.Context, logger *zap.Logger) *notional.NotionalJob {
+	cfg, err := config.NewNotionalConfiguration(ctx)
+	if err != nil {
+		logger.Fatal("error creating notional job config", zap.Error(err))
+	}
 	// init coingecko api client.
 	api := coingecko.NewCoingeckoAPI(cfg.CoingeckoURL)
 	// init redis client.
@@ -57,6 +67,19 @@ func initNotionalJob(ctx context.Context, cfg *config.Configuration, logger *zap
 	return notionalJob
 }
 
+// initParsedVaaTimestampJob initializes the parsedVaa timestamp job, with the database it must close.
+func initParsedVaaTimestampJob(ctx context.Context, logger *zap.Logger) (*parsedvaa.TimestampJob, *db.Database) {
+	cfg, err := config.NewParsedVaaTimestampConfiguration(ctx)
+	if err != nil {
+		logger.Fatal("error creating parsedVaa timestamp job config", zap.Error(err))
+	}
+	database, err := db.New(ctx, logger, cfg.MongoURI, cfg.MongoDatabase)
+	if err != nil {
+		logger.Fatal("failed to connect MongoDB", zap.Error(err))
+	}
+	return parsedvaa.NewTimestampJob(database.Database, logger), database
+}
+
 func handleExit() {
 	if r := recover(); r != nil {
 		if e, ok := r.(exitCode); ok {
@@ -11,9 +11,13 @@ import (
 
 // Configuration is the configuration for the job
 type Configuration struct {
-	Environment     string `env:"ENVIRONMENT,required"`
-	LogLevel        string `env:"LOG_LEVEL,default=INFO"`
-	JobID           string `env:"JOB_ID,required"`
+	Environment string `env:"ENVIRONMENT,required"`
+	LogLevel    string `env:"LOG_LEVEL,default=INFO"`
+	JobID       string `env:"JOB_ID,required"`
+}
+
+// NotionalConfiguration is the configuration for the notional job.
+type NotionalConfiguration struct {
 	CoingeckoURL    string `env:"COINGECKO_URL,required"`
 	CacheURL        string `env:"CACHE_URL,required"`
 	CachePrefix     string `env:"CACHE_PREFIX,required"`
@@ -21,14 +25,41 @@ type Configuration struct {
 	P2pNetwork      string `env:"P2P_NETWORK,required"`
 }
 
+// ParsedVaaTimestampConfiguration is the configuration for the parsedVaa timestamp job.
+type ParsedVaaTimestampConfiguration struct {
+	MongoURI      string `env:"MONGODB_URI,required"`
+	MongoDatabase string `env:"MONGODB_DATABASE,required"`
+}
+
 // New creates a configuration with the values from .env file and environment variables.
 func New(ctx context.Context) (*Configuration, error) {
-	_ = godotenv.Load(".env", "../.env")
-
 	var configuration Configuration
-	if err := envconfig.Process(ctx, &configuration); err != nil {
+	if err := load(ctx, &configuration); err != nil {
+		return nil, err
+	}
+	return &configuration, nil
+}
+
+// NewNotionalConfiguration creates the configuration of the notional job.
+func NewNotionalConfiguration(ctx context.Context) (*NotionalConfiguration, error) {
+	var configuration NotionalConfiguration
+	if err := load(ctx, &configuration); err != nil {
 		return nil, err
 	}
+	return &configuration, nil
+}
 
+// NewParsedVaaTimestampConfiguration creates the configuration of the parsedVaa timestamp job.
+func NewParsedVaaTimestampConfiguration(ctx context.Context) (*ParsedVaaTimestampConfiguration, error) {
+	var configuration ParsedVaaTimestampConfiguration
+	if err := load(ctx, &configuration); err != nil {
+		return nil, err
+	}
 	return &configuration, nil
 }
+
+// load sets the configuration of a job from .env file and environment variables.
+func load(ctx context.Context, configuration interface{}) error {
+	_ = godotenv.Load(".env", "../.env")
+	return envconfig.Process(ctx, configuration)
+}
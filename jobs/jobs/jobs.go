@@ -1,9 +1,12 @@
 // Package jobs define an interface to execute jobs
 package jobs
 
-// JobIDNotional is the job id for notional job.
+// Job ids of the jobs.
 const (
+	// JobIDNotional is the job id for notional job.
 	JobIDNotional = "JOB_NOTIONAL_USD"
+	// JobIDParsedVaaTimestamp is the job id for the one-off backfill of the parsedVaa timestamp.
+	JobIDParsedVaaTimestamp = "JOB_PARSED_VAA_TIMESTAMP"
 )
 
 // Job is the interface for jobs.
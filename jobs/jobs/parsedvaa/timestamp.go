@@ -0,0 +1,74 @@
+// Package parsedvaa contains the one-off jobs over the parsedVaa collection.
+package parsedvaa
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// TimestampJob copies the timestamp of the vaas to the parsedVaa documents stored before the timestamp was added,
+// so they are not missing from the queries by standardized properties.
+//
+// It only updates the documents without timestamp, so it can be run again if it is interrupted.
+type TimestampJob struct {
+	db     *mongo.Database
+	logger *zap.Logger
+}
+
+// NewTimestampJob creates a new parsedVaa timestamp job.
+func NewTimestampJob(db *mongo.Database, logger *zap.Logger) *TimestampJob {
+	return &TimestampJob{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Run runs the parsedVaa timestamp job.
+func (j *TimestampJob) Run() error {
+	ctx := context.Background()
+
+	filter := bson.D{{Key: "timestamp", Value: bson.D{{Key: "$exists", Value: false}}}}
+	pending, err := j.db.Collection("parsedVaa").CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	j.logger.Info("found parsedVaa documents without timestamp", zap.Int64("count", pending))
+	if pending == 0 {
+		return nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: "vaas"},
+			{Key: "localField", Value: "_id"},
+			{Key: "foreignField", Value: "_id"},
+			{Key: "as", Value: "vaas"}}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "timestamp", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$vaas.timestamp", 0}}}}}}},
+		{{Key: "$match", Value: bson.D{{Key: "timestamp", Value: bson.D{{Key: "$ne", Value: nil}}}}}},
+		{{Key: "$merge", Value: bson.D{
+			{Key: "into", Value: "parsedVaa"},
+			{Key: "on", Value: "_id"},
+			{Key: "whenMatched", Value: "merge"},
+			{Key: "whenNotMatched", Value: "discard"}}}},
+	}
+	cur, err := j.db.Collection("parsedVaa").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	if err := cur.Close(ctx); err != nil {
+		return err
+	}
+
+	remaining, err := j.db.Collection("parsedVaa").CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	// the parsedVaa documents whose vaa was deleted can not be backfilled.
+	j.logger.Info("backfilled parsedVaa timestamps", zap.Int64("updated", pending-remaining), zap.Int64("remaining", remaining))
+	return nil
+}
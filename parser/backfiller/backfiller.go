@@ -0,0 +1,285 @@
+package backfiller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// Results of processing a VAA.
+const (
+	resultParsed  = "parsed"
+	resultSkipped = "skipped"
+	resultFailed  = "failed"
+)
+
+// runLease is how long a run belongs to a backfiller without renewing it, the lease is renewed after each page.
+// If a page takes longer, another backfiller can take the run and this one stops when it saves the page.
+const runLease = 5 * time.Minute
+
+// VersionFunc returns the version of the parser that parses a VAA, or false if no parser supports it.
+type VersionFunc func(vaa *sdk.VAA) (parser.ParserVersion, bool)
+
+// Backfiller parses the VAAs of a run in pages, each page with a pool of workers.
+type Backfiller struct {
+	repository *Repository
+	process    processor.ProcessorFunc
+	version    VersionFunc
+	workers    int
+	pageSize   int64
+	owner      string
+	metrics    metrics.Metrics
+	logger     *zap.Logger
+}
+
+// New creates a new Backfiller with a number of workers, that processes pageSize VAAs at a time.
+// Each backfiller has its own owner id to lease the runs it processes.
+func New(repository *Repository, process processor.ProcessorFunc, version VersionFunc, workers int, pageSize int64,
+	metrics metrics.Metrics, logger *zap.Logger) *Backfiller {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Backfiller{
+		repository: repository,
+		process:    process,
+		version:    version,
+		workers:    workers,
+		pageSize:   pageSize,
+		owner:      uuid.New().String(),
+		metrics:    metrics,
+		logger:     logger.With(zap.String("module", "Backfiller")),
+	}
+}
+
+// Run processes the VAAs of a run, resuming it from its checkpoint if it already exists.
+// If the run id is empty, a new run is created with a generated id.
+// If the end time of the filter is zero, a new run ends now and a resumed run ends when it was created.
+// It returns ErrRunLeased if the run is being processed by another backfiller.
+func (b *Backfiller) Run(ctx context.Context, runID string, filter *Filter) error {
+	// the generated id is logged to resume the run.
+	if runID == "" {
+		runID = uuid.New().String()
+		b.logger.Info("Generated the id of a new run, set it with --run-id to resume the run", zap.String("runId", runID))
+	}
+	run, err := b.startRun(ctx, runID, filter)
+	if err != nil {
+		return err
+	}
+	logger := b.logger.With(zap.String("runId", run.ID))
+	if run.Status == RunStatusCompleted {
+		logger.Info("Run is already completed", zap.Int64("processed", run.Processed))
+		return nil
+	}
+	defer b.releaseRun(run, logger)
+	logger.Info("Starting run", zap.Int64("total", run.Total), zap.Int64("processed", run.Processed))
+
+	start, startProcessed := time.Now(), run.Processed
+	for {
+		vaas, err := b.repository.FindVaas(ctx, &run.Filter, run.Checkpoint, b.pageSize)
+		if err != nil {
+			return err
+		}
+		if len(vaas) == 0 {
+			break
+		}
+
+		results, err := b.processPage(ctx, vaas, run.Filter.AppID)
+		if err != nil {
+			return err
+		}
+		// the checkpoint is saved only when the whole page is processed, so an interrupted page is processed again.
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for _, result := range results {
+			switch result {
+			case resultParsed:
+				run.Parsed++
+			case resultSkipped:
+				run.Skipped++
+			default:
+				run.Failed++
+			}
+			b.metrics.IncBackfillerVaa(run.ID, result)
+		}
+		last := vaas[len(vaas)-1]
+		run.Processed += int64(len(vaas))
+		run.Checkpoint = &Checkpoint{Timestamp: last.Timestamp, ID: last.ID}
+		run.UpdatedAt = time.Now()
+		if err := b.repository.UpdateProgress(ctx, run, run.UpdatedAt.Add(runLease)); err != nil {
+			return err
+		}
+
+		eta := run.eta(run.Processed-startProcessed, time.Since(start))
+		b.metrics.SetBackfillerProgress(run.ID, run.Processed, run.Total, eta)
+		logger.Info("Processed page",
+			zap.Int64("processed", run.Processed),
+			zap.Int64("total", run.Total),
+			zap.Int64("parsed", run.Parsed),
+			zap.Int64("skipped", run.Skipped),
+			zap.Int64("failed", run.Failed),
+			zap.Time("checkpoint", last.Timestamp),
+			zap.Duration("eta", eta))
+	}
+
+	run.Status = RunStatusCompleted
+	run.UpdatedAt = time.Now()
+	if err := b.repository.UpdateProgress(ctx, run, run.UpdatedAt); err != nil {
+		return err
+	}
+	b.metrics.SetBackfillerProgress(run.ID, run.Processed, run.Total, 0)
+	logger.Info("Run completed",
+		zap.Int64("processed", run.Processed),
+		zap.Int64("parsed", run.Parsed),
+		zap.Int64("skipped", run.Skipped),
+		zap.Int64("failed", run.Failed))
+	return nil
+}
+
+// startRun returns the run to resume, or creates a new one, leased to this backfiller unless it is completed.
+func (b *Backfiller) startRun(ctx context.Context, runID string, filter *Filter) (*Run, error) {
+	run, err := b.repository.FindRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run != nil {
+		// a run without end time is resumed until the end time it was created with.
+		if filter.EndTime.IsZero() {
+			filter.EndTime = run.Filter.EndTime
+		}
+		if !run.Filter.equal(filter) {
+			return nil, ErrFilterMismatch
+		}
+		if run.Status == RunStatusCompleted {
+			return run, nil
+		}
+		now := time.Now()
+		if err := b.repository.ClaimRun(ctx, run.ID, b.owner, now, now.Add(runLease)); err != nil {
+			return nil, err
+		}
+		run.LeaseOwner = b.owner
+		return run, nil
+	}
+
+	if filter.EndTime.IsZero() {
+		filter.EndTime = time.Now()
+	}
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	total, err := b.repository.CountVaas(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	leaseUntil := now.Add(runLease)
+	run = &Run{
+		ID:         runID,
+		Filter:     *filter,
+		Status:     RunStatusRunning,
+		Total:      total,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		LeaseOwner: b.owner,
+		LeaseUntil: &leaseUntil,
+	}
+	if err := b.repository.InsertRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// releaseRun releases the lease of a run, so it can be resumed right away after an interruption.
+func (b *Backfiller) releaseRun(run *Run, logger *zap.Logger) {
+	// the context of the run may be cancelled, so the lease is released with its own timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.repository.ReleaseRun(ctx, run); err != nil {
+		logger.Error("Failed to release run", zap.Error(err))
+	}
+}
+
+// processPage processes the VAAs of a page with the pool of workers and returns the result of each VAA.
+func (b *Backfiller) processPage(ctx context.Context, vaas []*VaaDoc, appID string) ([]string, error) {
+	ids := make([]string, 0, len(vaas))
+	for _, v := range vaas {
+		ids = append(ids, v.ID)
+	}
+	parsed, err := b.repository.FindParsed(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]string, len(vaas))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = b.processVaa(ctx, vaas[i], parsed[vaas[i].ID], appID)
+			}
+		}()
+	}
+	for i := range vaas {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results, nil
+}
+
+// processVaa parses a VAA, unless it is already parsed at the current version or it has not the app id of the filter.
+func (b *Backfiller) processVaa(ctx context.Context, v *VaaDoc, parsed *ParsedDoc, appID string) string {
+	if appID != "" && (parsed == nil || !contains(parsed.AppIDs, appID)) {
+		return resultSkipped
+	}
+
+	vaa, err := sdk.Unmarshal(v.Vaa)
+	if err != nil {
+		b.logger.Error("Failed to unmarshal vaa", zap.String("id", v.ID), zap.Error(err))
+		return resultFailed
+	}
+	if parsed != nil {
+		current, ok := b.version(vaa)
+		if ok && parsed.Parser.Type == current.Type && parsed.Parser.Version >= current.Version {
+			return resultSkipped
+		}
+	}
+
+	// the VAAs that can not be parsed are recorded as parser failures by the processor.
+	update, err := b.process(ctx, v.Vaa)
+	if errors.Is(err, failure.ErrRecorded) {
+		return resultFailed
+	}
+	if err != nil {
+		b.logger.Error("Failed to process vaa", zap.String("id", v.ID), zap.Error(err))
+		return resultFailed
+	}
+	if update == nil {
+		return resultFailed
+	}
+	return resultParsed
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package backfiller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// newTestBackfiller creates a backfiller that parses every VAA with a native parser and counts the parsed VAAs.
+func newTestBackfiller(mt *mtest.T, processed *int) *Backfiller {
+	process := func(context.Context, []byte) (*parser.ParsedVaaUpdate, error) {
+		*processed++
+		return &parser.ParsedVaaUpdate{}, nil
+	}
+	version := func(*sdk.VAA) (parser.ParserVersion, bool) {
+		return parser.ParserVersion{Type: parser.ParserTypeNative, Version: 1}, true
+	}
+	return New(NewRepository(mt.DB, zap.NewNop()), process, version, 1, 10, metrics.NewDummyMetrics(), zap.NewNop())
+}
+
+func newTestVaa(t *testing.T, sequence uint64) []byte {
+	vaa := sdk.VAA{Version: 1, EmitterChain: sdk.ChainIDEthereum, Sequence: sequence, Timestamp: time.Unix(0, 0)}
+	b, err := vaa.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func updateResponse(matched int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: matched}, bson.E{Key: "nModified", Value: matched})
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	checkpoint := start.Add(time.Hour)
+	runDoc := bson.D{
+		{Key: "_id", Value: "run"},
+		{Key: "filter", Value: bson.D{{Key: "startTime", Value: start}, {Key: "endTime", Value: end}}},
+		{Key: "status", Value: RunStatusRunning},
+		{Key: "checkpoint", Value: bson.D{{Key: "timestamp", Value: checkpoint}, {Key: "id", Value: "2/abc/1"}}},
+		{Key: "total", Value: int64(2)},
+		{Key: "processed", Value: int64(1)},
+	}
+
+	mt.Run("resumes the run from its checkpoint", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "wormscan.parserBackfillerRuns", mtest.FirstBatch, runDoc),
+			updateResponse(1),
+			mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch, bson.D{
+				{Key: "_id", Value: "2/abc/2"},
+				{Key: "timestamp", Value: checkpoint.Add(-time.Minute)},
+				{Key: "vaas", Value: newTestVaa(t, 2)},
+			}),
+			mtest.CreateCursorResponse(0, "wormscan.parsedVaa", mtest.FirstBatch),
+			updateResponse(1),
+			mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch),
+			updateResponse(1),
+			updateResponse(1),
+		)
+
+		var processed int
+		b := newTestBackfiller(mt, &processed)
+		// a resumed run without end time ends when it was created.
+		if err := b.Run(context.Background(), "run", &Filter{StartTime: start}); err != nil {
+			t.Fatal(err)
+		}
+		if processed != 1 {
+			t.Errorf("expected 1 VAA processed, got %d", processed)
+		}
+
+		// find run, claim run, find vaas after the checkpoint, find parsed, save progress, find vaas,
+		// complete and release the run.
+		events := mt.GetAllStartedEvents()
+		if len(events) != 8 {
+			t.Fatalf("expected 8 commands, got %d", len(events))
+		}
+		after := events[2].Command.Lookup("filter", "$or").Array().Index(1).Value().Document()
+		// the VAAs are processed from the newest by default.
+		if id := after.Lookup("_id", "$lt").StringValue(); id != "2/abc/1" {
+			t.Errorf("expected the VAAs after the checkpoint 2/abc/1, got %s", id)
+		}
+		progress := events[4].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if n := progress.Lookup("u", "$set", "processed").Int64(); n != 2 {
+			t.Errorf("expected 2 VAAs processed, got %d", n)
+		}
+		if owner := progress.Lookup("q", "leaseOwner").StringValue(); owner != b.owner {
+			t.Errorf("expected the progress of the owner %s, got %s", b.owner, owner)
+		}
+		completed := events[6].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if status := completed.Lookup("u", "$set", "status").StringValue(); status != string(RunStatusCompleted) {
+			t.Errorf("expected the run completed, got %s", status)
+		}
+	})
+
+	mt.Run("run leased by another backfiller", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "wormscan.parserBackfillerRuns", mtest.FirstBatch, runDoc),
+			updateResponse(0),
+		)
+
+		var processed int
+		err := newTestBackfiller(mt, &processed).Run(context.Background(), "run", &Filter{StartTime: start})
+		if !errors.Is(err, ErrRunLeased) {
+			t.Errorf("expected ErrRunLeased, got %v", err)
+		}
+		if processed != 0 {
+			t.Errorf("expected no VAA processed, got %d", processed)
+		}
+	})
+
+	mt.Run("generates the id of a new run", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "wormscan.parserBackfillerRuns", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch, bson.D{{Key: "n", Value: int64(0)}}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch),
+			updateResponse(1),
+			updateResponse(1),
+		)
+
+		var processed int
+		if err := newTestBackfiller(mt, &processed).Run(context.Background(), "", &Filter{StartTime: start}); err != nil {
+			t.Fatal(err)
+		}
+
+		// find run, count vaas and insert the run with the generated id.
+		events := mt.GetAllStartedEvents()
+		if len(events) < 3 {
+			t.Fatalf("expected the run inserted, got %d commands", len(events))
+		}
+		id := events[2].Command.Lookup("documents").Array().Index(0).Value().Document().Lookup("_id").StringValue()
+		if _, err := uuid.Parse(id); err != nil {
+			t.Errorf("expected a generated run id, got %q", id)
+		}
+		if runID := events[0].Command.Lookup("filter", "_id").StringValue(); runID != id {
+			t.Errorf("expected to find the run %s, got %s", id, runID)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+// Package backfiller parses the stored VAAs with a pool of workers, and saves a checkpoint of each run to resume it.
+package backfiller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	// ErrFilterMismatch is returned when a run is resumed with other filter than the one it was created with.
+	ErrFilterMismatch = errors.New("the run was created with a different filter")
+	// ErrInvalidTimeRange is returned when the start time of a run is after its end time.
+	ErrInvalidTimeRange = errors.New("the start time must be before the end time")
+	// ErrRunLeased is returned when a run belongs to another backfiller, or it was taken by another one after its lease expired.
+	ErrRunLeased = errors.New("the run is being processed by another backfiller")
+)
+
+// Filter selects the VAAs of a run.
+type Filter struct {
+	StartTime      time.Time `bson:"startTime" json:"startTime"`
+	EndTime        time.Time `bson:"endTime" json:"endTime"`
+	EmitterChain   *uint16   `bson:"emitterChain,omitempty" json:"emitterChain,omitempty"`
+	EmitterAddress string    `bson:"emitterAddress,omitempty" json:"emitterAddress,omitempty"`
+	// AppID selects the VAAs already parsed with this app id, so it only reparses VAAs.
+	AppID   string `bson:"appId,omitempty" json:"appId,omitempty"`
+	SortAsc bool   `bson:"sortAsc" json:"sortAsc"`
+}
+
+// equal returns true if both filters select the same VAAs in the same order.
+func (f *Filter) equal(o *Filter) bool {
+	sameChain := (f.EmitterChain == nil && o.EmitterChain == nil) ||
+		(f.EmitterChain != nil && o.EmitterChain != nil && *f.EmitterChain == *o.EmitterChain)
+	return sameChain &&
+		f.StartTime.Equal(o.StartTime) &&
+		f.EndTime.Equal(o.EndTime) &&
+		f.EmitterAddress == o.EmitterAddress &&
+		f.AppID == o.AppID &&
+		f.SortAsc == o.SortAsc
+}
+
+// validate checks the time range of the filter, once its end time is set.
+func (f *Filter) validate() error {
+	if f.StartTime.After(f.EndTime) {
+		return fmt.Errorf("%w: start time %s, end time %s", ErrInvalidTimeRange,
+			f.StartTime.Format(time.RFC3339), f.EndTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// vaasFilter returns the filter of the vaas collection for the VAAs after the checkpoint.
+func (f *Filter) vaasFilter(c *Checkpoint) bson.D {
+	filter := bson.D{{Key: "timestamp", Value: bson.D{
+		{Key: "$gte", Value: f.StartTime},
+		{Key: "$lt", Value: f.EndTime},
+	}}}
+	if f.EmitterChain != nil {
+		filter = append(filter, bson.E{Key: "emitterChain", Value: *f.EmitterChain})
+	}
+	if f.EmitterAddress != "" {
+		filter = append(filter, bson.E{Key: "emitterAddr", Value: f.EmitterAddress})
+	}
+	if c != nil {
+		// the VAAs are sorted by timestamp and id, so the VAAs with the same timestamp are not skipped.
+		op := "$lt"
+		if f.SortAsc {
+			op = "$gt"
+		}
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "timestamp", Value: bson.D{{Key: op, Value: c.Timestamp}}}},
+			bson.D{{Key: "timestamp", Value: c.Timestamp}, {Key: "_id", Value: bson.D{{Key: op, Value: c.ID}}}},
+		}})
+	}
+	return filter
+}
+
+// sort returns the sort of the vaas collection for the filter.
+func (f *Filter) sort() bson.D {
+	order := -1
+	if f.SortAsc {
+		order = 1
+	}
+	return bson.D{{Key: "timestamp", Value: order}, {Key: "_id", Value: order}}
+}
+
+// Checkpoint is the last processed VAA of a run.
+type Checkpoint struct {
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+	ID        string    `bson:"id" json:"id"`
+}
+
+// RunStatus is the status of a backfiller run.
+type RunStatus string
+
+const (
+	// RunStatusRunning is the status of a run being processed, or interrupted and waiting to be resumed.
+	RunStatusRunning RunStatus = "running"
+	// RunStatusCompleted is the status of a run when all its VAAs were processed.
+	RunStatusCompleted RunStatus = "completed"
+)
+
+// Run is a document of the parserBackfillerRuns collection.
+type Run struct {
+	ID         string      `bson:"_id" json:"id"`
+	Filter     Filter      `bson:"filter" json:"filter"`
+	Status     RunStatus   `bson:"status" json:"status"`
+	Checkpoint *Checkpoint `bson:"checkpoint,omitempty" json:"checkpoint,omitempty"`
+	// Total is the number of VAAs that matched the filter when the run was created, without the app id.
+	Total     int64 `bson:"total" json:"total"`
+	Processed int64 `bson:"processed" json:"processed"`
+	Parsed    int64 `bson:"parsed" json:"parsed"`
+	// Skipped is the number of VAAs already parsed at the current version, or without the app id of the filter.
+	Skipped   int64     `bson:"skipped" json:"skipped"`
+	Failed    int64     `bson:"failed" json:"failed"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+	// LeaseOwner is the backfiller that processes the run until LeaseUntil, so two backfillers do not process it at the same time.
+	LeaseOwner string     `bson:"leaseOwner,omitempty" json:"-"`
+	LeaseUntil *time.Time `bson:"leaseUntil,omitempty" json:"-"`
+}
+
+// eta returns the estimated time to process the remaining VAAs of a run, at the rate of the processed ones.
+func (r *Run) eta(processed int64, elapsed time.Duration) time.Duration {
+	remaining := r.Total - r.Processed
+	if processed <= 0 || remaining <= 0 {
+		return 0
+	}
+	return time.Duration(float64(elapsed) / float64(processed) * float64(remaining))
+}
@@ -0,0 +1,94 @@
+package backfiller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFilter_vaasFilter(t *testing.T) {
+	chain := uint16(2)
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	f := Filter{StartTime: start, EndTime: end, EmitterChain: &chain, EmitterAddress: "abc", SortAsc: true}
+	c := Checkpoint{Timestamp: start.Add(time.Hour), ID: "2/abc/10"}
+
+	expected := bson.D{
+		{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: start}, {Key: "$lt", Value: end}}},
+		{Key: "emitterChain", Value: uint16(2)},
+		{Key: "emitterAddr", Value: "abc"},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gt", Value: c.Timestamp}}}},
+			bson.D{{Key: "timestamp", Value: c.Timestamp}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: c.ID}}}},
+		}},
+	}
+	if filter := f.vaasFilter(&c); !reflect.DeepEqual(expected, filter) {
+		t.Errorf("unexpected filter %v", filter)
+	}
+
+	// without checkpoint, all the VAAs of the time range are selected.
+	f = Filter{StartTime: start, EndTime: end}
+	expected = bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: start}, {Key: "$lt", Value: end}}}}
+	if filter := f.vaasFilter(nil); !reflect.DeepEqual(expected, filter) {
+		t.Errorf("unexpected filter %v", filter)
+	}
+}
+
+func TestFilter_equal(t *testing.T) {
+	chain, otherChain := uint16(2), uint16(4)
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := Filter{StartTime: start, EndTime: start.Add(time.Hour), EmitterChain: &chain}
+
+	same := f
+	sameChain := uint16(2)
+	same.EmitterChain = &sameChain
+	if !f.equal(&same) {
+		t.Error("expected equal filters")
+	}
+
+	other := f
+	other.EmitterChain = &otherChain
+	if f.equal(&other) {
+		t.Error("expected different emitter chain")
+	}
+	other = f
+	other.EmitterChain = nil
+	if f.equal(&other) {
+		t.Error("expected different emitter chain")
+	}
+	other = f
+	other.SortAsc = true
+	if f.equal(&other) {
+		t.Error("expected different sort")
+	}
+}
+
+func TestFilter_validate(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f := Filter{StartTime: start, EndTime: start.Add(time.Hour)}
+	if err := f.validate(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	// the start time of a run without end time is after the time it is created.
+	f = Filter{StartTime: start, EndTime: start.Add(-time.Hour)}
+	if err := f.validate(); !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("expected invalid time range, got %v", err)
+	}
+}
+
+func TestRun_eta(t *testing.T) {
+	run := Run{Total: 1000, Processed: 400}
+
+	// 200 VAAs were processed in 10s, so the remaining 600 take 30s.
+	if eta := run.eta(200, 10*time.Second); eta != 30*time.Second {
+		t.Errorf("unexpected eta %v", eta)
+	}
+	if eta := run.eta(0, 10*time.Second); eta != 0 {
+		t.Errorf("expected no eta without processed VAAs, got %v", eta)
+	}
+}
@@ -0,0 +1,173 @@
+package backfiller
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// VaaDoc is a VAA to backfill.
+type VaaDoc struct {
+	ID        string    `bson:"_id"`
+	Timestamp time.Time `bson:"timestamp"`
+	Vaa       []byte    `bson:"vaas"`
+}
+
+// ParsedDoc is the parser version and app ids of a parsed VAA.
+type ParsedDoc struct {
+	ID     string               `bson:"_id"`
+	AppIDs []string             `bson:"appIds"`
+	Parser parser.ParserVersion `bson:"parser"`
+}
+
+// Repository is the backfiller data access layer.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		vaas      *mongo.Collection
+		parsedVaa *mongo.Collection
+		runs      *mongo.Collection
+	}
+}
+
+// NewRepository creates a new backfiller repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db, logger.With(zap.String("module", "BackfillerRepository")), struct {
+		vaas      *mongo.Collection
+		parsedVaa *mongo.Collection
+		runs      *mongo.Collection
+	}{
+		vaas:      db.Collection("vaas"),
+		parsedVaa: db.Collection("parsedVaa"),
+		runs:      db.Collection("parserBackfillerRuns"),
+	}}
+}
+
+// FindRun returns a run by id, or nil if it does not exist.
+func (r *Repository) FindRun(ctx context.Context, id string) (*Run, error) {
+	var run Run
+	err := r.collections.runs.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&run)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &run, nil
+}
+
+// InsertRun inserts a new run, already leased to its owner.
+// It returns ErrRunLeased if another backfiller inserted a run with the same id meanwhile.
+func (r *Repository) InsertRun(ctx context.Context, run *Run) error {
+	_, err := r.collections.runs.InsertOne(ctx, run)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrRunLeased
+	}
+	return errors.WithStack(err)
+}
+
+// ClaimRun leases a run to an owner until leaseUntil, if it is not leased or its lease expired.
+// It returns ErrRunLeased if the run belongs to another backfiller.
+func (r *Repository) ClaimRun(ctx context.Context, id, owner string, now, leaseUntil time.Time) error {
+	filter := bson.D{
+		{Key: "_id", Value: id},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$lt", Value: now}}}},
+			bson.D{{Key: "leaseOwner", Value: owner}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "leaseOwner", Value: owner},
+		{Key: "leaseUntil", Value: leaseUntil},
+	}}}
+	res, err := r.collections.runs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrRunLeased
+	}
+	return nil
+}
+
+// UpdateProgress saves the status, checkpoint and counters of a run and renews its lease until leaseUntil.
+// It returns ErrRunLeased if the run was taken by another backfiller.
+func (r *Repository) UpdateProgress(ctx context.Context, run *Run, leaseUntil time.Time) error {
+	filter := bson.D{{Key: "_id", Value: run.ID}, {Key: "leaseOwner", Value: run.LeaseOwner}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: run.Status},
+		{Key: "checkpoint", Value: run.Checkpoint},
+		{Key: "processed", Value: run.Processed},
+		{Key: "parsed", Value: run.Parsed},
+		{Key: "skipped", Value: run.Skipped},
+		{Key: "failed", Value: run.Failed},
+		{Key: "updatedAt", Value: run.UpdatedAt},
+		{Key: "leaseUntil", Value: leaseUntil},
+	}}}
+	res, err := r.collections.runs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrRunLeased
+	}
+	return nil
+}
+
+// ReleaseRun releases the lease of a run, if it still belongs to its owner.
+func (r *Repository) ReleaseRun(ctx context.Context, run *Run) error {
+	filter := bson.D{{Key: "_id", Value: run.ID}, {Key: "leaseOwner", Value: run.LeaseOwner}}
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "leaseOwner", Value: ""}, {Key: "leaseUntil", Value: ""}}}}
+	_, err := r.collections.runs.UpdateOne(ctx, filter, update)
+	return errors.WithStack(err)
+}
+
+// CountVaas returns the number of VAAs that match a filter.
+func (r *Repository) CountVaas(ctx context.Context, f *Filter) (int64, error) {
+	count, err := r.collections.vaas.CountDocuments(ctx, f.vaasFilter(nil))
+	return count, errors.WithStack(err)
+}
+
+// FindVaas returns the next page of VAAs that match a filter after the checkpoint.
+func (r *Repository) FindVaas(ctx context.Context, f *Filter, c *Checkpoint, limit int64) ([]*VaaDoc, error) {
+	opts := options.Find().
+		SetSort(f.sort()).
+		SetLimit(limit).
+		SetProjection(bson.D{{Key: "timestamp", Value: 1}, {Key: "vaas", Value: 1}})
+	cur, err := r.collections.vaas.Find(ctx, f.vaasFilter(c), opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var vaas []*VaaDoc
+	if err := cur.All(ctx, &vaas); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return vaas, nil
+}
+
+// FindParsed returns the parsed VAAs by id.
+func (r *Repository) FindParsed(ctx context.Context, ids []string) (map[string]*ParsedDoc, error) {
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "appIds", Value: 1}, {Key: "parser", Value: 1}})
+	cur, err := r.collections.parsedVaa.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var docs []*ParsedDoc
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	parsed := make(map[string]*ParsedDoc, len(docs))
+	for _, d := range docs {
+		parsed[d.ID] = d
+	}
+	return parsed, nil
+}
@@ -2,93 +2,136 @@ package backfiller
 
 import (
 	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/backfiller"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/config"
-	"github.com/wormhole-foundation/wormhole-explorer/parser/http/vaa"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/governance"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/db"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
 func Run(config *config.BackfillerConfiguration) {
 
-	rootCtx := context.Background()
+	rootCtx, rootCtxCancel := context.WithCancel(context.Background())
+	defer rootCtxCancel()
 
 	logger := logger.New("wormhole-explorer-parser", logger.WithLevel(config.LogLevel))
 
 	logger.Info("Starting wormhole-explorer-parser  as backfiller ...")
 
-	startTime, err := time.Parse(time.RFC3339, config.StartTime)
+	filter := backfiller.Filter{
+		EmitterChain: config.EmitterChain,
+		AppID:        config.AppID,
+		SortAsc:      config.SortAsc,
+	}
+
+	// the emitter address is normalized as it is stored in the vaas.
+	if config.EmitterAddress != "" {
+		addr, err := sdk.StringToAddress(config.EmitterAddress)
+		if err != nil {
+			logger.Fatal("failed to parse emitter address", zap.Error(err))
+		}
+		filter.EmitterAddress = addr.String()
+	}
+
+	var err error
+	filter.StartTime, err = time.Parse(time.RFC3339, config.StartTime)
 	if err != nil {
 		logger.Fatal("failed to parse start time", zap.Error(err))
 	}
 
-	endTime := time.Now()
+	// the end time of a new run is now, and the end time of a resumed run is the one it was created with,
+	// so the time range is validated by the backfiller once the end time is set.
 	if config.EndTime != "" {
-		endTime, err = time.Parse(time.RFC3339, config.EndTime)
+		filter.EndTime, err = time.Parse(time.RFC3339, config.EndTime)
 		if err != nil {
 			logger.Fatal("Failed to parse end time", zap.Error(err))
 		}
 	}
 
-	if startTime.After(endTime) {
-		logger.Fatal("Start time should be before end time",
-			zap.String("start_time", startTime.Format(time.RFC3339)),
-			zap.String("end_time", endTime.Format(time.RFC3339)))
-	}
-
 	//setup DB connection
 	db, err := db.New(rootCtx, logger, config.MongoURI, config.MongoDatabase)
 	if err != nil {
 		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
 	}
 
-	parserVAAAPIClient, err := parser.NewParserVAAAPIClient(config.VaaPayloadParserTimeout, config.VaaPayloadParserURL, logger)
+	// create a payload parser, with the vaa-payload-parser api as fallback of the native parsers.
+	payloadParser, err := payload.NewRegistryFromConfig(payload.Config{
+		P2pNetwork:    config.P2pNetwork,
+		Emitters:      config.NativeParserEmitters,
+		RemoteURL:     config.VaaPayloadParserURL,
+		RemoteTimeout: config.VaaPayloadParserTimeout,
+		RemoteVersion: config.VaaPayloadParserVersion,
+	}, logger)
 	if err != nil {
-		logger.Fatal("Failed to create parse vaa api client")
+		logger.Fatal("failed to create payload parser", zap.Error(err))
+	}
+	if config.NativeGovernanceParser {
+		payloadParser.Register(sdk.ChainIDSolana, sdk.GovernanceEmitter, governance.Parse)
 	}
 
+	// create a metrics, served for the scraper only if a metrics port is set.
+	metrics := newMetrics(config, logger)
+
 	parserRepository := parser.NewRepository(db.Database, logger)
-	vaaRepository := vaa.NewRepository(db.Database, logger)
+	governanceRepository := governance.NewRepository(db.Database, logger)
+
+	// the failures are recorded to be retried by the parser service.
+	failureService := failure.NewService(failure.NewRepository(db.Database, logger), failure.DefaultRetryPolicy, logger)
 
 	//create a processor
-	processor := processor.New(parserVAAAPIClient, parserRepository, alert.NewDummyClient(), metrics.NewDummyMetrics(), logger)
+	processor := processor.New(payloadParser, parserRepository, governanceRepository, failureService, alert.NewDummyClient(), metrics, logger)
 
-	logger.Info("Started wormhole-explorer-parser as backfiller")
+	// stop the run on signal, it is resumed from its checkpoint with the same run id.
+	go func() {
+		sigterm := make(chan os.Signal, 1)
+		signal.Notify(sigterm, syscall.SIGINT, syscall.SIGTERM)
+		signal := <-sigterm
+		logger.Info("Terminating with signal.", zap.String("signal", signal.String()))
+		rootCtxCancel()
+	}()
 
-	//start backfilling
-	page := int64(0)
-	for {
-		logger.Info("Processing page", zap.Int64("page", page),
-			zap.String("start_time", startTime.Format(time.RFC3339)),
-			zap.String("end_time", endTime.Format(time.RFC3339)))
+	logger.Info("Started wormhole-explorer-parser as backfiller",
+		zap.String("run_id", config.RunID), zap.Int("workers", config.Workers))
 
-		vaas, err := vaaRepository.FindPageByTimeRange(rootCtx, startTime, endTime, page, config.PageSize, config.SortAsc)
-		if err != nil {
-			logger.Error("Failed to get vaas", zap.Error(err))
-			break
-		}
-
-		if len(vaas) == 0 {
-			logger.Info("Empty page", zap.Int64("page", page))
-			break
-		}
-		for _, v := range vaas {
-			logger.Debug("Processing vaa", zap.String("id", v.ID))
-			_, err := processor.Process(rootCtx, v.Vaa)
-			if err != nil {
-				logger.Error("Failed to process vaa", zap.String("id", v.ID), zap.Error(err))
-			}
-		}
-		page++
+	//start backfilling
+	b := backfiller.New(backfiller.NewRepository(db.Database, logger), processor.Process, payloadParser.Version,
+		config.Workers, config.PageSize, metrics, logger)
+	if err := b.Run(rootCtx, config.RunID, &filter); err != nil {
+		logger.Error("Failed to run backfiller", zap.String("run_id", config.RunID), zap.Error(err))
 	}
+
 	logger.Info("Closing database connections ...")
 	db.Close()
 
 	logger.Info("Finish wormhole-explorer-parser as backfiller")
 }
+
+// Creates a metrics depending on whether a metrics port is set (Prometheus metrics) or not (dummy metrics)
+func newMetrics(cfg *config.BackfillerConfiguration, logger *zap.Logger) metrics.Metrics {
+	if cfg.MetricsPort == "" {
+		return metrics.NewDummyMetrics()
+	}
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		if err := http.ListenAndServe(":"+cfg.MetricsPort, mux); err != nil {
+			logger.Error("Failed to serve metrics", zap.Error(err))
+		}
+	}()
+	return metrics.NewPrometheusMetrics(cfg.Environment)
+}
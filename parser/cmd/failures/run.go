@@ -0,0 +1,69 @@
+package failures
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/config"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/db"
+	"go.uber.org/zap"
+)
+
+// List prints the parser failures that match a query as JSON.
+func List(cfg *config.FailuresConfiguration, q *failure.Query) {
+	run(cfg, func(ctx context.Context, srv *failure.Service, logger *zap.Logger) {
+		failures, err := srv.List(ctx, q)
+		if err != nil {
+			logger.Fatal("Failed to list parser failures", zap.Error(err))
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(failures); err != nil {
+			logger.Fatal("Failed to print parser failures", zap.Error(err))
+		}
+	})
+}
+
+// Retry schedules the parser failures to be retried now by the parser service.
+func Retry(cfg *config.FailuresConfiguration, ids []string) {
+	run(cfg, func(ctx context.Context, srv *failure.Service, logger *zap.Logger) {
+		for _, id := range ids {
+			if err := srv.Retry(ctx, id); err != nil {
+				logger.Fatal("Failed to retry parser failure", zap.String("id", id), zap.Error(err))
+			}
+			logger.Info("Parser failure scheduled to retry", zap.String("id", id))
+		}
+	})
+}
+
+// Discard removes the parser failures.
+func Discard(cfg *config.FailuresConfiguration, ids []string) {
+	run(cfg, func(ctx context.Context, srv *failure.Service, logger *zap.Logger) {
+		for _, id := range ids {
+			if err := srv.Discard(ctx, id); err != nil {
+				logger.Fatal("Failed to discard parser failure", zap.String("id", id), zap.Error(err))
+			}
+			logger.Info("Parser failure discarded", zap.String("id", id))
+		}
+	})
+}
+
+func run(cfg *config.FailuresConfiguration, fn func(context.Context, *failure.Service, *zap.Logger)) {
+
+	rootCtx := context.Background()
+
+	logger := logger.New("wormhole-explorer-parser", logger.WithLevel(cfg.LogLevel))
+
+	//setup DB connection
+	db, err := db.New(rootCtx, logger, cfg.MongoURI, cfg.MongoDatabase)
+	if err != nil {
+		logger.Fatal("Failed to connect MongoDB", zap.Error(err))
+	}
+	defer db.Close()
+
+	repository := failure.NewRepository(db.Database, logger)
+	fn(rootCtx, failure.NewService(repository, failure.DefaultRetryPolicy, logger), logger)
+}
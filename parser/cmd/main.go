@@ -5,8 +5,10 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/cmd/backfiller"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/cmd/failures"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/cmd/service"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/config"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
 )
 
 func main() {
@@ -25,6 +27,7 @@ func execute() error {
 
 	addServiceCommand(root)
 	addBackfiller(root)
+	addFailuresCommand(root)
 
 	return root.Execute()
 }
@@ -41,13 +44,12 @@ func addServiceCommand(root *cobra.Command) {
 }
 
 func addBackfiller(root *cobra.Command) {
-	var mongoUri, mongoDb, vaaPayloadParserURL, logLevel, startTime, endTime, sort string
+	var mongoUri, mongoDb, vaaPayloadParserURL, nativeParserEmitters, logLevel, startTime, endTime, sort string
+	var runID, emitterAddress, appID, metricsPort, environment, p2pNetwork string
+	var nativeGovernanceParser bool
 	var vaaPayloadParserTimeout, pageSize int64
+	var vaaPayloadParserVersion, workers, emitterChain int
 
-	sortAsc := false
-	if strings.ToLower(sort) == "asc" {
-		sortAsc = true
-	}
 	backfillerCommand := &cobra.Command{
 		Use:   "backfiller",
 		Short: "Run backfiller to backfill data",
@@ -58,10 +60,24 @@ func addBackfiller(root *cobra.Command) {
 				MongoDatabase:           mongoDb,
 				VaaPayloadParserURL:     vaaPayloadParserURL,
 				VaaPayloadParserTimeout: vaaPayloadParserTimeout,
+				VaaPayloadParserVersion: vaaPayloadParserVersion,
+				NativeParserEmitters:    nativeParserEmitters,
+				NativeGovernanceParser:  nativeGovernanceParser,
+				P2pNetwork:              p2pNetwork,
 				StartTime:               startTime,
 				EndTime:                 endTime,
 				PageSize:                pageSize,
-				SortAsc:                 sortAsc,
+				SortAsc:                 strings.ToLower(sort) == "asc",
+				RunID:                   runID,
+				Workers:                 workers,
+				EmitterAddress:          emitterAddress,
+				AppID:                   appID,
+				MetricsPort:             metricsPort,
+				Environment:             environment,
+			}
+			if emitterChain >= 0 {
+				chain := uint16(emitterChain)
+				cfg.EmitterChain = &chain
 			}
 			backfiller.Run(cfg)
 		},
@@ -69,18 +85,92 @@ func addBackfiller(root *cobra.Command) {
 	backfillerCommand.Flags().StringVar(&logLevel, "log-level", "INFO", "log level")
 	backfillerCommand.Flags().StringVar(&mongoUri, "mongo-uri", "", "Mongo connection")
 	backfillerCommand.Flags().StringVar(&mongoDb, "mongo-database", "", "Mongo database")
-	backfillerCommand.Flags().StringVar(&vaaPayloadParserURL, "vaa-payload-parser-url", "", "VAA payload parser service URL")
+	backfillerCommand.Flags().StringVar(&vaaPayloadParserURL, "vaa-payload-parser-url", "", "VAA payload parser service URL, used for the emitters without a native parser")
 	backfillerCommand.Flags().Int64Var(&vaaPayloadParserTimeout, "vaa-payload-parser-timeout", 10, "maximum waiting time in call to VAA payload service in seconds")
+	backfillerCommand.Flags().IntVar(&vaaPayloadParserVersion, "vaa-payload-parser-version", 1, "version of the VAA payload parser service, stamped on the VAAs it parses")
+	backfillerCommand.Flags().StringVar(&nativeParserEmitters, "native-parser-emitters", "", "emitters of each native parser type encoded as JSON, they replace the known emitters of the network")
+	backfillerCommand.Flags().BoolVar(&nativeGovernanceParser, "native-governance-parser", false, "parse the governance VAA natively, classified by module and action")
+	backfillerCommand.Flags().StringVar(&p2pNetwork, "p2p-network", "", "network of the known emitters parsed natively (mainnet/testnet)")
 	backfillerCommand.Flags().StringVar(&startTime, "start-time", "1970-01-01T00:00:00Z", "minimum VAA timestamp to process")
 	backfillerCommand.Flags().StringVar(&endTime, "end-time", "", "maximum VAA timestamp to process (default now)")
 	backfillerCommand.Flags().Int64Var(&pageSize, "page-size", 100, "number of documents retrieved at a time")
 	backfillerCommand.Flags().StringVar(&sort, "sort", "desc", "process VAA in asc/desc order of timestamp")
+	backfillerCommand.Flags().StringVar(&runID, "run-id", "", "id of the run, a run with the same id is resumed from its checkpoint (default a new run id)")
+	backfillerCommand.Flags().IntVar(&workers, "workers", 10, "number of VAA processed in parallel")
+	backfillerCommand.Flags().IntVar(&emitterChain, "chain", -1, "process only the VAA of this emitter chain")
+	backfillerCommand.Flags().StringVar(&emitterAddress, "emitter", "", "process only the VAA of this emitter address")
+	backfillerCommand.Flags().StringVar(&appID, "app-id", "", "process only the VAA already parsed with this app id")
+	backfillerCommand.Flags().StringVar(&metricsPort, "metrics-port", "", "port to serve the progress metrics, disabled if empty")
+	backfillerCommand.Flags().StringVar(&environment, "environment", "", "environment label of the metrics")
 
 	backfillerCommand.MarkFlagRequired("mongo-uri")
 	backfillerCommand.MarkFlagRequired("mongo-database")
 	backfillerCommand.MarkFlagRequired("p2p-network")
-	backfillerCommand.MarkFlagRequired("vaa-payload-parser-url")
 	backfillerCommand.MarkFlagRequired("start-time")
 
 	root.AddCommand(backfillerCommand)
 }
+
+func addFailuresCommand(root *cobra.Command) {
+	var mongoUri, mongoDb, logLevel string
+	cfg := func() *config.FailuresConfiguration {
+		return &config.FailuresConfiguration{LogLevel: logLevel, MongoURI: mongoUri, MongoDatabase: mongoDb}
+	}
+
+	failuresCommand := &cobra.Command{
+		Use:   "failures",
+		Short: "Manage the VAAs that failed to be parsed",
+	}
+	failuresCommand.PersistentFlags().StringVar(&logLevel, "log-level", "INFO", "log level")
+	failuresCommand.PersistentFlags().StringVar(&mongoUri, "mongo-uri", "", "Mongo connection")
+	failuresCommand.PersistentFlags().StringVar(&mongoDb, "mongo-database", "", "Mongo database")
+	failuresCommand.MarkPersistentFlagRequired("mongo-uri")
+	failuresCommand.MarkPersistentFlagRequired("mongo-database")
+
+	var status, reason string
+	var chain int
+	var page, pageSize int64
+	listCommand := &cobra.Command{
+		Use:   "list",
+		Short: "List the parser failures, the last failed first",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			st, err := failure.ParseStatus(status)
+			if err != nil {
+				return err
+			}
+			q := &failure.Query{Status: st, Reason: failure.Reason(reason), Skip: page * pageSize, Limit: pageSize}
+			if chain >= 0 {
+				emitterChain := uint16(chain)
+				q.EmitterChain = &emitterChain
+			}
+			failures.List(cfg(), q)
+			return nil
+		},
+	}
+	listCommand.Flags().StringVar(&status, "status", "", "filter by status (pending/deadLetter)")
+	listCommand.Flags().StringVar(&reason, "reason", "", "filter by reason (parserError/storeError/unparseable)")
+	listCommand.Flags().IntVar(&chain, "chain", -1, "filter by emitter chain")
+	listCommand.Flags().Int64Var(&page, "page", 0, "page number")
+	listCommand.Flags().Int64Var(&pageSize, "page-size", 50, "number of failures listed at a time")
+
+	retryCommand := &cobra.Command{
+		Use:   "retry <vaa-id>...",
+		Short: "Retry parser failures now, including dead letters",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			failures.Retry(cfg(), args)
+		},
+	}
+
+	discardCommand := &cobra.Command{
+		Use:   "discard <vaa-id>...",
+		Short: "Discard parser failures, so they are not retried anymore",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(_ *cobra.Command, args []string) {
+			failures.Discard(cfg(), args)
+		},
+	}
+
+	failuresCommand.AddCommand(listCommand, retryCommand, discardCommand)
+	root.AddCommand(failuresCommand)
+}
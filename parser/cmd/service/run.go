@@ -6,15 +6,23 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/config"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/consumer"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/governance"
+	failureHttp "github.com/wormhole-foundation/wormhole-explorer/parser/http/failure"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/http/infrastructure"
+	reparseHttp "github.com/wormhole-foundation/wormhole-explorer/parser/http/reparse"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/http/vaa"
 	parserAlert "github.com/wormhole-foundation/wormhole-explorer/parser/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/db"
@@ -23,6 +31,8 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/queue"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/reparse"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 )
 
@@ -49,6 +59,13 @@ func Run() {
 
 	logger := logger.New("wormhole-explorer-parser", logger.WithLevel(config.LogLevel))
 
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(rootCtx, "wormhole-explorer-parser")
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	logger.Info("Starting wormhole-explorer-parser ...")
 
 	//setup DB connection
@@ -66,27 +83,65 @@ func Run() {
 	// create a metrics
 	metrics := newMetrics(config)
 
-	// create a parserVAAAPIClient
-	parserVAAAPIClient, err := parser.NewParserVAAAPIClient(config.VaaPayloadParserTimeout,
-		config.VaaPayloadParserURL, logger)
+	// create a payload parser, with the vaa-payload-parser api as fallback of the native parsers.
+	payloadParser, err := payload.NewRegistryFromConfig(payload.Config{
+		P2pNetwork:    config.P2pNetwork,
+		Emitters:      config.NativeParserEmitters,
+		RemoteURL:     config.VaaPayloadParserURL,
+		RemoteTimeout: config.VaaPayloadParserTimeout,
+		RemoteVersion: config.VaaPayloadParserVersion,
+	}, logger)
 	if err != nil {
-		logger.Fatal("failed to create parse vaa api client")
+		logger.Fatal("failed to create payload parser", zap.Error(err))
+	}
+	if config.NativeGovernanceParser {
+		payloadParser.Register(sdk.ChainIDSolana, sdk.GovernanceEmitter, governance.Parse)
 	}
 
 	// get consumer function.
 	sqsConsumer, vaaConsumeFunc := newVAAConsume(rootCtx, config, metrics, logger)
 	repository := parser.NewRepository(db.Database, logger)
+	governanceRepository := governance.NewRepository(db.Database, logger)
+
+	// create the parser failures service.
+	failureRepository := failure.NewRepository(db.Database, logger)
+	failureService := failure.NewService(failureRepository, failure.RetryPolicy{
+		MaxAttempts: config.FailureMaxAttempts,
+		BaseDelay:   time.Duration(config.FailureRetryBaseDelay) * time.Second,
+		MaxDelay:    time.Duration(config.FailureRetryMaxDelay) * time.Second,
+	}, logger)
 
 	//create a processor
-	processor := processor.New(parserVAAAPIClient, repository, alertClient, metrics, logger)
+	processor := processor.New(payloadParser, repository, governanceRepository, failureService, alertClient, metrics, logger)
 
 	// create and start a consumer
-	consumer := consumer.New(vaaConsumeFunc, processor.Process, metrics, logger)
+	tracker := reprocess.NewTracker(db.Database, logger)
+	consumer := consumer.New(vaaConsumeFunc, processor.Process, metrics, tracker, logger)
 	consumer.Start(rootCtx)
 
+	// create and start the retrier of the parser failures.
+	retrier := failure.NewRetrier(failureRepository, processor.Process,
+		time.Duration(config.FailureRetryInterval)*time.Second, config.FailureRetryBatchSize, logger)
+	retrier.Start(rootCtx)
+
+	// create and start the runner of the reparse jobs, throttled to not starve the consumer.
+	reparseRepository := reparse.NewRepository(db.Database, logger)
+	reparseRunner := reparse.NewRunner(reparseRepository, processor.Process,
+		time.Duration(config.ReparseInterval)*time.Second, config.ReparseBatchSize, config.ReparseRate,
+		time.Duration(config.VaaPayloadParserTimeout)*time.Second, logger)
+	reparseRunner.Start(rootCtx)
+
+	// create the admin controllers, the admin endpoints are disabled without an api key.
+	var failureCtrl *failureHttp.Controller
+	var reparseCtrl *reparseHttp.Controller
+	if config.AdminApiKey != "" {
+		failureCtrl = failureHttp.NewController(failureService, config.AdminApiKey, logger)
+		reparseCtrl = reparseHttp.NewController(reparse.NewService(reparseRepository, payloadParser.Version, logger), config.AdminApiKey, logger)
+	}
+
 	vaaRepository := vaa.NewRepository(db.Database, logger)
 	vaaController := vaa.NewController(vaaRepository, processor.Process, logger)
-	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, config.IsQueueConsumer(), sqsConsumer, db.Database, vaaController)
+	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, config.IsQueueConsumer(), sqsConsumer, db.Database, vaaController, failureCtrl, reparseCtrl)
 	server.Start()
 
 	logger.Info("Started wormhole-explorer-parser")
@@ -16,24 +16,49 @@ const (
 
 // ServiceConfiguration represents the application configuration when running as service with default values.
 type ServiceConfiguration struct {
-	Environment             string `env:"ENVIRONMENT,required"`
-	LogLevel                string `env:"LOG_LEVEL,default=INFO"`
-	Port                    string `env:"PORT,default=8000"`
-	ConsumerMode            string `env:"CONSUMER_MODE,default=QUEUE"`
-	MongoURI                string `env:"MONGODB_URI,required"`
-	MongoDatabase           string `env:"MONGODB_DATABASE,required"`
-	AwsEndpoint             string `env:"AWS_ENDPOINT"`
-	AwsAccessKeyID          string `env:"AWS_ACCESS_KEY_ID"`
-	AwsSecretAccessKey      string `env:"AWS_SECRET_ACCESS_KEY"`
-	AwsRegion               string `env:"AWS_REGION"`
-	SQSUrl                  string `env:"SQS_URL"`
-	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL, required"`
-	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
-	PprofEnabled            bool   `env:"PPROF_ENABLED,default=false"`
-	P2pNetwork              string `env:"P2P_NETWORK,required"`
-	AlertEnabled            bool   `env:"ALERT_ENABLED,default=false"`
-	AlertApiKey             string `env:"ALERT_API_KEY"`
-	MetricsEnabled          bool   `env:"METRICS_ENABLED,default=false"`
+	Environment        string `env:"ENVIRONMENT,required"`
+	LogLevel           string `env:"LOG_LEVEL,default=INFO"`
+	Port               string `env:"PORT,default=8000"`
+	ConsumerMode       string `env:"CONSUMER_MODE,default=QUEUE"`
+	MongoURI           string `env:"MONGODB_URI,required"`
+	MongoDatabase      string `env:"MONGODB_DATABASE,required"`
+	AwsEndpoint        string `env:"AWS_ENDPOINT"`
+	AwsAccessKeyID     string `env:"AWS_ACCESS_KEY_ID"`
+	AwsSecretAccessKey string `env:"AWS_SECRET_ACCESS_KEY"`
+	AwsRegion          string `env:"AWS_REGION"`
+	SQSUrl             string `env:"SQS_URL"`
+	// VaaPayloadParserURL is the vaa-payload-parser service, which parses the emitters without a native parser if it is set.
+	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL"`
+	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT,default=10"`
+	// VaaPayloadParserVersion is stamped on the VAAs parsed by the vaa-payload-parser service when it does not report its version.
+	VaaPayloadParserVersion int `env:"VAA_PAYLOAD_PARSER_VERSION,default=1"`
+	// NativeParserEmitters maps the native parser types to their emitters, encoded as JSON. The known emitters of the
+	// network are parsed natively by default, the emitters of a type replace its defaults:
+	// {"tokenBridge": ["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"], "nftBridge": [], "relayer": []}
+	NativeParserEmitters string `env:"NATIVE_PARSER_EMITTERS"`
+	PprofEnabled         bool   `env:"PPROF_ENABLED,default=false"`
+	P2pNetwork           string `env:"P2P_NETWORK,required"`
+	AlertEnabled         bool   `env:"ALERT_ENABLED,default=false"`
+	AlertApiKey          string `env:"ALERT_API_KEY"`
+	MetricsEnabled       bool   `env:"METRICS_ENABLED,default=false"`
+	// FailureMaxAttempts is the number of failed attempts after which a VAA becomes a dead letter.
+	FailureMaxAttempts int `env:"FAILURE_MAX_ATTEMPTS,default=10"`
+	// FailureRetryBaseDelay and FailureRetryMaxDelay bound the exponential backoff of the retries, in seconds.
+	FailureRetryBaseDelay int64 `env:"FAILURE_RETRY_BASE_DELAY,default=60"`
+	FailureRetryMaxDelay  int64 `env:"FAILURE_RETRY_MAX_DELAY,default=86400"`
+	// FailureRetryInterval is how often the due failures are retried, in seconds.
+	FailureRetryInterval  int64 `env:"FAILURE_RETRY_INTERVAL,default=30"`
+	FailureRetryBatchSize int64 `env:"FAILURE_RETRY_BATCH_SIZE,default=100"`
+	// ReparseRate is the max number of VAAs reparsed per second, to not starve the live parsing.
+	ReparseRate      int   `env:"REPARSE_RATE,default=50"`
+	ReparseBatchSize int64 `env:"REPARSE_BATCH_SIZE,default=100"`
+	// ReparseInterval is how often the reparse jobs to run are looked for, in seconds.
+	ReparseInterval int64 `env:"REPARSE_INTERVAL,default=60"`
+	// AdminApiKey enables the admin endpoints, the requests must be authenticated with this key.
+	AdminApiKey string `env:"ADMIN_API_KEY"`
+	// NativeGovernanceParser parses the governance VAAs natively, classified by module and action.
+	// The parsed payload has a different shape than the payload of the vaa-payload-parser service.
+	NativeGovernanceParser bool `env:"NATIVE_GOVERNANCE_PARSER,default=false"`
 }
 
 // BackfillerConfiguration represents the application configuration when running as backfiller with default values.
@@ -41,12 +66,32 @@ type BackfillerConfiguration struct {
 	LogLevel                string `env:"LOG_LEVEL,default=INFO"`
 	MongoURI                string `env:"MONGODB_URI,required"`
 	MongoDatabase           string `env:"MONGODB_DATABASE,required"`
-	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL, required"`
-	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT, required"`
+	VaaPayloadParserURL     string `env:"VAA_PAYLOAD_PARSER_URL"`
+	VaaPayloadParserTimeout int64  `env:"VAA_PAYLOAD_PARSER_TIMEOUT,default=10"`
+	VaaPayloadParserVersion int    `env:"VAA_PAYLOAD_PARSER_VERSION,default=1"`
+	NativeParserEmitters    string `env:"NATIVE_PARSER_EMITTERS"`
+	NativeGovernanceParser  bool   `env:"NATIVE_GOVERNANCE_PARSER,default=false"`
+	P2pNetwork              string `env:"P2P_NETWORK"`
 	StartTime               string `env:"START_TIME"`
 	EndTime                 string `env:"END_TIME"`
 	PageSize                int64  `env:"PAGE_SIZE,default=100"`
 	SortAsc                 bool   `env:"SORT_ASC,default=false"`
+	// RunID identifies the checkpoint of a run, a run with the same id is resumed from it. A new run is created if it is empty.
+	RunID          string  `env:"RUN_ID"`
+	Workers        int     `env:"WORKERS,default=10"`
+	EmitterChain   *uint16 `env:"EMITTER_CHAIN"`
+	EmitterAddress string  `env:"EMITTER_ADDRESS"`
+	AppID          string  `env:"APP_ID"`
+	// MetricsPort serves the progress metrics of the run, if it is set.
+	MetricsPort string `env:"METRICS_PORT"`
+	Environment string `env:"ENVIRONMENT"`
+}
+
+// FailuresConfiguration represents the application configuration when managing the parser failures.
+type FailuresConfiguration struct {
+	LogLevel      string
+	MongoURI      string
+	MongoDatabase string
 }
 
 // New creates a configuration with the values from .env file and environment variables.
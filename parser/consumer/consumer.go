@@ -2,7 +2,11 @@ package consumer
 
 import (
 	"context"
+	"errors"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/queue"
@@ -14,12 +18,13 @@ type Consumer struct {
 	consume queue.VAAConsumeFunc
 	process processor.ProcessorFunc
 	metrics metrics.Metrics
+	tracker *reprocess.Tracker
 	logger  *zap.Logger
 }
 
 // New creates a new vaa consumer.
-func New(consume queue.VAAConsumeFunc, process processor.ProcessorFunc, metrics metrics.Metrics, logger *zap.Logger) *Consumer {
-	return &Consumer{consume: consume, process: process, metrics: metrics, logger: logger}
+func New(consume queue.VAAConsumeFunc, process processor.ProcessorFunc, metrics metrics.Metrics, tracker *reprocess.Tracker, logger *zap.Logger) *Consumer {
+	return &Consumer{consume: consume, process: process, metrics: metrics, tracker: tracker, logger: logger}
 }
 
 // Start consumes messages from VAA queue, parse and store those messages in a repository.
@@ -31,12 +36,30 @@ func (c *Consumer) Start(ctx context.Context) {
 			// check id message is expired.
 			if msg.IsExpired() {
 				c.logger.Warn("Message with vaa expired", zap.String("id", event.ID))
+				if event.Reprocess.Includes(reprocess.StageParser) {
+					c.tracker.Track(ctx, event.Reprocess, reprocess.StageParser, event.ID, reprocess.ErrEventExpired)
+				}
 				msg.Failed()
 				continue
 			}
 			c.metrics.IncVaaUnexpired(event.ChainID)
 
-			_, err := c.process(ctx, event.Vaa)
+			// skip the events of reprocessing jobs that do not include the parser.
+			if !event.Reprocess.Includes(reprocess.StageParser) {
+				msg.Done()
+				continue
+			}
+
+			msgCtx, span := telemetry.StartVaaSpan(msg.Context(), "parser.ProcessVaa", event.ID)
+			_, err := c.process(msgCtx, event.Vaa)
+			telemetry.EndSpan(span, err)
+			c.tracker.Track(ctx, event.Reprocess, reprocess.StageParser, event.ID, err)
+			if errors.Is(err, failure.ErrRecorded) {
+				// the failure is retried by the failure retrier, so the message is not redelivered.
+				c.logger.Warn("VAA could not be parsed", zap.String("id", event.ID), zap.Error(err))
+				msg.Done()
+				continue
+			}
 			if err != nil {
 				c.logger.Error("Error processing parsed vaa",
 					zap.String("id", event.ID),
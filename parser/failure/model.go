@@ -0,0 +1,119 @@
+// Package failure records the VAAs that the parser fails to parse, retries them with exponential backoff
+// and keeps the ones that can not be parsed as dead letters for the operators.
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Reason is the reason a VAA could not be parsed.
+type Reason string
+
+const (
+	// ReasonParserError is a transient error of the parser, like the vaa-payload-parser service being down.
+	ReasonParserError Reason = "parserError"
+	// ReasonStoreError is an error saving the parsed VAA.
+	ReasonStoreError Reason = "storeError"
+	// ReasonUnparseable is a VAA that no parser supports or whose payload is invalid.
+	ReasonUnparseable Reason = "unparseable"
+)
+
+// Status is the state of a failure.
+type Status string
+
+const (
+	// StatusPending failures are retried when their next retry time is reached.
+	StatusPending Status = "pending"
+	// StatusDeadLetter failures are not retried automatically: they are unparseable or ran out of attempts.
+	StatusDeadLetter Status = "deadLetter"
+)
+
+// Errors of the failures.
+var (
+	ErrInvalidStatus = errors.New("INVALID STATUS")
+	ErrNotFound      = errors.New("FAILURE NOT FOUND")
+	// ErrRecorded is wrapped by the error of a VAA that failed to be parsed and whose failure was recorded,
+	// to be retried later or kept as a dead letter.
+	ErrRecorded = errors.New("PARSER FAILURE RECORDED")
+)
+
+// ParseStatus parses a failure status, the empty status is any status.
+func ParseStatus(s string) (Status, error) {
+	switch st := Status(s); st {
+	case "", StatusPending, StatusDeadLetter:
+		return st, nil
+	default:
+		return "", fmt.Errorf("%w: %s must be %s or %s", ErrInvalidStatus, s, StatusPending, StatusDeadLetter)
+	}
+}
+
+// Failure is a VAA that failed to be parsed.
+type Failure struct {
+	ID           string      `bson:"_id" json:"id"`
+	EmitterChain sdk.ChainID `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr  string      `bson:"emitterAddr" json:"emitterAddr"`
+	Sequence     string      `bson:"sequence" json:"sequence"`
+	Vaa          []byte      `bson:"vaa" json:"vaa"`
+	Reason       Reason      `bson:"reason" json:"reason"`
+	Error        string      `bson:"error" json:"error"`
+	Status       Status      `bson:"status" json:"status"`
+	// Attempts is the number of times the VAA failed to be parsed.
+	Attempts      int        `bson:"attempts" json:"attempts"`
+	NextRetryAt   *time.Time `bson:"nextRetryAt,omitempty" json:"nextRetryAt,omitempty"`
+	FirstFailedAt time.Time  `bson:"firstFailedAt" json:"firstFailedAt"`
+	LastFailedAt  time.Time  `bson:"lastFailedAt" json:"lastFailedAt"`
+}
+
+// RetryPolicy defines how many times and how often the failures are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of failed attempts after which a failure becomes a dead letter.
+	MaxAttempts int
+	// BaseDelay is the delay after the first failed attempt, doubled after every attempt up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy retries the failures 10 times, from a minute up to a day apart.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 10, BaseDelay: time.Minute, MaxDelay: 24 * time.Hour}
+
+// next returns the status and next retry time of a failure with a reason after a number of attempts.
+func (p RetryPolicy) next(reason Reason, attempts int, now time.Time) (Status, *time.Time) {
+	if reason == ReasonUnparseable || attempts >= p.MaxAttempts {
+		return StatusDeadLetter, nil
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	next := now.Add(delay)
+	return StatusPending, &next
+}
+
+// schedule returns the fields that set the status and the next retry time of a failure with a reason from its
+// attempts, so they are updated in the same write that increments the attempts.
+func (p RetryPolicy) schedule(reason Reason, now time.Time) bson.M {
+	// retries[i] is the next retry time after i+1 failed attempts, the failures with more attempts are dead letters.
+	retries := bson.A{}
+	for attempts := 1; ; attempts++ {
+		status, next := p.next(reason, attempts, now)
+		if status == StatusDeadLetter {
+			break
+		}
+		retries = append(retries, *next)
+	}
+
+	pending := bson.M{"$lte": bson.A{"$attempts", len(retries)}}
+	return bson.M{
+		"status": bson.M{"$cond": bson.A{pending, StatusPending, StatusDeadLetter}},
+		"nextRetryAt": bson.M{"$cond": bson.A{pending,
+			bson.M{"$arrayElemAt": bson.A{retries, bson.M{"$subtract": bson.A{"$attempts", 1}}}}, nil}},
+	}
+}
@@ -0,0 +1,121 @@
+package failure
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRetryPolicy_Next(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		reason   Reason
+		attempts int
+		status   Status
+		delay    time.Duration
+	}{
+		{name: "first attempt", reason: ReasonParserError, attempts: 1, status: StatusPending, delay: time.Minute},
+		{name: "doubled delay", reason: ReasonStoreError, attempts: 3, status: StatusPending, delay: 4 * time.Minute},
+		{name: "max delay", reason: ReasonParserError, attempts: 4, status: StatusPending, delay: 5 * time.Minute},
+		{name: "out of attempts", reason: ReasonParserError, attempts: 5, status: StatusDeadLetter},
+		{name: "unparseable", reason: ReasonUnparseable, attempts: 1, status: StatusDeadLetter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, next := policy.next(tt.reason, tt.attempts, now)
+			if status != tt.status {
+				t.Errorf("expected status %s, got %s", tt.status, status)
+			}
+			if tt.status == StatusDeadLetter {
+				if next != nil {
+					t.Errorf("expected no next retry for dead letter, got %v", next)
+				}
+				return
+			}
+			if next == nil || next.Sub(now) != tt.delay {
+				t.Errorf("expected next retry in %v, got %v", tt.delay, next)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Schedule(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	schedule := func(retries bson.A) bson.M {
+		pending := bson.M{"$lte": bson.A{"$attempts", len(retries)}}
+		return bson.M{
+			"status": bson.M{"$cond": bson.A{pending, StatusPending, StatusDeadLetter}},
+			"nextRetryAt": bson.M{"$cond": bson.A{pending,
+				bson.M{"$arrayElemAt": bson.A{retries, bson.M{"$subtract": bson.A{"$attempts", 1}}}}, nil}},
+		}
+	}
+
+	expected := schedule(bson.A{now.Add(time.Minute), now.Add(2 * time.Minute)})
+	if got := policy.schedule(ReasonParserError, now); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected schedule %v, got %v", expected, got)
+	}
+	// the unparseable failures are dead letters from the first attempt.
+	expected = schedule(bson.A{})
+	if got := policy.schedule(ReasonUnparseable, now); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected schedule %v, got %v", expected, got)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, s := range []string{"", "pending", "deadLetter"} {
+		if _, err := ParseStatus(s); err != nil {
+			t.Errorf("unexpected error for status %q: %v", s, err)
+		}
+	}
+	if _, err := ParseStatus("done"); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("expected ErrInvalidStatus, got %v", err)
+	}
+}
+
+// evalSchedule evaluates the fields set by a schedule for a failure with a number of attempts, like the database does.
+func evalSchedule(t *testing.T, schedule bson.M, attempts int) (Status, *time.Time) {
+	t.Helper()
+	status := schedule["status"].(bson.M)["$cond"].(bson.A)
+	pending := attempts <= status[0].(bson.M)["$lte"].(bson.A)[1].(int)
+	if !pending {
+		return status[2].(Status), nil
+	}
+	nextRetryAt := schedule["nextRetryAt"].(bson.M)["$cond"].(bson.A)
+	retries := nextRetryAt[1].(bson.M)["$arrayElemAt"].(bson.A)[0].(bson.A)
+	next := retries[attempts-1].(time.Time)
+	return status[1].(Status), &next
+}
+
+func TestRetryPolicy_ScheduleAttempts(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: 5 * time.Minute}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	schedule := policy.schedule(ReasonParserError, now)
+
+	// the delay is doubled after every failed attempt up to the max delay.
+	delays := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute}
+	for i, delay := range delays {
+		status, next := evalSchedule(t, schedule, i+1)
+		if status != StatusPending {
+			t.Errorf("expected status %s after %d attempts, got %s", StatusPending, i+1, status)
+		}
+		if next == nil || next.Sub(now) != delay {
+			t.Errorf("expected next retry in %v after %d attempts, got %v", delay, i+1, next)
+		}
+	}
+
+	// the failure becomes a dead letter at the max attempts.
+	for _, attempts := range []int{policy.MaxAttempts, policy.MaxAttempts + 1} {
+		status, next := evalSchedule(t, schedule, attempts)
+		if status != StatusDeadLetter || next != nil {
+			t.Errorf("expected dead letter after %d attempts, got %s %v", attempts, status, next)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package failure
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definitions.
+type Repository struct {
+	db          *mongo.Database
+	log         *zap.Logger
+	collections struct {
+		parserFailures *mongo.Collection
+	}
+}
+
+// NewRepository create a new respository instance.
+func NewRepository(db *mongo.Database, log *zap.Logger) *Repository {
+	return &Repository{db, log, struct {
+		parserFailures *mongo.Collection
+	}{
+		parserFailures: db.Collection("parserFailures"),
+	}}
+}
+
+// IncAttempts saves a failed attempt to parse a VAA and sets the fields of schedule from the updated attempts,
+// in a single write. It returns the failure with the attempts and the schedule updated.
+func (r *Repository) IncAttempts(ctx context.Context, f *Failure, schedule bson.M) (*Failure, error) {
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"emitterChain":  f.EmitterChain,
+			"emitterAddr":   f.EmitterAddr,
+			"sequence":      f.Sequence,
+			"vaa":           f.Vaa,
+			"reason":        f.Reason,
+			"error":         f.Error,
+			"lastFailedAt":  f.LastFailedAt,
+			"firstFailedAt": bson.M{"$ifNull": bson.A{"$firstFailedAt", f.LastFailedAt}},
+			"attempts":      bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$attempts", 0}}, 1}},
+		}},
+		bson.M{"$set": schedule},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var updated Failure
+	err := r.collections.parserFailures.FindOneAndUpdate(ctx, bson.M{"_id": f.ID}, update, opts).Decode(&updated)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &updated, nil
+}
+
+// UpdateStatus sets the status and the next retry time of a failure.
+func (r *Repository) UpdateStatus(ctx context.Context, id string, status Status, nextRetryAt *time.Time) error {
+	update := bson.M{"$set": bson.M{"status": status, "nextRetryAt": nextRetryAt}}
+	res, err := r.collections.parserFailures.UpdateByID(ctx, id, update)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ClaimDue takes the oldest pending failure whose next retry time is reached, and postpones it by lease,
+// so the other parser instances do not retry it at the same time. It returns nil if there is no due failure.
+func (r *Repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*Failure, error) {
+	filter := bson.M{
+		"status":      StatusPending,
+		"nextRetryAt": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"nextRetryAt": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "nextRetryAt", Value: 1}})
+
+	var f Failure
+	err := r.collections.parserFailures.FindOneAndUpdate(ctx, filter, update, opts).Decode(&f)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &f, nil
+}
+
+// Query is the filter of the failures to list.
+type Query struct {
+	Status       Status
+	EmitterChain *uint16
+	Reason       Reason
+	Skip         int64
+	Limit        int64
+}
+
+// Find returns the failures that match a query without their VAA, the last failed first.
+func (r *Repository) Find(ctx context.Context, q *Query) ([]*Failure, error) {
+	filter := bson.M{}
+	if q.Status != "" {
+		filter["status"] = q.Status
+	}
+	if q.EmitterChain != nil {
+		filter["emitterChain"] = *q.EmitterChain
+	}
+	if q.Reason != "" {
+		filter["reason"] = q.Reason
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "lastFailedAt", Value: -1}}).
+		SetSkip(q.Skip).
+		SetLimit(q.Limit).
+		SetProjection(bson.M{"vaa": 0})
+	return r.find(ctx, filter, opts)
+}
+
+func (r *Repository) find(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]*Failure, error) {
+	cur, err := r.collections.parserFailures.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	failures := make([]*Failure, 0)
+	if err := cur.All(ctx, &failures); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return failures, nil
+}
+
+// DeleteIfExists removes a failure, if there is one.
+func (r *Repository) DeleteIfExists(ctx context.Context, id string) error {
+	_, err := r.collections.parserFailures.DeleteOne(ctx, bson.M{"_id": id})
+	return errors.WithStack(err)
+}
+
+// Delete removes a failure.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	res, err := r.collections.parserFailures.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
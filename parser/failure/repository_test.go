@@ -0,0 +1,63 @@
+package failure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestRepository_ClaimDue(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mt.Run("claim", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: "2/0000000000000000000000000000000000000000000000000000000000000004/1"},
+				{Key: "status", Value: StatusPending},
+				{Key: "attempts", Value: 2},
+			}},
+		})
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		f, err := repository.ClaimDue(context.Background(), now, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if f == nil || f.ID != "2/0000000000000000000000000000000000000000000000000000000000000004/1" || f.Attempts != 2 {
+			t.Fatalf("expected the claimed failure, got %+v", f)
+		}
+
+		// the due pending failure is postponed by the lease, the oldest first.
+		cmd := mt.GetStartedEvent().Command
+		if status := cmd.Lookup("query", "status").StringValue(); status != string(StatusPending) {
+			t.Errorf("expected to claim a %s failure, got %s", StatusPending, status)
+		}
+		if due := cmd.Lookup("query", "nextRetryAt", "$lte").Time(); !due.Equal(now) {
+			t.Errorf("expected to claim a failure due at %v, got %v", now, due)
+		}
+		if next := cmd.Lookup("update", "$set", "nextRetryAt").Time(); !next.Equal(now.Add(time.Minute)) {
+			t.Errorf("expected the next retry at %v, got %v", now.Add(time.Minute), next)
+		}
+		if sort := cmd.Lookup("sort", "nextRetryAt").Int32(); sort != 1 {
+			t.Errorf("expected to claim the oldest failure first, got sort %d", sort)
+		}
+	})
+
+	mt.Run("no due failure", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		f, err := repository.ClaimDue(context.Background(), now, time.Minute)
+		if err != nil || f != nil {
+			t.Errorf("expected no failure, got %+v %v", f, err)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	"go.uber.org/zap"
+)
+
+// ProcessFunc parses a VAA and saves it. It records a new failed attempt if the VAA can not be parsed.
+type ProcessFunc func(context.Context, []byte) (*parser.ParsedVaaUpdate, error)
+
+// Retrier retries the pending failures whose next retry time is reached.
+type Retrier struct {
+	repository *Repository
+	process    ProcessFunc
+	interval   time.Duration
+	batchSize  int64
+	logger     *zap.Logger
+}
+
+// NewRetrier creates a new Retrier that retries up to batchSize due failures every interval.
+func NewRetrier(repository *Repository, process ProcessFunc, interval time.Duration, batchSize int64, logger *zap.Logger) *Retrier {
+	return &Retrier{
+		repository: repository,
+		process:    process,
+		interval:   interval,
+		batchSize:  batchSize,
+		logger:     logger.With(zap.String("module", "FailureRetrier")),
+	}
+}
+
+// Start retries the failures until the context is cancelled.
+func (r *Retrier) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.retryDue(ctx)
+			}
+		}
+	}()
+}
+
+// retryDue retries up to batchSize due failures.
+func (r *Retrier) retryDue(ctx context.Context) {
+	var retried, resolved int
+	for ; int64(retried) < r.batchSize && ctx.Err() == nil; retried++ {
+		// the claimed failure is postponed by an interval, which is its next retry if this attempt is lost.
+		f, err := r.repository.ClaimDue(ctx, time.Now(), r.interval)
+		if err != nil {
+			r.logger.Error("Error finding failures to retry", zap.Error(err))
+			break
+		}
+		if f == nil {
+			break
+		}
+		if r.retry(ctx, f) {
+			resolved++
+		}
+	}
+	if retried > 0 {
+		r.logger.Info("Retried parser failures", zap.Int("retried", retried), zap.Int("resolved", resolved))
+	}
+}
+
+// retry parses the VAA of a failure again, the failure is removed if it is parsed.
+func (r *Retrier) retry(ctx context.Context, f *Failure) bool {
+	_, err := r.process(ctx, f.Vaa)
+	if errors.Is(err, ErrRecorded) {
+		// the new attempt failed and was recorded with its next retry time.
+		return false
+	}
+	if err != nil {
+		r.logger.Error("Error retrying parser failure", zap.String("id", f.ID), zap.Error(err))
+		return false
+	}
+	// the processor removes the failure of the parsed VAA.
+	return true
+}
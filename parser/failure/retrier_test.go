@@ -0,0 +1,100 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func claimResponse(id string, vaa []byte) bson.D {
+	return bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "value", Value: bson.D{
+			{Key: "_id", Value: id},
+			{Key: "vaa", Value: vaa},
+			{Key: "status", Value: StatusPending},
+		}},
+	}
+}
+
+func TestRetrier_RetryDue(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("retries the due failures until there is none", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			claimResponse("parsed", []byte("parsed")),
+			claimResponse("failed", []byte("failed")),
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}},
+		)
+
+		var retried []string
+		process := func(_ context.Context, vaa []byte) (*parser.ParsedVaaUpdate, error) {
+			retried = append(retried, string(vaa))
+			if string(vaa) == "failed" {
+				// the processor records the new failed attempt.
+				return nil, fmt.Errorf("%w: parser error", ErrRecorded)
+			}
+			return &parser.ParsedVaaUpdate{}, nil
+		}
+		retrier := NewRetrier(NewRepository(mt.DB, zap.NewNop()), process, time.Minute, 10, zap.NewNop())
+
+		retrier.retryDue(context.Background())
+		if expected := []string{"parsed", "failed"}; !reflect.DeepEqual(retried, expected) {
+			t.Errorf("expected to retry %v, got %v", expected, retried)
+		}
+	})
+
+	mt.Run("retries up to the batch size", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			claimResponse("first", []byte("first")),
+			claimResponse("second", []byte("second")),
+		)
+
+		var retried []string
+		process := func(_ context.Context, vaa []byte) (*parser.ParsedVaaUpdate, error) {
+			retried = append(retried, string(vaa))
+			return &parser.ParsedVaaUpdate{}, nil
+		}
+		retrier := NewRetrier(NewRepository(mt.DB, zap.NewNop()), process, time.Minute, 1, zap.NewNop())
+
+		retrier.retryDue(context.Background())
+		if expected := []string{"first"}; !reflect.DeepEqual(retried, expected) {
+			t.Errorf("expected to retry %v, got %v", expected, retried)
+		}
+	})
+}
+
+func TestRetrier_Retry(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		resolved bool
+	}{
+		{name: "parsed", resolved: true},
+		{name: "failure recorded", err: fmt.Errorf("%w: parser error", ErrRecorded)},
+		{name: "failure not recorded", err: errors.New("store error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			process := func(context.Context, []byte) (*parser.ParsedVaaUpdate, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &parser.ParsedVaaUpdate{}, nil
+			}
+			retrier := NewRetrier(nil, process, time.Minute, 1, zap.NewNop())
+			if resolved := retrier.retry(context.Background(), &Failure{ID: tt.name}); resolved != tt.resolved {
+				t.Errorf("expected resolved %v, got %v", tt.resolved, resolved)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package failure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// maxPageSize is the max number of failures listed at a time.
+const maxPageSize = 1000
+
+// Service records the failures and lets the operators list, retry and discard them.
+type Service struct {
+	repository *Repository
+	policy     RetryPolicy
+	logger     *zap.Logger
+}
+
+// NewService creates a new failures service.
+func NewService(repository *Repository, policy RetryPolicy, logger *zap.Logger) *Service {
+	return &Service{
+		repository: repository,
+		policy:     policy,
+		logger:     logger.With(zap.String("module", "FailureService")),
+	}
+}
+
+// Record saves a failed attempt to parse a VAA, and schedules its next retry or makes it a dead letter.
+func (s *Service) Record(ctx context.Context, vaa *sdk.VAA, vaaBytes []byte, reason Reason, cause error) (*Failure, error) {
+	now := time.Now()
+	return s.repository.IncAttempts(ctx, &Failure{
+		ID:           vaa.MessageID(),
+		EmitterChain: vaa.EmitterChain,
+		EmitterAddr:  vaa.EmitterAddress.String(),
+		Sequence:     fmt.Sprintf("%d", vaa.Sequence),
+		Vaa:          vaaBytes,
+		Reason:       reason,
+		Error:        cause.Error(),
+		LastFailedAt: now,
+	}, s.policy.schedule(reason, now))
+}
+
+// Resolve removes the failure of a VAA that was parsed, if it failed before.
+func (s *Service) Resolve(ctx context.Context, id string) error {
+	return s.repository.DeleteIfExists(ctx, id)
+}
+
+// List returns the failures that match a query, the last failed first.
+func (s *Service) List(ctx context.Context, q *Query) ([]*Failure, error) {
+	if q.Limit <= 0 || q.Limit > maxPageSize {
+		q.Limit = maxPageSize
+	}
+	return s.repository.Find(ctx, q)
+}
+
+// Retry schedules a failure to be retried now, even if it is a dead letter.
+func (s *Service) Retry(ctx context.Context, id string) error {
+	now := time.Now()
+	return s.repository.UpdateStatus(ctx, id, StatusPending, &now)
+}
+
+// Discard removes a failure, so it is not retried anymore.
+func (s *Service) Discard(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
@@ -0,0 +1,230 @@
+// Package governance classifies the VAAs emitted by the governance emitter by module and action,
+// and decodes the fields of each action.
+package governance
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// governanceEmitter is the address of the governance emitter, on Solana.
+var governanceEmitter = sdk.Address{31: 0x04}
+
+// Governance modules.
+const (
+	ModuleCore              = "Core"
+	ModuleTokenBridge       = "TokenBridge"
+	ModuleNFTBridge         = "NFTBridge"
+	ModuleWormholeRelayer   = "WormholeRelayer"
+	ModuleCircleIntegration = "CircleIntegration"
+	ModuleGlobalAccountant  = "GlobalAccountant"
+)
+
+// headerLength is the length of the header of a governance payload: module, action and target chain.
+const headerLength = 32 + 1 + 2
+
+// GovernanceVaa is a VAA of the governance emitter, classified by module and action.
+type GovernanceVaa struct {
+	ID               string      `bson:"_id" json:"id"`
+	Sequence         string      `bson:"sequence" json:"sequence"`
+	GuardianSetIndex uint32      `bson:"guardianSetIndex" json:"guardianSetIndex"`
+	Module           string      `bson:"module" json:"module"`
+	ActionID         uint8       `bson:"actionId" json:"actionId"`
+	Action           string      `bson:"action" json:"action"`
+	TargetChain      sdk.ChainID `bson:"targetChain" json:"targetChain"`
+	// Chains are the chains the action applies to: the target chain, if any, and the chains referenced by the action.
+	Chains []sdk.ChainID `bson:"chains" json:"chains"`
+	// Fields are the decoded fields of the action. It is nil if the action is unknown.
+	Fields    map[string]interface{} `bson:"fields" json:"fields,omitempty"`
+	Payload   []byte                 `bson:"payload" json:"payload"`
+	Timestamp time.Time              `bson:"timestamp" json:"timestamp"`
+	UpdatedAt *time.Time             `bson:"updatedAt" json:"updatedAt"`
+}
+
+// action is a known action of a governance module.
+type action struct {
+	name   string
+	decode func(r *payload.Reader) map[string]interface{}
+}
+
+// actions contains the known actions of each governance module, indexed by action ID.
+var actions = map[string]map[uint8]action{
+	ModuleCore: {
+		1: {"ContractUpgrade", decodeContractUpgrade},
+		2: {"GuardianSetUpgrade", decodeGuardianSetUpgrade},
+		3: {"SetMessageFee", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{"messageFee": r.Uint256()}
+		}},
+		4: {"TransferFees", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{"amount": r.Uint256(), "recipient": address(r)}
+		}},
+		5: {"RecoverChainId", decodeRecoverChainID},
+	},
+	ModuleTokenBridge: {
+		1: {"RegisterChain", decodeRegisterChain},
+		2: {"ContractUpgrade", decodeContractUpgrade},
+		3: {"RecoverChainId", decodeRecoverChainID},
+	},
+	ModuleNFTBridge: {
+		1: {"RegisterChain", decodeRegisterChain},
+		2: {"ContractUpgrade", decodeContractUpgrade},
+		3: {"RecoverChainId", decodeRecoverChainID},
+	},
+	ModuleWormholeRelayer: {
+		1: {"RegisterChain", decodeRegisterChain},
+		2: {"ContractUpgrade", decodeContractUpgrade},
+		3: {"UpdateDefaultProvider", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{"defaultProvider": address(r)}
+		}},
+	},
+	ModuleCircleIntegration: {
+		1: {"UpdateWormholeFinality", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{"finality": r.Uint8()}
+		}},
+		2: {"RegisterEmitterAndDomain", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{"emitterChain": r.Chain(), "emitterAddress": address(r), "domain": r.Uint32()}
+		}},
+		3: {"ContractUpgrade", decodeContractUpgrade},
+	},
+	// the balance adjustments of the global accountant, which complements the governor.
+	ModuleGlobalAccountant: {
+		1: {"ModifyBalance", func(r *payload.Reader) map[string]interface{} {
+			return map[string]interface{}{
+				"sequence":     r.Uint64(),
+				"chainId":      r.Chain(),
+				"tokenChain":   r.Chain(),
+				"tokenAddress": address(r),
+				"kind":         r.Uint8(),
+				"amount":       r.Uint256(),
+				"reason":       strings.Trim(string(r.Bytes(32)), "\x00"),
+			}
+		}},
+	},
+}
+
+func decodeContractUpgrade(r *payload.Reader) map[string]interface{} {
+	return map[string]interface{}{"newContract": address(r)}
+}
+
+func decodeGuardianSetUpgrade(r *payload.Reader) map[string]interface{} {
+	index := r.Uint32()
+	n := r.Uint8()
+	keys := make([]string, 0, n)
+	for i := 0; i < int(n); i++ {
+		keys = append(keys, "0x"+hex.EncodeToString(r.Bytes(20)))
+	}
+	return map[string]interface{}{"newGuardianSetIndex": index, "newGuardianSetKeys": keys}
+}
+
+func decodeRecoverChainID(r *payload.Reader) map[string]interface{} {
+	return map[string]interface{}{"evmChainId": r.Uint256(), "newChainId": r.Chain()}
+}
+
+func decodeRegisterChain(r *payload.Reader) map[string]interface{} {
+	return map[string]interface{}{"emitterChain": r.Chain(), "emitterAddress": address(r)}
+}
+
+// address returns an address as hex.
+func address(r *payload.Reader) string {
+	addr := r.Address()
+	return hex.EncodeToString(addr[:])
+}
+
+// IsGovernanceVaa returns whether a VAA was emitted by the governance emitter.
+func IsGovernanceVaa(vaa *sdk.VAA) bool {
+	return vaa.EmitterChain == sdk.ChainIDSolana && vaa.EmitterAddress == governanceEmitter
+}
+
+// Decode classifies a governance VAA by module and action, and decodes the fields of the action.
+//
+// Unknown modules and actions are classified without fields.
+func Decode(vaa *sdk.VAA) (*GovernanceVaa, error) {
+
+	if len(vaa.Payload) < headerLength {
+		return nil, errors.New("governance payload is too short")
+	}
+
+	r := payload.NewReader(vaa.Payload)
+	module := strings.TrimLeft(string(r.Bytes(32)), "\x00")
+	actionID := r.Uint8()
+	targetChain := r.Chain()
+
+	g := GovernanceVaa{
+		ID:               vaa.MessageID(),
+		Sequence:         fmt.Sprintf("%d", vaa.Sequence),
+		GuardianSetIndex: vaa.GuardianSetIndex,
+		Module:           module,
+		ActionID:         actionID,
+		Action:           fmt.Sprintf("Unknown(%d)", actionID),
+		TargetChain:      targetChain,
+		Payload:          vaa.Payload,
+		Timestamp:        vaa.Timestamp,
+	}
+
+	if a, ok := actions[module][actionID]; ok {
+		g.Action = a.name
+		fields := a.decode(r)
+		if err := r.Err(); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s %s", module, a.name)
+		}
+		g.Fields = fields
+	}
+
+	g.Chains = affectedChains(targetChain, g.Fields)
+	return &g, nil
+}
+
+// Parse parses a governance VAA as a native payload, classified by module and action.
+//
+// The payload has a different shape than the payload of the vaa-payload-parser service.
+func Parse(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, error) {
+	g, err := Decode(vaa)
+	if err != nil {
+		return nil, err
+	}
+	return &vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse{
+		ParsedPayload: map[string]interface{}{
+			"module":      g.Module,
+			"actionId":    g.ActionID,
+			"action":      g.Action,
+			"targetChain": g.TargetChain,
+			"fields":      g.Fields,
+		},
+		StandardizedProperties: vaaPayloadParser.StandardizedProperties{
+			AppIds:    []string{},
+			FromChain: vaa.EmitterChain,
+			ToChain:   g.TargetChain,
+		},
+	}, nil
+}
+
+// affectedChains returns the target chain, if the action is not global, and the chains referenced by the fields.
+func affectedChains(targetChain sdk.ChainID, fields map[string]interface{}) []sdk.ChainID {
+	chains := make([]sdk.ChainID, 0)
+	add := func(c sdk.ChainID) {
+		if c == sdk.ChainIDUnset {
+			return
+		}
+		for _, existing := range chains {
+			if existing == c {
+				return
+			}
+		}
+		chains = append(chains, c)
+	}
+
+	add(targetChain)
+	for _, key := range []string{"emitterChain", "newChainId", "chainId"} {
+		if c, ok := fields[key].(sdk.ChainID); ok {
+			add(c)
+		}
+	}
+	return chains
+}
@@ -0,0 +1,129 @@
+package governance
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// governancePayload builds the payload of a governance VAA.
+func governancePayload(module string, action uint8, targetChain sdk.ChainID, body string) []byte {
+	payload := make([]byte, 32-len(module))
+	payload = append(payload, module...)
+	payload = append(payload, action, byte(targetChain>>8), byte(targetChain))
+	b, _ := hex.DecodeString(body)
+	return append(payload, b...)
+}
+
+func TestDecode_RegisterChain(t *testing.T) {
+	vaa := &sdk.VAA{
+		EmitterChain:   sdk.ChainIDSolana,
+		EmitterAddress: governanceEmitter,
+		Sequence:       10,
+		Payload: governancePayload(ModuleTokenBridge, 1, sdk.ChainIDUnset,
+			"0017"+"0000000000000000000000000b2402144bb366a632d14b83f244d2e0e21bd39c"),
+	}
+	if !IsGovernanceVaa(vaa) {
+		t.Fatal("expected a governance vaa")
+	}
+
+	g, err := Decode(vaa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Module != ModuleTokenBridge || g.Action != "RegisterChain" || g.TargetChain != sdk.ChainIDUnset {
+		t.Errorf("unexpected classification %s %s %d", g.Module, g.Action, g.TargetChain)
+	}
+	if g.Fields["emitterChain"] != sdk.ChainIDArbitrum {
+		t.Errorf("unexpected emitter chain %v", g.Fields["emitterChain"])
+	}
+	if !reflect.DeepEqual(g.Chains, []sdk.ChainID{sdk.ChainIDArbitrum}) {
+		t.Errorf("unexpected chains %v", g.Chains)
+	}
+}
+
+func TestDecode_GuardianSetUpgrade(t *testing.T) {
+	vaa := &sdk.VAA{
+		EmitterChain:   sdk.ChainIDSolana,
+		EmitterAddress: governanceEmitter,
+		Payload: governancePayload(ModuleCore, 2, sdk.ChainIDUnset,
+			"00000003"+"02"+"58cc3ae5c097b213ce3c81979e1b9f9570746aa5"+"ff6cb952589bde862c25ef4392132fb9d4a42157"),
+	}
+
+	g, err := Decode(vaa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Action != "GuardianSetUpgrade" || g.Fields["newGuardianSetIndex"] != uint32(3) {
+		t.Errorf("unexpected decoded vaa %s %v", g.Action, g.Fields)
+	}
+	keys := []string{"0x58cc3ae5c097b213ce3c81979e1b9f9570746aa5", "0xff6cb952589bde862c25ef4392132fb9d4a42157"}
+	if !reflect.DeepEqual(g.Fields["newGuardianSetKeys"], keys) {
+		t.Errorf("unexpected keys %v", g.Fields["newGuardianSetKeys"])
+	}
+	if len(g.Chains) != 0 {
+		t.Errorf("unexpected chains %v", g.Chains)
+	}
+}
+
+func TestDecode_ContractUpgrade(t *testing.T) {
+	vaa := &sdk.VAA{
+		EmitterChain:   sdk.ChainIDSolana,
+		EmitterAddress: governanceEmitter,
+		Payload: governancePayload(ModuleCore, 1, sdk.ChainIDEthereum,
+			"0000000000000000000000003c3d457f1522d3540ab3325aa5f1864e34cba9d0"),
+	}
+
+	g, err := Decode(vaa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Action != "ContractUpgrade" || g.Fields["newContract"] != "0000000000000000000000003c3d457f1522d3540ab3325aa5f1864e34cba9d0" {
+		t.Errorf("unexpected decoded vaa %s %v", g.Action, g.Fields)
+	}
+	if !reflect.DeepEqual(g.Chains, []sdk.ChainID{sdk.ChainIDEthereum}) {
+		t.Errorf("unexpected chains %v", g.Chains)
+	}
+}
+
+func TestDecode_UnknownAndTruncated(t *testing.T) {
+	g, err := Decode(&sdk.VAA{Payload: governancePayload("SomeModule", 7, sdk.ChainIDSolana, "")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Module != "SomeModule" || g.Action != "Unknown(7)" || g.Fields != nil {
+		t.Errorf("unexpected decoded vaa %s %s %v", g.Module, g.Action, g.Fields)
+	}
+
+	// the body of a contract upgrade is 32 bytes long.
+	if _, err := Decode(&sdk.VAA{Payload: governancePayload(ModuleCore, 1, sdk.ChainIDEthereum, "0102")}); err == nil {
+		t.Error("expected an error decoding a truncated payload")
+	}
+	if _, err := Decode(&sdk.VAA{Payload: []byte{0x01}}); err == nil {
+		t.Error("expected an error decoding a short payload")
+	}
+}
+
+func TestParse(t *testing.T) {
+	vaa := &sdk.VAA{
+		EmitterChain:   sdk.ChainIDSolana,
+		EmitterAddress: governanceEmitter,
+		Sequence:       12,
+		Payload: governancePayload(ModuleCore, 3, sdk.ChainIDEthereum,
+			"0000000000000000000000000000000000000000000000000000000000000064"),
+	}
+
+	parsed, err := Parse(vaa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := parsed.ParsedPayload.(map[string]interface{})
+	if payload["module"] != ModuleCore || payload["action"] != "SetMessageFee" || payload["targetChain"] != sdk.ChainIDEthereum {
+		t.Errorf("unexpected parsed payload %v", payload)
+	}
+	if parsed.StandardizedProperties.FromChain != sdk.ChainIDSolana || parsed.StandardizedProperties.ToChain != sdk.ChainIDEthereum {
+		t.Errorf("unexpected standardized properties %+v", parsed.StandardizedProperties)
+	}
+}
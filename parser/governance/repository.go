@@ -0,0 +1,40 @@
+package governance
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository definitions.
+type Repository struct {
+	db          *mongo.Database
+	log         *zap.Logger
+	collections struct {
+		governanceVaas *mongo.Collection
+	}
+}
+
+// NewRepository create a new respository instance.
+func NewRepository(db *mongo.Database, log *zap.Logger) *Repository {
+	return &Repository{db, log, struct {
+		governanceVaas *mongo.Collection
+	}{
+		governanceVaas: db.Collection("governanceVaas"),
+	}}
+}
+
+// UpsertGovernanceVaa saves a classified governance VAA.
+func (s *Repository) UpsertGovernanceVaa(ctx context.Context, g *GovernanceVaa) error {
+	update := bson.M{
+		"$set": g,
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := s.collections.governanceVaas.UpdateByID(ctx, g.ID, update, opts)
+	return errors.WithStack(err)
+}
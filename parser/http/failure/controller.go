@@ -0,0 +1,112 @@
+package failure
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *failure.Service
+	apiKey string
+	logger *zap.Logger
+}
+
+// NewController creates a Controller instance.
+// The endpoints require the header `Authorization: Bearer <apiKey>`.
+func NewController(srv *failure.Service, apiKey string, logger *zap.Logger) *Controller {
+	return &Controller{srv: srv, apiKey: apiKey, logger: logger.With(zap.String("module", "FailureController"))}
+}
+
+// Authenticate is a middleware that validates the admin api key.
+func (c *Controller) Authenticate(ctx *fiber.Ctx) error {
+	token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.apiKey)) != 1 {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+	return ctx.Next()
+}
+
+// List handler for the endpoint GET /admin/failures.
+// The query params status, chain and reason filter the failures, page and pageSize paginate them.
+func (c *Controller) List(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	status, err := failure.ParseStatus(ctx.Query("status"))
+	if err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	q := failure.Query{Status: status, Reason: failure.Reason(ctx.Query("reason"))}
+	if chain := ctx.Query("chain"); chain != "" {
+		chainID, err := strconv.ParseUint(chain, 10, 16)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid chain"})
+		}
+		emitterChain := uint16(chainID)
+		q.EmitterChain = &emitterChain
+	}
+	page, err := strconv.ParseInt(ctx.Query("page", "0"), 10, 64)
+	if err != nil || page < 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid page"})
+	}
+	pageSize, err := strconv.ParseInt(ctx.Query("pageSize", "50"), 10, 64)
+	if err != nil || pageSize <= 0 {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid pageSize"})
+	}
+	q.Skip, q.Limit = page*pageSize, pageSize
+
+	failures, err := c.srv.List(ctx.Context(), &q)
+	if err != nil {
+		c.logger.Error("Error listing parser failures", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+	return ctx.JSON(failures)
+}
+
+// Retry handler for the endpoint POST /admin/failures/:chain/:emitter/:seq/retry.
+func (c *Controller) Retry(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	id := failureID(ctx)
+	err := c.srv.Retry(ctx.Context(), id)
+	if errors.Is(err, failure.ErrNotFound) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "failure not found"})
+	}
+	if err != nil {
+		c.logger.Error("Error retrying parser failure", zap.Error(err), zap.String("id", id), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	c.logger.Info("Parser failure scheduled to retry", zap.String("id", id), zap.String("requestID", requestID))
+	return ctx.SendStatus(fiber.StatusAccepted)
+}
+
+// Discard handler for the endpoint DELETE /admin/failures/:chain/:emitter/:seq.
+func (c *Controller) Discard(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	id := failureID(ctx)
+	err := c.srv.Discard(ctx.Context(), id)
+	if errors.Is(err, failure.ErrNotFound) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "failure not found"})
+	}
+	if err != nil {
+		c.logger.Error("Error discarding parser failure", zap.Error(err), zap.String("id", id), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	c.logger.Info("Parser failure discarded", zap.String("id", id), zap.String("requestID", requestID))
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// failureID returns the id of the failure, the id of its VAA, from the path params.
+func failureID(ctx *fiber.Ctx) string {
+	return fmt.Sprintf("%s/%s/%s", ctx.Params("chain"), ctx.Params("emitter"), ctx.Params("seq"))
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/ansrivas/fiberprometheus/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/http/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/http/reparse"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/http/vaa"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/sqs"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,8 +18,9 @@ type Server struct {
 	logger *zap.Logger
 }
 
+// The admin endpoints are registered only if their controllers are not nil.
 func NewServer(logger *zap.Logger, port string, pprofEnabled bool, isQueueConsumer bool, consumer *sqs.Consumer,
-	db *mongo.Database, vaaController *vaa.Controller) *Server {
+	db *mongo.Database, vaaController *vaa.Controller, failureCtrl *failure.Controller, reparseCtrl *reparse.Controller) *Server {
 	repository := NewRepository(db, logger)
 	service := NewService(repository, consumer, isQueueConsumer, logger)
 	ctrl := NewController(service, logger)
@@ -38,6 +41,19 @@ func NewServer(logger *zap.Logger, port string, pprofEnabled bool, isQueueConsum
 
 	api.Post("/vaa/parse", vaaController.Parse)
 
+	if failureCtrl != nil {
+		failures := api.Group("/admin/failures", failureCtrl.Authenticate)
+		failures.Get("/", failureCtrl.List)
+		failures.Post("/:chain/:emitter/:seq/retry", failureCtrl.Retry)
+		failures.Delete("/:chain/:emitter/:seq", failureCtrl.Discard)
+	}
+	if reparseCtrl != nil {
+		reparse := api.Group("/admin/reparse", reparseCtrl.Authenticate)
+		reparse.Post("/", reparseCtrl.CreateJob)
+		reparse.Get("/:id", reparseCtrl.GetJob)
+		reparse.Delete("/:id", reparseCtrl.CancelJob)
+	}
+
 	return &Server{
 		app:    app,
 		port:   port,
@@ -0,0 +1,105 @@
+package reparse
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/reparse"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *reparse.Service
+	apiKey string
+	logger *zap.Logger
+}
+
+// NewController creates a Controller instance.
+// The endpoints require the header `Authorization: Bearer <apiKey>`.
+func NewController(srv *reparse.Service, apiKey string, logger *zap.Logger) *Controller {
+	return &Controller{srv: srv, apiKey: apiKey, logger: logger.With(zap.String("module", "ReparseController"))}
+}
+
+// Authenticate is a middleware that validates the admin api key.
+func (c *Controller) Authenticate(ctx *fiber.Ctx) error {
+	token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.apiKey)) != 1 {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+	return ctx.Next()
+}
+
+// CreateJob handler for the endpoint POST /admin/reparse.
+func (c *Controller) CreateJob(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	var req reparse.CreateJobRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	job, err := c.srv.CreateJob(ctx.Context(), &req)
+	switch {
+	case err != nil && isValidationError(err):
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		c.logger.Error("Error creating reparse job", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	c.logger.Info("Reparse job created",
+		zap.String("jobId", job.ID),
+		zap.String("reason", job.Reason),
+		zap.Uint16("emitterChain", job.EmitterChain),
+		zap.String("emitterAddress", job.EmitterAddress),
+		zap.Int("version", job.Version),
+		zap.String("requestID", requestID))
+	return ctx.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetJob handler for the endpoint GET /admin/reparse/:id.
+func (c *Controller) GetJob(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	job, err := c.srv.GetJob(ctx.Context(), ctx.Params("id"))
+	if errors.Is(err, reparse.ErrJobNotFound) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		c.logger.Error("Error getting reparse job", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+	return ctx.JSON(job)
+}
+
+// CancelJob handler for the endpoint DELETE /admin/reparse/:id.
+func (c *Controller) CancelJob(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	id := ctx.Params("id")
+	err := c.srv.CancelJob(ctx.Context(), id)
+	switch {
+	case errors.Is(err, reparse.ErrJobNotFound):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, reparse.ErrJobFinished):
+		return ctx.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		c.logger.Error("Error cancelling reparse job", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	c.logger.Info("Reparse job cancelled", zap.String("jobId", id), zap.String("requestID", requestID))
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+func isValidationError(err error) bool {
+	switch err {
+	case reparse.ErrMissingReason, reparse.ErrMissingEmitter, reparse.ErrInvalidVersion, reparse.ErrInvalidParserType, reparse.ErrNoParser:
+		return true
+	}
+	return false
+}
@@ -1,5 +1,7 @@
 package metrics
 
+import "time"
+
 // DummyMetrics is a dummy implementation of Metric interface.
 type DummyMetrics struct {
 }
@@ -35,3 +37,13 @@ func (d *DummyMetrics) IncVaaPayloadParserSuccessCount(chainID uint16) {}
 
 // IncVaaPayloadParserSuccessCount increments the number of vaa payload parser success.
 func (d *DummyMetrics) IncVaaPayloadParserNotFoundCount(chainID uint16) {}
+
+// IncParserFailure increments the number of recorded parser failures.
+func (d *DummyMetrics) IncParserFailure(chainID uint16, status string) {}
+
+// IncBackfillerVaa increments the number of VAA processed by a backfiller run by result.
+func (d *DummyMetrics) IncBackfillerVaa(runID string, result string) {}
+
+// SetBackfillerProgress sets the progress and the estimated time to finish a backfiller run.
+func (d *DummyMetrics) SetBackfillerProgress(runID string, processed, total int64, eta time.Duration) {
+}
@@ -1,5 +1,7 @@
 package metrics
 
+import "time"
+
 const serviceName = "wormscan-parser"
 
 type Metrics interface {
@@ -13,4 +15,9 @@ type Metrics interface {
 	IncVaaPayloadParserErrorCount(chainID uint16)
 	IncVaaPayloadParserNotFoundCount(chainID uint16)
 	IncVaaPayloadParserSuccessCount(chainID uint16)
+
+	IncParserFailure(chainID uint16, status string)
+
+	IncBackfillerVaa(runID string, result string)
+	SetBackfillerProgress(runID string, processed, total int64, eta time.Duration)
 }
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
@@ -11,6 +13,9 @@ type PrometheusMetrics struct {
 	vaaParseCount                 *prometheus.CounterVec
 	vaaPayloadParserRequest       *prometheus.CounterVec
 	vaaPayloadParserResponseCount *prometheus.CounterVec
+	parserFailureCount            *prometheus.CounterVec
+	backfillerVaaCount            *prometheus.CounterVec
+	backfillerProgress            *prometheus.GaugeVec
 }
 
 // NewPrometheusMetrics returns a new instance of PrometheusMetrics.
@@ -42,10 +47,40 @@ func NewPrometheusMetrics(environment string) *PrometheusMetrics {
 				"service":     serviceName,
 			},
 		}, []string{"chain", "status"})
+	parserFailureCount := promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parse_vaa_failure_count_by_chain",
+			Help: "Total number of recorded parser failures by chain and status",
+			ConstLabels: map[string]string{
+				"environment": environment,
+				"service":     serviceName,
+			},
+		}, []string{"chain", "status"})
+	backfillerVaaCount := promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parse_backfiller_vaa_count_by_run",
+			Help: "Total number of vaa processed by backfiller run and result",
+			ConstLabels: map[string]string{
+				"environment": environment,
+				"service":     serviceName,
+			},
+		}, []string{"run_id", "result"})
+	backfillerProgress := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "parse_backfiller_progress_by_run",
+			Help: "Progress of the backfiller runs: processed and total vaa, and estimated seconds to finish",
+			ConstLabels: map[string]string{
+				"environment": environment,
+				"service":     serviceName,
+			},
+		}, []string{"run_id", "type"})
 	return &PrometheusMetrics{
 		vaaParseCount:                 vaaParseCount,
 		vaaPayloadParserRequest:       vaaPayloadParserRequestCount,
 		vaaPayloadParserResponseCount: vaaPayloadParserResponseCount,
+		parserFailureCount:            parserFailureCount,
+		backfillerVaaCount:            backfillerVaaCount,
+		backfillerProgress:            backfillerProgress,
 	}
 }
 
@@ -102,3 +137,21 @@ func (m *PrometheusMetrics) IncVaaPayloadParserNotFoundCount(chainID uint16) {
 	chain := vaa.ChainID(chainID).String()
 	m.vaaPayloadParserResponseCount.WithLabelValues(chain, "not_found").Inc()
 }
+
+// IncParserFailure increments the number of recorded parser failures.
+func (m *PrometheusMetrics) IncParserFailure(chainID uint16, status string) {
+	chain := vaa.ChainID(chainID).String()
+	m.parserFailureCount.WithLabelValues(chain, status).Inc()
+}
+
+// IncBackfillerVaa increments the number of VAA processed by a backfiller run by result.
+func (m *PrometheusMetrics) IncBackfillerVaa(runID string, result string) {
+	m.backfillerVaaCount.WithLabelValues(runID, result).Inc()
+}
+
+// SetBackfillerProgress sets the progress and the estimated time to finish a backfiller run.
+func (m *PrometheusMetrics) SetBackfillerProgress(runID string, processed, total int64, eta time.Duration) {
+	m.backfillerProgress.WithLabelValues(runID, "processed").Set(float64(processed))
+	m.backfillerProgress.WithLabelValues(runID, "total").Set(float64(total))
+	m.backfillerProgress.WithLabelValues(runID, "eta_seconds").Set(eta.Seconds())
+}
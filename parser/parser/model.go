@@ -3,34 +3,31 @@ package parser
 import (
 	"time"
 
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/common/payload"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
-// StandardizedProperties represent a standardized properties.
-type StandardizedProperties struct {
-	AppIds       []string    `json:"appIds" bson:"appIds"`
-	FromChain    sdk.ChainID `json:"fromChain" bson:"fromChain"`
-	FromAddress  string      `json:"fromAddress" bson:"fromAddress"`
-	ToChain      sdk.ChainID `json:"toChain" bson:"toChain"`
-	ToAddress    string      `json:"toAddress" bson:"toAddress"`
-	TokenChain   sdk.ChainID `json:"tokenChain" bson:"tokenChain"`
-	TokenAddress string      `json:"tokenAddress" bson:"tokenAddress"`
-	Amount       string      `json:"amount" bson:"amount"`
-	FeeAddress   string      `json:"feeAddress" bson:"feeAddress"`
-	FeeChain     sdk.ChainID `json:"feeChain" bson:"feeChain"`
-	Fee          string      `json:"fee" bson:"fee"`
-}
+// Types of the parsers of the VAAs.
+const (
+	ParserTypeNative = payload.ParserTypeNative
+	ParserTypeRemote = payload.ParserTypeRemote
+)
+
+// ParserVersion is the parser that produced a parsed vaa, to find the vaas parsed by an outdated parser.
+type ParserVersion = payload.ParserVersion
 
 // ParsedVaaUpdate represent a parsed vaa update.
 type ParsedVaaUpdate struct {
-	ID                        string                 `bson:"_id" json:"id"`
-	EmitterChain              sdk.ChainID            `bson:"emitterChain" json:"emitterChain"`
-	EmitterAddr               string                 `bson:"emitterAddr" json:"emitterAddr"`
-	Sequence                  string                 `bson:"sequence" json:"sequence"`
-	AppIDs                    []string               `bson:"appIds" json:"appIds"`
-	ParsedPayload             interface{}            `bson:"parsedPayload" json:"parsedPayload"`
-	RawStandardizedProperties StandardizedProperties `bson:"rawStandardizedProperties" json:"rawStandardizedProperties"`
-	StandardizedProperties    StandardizedProperties `bson:"standardizedProperties" json:"standardizedProperties"`
-	UpdatedAt                 *time.Time             `bson:"updatedAt" json:"updatedAt"`
-	Timestamp                 time.Time              `bson:"-" json:"-"`
+	ID                        string                                  `bson:"_id" json:"id"`
+	EmitterChain              sdk.ChainID                             `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddr               string                                  `bson:"emitterAddr" json:"emitterAddr"`
+	Sequence                  string                                  `bson:"sequence" json:"sequence"`
+	AppIDs                    []string                                `bson:"appIds" json:"appIds"`
+	ParsedPayload             interface{}                             `bson:"parsedPayload" json:"parsedPayload"`
+	RawStandardizedProperties vaaPayloadParser.StandardizedProperties `bson:"rawStandardizedProperties" json:"rawStandardizedProperties"`
+	StandardizedProperties    vaaPayloadParser.StandardizedProperties `bson:"standardizedProperties" json:"standardizedProperties"`
+	Parser                    ParserVersion                           `bson:"parser" json:"parser"`
+	UpdatedAt                 *time.Time                              `bson:"updatedAt" json:"updatedAt"`
+	Timestamp                 time.Time                               `bson:"timestamp" json:"-"`
 }
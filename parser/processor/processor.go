@@ -4,12 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/big"
 	"strings"
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
-	"github.com/wormhole-foundation/wormhole-explorer/common/domain"
+	vaaPayloadParser "github.com/wormhole-foundation/wormhole-explorer/common/client/parser"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/governance"
 	parserAlert "github.com/wormhole-foundation/wormhole-explorer/parser/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
@@ -17,21 +18,38 @@ import (
 	"go.uber.org/zap"
 )
 
+// PayloadParser parses the payload of a VAA with its standardized properties, and returns the version of the parser.
+type PayloadParser interface {
+	Parse(vaa *sdk.VAA) (*vaaPayloadParser.ParseVaaWithStandarizedPropertiesdResponse, parser.ParserVersion, error)
+}
+
 type Processor struct {
-	parser     parser.ParserVAAAPIClient
-	repository *parser.Repository
-	alert      alert.AlertClient
-	metrics    metrics.Metrics
-	logger     *zap.Logger
+	parser               PayloadParser
+	repository           *parser.Repository
+	governanceRepository *governance.Repository
+	failures             *failure.Service
+	alert                alert.AlertClient
+	metrics              metrics.Metrics
+	logger               *zap.Logger
 }
 
-func New(parser parser.ParserVAAAPIClient, repository *parser.Repository, alert alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) *Processor {
+func New(
+	parser PayloadParser,
+	repository *parser.Repository,
+	governanceRepository *governance.Repository,
+	failures *failure.Service,
+	alert alert.AlertClient,
+	metrics metrics.Metrics,
+	logger *zap.Logger,
+) *Processor {
 	return &Processor{
-		parser:     parser,
-		repository: repository,
-		alert:      alert,
-		metrics:    metrics,
-		logger:     logger,
+		parser:               parser,
+		repository:           repository,
+		governanceRepository: governanceRepository,
+		failures:             failures,
+		alert:                alert,
+		metrics:              metrics,
+		logger:               logger,
 	}
 }
 
@@ -42,23 +60,30 @@ func (p *Processor) Process(ctx context.Context, vaaBytes []byte) (*parser.Parse
 		return nil, err
 	}
 
-	// call vaa-payload-parser api to parse a VAA.
+	// classify the VAAs of the governance emitter.
+	if governance.IsGovernanceVaa(vaa) {
+		if err := p.processGovernanceVaa(ctx, vaa); err != nil {
+			return nil, err
+		}
+	}
+
+	// parse the VAA natively or with the vaa-payload-parser api.
 	chainID := uint16(vaa.EmitterChain)
 	emitterAddress := vaa.EmitterAddress.String()
 	sequence := fmt.Sprintf("%d", vaa.Sequence)
 
 	p.metrics.IncVaaPayloadParserRequestCount(chainID)
-	vaaParseResponse, err := p.parser.ParseVaaWithStandarizedProperties(vaa)
+	vaaParseResponse, parserVersion, err := p.parser.Parse(vaa)
 	if err != nil {
 		// split metrics error not found and others errors.
-		if errors.Is(err, parser.ErrNotFound) {
+		if errors.Is(err, vaaPayloadParser.ErrNotFound) {
 			p.metrics.IncVaaPayloadParserNotFoundCount(chainID)
 		} else {
 			p.metrics.IncVaaPayloadParserErrorCount(chainID)
 		}
 
-		// if error is ErrInternalError or ErrCallEndpoint return error in order to retry.
-		if errors.Is(err, parser.ErrInternalError) || errors.Is(err, parser.ErrCallEndpoint) {
+		// if error is ErrInternalError or ErrCallEndpoint record the failure in order to retry.
+		if errors.Is(err, vaaPayloadParser.ErrInternalError) || errors.Is(err, vaaPayloadParser.ErrCallEndpoint) {
 			// send alert when exists and error calling vaa-payload-parser component.
 			alertContext := alert.AlertContext{
 				Details: map[string]string{
@@ -69,19 +94,19 @@ func (p *Processor) Process(ctx context.Context, vaaBytes []byte) (*parser.Parse
 				Error: err,
 			}
 			p.alert.CreateAndSend(ctx, parserAlert.AlertKeyVaaPayloadParserError, alertContext)
-			return nil, err
+			return nil, p.recordFailure(ctx, vaa, vaaBytes, failure.ReasonParserError, err)
 		}
 
 		p.logger.Info("VAA cannot be parsed", zap.Error(err),
 			zap.Uint16("chainID", chainID),
 			zap.String("address", emitterAddress),
 			zap.String("sequence", sequence))
-		return nil, nil
+		return nil, p.recordFailure(ctx, vaa, vaaBytes, failure.ReasonUnparseable, err)
 	}
 	p.metrics.IncVaaPayloadParserSuccessCount(chainID)
 	p.metrics.IncVaaParsed(chainID)
 
-	standardizedProperties := p.transformStandarizedProperties(vaa.MessageID(), vaaParseResponse.StandardizedProperties)
+	standardizedProperties := vaaPayloadParser.TransformStandarizedProperties(vaa.MessageID(), vaaParseResponse.StandardizedProperties, p.logger)
 
 	// create ParsedVaaUpdate to upsert.
 	now := time.Now()
@@ -94,6 +119,7 @@ func (p *Processor) Process(ctx context.Context, vaaBytes []byte) (*parser.Parse
 		ParsedPayload:             vaaParseResponse.ParsedPayload,
 		RawStandardizedProperties: vaaParseResponse.StandardizedProperties,
 		StandardizedProperties:    standardizedProperties,
+		Parser:                    parserVersion,
 		Timestamp:                 vaa.Timestamp,
 		UpdatedAt:                 &now,
 	}
@@ -113,97 +139,69 @@ func (p *Processor) Process(ctx context.Context, vaaBytes []byte) (*parser.Parse
 			},
 			Error: err}
 		p.alert.CreateAndSend(ctx, parserAlert.AlertKeyInsertParsedVaaError, alertContext)
-		return nil, err
+		return nil, p.recordFailure(ctx, vaa, vaaBytes, failure.ReasonStoreError, err)
 	}
 	p.metrics.IncVaaParsedInserted(chainID)
 
+	// remove the failure of a VAA that failed before, the failure would be retried otherwise.
+	if err := p.failures.Resolve(ctx, vaaParsed.ID); err != nil {
+		p.logger.Error("Error removing resolved parser failure", zap.String("id", vaaParsed.ID), zap.Error(err))
+	}
+
 	p.logger.Info("parsed VAA was successfully persisted", zap.String("id", vaaParsed.ID))
 	return &vaaParsed, nil
 }
 
-// transformStandarizedProperties transform amount and fee amount.
-func (p *Processor) transformStandarizedProperties(vaaID string, sp parser.StandardizedProperties) parser.StandardizedProperties {
-	// transform amount.
-	amount := p.transformAmount(sp.TokenChain, sp.TokenAddress, sp.Amount, vaaID)
-	// transform fee amount.
-	feeAmount := p.transformAmount(sp.FeeChain, sp.FeeAddress, sp.Fee, vaaID)
-	// create StandardizedProperties.
-	return parser.StandardizedProperties{
-		AppIds:       sp.AppIds,
-		FromChain:    sp.FromChain,
-		FromAddress:  sp.FromAddress,
-		ToChain:      sp.ToChain,
-		ToAddress:    sp.ToAddress,
-		TokenChain:   sp.TokenChain,
-		TokenAddress: sp.TokenAddress,
-		Amount:       amount,
-		FeeAddress:   sp.FeeAddress,
-		FeeChain:     sp.FeeChain,
-		Fee:          feeAmount,
+// recordFailure saves a failed attempt to parse a VAA, to retry it later or keep it as a dead letter.
+//
+// It returns an error that wraps failure.ErrRecorded when the failure is recorded, and the parser error when the
+// failure can not be recorded, so the VAA is redelivered instead of lost.
+func (p *Processor) recordFailure(ctx context.Context, vaa *sdk.VAA, vaaBytes []byte, reason failure.Reason, parseErr error) error {
+	f, err := p.failures.Record(ctx, vaa, vaaBytes, reason, parseErr)
+	if err != nil {
+		p.logger.Error("Error recording parser failure",
+			zap.String("id", vaa.MessageID()),
+			zap.String("reason", string(reason)),
+			zap.Error(err))
+		return parseErr
 	}
+	p.metrics.IncParserFailure(uint16(vaa.EmitterChain), string(f.Status))
+	p.logger.Info("parser failure was recorded",
+		zap.String("id", f.ID),
+		zap.String("reason", string(f.Reason)),
+		zap.String("status", string(f.Status)),
+		zap.Int("attempts", f.Attempts))
+	return fmt.Errorf("%w: %v", failure.ErrRecorded, parseErr)
 }
 
-// transformAmount transform amount and fee amount.
-func (p *Processor) transformAmount(chainID sdk.ChainID, nativeAddress, amount, vaaID string) string {
-
-	if chainID == sdk.ChainIDUnset || nativeAddress == "" || amount == "" {
-		return ""
-	}
-
-	nativeHex, err := domain.DecodeNativeAddressToHex(sdk.ChainID(chainID), nativeAddress)
-	if err != nil {
-		p.logger.Warn("Native address cannot be transformed to hex",
-			zap.String("vaaId", vaaID),
-			zap.String("nativeAddress", nativeAddress),
-			zap.Uint16("chain", uint16(chainID)))
-		return ""
-	}
+// processGovernanceVaa decodes a governance VAA by module and action and saves it.
+//
+// VAAs that can not be decoded are logged and skipped, since retrying would not fix them.
+func (p *Processor) processGovernanceVaa(ctx context.Context, vaa *sdk.VAA) error {
 
-	addr, err := sdk.StringToAddress(nativeHex)
+	g, err := governance.Decode(vaa)
 	if err != nil {
-		p.logger.Warn("Address cannot be parsed",
-			zap.String("vaaId", vaaID),
-			zap.String("nativeAddress", nativeAddress),
-			zap.Uint16("chain", uint16(chainID)))
-		return ""
-	}
-	// Get the token metadata
-	//
-	// This is complementary data about the token that is not present in the VAA itself.
-	tokenMeta, ok := domain.GetTokenByAddress(sdk.ChainID(chainID), addr.String())
-	if !ok {
-		p.logger.Warn("Token metadata not found",
-			zap.String("vaaId", vaaID),
-			zap.String("nativeAddress", nativeAddress),
-			zap.Uint16("chain", uint16(chainID)))
-		return ""
-	}
-
-	bigAmount := new(big.Int)
-	bigAmount, ok = bigAmount.SetString(amount, 10)
-	if !ok {
-		p.logger.Error("Cannot parse amount",
-			zap.String("vaaId", vaaID),
-			zap.String("amount", amount),
-			zap.String("nativeAddress", nativeAddress),
-			zap.Uint16("chain", uint16(chainID)))
-		return ""
+		p.logger.Warn("Governance VAA cannot be decoded", zap.String("id", vaa.MessageID()), zap.Error(err))
+		return nil
 	}
 
-	if tokenMeta.Decimals < 8 {
-		// factor = 10 ^ (8 - tokenMeta.Decimals)
-		var factor big.Int
-		factor.Exp(big.NewInt(10), big.NewInt(int64(8-tokenMeta.Decimals)), nil)
-
-		bigAmount = bigAmount.Mul(bigAmount, &factor)
+	now := time.Now()
+	g.UpdatedAt = &now
+	if err := p.governanceRepository.UpsertGovernanceVaa(ctx, g); err != nil {
+		p.logger.Error("Error inserting governance vaa in repository", zap.String("id", g.ID), zap.Error(err))
+		return err
 	}
 
-	return bigAmount.String()
+	p.logger.Info("governance VAA was successfully persisted",
+		zap.String("id", g.ID),
+		zap.String("module", g.Module),
+		zap.String("action", g.Action))
+	return nil
 }
 
 // createStandarizedProperties create a new StandardizedProperties with amount and fee amount transformed.
-func createStandarizedProperties(m parser.StandardizedProperties, amount, feeAmount, fromAddress, toAddress, tokenAddress, feeAddress string) parser.StandardizedProperties {
-	return parser.StandardizedProperties{
+func createStandarizedProperties(m vaaPayloadParser.StandardizedProperties, amount, feeAmount, fromAddress, toAddress, tokenAddress, feeAddress string) vaaPayloadParser.StandardizedProperties {
+	return vaaPayloadParser.StandardizedProperties{
 		AppIds:       m.AppIds,
 		FromChain:    m.FromChain,
 		FromAddress:  fromAddress,
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/parser/internal/sqs"
 	"go.uber.org/zap"
@@ -96,7 +97,7 @@ func (q *SQS) Consume(ctx context.Context) <-chan ConsumerMessage {
 					logger:    q.logger,
 					consumer:  q.consumer,
 					expiredAt: expiredAt,
-					ctx:       ctx,
+					ctx:       telemetry.Extract(ctx, sqsEvent.MessageAttributes.Carrier()),
 				}
 			}
 			q.wg.Wait()
@@ -125,6 +126,10 @@ func (m *sqsConsumerMessage) Data() *VaaEvent {
 	return m.data
 }
 
+func (m *sqsConsumerMessage) Context() context.Context {
+	return m.ctx
+}
+
 func (m *sqsConsumerMessage) Done() {
 	if err := m.consumer.DeleteMessage(m.ctx, m.id); err != nil {
 		m.logger.Error("Error deleting message from SQS", zap.Error(err))
@@ -0,0 +1,118 @@
+// Package reparse reparses the VAAs of an emitter that were parsed by an outdated parser version.
+//
+// The jobs are throttled to not starve the live parsing, and checkpointed so they resume after a restart.
+package reparse
+
+import (
+	"errors"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Errors returned when creating or cancelling a reparse job.
+var (
+	ErrMissingReason     = errors.New("reason is required")
+	ErrMissingEmitter    = errors.New("emitterChain and a valid emitterAddress are required")
+	ErrInvalidVersion    = errors.New("version must be greater than 0")
+	ErrInvalidParserType = errors.New("parserType must be native or remote")
+	ErrNoParser          = errors.New("no parser supports the emitter")
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobFinished       = errors.New("job is already finished")
+)
+
+// CreateJobRequest is the request to create a reparse job.
+type CreateJobRequest struct {
+	Reason         string  `json:"reason"`
+	EmitterChain   *uint16 `json:"emitterChain"`
+	EmitterAddress string  `json:"emitterAddress"`
+	// Version selects the VAAs parsed by a lower version, or without version.
+	Version int `json:"version"`
+	// ParserType selects too the VAAs parsed by another type of parser. If it is not set,
+	// it is the type of the parser that currently parses the emitter.
+	ParserType string `json:"parserType"`
+}
+
+// Validate validates and normalizes the request.
+func (r *CreateJobRequest) Validate() error {
+	if r.Reason == "" {
+		return ErrMissingReason
+	}
+	if r.EmitterChain == nil || r.EmitterAddress == "" {
+		return ErrMissingEmitter
+	}
+	addr, err := sdk.StringToAddress(r.EmitterAddress)
+	if err != nil {
+		return ErrMissingEmitter
+	}
+	r.EmitterAddress = addr.String()
+	if r.Version <= 0 {
+		return ErrInvalidVersion
+	}
+	if r.ParserType != "" && r.ParserType != parser.ParserTypeNative && r.ParserType != parser.ParserTypeRemote {
+		return ErrInvalidParserType
+	}
+	return nil
+}
+
+// JobStatus is the status of a reparse job.
+type JobStatus string
+
+const (
+	// JobStatusPending is the status of a job waiting to be run.
+	JobStatusPending JobStatus = "pending"
+	// JobStatusRunning is the status of a job being run, or interrupted and waiting to be resumed.
+	JobStatusRunning JobStatus = "running"
+	// JobStatusCompleted is the status of a job when all its VAAs were reparsed.
+	JobStatusCompleted JobStatus = "completed"
+	// JobStatusCancelled is the status of a job cancelled before it was completed.
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Job is a document of the parserReparseJobs collection.
+type Job struct {
+	ID             string    `bson:"_id" json:"id"`
+	Reason         string    `bson:"reason" json:"reason"`
+	EmitterChain   uint16    `bson:"emitterChain" json:"emitterChain"`
+	EmitterAddress string    `bson:"emitterAddress" json:"emitterAddress"`
+	Version        int       `bson:"version" json:"version"`
+	ParserType     string    `bson:"parserType,omitempty" json:"parserType,omitempty"`
+	Status         JobStatus `bson:"status" json:"status"`
+	// Checkpoint is the id of the last reparsed VAA, the job resumes after it.
+	Checkpoint string `bson:"checkpoint" json:"checkpoint"`
+	Reparsed   int64  `bson:"reparsed" json:"reparsed"`
+	// Failed is the number of VAAs that could not be reparsed, they are recorded as parser failures.
+	Failed int64 `bson:"failed" json:"failed"`
+	// LeaseUntil is the time until the job belongs to the parser instance that runs it.
+	LeaseUntil *time.Time `bson:"leaseUntil,omitempty" json:"-"`
+	// LeaseOwner is the parser instance that owns the lease, only its updates of the progress are saved.
+	LeaseOwner string    `bson:"leaseOwner,omitempty" json:"-"`
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// staleFilter returns the filter of the parsedVaa collection for the outdated VAAs of the job after its checkpoint.
+func (j *Job) staleFilter() bson.D {
+	// the versions of different parser types are not comparable, so the version of the job is compared
+	// only with the VAAs parsed by its type.
+	stale := bson.A{bson.D{{Key: "parser", Value: bson.D{{Key: "$exists", Value: false}}}}}
+	if j.ParserType != "" {
+		stale = append(stale,
+			bson.D{{Key: "parser.type", Value: bson.D{{Key: "$ne", Value: j.ParserType}}}},
+			bson.D{{Key: "parser.type", Value: j.ParserType}, {Key: "parser.version", Value: bson.D{{Key: "$lt", Value: j.Version}}}})
+	} else {
+		// the jobs created before the parser type was resolved compare only the version.
+		stale = append(stale, bson.D{{Key: "parser.version", Value: bson.D{{Key: "$lt", Value: j.Version}}}})
+	}
+
+	filter := bson.D{
+		{Key: "emitterChain", Value: j.EmitterChain},
+		{Key: "emitterAddr", Value: j.EmitterAddress},
+	}
+	if j.Checkpoint != "" {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: j.Checkpoint}}})
+	}
+	return append(filter, bson.E{Key: "$or", Value: stale})
+}
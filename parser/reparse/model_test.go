@@ -0,0 +1,61 @@
+package reparse
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const emitter = "0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"
+
+func TestCreateJobRequest_Validate(t *testing.T) {
+	chain := uint16(2)
+
+	tests := []struct {
+		name string
+		req  CreateJobRequest
+		err  error
+	}{
+		{name: "missing reason", req: CreateJobRequest{EmitterChain: &chain, EmitterAddress: emitter, Version: 1}, err: ErrMissingReason},
+		{name: "missing chain", req: CreateJobRequest{Reason: "r", EmitterAddress: emitter, Version: 1}, err: ErrMissingEmitter},
+		{name: "invalid address", req: CreateJobRequest{Reason: "r", EmitterChain: &chain, EmitterAddress: "xyz", Version: 1}, err: ErrMissingEmitter},
+		{name: "invalid version", req: CreateJobRequest{Reason: "r", EmitterChain: &chain, EmitterAddress: emitter}, err: ErrInvalidVersion},
+		{name: "invalid parser type", req: CreateJobRequest{Reason: "r", EmitterChain: &chain, EmitterAddress: emitter, Version: 1, ParserType: "other"}, err: ErrInvalidParserType},
+		{name: "valid", req: CreateJobRequest{Reason: "r", EmitterChain: &chain, EmitterAddress: emitter, Version: 1, ParserType: "native"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); err != tt.err {
+				t.Errorf("expected error %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	// the emitter address is normalized as it is stored in the parsed VAAs.
+	req := CreateJobRequest{Reason: "r", EmitterChain: &chain, EmitterAddress: "0x3EE18B2214AFF97000D974CF647E7C347E8FA585", Version: 1}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if req.EmitterAddress != emitter {
+		t.Errorf("unexpected emitter address %s", req.EmitterAddress)
+	}
+}
+
+func TestJob_staleFilter(t *testing.T) {
+	job := Job{EmitterChain: 2, EmitterAddress: emitter, Version: 2, ParserType: "native", Checkpoint: "2/" + emitter + "/10"}
+
+	expected := bson.D{
+		{Key: "emitterChain", Value: uint16(2)},
+		{Key: "emitterAddr", Value: emitter},
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: "2/" + emitter + "/10"}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "parser", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "parser.type", Value: bson.D{{Key: "$ne", Value: "native"}}}},
+			bson.D{{Key: "parser.type", Value: "native"}, {Key: "parser.version", Value: bson.D{{Key: "$lt", Value: 2}}}},
+		}},
+	}
+	if filter := job.staleFilter(); !reflect.DeepEqual(expected, filter) {
+		t.Errorf("unexpected filter %v", filter)
+	}
+}
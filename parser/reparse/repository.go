@@ -0,0 +1,169 @@
+package reparse
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository is the reparse jobs data access layer.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		vaas      *mongo.Collection
+		parsedVaa *mongo.Collection
+		jobs      *mongo.Collection
+	}
+}
+
+// NewRepository creates a new reparse jobs repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db, logger.With(zap.String("module", "ReparseRepository")), struct {
+		vaas      *mongo.Collection
+		parsedVaa *mongo.Collection
+		jobs      *mongo.Collection
+	}{
+		vaas:      db.Collection("vaas"),
+		parsedVaa: db.Collection("parsedVaa"),
+		jobs:      db.Collection("parserReparseJobs"),
+	}}
+}
+
+// InsertJob inserts a new reparse job.
+func (r *Repository) InsertJob(ctx context.Context, job *Job) error {
+	_, err := r.collections.jobs.InsertOne(ctx, job)
+	return errors.WithStack(err)
+}
+
+// FindJob returns a reparse job by id.
+func (r *Repository) FindJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	err := r.collections.jobs.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &job, nil
+}
+
+// ClaimJob takes the oldest pending or interrupted job whose lease expired, and leases it to owner until leaseUntil,
+// so the other parser instances do not run it at the same time. It returns nil if there is no job to run.
+func (r *Repository) ClaimJob(ctx context.Context, owner string, now, leaseUntil time.Time) (*Job, error) {
+	filter := bson.D{
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{JobStatusPending, JobStatusRunning}}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$lt", Value: now}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: JobStatusRunning},
+		{Key: "leaseUntil", Value: leaseUntil},
+		{Key: "leaseOwner", Value: owner},
+		{Key: "updatedAt", Value: now},
+	}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job Job
+	err := r.collections.jobs.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &job, nil
+}
+
+// UpdateProgress saves the checkpoint and counters of a running job and renews its lease.
+// It returns false if the job is not running anymore because it was cancelled, or if its lease expired
+// and was claimed by another parser instance.
+func (r *Repository) UpdateProgress(ctx context.Context, job *Job, leaseUntil time.Time) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: job.ID},
+		{Key: "status", Value: JobStatusRunning},
+		{Key: "leaseOwner", Value: job.LeaseOwner},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "checkpoint", Value: job.Checkpoint},
+		{Key: "reparsed", Value: job.Reparsed},
+		{Key: "failed", Value: job.Failed},
+		{Key: "leaseUntil", Value: leaseUntil},
+		{Key: "updatedAt", Value: time.Now()},
+	}}}
+	res, err := r.collections.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// UpdateStatus sets the status of a job that is in one of the from statuses, and releases its lease.
+// It returns false if the job is not in any of the from statuses.
+func (r *Repository) UpdateStatus(ctx context.Context, id string, status JobStatus, from ...JobStatus) (bool, error) {
+	filter := bson.D{{Key: "_id", Value: id}, {Key: "status", Value: bson.D{{Key: "$in", Value: from}}}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "status", Value: status}, {Key: "updatedAt", Value: time.Now()}}},
+		{Key: "$unset", Value: bson.D{{Key: "leaseUntil", Value: ""}}},
+	}
+	res, err := r.collections.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// FindStaleIDs returns the ids of the next outdated parsed VAAs of a job, in order of id.
+func (r *Repository) FindStaleIDs(ctx context.Context, job *Job, limit int64) ([]string, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(bson.D{{Key: "_id", Value: 1}})
+	cur, err := r.collections.parsedVaa.Find(ctx, job.staleFilter(), opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var docs []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ids := make([]string, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// FindVaas returns the bytes of the VAAs by id.
+func (r *Repository) FindVaas(ctx context.Context, ids []string) (map[string][]byte, error) {
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "vaas", Value: 1}})
+	cur, err := r.collections.vaas.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var docs []struct {
+		ID  string `bson:"_id"`
+		Vaa []byte `bson:"vaas"`
+	}
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	vaas := make(map[string][]byte, len(docs))
+	for _, d := range docs {
+		vaas[d.ID] = d.Vaa
+	}
+	return vaas, nil
+}
@@ -0,0 +1,178 @@
+package reparse
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/processor"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// Runner runs the reparse jobs one at a time, throttled to a max number of VAAs per second.
+type Runner struct {
+	repository *Repository
+	process    processor.ProcessorFunc
+	interval   time.Duration
+	batchSize  int64
+	rate       int
+	// parseTimeout is the max time to parse a VAA, the lease is renewed before it can expire while parsing.
+	parseTimeout time.Duration
+	// owner identifies this parser instance in the lease of the jobs.
+	owner  string
+	logger *zap.Logger
+}
+
+// NewRunner creates a new Runner that looks for jobs to run every interval,
+// and reparses batchSize VAAs at a time, up to rate VAAs per second, each in up to parseTimeout.
+func NewRunner(repository *Repository, process processor.ProcessorFunc, interval time.Duration, batchSize int64, rate int,
+	parseTimeout time.Duration, logger *zap.Logger) *Runner {
+	if rate <= 0 {
+		rate = 1
+	}
+	return &Runner{
+		repository:   repository,
+		process:      process,
+		interval:     interval,
+		batchSize:    batchSize,
+		rate:         rate,
+		parseTimeout: parseTimeout,
+		owner:        primitive.NewObjectID().Hex(),
+		logger:       logger.With(zap.String("module", "ReparseRunner")),
+	}
+}
+
+// Start runs the jobs until the context is cancelled.
+func (r *Runner) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.runJobs(ctx)
+			}
+		}
+	}()
+}
+
+// lease returns how long a job belongs to this runner without renewing it, the progress is saved
+// at least every parseTimeout so the lease is renewed before a slow parse can exceed it.
+func (r *Runner) lease() time.Duration {
+	return r.interval + 3*r.parseTimeout
+}
+
+// runJobs runs the jobs until there are no more jobs to run.
+func (r *Runner) runJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		job, err := r.repository.ClaimJob(ctx, r.owner, now, now.Add(r.lease()))
+		if err != nil {
+			r.logger.Error("Error finding reparse job to run", zap.Error(err))
+			return
+		}
+		if job == nil {
+			return
+		}
+		r.run(ctx, job)
+	}
+}
+
+// run reparses the outdated VAAs of a job in batches, saving the checkpoint after each batch
+// and renewing the lease while the VAAs are reparsed.
+func (r *Runner) run(ctx context.Context, job *Job) {
+	logger := r.logger.With(zap.String("jobId", job.ID), zap.String("reason", job.Reason))
+	logger.Info("Running reparse job",
+		zap.Uint16("emitterChain", job.EmitterChain),
+		zap.String("emitterAddress", job.EmitterAddress),
+		zap.Int("version", job.Version),
+		zap.String("checkpoint", job.Checkpoint))
+
+	throttle := time.NewTicker(time.Second / time.Duration(r.rate))
+	defer throttle.Stop()
+
+	for {
+		ids, err := r.repository.FindStaleIDs(ctx, job, r.batchSize)
+		if err != nil {
+			// the job is resumed from the checkpoint when its lease expires.
+			logger.Error("Error finding VAAs to reparse", zap.Error(err))
+			return
+		}
+		if len(ids) == 0 {
+			if _, err := r.repository.UpdateStatus(ctx, job.ID, JobStatusCompleted, JobStatusRunning); err != nil {
+				logger.Error("Error completing reparse job", zap.Error(err))
+				return
+			}
+			logger.Info("Reparse job completed", zap.Int64("reparsed", job.Reparsed), zap.Int64("failed", job.Failed))
+			return
+		}
+		vaas, err := r.repository.FindVaas(ctx, ids)
+		if err != nil {
+			logger.Error("Error finding VAAs to reparse", zap.Error(err))
+			return
+		}
+
+		savedAt := time.Now()
+		for _, id := range ids {
+			select {
+			case <-ctx.Done():
+				return
+			case <-throttle.C:
+			}
+			if r.reparse(ctx, id, vaas[id], logger) {
+				job.Reparsed++
+			} else {
+				job.Failed++
+			}
+			job.Checkpoint = id
+			if time.Since(savedAt) >= r.parseTimeout {
+				if !r.saveProgress(ctx, job, logger) {
+					return
+				}
+				savedAt = time.Now()
+			}
+		}
+
+		if !r.saveProgress(ctx, job, logger) {
+			return
+		}
+		logger.Debug("Reparse job progress", zap.String("checkpoint", job.Checkpoint),
+			zap.Int64("reparsed", job.Reparsed), zap.Int64("failed", job.Failed))
+	}
+}
+
+// saveProgress saves the checkpoint of a job and renews its lease. It returns false if the job must be stopped.
+func (r *Runner) saveProgress(ctx context.Context, job *Job, logger *zap.Logger) bool {
+	running, err := r.repository.UpdateProgress(ctx, job, time.Now().Add(r.lease()))
+	if err != nil {
+		logger.Error("Error saving reparse job checkpoint", zap.Error(err))
+		return false
+	}
+	if !running {
+		logger.Info("Reparse job cancelled or claimed by another instance",
+			zap.Int64("reparsed", job.Reparsed), zap.Int64("failed", job.Failed))
+		return false
+	}
+	return true
+}
+
+// reparse parses a VAA again. The VAAs that can not be parsed are recorded as parser failures by the processor.
+func (r *Runner) reparse(ctx context.Context, id string, vaaBytes []byte, logger *zap.Logger) bool {
+	if vaaBytes == nil {
+		logger.Warn("VAA to reparse not found", zap.String("id", id))
+		return false
+	}
+	parsed, err := r.process(ctx, vaaBytes)
+	if errors.Is(err, failure.ErrRecorded) {
+		return false
+	}
+	if err != nil {
+		logger.Error("Error reparsing VAA", zap.String("id", id), zap.Error(err))
+		return false
+	}
+	return parsed != nil
+}
@@ -0,0 +1,182 @@
+package reparse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/failure"
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+// staleResponses are the responses of FindStaleIDs and FindVaas with the VAAs of the ids.
+func staleResponses(ids ...string) []bson.D {
+	stale := make([]bson.D, 0, len(ids))
+	vaas := make([]bson.D, 0, len(ids))
+	for _, id := range ids {
+		stale = append(stale, bson.D{{Key: "_id", Value: id}})
+		vaas = append(vaas, bson.D{{Key: "_id", Value: id}, {Key: "vaas", Value: []byte(id)}})
+	}
+	return []bson.D{
+		mtest.CreateCursorResponse(0, "wormscan.parsedVaa", mtest.FirstBatch, stale...),
+		mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch, vaas...),
+	}
+}
+
+func updateResponse(matched int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: matched}, bson.E{Key: "nModified", Value: matched})
+}
+
+// newTestRunner creates a runner that parses the VAAs named "ok" and records a failure of the others.
+func newTestRunner(mt *mtest.T, parseTimeout time.Duration, parsed *[]string) *Runner {
+	process := func(_ context.Context, vaa []byte) (*parser.ParsedVaaUpdate, error) {
+		*parsed = append(*parsed, string(vaa))
+		if string(vaa) != "ok" {
+			return nil, fmt.Errorf("%w: parser error", failure.ErrRecorded)
+		}
+		return &parser.ParsedVaaUpdate{}, nil
+	}
+	return NewRunner(NewRepository(mt.DB, zap.NewNop()), process, time.Minute, 10, 1000, parseTimeout, zap.NewNop())
+}
+
+func TestRunner_Run(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("saves the progress and renews the lease", func(mt *mtest.T) {
+		responses := staleResponses("ok", "failed")
+		responses = append(responses, updateResponse(1))
+		responses = append(responses, mtest.CreateCursorResponse(0, "wormscan.parsedVaa", mtest.FirstBatch))
+		responses = append(responses, updateResponse(1))
+		mt.AddMockResponses(responses...)
+
+		var parsed []string
+		runner := newTestRunner(mt, time.Hour, &parsed)
+		job := &Job{ID: "job", Status: JobStatusRunning, LeaseOwner: runner.owner}
+
+		start := time.Now()
+		runner.run(context.Background(), job)
+		if len(parsed) != 2 || job.Reparsed != 1 || job.Failed != 1 || job.Checkpoint != "failed" {
+			t.Fatalf("unexpected progress %v %+v", parsed, job)
+		}
+
+		// find ids, find vaas, save progress, find ids and complete the job.
+		events := mt.GetAllStartedEvents()
+		if len(events) != 5 {
+			t.Fatalf("expected 5 commands, got %d", len(events))
+		}
+		update := events[2].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if owner := update.Lookup("q", "leaseOwner").StringValue(); owner != runner.owner {
+			t.Errorf("expected the progress of the owner %s, got %s", runner.owner, owner)
+		}
+		if checkpoint := update.Lookup("u", "$set", "checkpoint").StringValue(); checkpoint != "failed" {
+			t.Errorf("expected checkpoint failed, got %s", checkpoint)
+		}
+		if leaseUntil := update.Lookup("u", "$set", "leaseUntil").Time(); leaseUntil.Before(start.Add(runner.lease())) {
+			t.Errorf("expected the lease renewed until %v, got %v", start.Add(runner.lease()), leaseUntil)
+		}
+		complete := events[4].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if status := complete.Lookup("u", "$set", "status").StringValue(); status != string(JobStatusCompleted) {
+			t.Errorf("expected the job completed, got %s", status)
+		}
+	})
+
+	mt.Run("renews the lease while parsing", func(mt *mtest.T) {
+		responses := staleResponses("ok", "ok")
+		responses = append(responses, updateResponse(1), updateResponse(1), updateResponse(1))
+		responses = append(responses, mtest.CreateCursorResponse(0, "wormscan.parsedVaa", mtest.FirstBatch))
+		responses = append(responses, updateResponse(1))
+		mt.AddMockResponses(responses...)
+
+		var parsed []string
+		runner := newTestRunner(mt, time.Nanosecond, &parsed)
+		runner.run(context.Background(), &Job{ID: "job", Status: JobStatusRunning, LeaseOwner: runner.owner})
+
+		// the progress is saved after each VAA that takes longer than the parse timeout, and after the batch.
+		if events := mt.GetAllStartedEvents(); len(events) != 7 {
+			t.Errorf("expected 7 commands, got %d", len(events))
+		}
+	})
+
+	mt.Run("stops when the job is cancelled or its lease is lost", func(mt *mtest.T) {
+		responses := staleResponses("ok")
+		responses = append(responses, updateResponse(0))
+		mt.AddMockResponses(responses...)
+
+		var parsed []string
+		runner := newTestRunner(mt, time.Hour, &parsed)
+		runner.run(context.Background(), &Job{ID: "job", Status: JobStatusRunning, LeaseOwner: runner.owner})
+
+		// no more VAAs are reparsed after the progress is not saved.
+		if events := mt.GetAllStartedEvents(); len(events) != 3 {
+			t.Errorf("expected 3 commands, got %d", len(events))
+		}
+	})
+
+	mt.Run("stops when the context is cancelled", func(mt *mtest.T) {
+		mt.AddMockResponses(staleResponses("ok", "ok")...)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var parsed []string
+		runner := newTestRunner(mt, time.Hour, &parsed)
+		// the next VAA is not due before the context is cancelled.
+		runner.rate = 10
+		process := runner.process
+		runner.process = func(ctx context.Context, vaa []byte) (*parser.ParsedVaaUpdate, error) {
+			cancel()
+			return process(ctx, vaa)
+		}
+		runner.run(ctx, &Job{ID: "job", Status: JobStatusRunning, LeaseOwner: runner.owner})
+
+		// the job is resumed from its last checkpoint when its lease expires.
+		if len(parsed) != 1 {
+			t.Errorf("expected 1 VAA reparsed, got %d", len(parsed))
+		}
+		if events := mt.GetAllStartedEvents(); len(events) != 2 {
+			t.Errorf("expected 2 commands, got %d", len(events))
+		}
+	})
+}
+
+func TestRepository_ClaimJob(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("claim", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: "job"},
+				{Key: "status", Value: JobStatusRunning},
+				{Key: "leaseOwner", Value: "owner"},
+			}},
+		})
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		now := time.Now()
+		job, err := repository.ClaimJob(context.Background(), "owner", now, now.Add(time.Minute))
+		if err != nil || job == nil || job.LeaseOwner != "owner" {
+			t.Fatalf("expected the claimed job, got %+v %v", job, err)
+		}
+		update := mt.GetStartedEvent().Command.Lookup("update", "$set")
+		if owner := update.Document().Lookup("leaseOwner").StringValue(); owner != "owner" {
+			t.Errorf("expected the job leased to owner, got %s", owner)
+		}
+	})
+
+	mt.Run("no job to run", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		now := time.Now()
+		job, err := repository.ClaimJob(context.Background(), "owner", now, now.Add(time.Minute))
+		if err != nil || job != nil {
+			t.Errorf("expected no job, got %+v %v", job, err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package reparse
+
+import (
+	"context"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/parser/parser"
+	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// VersionFunc returns the version of the parser that parses a VAA, or false if no parser supports its emitter.
+type VersionFunc func(vaa *sdk.VAA) (parser.ParserVersion, bool)
+
+// Service creates and cancels the reparse jobs, which are run by the Runner.
+type Service struct {
+	repository *Repository
+	version    VersionFunc
+	logger     *zap.Logger
+}
+
+// NewService creates a new reparse jobs service. version resolves the parser type of the jobs created without it.
+func NewService(repository *Repository, version VersionFunc, logger *zap.Logger) *Service {
+	return &Service{
+		repository: repository,
+		version:    version,
+		logger:     logger.With(zap.String("module", "ReparseService")),
+	}
+}
+
+// CreateJob validates the request and creates a pending job.
+func (s *Service) CreateJob(ctx context.Context, req *CreateJobRequest) (*Job, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.ParserType == "" {
+		emitterAddress, _ := sdk.StringToAddress(req.EmitterAddress)
+		version, ok := s.version(&sdk.VAA{EmitterChain: sdk.ChainID(*req.EmitterChain), EmitterAddress: emitterAddress})
+		if !ok {
+			return nil, ErrNoParser
+		}
+		req.ParserType = version.Type
+	}
+
+	now := time.Now()
+	job := Job{
+		ID:             primitive.NewObjectID().Hex(),
+		Reason:         req.Reason,
+		EmitterChain:   *req.EmitterChain,
+		EmitterAddress: req.EmitterAddress,
+		Version:        req.Version,
+		ParserType:     req.ParserType,
+		Status:         JobStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.repository.InsertJob(ctx, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJob returns a reparse job with its progress.
+func (s *Service) GetJob(ctx context.Context, id string) (*Job, error) {
+	return s.repository.FindJob(ctx, id)
+}
+
+// CancelJob cancels a pending or running job, the runner stops it after the current batch.
+func (s *Service) CancelJob(ctx context.Context, id string) error {
+	ok, err := s.repository.UpdateStatus(ctx, id, JobStatusCancelled, JobStatusPending, JobStatusRunning)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	if _, err := s.repository.FindJob(ctx, id); err != nil {
+		return err
+	}
+	return ErrJobFinished
+}
@@ -6,20 +6,26 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/go-redis/redis/v8"
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/invalidation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/sns"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/config"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/healthcheck"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/http/infrastructure"
+	reprocessHttp "github.com/wormhole-foundation/wormhole-explorer/pipeline/http/reprocess"
 	pipelineAlert "github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/db"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
-	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/sns"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/reprocess"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/watcher"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -49,6 +55,13 @@ func main() {
 
 	logger := logger.New("wormhole-explorer-pipeline", logger.WithLevel(config.LogLevel))
 
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(rootCtx, "wormhole-explorer-pipeline")
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	logger.Info("Starting wormhole-explorer-pipeline ...")
 
 	//setup DB connection
@@ -86,15 +99,28 @@ func main() {
 	txHashHandler := pipeline.NewTxHashHandler(repository, pushFunc, alertClient, metrics, logger, quit)
 	go txHashHandler.Run(rootCtx)
 
+	// create the notifier of new vaas for the api caches.
+	notifier := newCacheInvalidationNotifier(rootCtx, config, logger)
+
 	// create a new publisher.
-	publisher := pipeline.NewPublisher(pushFunc, metrics, repository, config.P2pNetwork, txHashHandler, logger)
-	watcher := watcher.NewWatcher(rootCtx, db.Database, config.MongoDatabase, publisher.Publish, alertClient, metrics, logger)
+	publisher := pipeline.NewPublisher(pushFunc, metrics, repository, config.P2pNetwork, txHashHandler, notifier, logger)
+	watcher := watcher.NewWatcher(rootCtx, db.Database, config.MongoDatabase, publisher.Publish,
+		config.GetWatcherResumeID(), time.Duration(config.WatcherResumeInterval)*time.Second, alertClient, metrics, logger)
 	err = watcher.Start(rootCtx)
 	if err != nil {
 		logger.Fatal("failed to watch MongoDB", zap.Error(err))
 	}
 
-	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, healthChecks...)
+	// create the reprocessing controller, the admin endpoints are disabled without an api key.
+	var reprocessCtrl *reprocessHttp.Controller
+	if config.AdminApiKey != "" {
+		reprocessRepository := reprocess.NewRepository(db.Database, logger)
+		reprocessService := reprocess.NewService(rootCtx, reprocessRepository, repository, pushFunc, logger)
+		reprocessService.Start(rootCtx)
+		reprocessCtrl = reprocessHttp.NewController(reprocessService, config.AdminApiKey, logger)
+	}
+
+	server := infrastructure.NewServer(logger, config.Port, config.PprofEnabled, reprocessCtrl, healthChecks...)
 	server.Start()
 
 	logger.Info("Started wormhole-explorer-pipeline")
@@ -173,6 +199,17 @@ func newHealthChecks(ctx context.Context, config *config.Configuration, db *mong
 	return []healthcheck.Check{healthcheck.Mongo(db), healthcheck.SNS(awsConfig, config.SNSUrl)}, nil
 }
 
+func newCacheInvalidationNotifier(ctx context.Context, cfg *config.Configuration, logger *zap.Logger) invalidation.Notifier {
+	if cfg.CacheURL == "" {
+		return invalidation.NewDummyNotifier()
+	}
+	redisClient := redis.NewClient(&redis.Options{Addr: cfg.CacheURL})
+	interval := time.Duration(cfg.CacheInvalidationInterval) * time.Second
+	notifier := invalidation.NewRedisNotifier(redisClient, cfg.CacheChannel, cfg.P2pNetwork, interval, logger)
+	go notifier.Run(ctx)
+	return notifier
+}
+
 func newMetrics(cfg *config.Configuration) metrics.Metrics {
 	metricsEnabled := cfg.MetricsEnabled
 	if !metricsEnabled {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/sethvargo/go-envconfig"
@@ -24,6 +25,18 @@ type Configuration struct {
 	AlertEnabled       bool   `env:"ALERT_ENABLED,default=false"`
 	AlertApiKey        string `env:"ALERT_API_KEY"`
 	MetricsEnabled     bool   `env:"METRICS_ENABLED,default=false"`
+	// CacheURL is the redis used to notify the new VAAs to the API, the notifications are disabled when it is empty.
+	CacheURL     string `env:"CACHE_URL"`
+	CacheChannel string `env:"CACHE_CHANNEL,default=WORMSCAN:CACHE_INVALIDATION"`
+	// CacheInvalidationInterval is the minimum time in seconds between two notifications.
+	CacheInvalidationInterval int `env:"CACHE_INVALIDATION_INTERVAL,default=30"`
+	// WatcherResumeID identifies the resume token of the watcher, each replica watching the same database must use its own.
+	// When it is empty it is derived from the host name, which is the pod name in kubernetes.
+	WatcherResumeID string `env:"WATCHER_RESUME_ID"`
+	// WatcherResumeInterval is the time in seconds between two saves of the resume token of the watcher.
+	WatcherResumeInterval int `env:"WATCHER_RESUME_INTERVAL,default=5"`
+	// AdminApiKey enables the admin endpoints, the requests must be authenticated with this key.
+	AdminApiKey string `env:"ADMIN_API_KEY"`
 }
 
 // New creates a configuration with the values from .env file and environment variables.
@@ -37,3 +50,15 @@ func New(ctx context.Context) (*Configuration, error) {
 
 	return &configuration, nil
 }
+
+// GetWatcherResumeID returns the id of the resume token of the watcher, unique for each replica unless it is set.
+func (c *Configuration) GetWatcherResumeID() string {
+	if c.WatcherResumeID != "" {
+		return c.WatcherResumeID
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "pipeline"
+	}
+	return "pipeline-" + hostname
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/healthcheck"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/http/reprocess"
 	"go.uber.org/zap"
 )
 
@@ -14,7 +15,9 @@ type Server struct {
 	logger *zap.Logger
 }
 
-func NewServer(logger *zap.Logger, port string, pprofEnabled bool, checks ...healthcheck.Check) *Server {
+// NewServer creates the http server of the pipeline.
+// The admin endpoints are registered only if reprocessCtrl is not nil.
+func NewServer(logger *zap.Logger, port string, pprofEnabled bool, reprocessCtrl *reprocess.Controller, checks ...healthcheck.Check) *Server {
 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
 
 	// config use of middlware.
@@ -31,6 +34,12 @@ func NewServer(logger *zap.Logger, port string, pprofEnabled bool, checks ...hea
 	api.Get("/health", ctrl.HealthCheck)
 	api.Get("/ready", ctrl.ReadyCheck)
 
+	if reprocessCtrl != nil {
+		admin := api.Group("/admin", reprocessCtrl.Authenticate)
+		admin.Post("/reprocess", reprocessCtrl.CreateJob)
+		admin.Get("/reprocess/:id", reprocessCtrl.GetJob)
+	}
+
 	return &Server{
 		app:    app,
 		port:   port,
@@ -0,0 +1,88 @@
+package reprocess
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	commonReprocess "github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/reprocess"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// Controller definition.
+type Controller struct {
+	srv    *reprocess.Service
+	apiKey string
+	logger *zap.Logger
+}
+
+// NewController creates a Controller instance.
+// The endpoints require the header `Authorization: Bearer <apiKey>`.
+func NewController(srv *reprocess.Service, apiKey string, logger *zap.Logger) *Controller {
+	return &Controller{srv: srv, apiKey: apiKey, logger: logger.With(zap.String("module", "ReprocessController"))}
+}
+
+// Authenticate is a middleware that validates the admin api key.
+func (c *Controller) Authenticate(ctx *fiber.Ctx) error {
+	token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.apiKey)) != 1 {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+	return ctx.Next()
+}
+
+// CreateJob handler for the endpoint POST /admin/reprocess.
+func (c *Controller) CreateJob(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	var req reprocess.CreateJobRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	job, err := c.srv.CreateJob(ctx.Context(), &req)
+	switch {
+	case errors.Is(err, reprocess.ErrNoVaas):
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case err != nil && isValidationError(err):
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		c.logger.Error("Error creating reprocessing job", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+
+	c.logger.Info("Reprocessing job created",
+		zap.String("jobId", job.ID),
+		zap.String("reason", job.Reason),
+		zap.Int64("total", job.Total),
+		zap.String("requestID", requestID))
+	return ctx.Status(fiber.StatusAccepted).JSON(job)
+}
+
+// GetJob handler for the endpoint GET /admin/reprocess/:id.
+func (c *Controller) GetJob(ctx *fiber.Ctx) error {
+	requestID := fmt.Sprintf("%v", ctx.Context().Value("requestid"))
+
+	job, err := c.srv.GetJob(ctx.Context(), ctx.Params("id"))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ctx.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "job not found"})
+	}
+	if err != nil {
+		c.logger.Error("Error getting reprocessing job", zap.Error(err), zap.String("requestID", requestID))
+		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "internal error"})
+	}
+	return ctx.JSON(job)
+}
+
+func isValidationError(err error) bool {
+	switch err {
+	case reprocess.ErrMissingReason, reprocess.ErrInvalidSelector, reprocess.ErrInvalidVaaID,
+		reprocess.ErrMissingChain, reprocess.ErrInvalidRange, reprocess.ErrInvalidSequence, reprocess.ErrTooManyVaas:
+		return true
+	}
+	return errors.Is(err, commonReprocess.ErrInvalidStage)
+}
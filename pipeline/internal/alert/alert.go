@@ -11,6 +11,8 @@ const (
 	ErrorDecodeWatcherEvent = "ERROR_DECODE_WATCHER_EVENT"
 	ErrorUpdateVaaTxHash    = "ERROR_UPDATE_VAA_TX_HASH"
 	ErrorPushEventSNS       = "ERROR_PUSH_EVENT_SNS"
+
+	ErrorWatcherResumeTokenLost = "ERROR_WATCHER_RESUME_TOKEN_LOST"
 )
 
 func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
@@ -49,5 +51,16 @@ func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
 		Priority:    alert.CRITICAL,
 	}
 
+	// Alert resume token of the watcher lost.
+	alerts[ErrorWatcherResumeTokenLost] = alert.Alert{
+		Alias:       "Watcher resume token lost",
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Watcher resume token lost"),
+		Description: "The resume token of the watcher is no longer in the oplog, the vaas inserted meanwhile are published with a catch-up query",
+		Actions:     []string{""},
+		Tags:        []string{cfg.Environment, "pipeline", "watcher", "mongo"},
+		Entity:      "pipeline",
+		Priority:    alert.HIGH,
+	}
+
 	return alerts
 }
@@ -3,6 +3,8 @@ package pipeline
 import (
 	"context"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/cache/invalidation"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/watcher"
@@ -18,10 +20,11 @@ type Publisher struct {
 	p2pNetwork    string
 	txHashHandler *TxHashHandler
 	metrics       metrics.Metrics
+	notifier      invalidation.Notifier
 }
 
 // NewPublisher creates a new publisher for vaa with parse configuration.
-func NewPublisher(pushFunc topic.PushFunc, metrics metrics.Metrics, repository *Repository, p2pNetwork string, txHashHandler *TxHashHandler, logger *zap.Logger) *Publisher {
+func NewPublisher(pushFunc topic.PushFunc, metrics metrics.Metrics, repository *Repository, p2pNetwork string, txHashHandler *TxHashHandler, notifier invalidation.Notifier, logger *zap.Logger) *Publisher {
 	return &Publisher{
 		logger:        logger,
 		repository:    repository,
@@ -29,12 +32,16 @@ func NewPublisher(pushFunc topic.PushFunc, metrics metrics.Metrics, repository *
 		p2pNetwork:    p2pNetwork,
 		txHashHandler: txHashHandler,
 		metrics:       metrics,
+		notifier:      notifier,
 	}
 }
 
 // Publish sends a Event for the vaa that has parse configuration defined.
 func (p *Publisher) Publish(ctx context.Context, e *watcher.Event) {
 
+	// notify the new vaa, so the cached metrics of the api are refreshed.
+	p.notifier.Notify(invalidation.EventVaaStored)
+
 	// create a Event.
 	event := topic.Event{
 		ID:               e.ID,
@@ -64,6 +71,7 @@ func (p *Publisher) Publish(ctx context.Context, e *watcher.Event) {
 			// add the event to the txhash handler.
 			// the handler will try to get the txhash for the vaa
 			// and publish the event with the txhash.
+			event.TraceContext = telemetry.Inject(ctx)
 			p.txHashHandler.AddVaaFixItem(event)
 			return
 		}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	pipelineAlert "github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
@@ -74,7 +75,7 @@ func (t *TxHashHandler) Run(ctx context.Context) {
 					} else {
 						t.logger.Info("Vaa txhash fixed", zap.String("vaaID", vaa), zap.String("txHash", txHash))
 						item.Event.TxHash = txHash
-						t.pushFunc(ctx, &item.Event)
+						t.pushFunc(telemetry.Extract(ctx, item.Event.TraceContext), &item.Event)
 						delete(t.fixItems, vaa)
 						// increment metrics vaa with txhash fixed
 						t.metrics.IncVaaWithTxHashFixed(item.Event.ChainID)
@@ -82,7 +83,7 @@ func (t *TxHashHandler) Run(ctx context.Context) {
 				} else {
 					t.logger.Error("Vaa txhash fix failed", zap.String("vaaID", vaa))
 					// publish the event to the topic anyway
-					t.pushFunc(ctx, &item.Event)
+					t.pushFunc(telemetry.Extract(ctx, item.Event.TraceContext), &item.Event)
 					delete(t.fixItems, vaa)
 				}
 			}
@@ -0,0 +1,179 @@
+package reprocess
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	commonReprocess "github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Errors returned when creating a reprocessing job.
+var (
+	ErrMissingReason   = errors.New("reason is required")
+	ErrInvalidSelector = errors.New("a vaaId, an emitter or a time window (from and to) is required")
+	ErrInvalidVaaID    = errors.New("vaaId can not be combined with other selectors")
+	ErrMissingChain    = errors.New("emitterChain is required to select an emitter")
+	ErrInvalidRange    = errors.New("from must be before to")
+	ErrInvalidSequence = errors.New("a sequence range requires an emitter and fromSequence must be less or equal than toSequence")
+	ErrNoVaas          = errors.New("no vaas match the selector")
+	ErrTooManyVaas     = errors.New("too many vaas match the selector")
+	// ErrLeaseLost is returned when the lease of a job expired and it was claimed by another pipeline instance.
+	ErrLeaseLost = errors.New("the lease of the job was claimed by another pipeline instance")
+)
+
+// Selector selects the vaas to reprocess: a single vaa, a range of sequences of an emitter or a time window.
+type Selector struct {
+	VaaID          string     `bson:"vaaId,omitempty" json:"vaaId,omitempty"`
+	EmitterChain   *uint16    `bson:"emitterChain,omitempty" json:"emitterChain,omitempty"`
+	EmitterAddress string     `bson:"emitterAddress,omitempty" json:"emitterAddress,omitempty"`
+	FromSequence   *uint64    `bson:"fromSequence,omitempty" json:"fromSequence,omitempty"`
+	ToSequence     *uint64    `bson:"toSequence,omitempty" json:"toSequence,omitempty"`
+	From           *time.Time `bson:"from,omitempty" json:"from,omitempty"`
+	To             *time.Time `bson:"to,omitempty" json:"to,omitempty"`
+}
+
+// Validate validates and normalizes the selector.
+func (s *Selector) Validate() error {
+	s.EmitterAddress = strings.ToLower(strings.TrimPrefix(s.EmitterAddress, "0x"))
+
+	if s.VaaID != "" {
+		if s.EmitterChain != nil || s.EmitterAddress != "" || s.FromSequence != nil || s.ToSequence != nil ||
+			s.From != nil || s.To != nil {
+			return ErrInvalidVaaID
+		}
+		return nil
+	}
+
+	hasEmitter := s.EmitterAddress != ""
+	if hasEmitter && s.EmitterChain == nil {
+		return ErrMissingChain
+	}
+	if !hasEmitter && (s.From == nil || s.To == nil) {
+		return ErrInvalidSelector
+	}
+	if s.From != nil && s.To != nil && !s.From.Before(*s.To) {
+		return ErrInvalidRange
+	}
+	if s.FromSequence != nil || s.ToSequence != nil {
+		if !hasEmitter || (s.FromSequence != nil && s.ToSequence != nil && *s.FromSequence > *s.ToSequence) {
+			return ErrInvalidSequence
+		}
+	}
+	return nil
+}
+
+// filter returns the filter of the vaas collection for the selector.
+func (s *Selector) filter() bson.D {
+	if s.VaaID != "" {
+		return bson.D{{Key: "_id", Value: s.VaaID}}
+	}
+
+	var filter bson.D
+	if s.EmitterChain != nil {
+		filter = append(filter, bson.E{Key: "emitterChain", Value: *s.EmitterChain})
+	}
+	if s.EmitterAddress != "" {
+		filter = append(filter, bson.E{Key: "emitterAddr", Value: s.EmitterAddress})
+	}
+
+	var timestamp bson.D
+	if s.From != nil {
+		timestamp = append(timestamp, bson.E{Key: "$gte", Value: *s.From})
+	}
+	if s.To != nil {
+		timestamp = append(timestamp, bson.E{Key: "$lt", Value: *s.To})
+	}
+	if len(timestamp) > 0 {
+		filter = append(filter, bson.E{Key: "timestamp", Value: timestamp})
+	}
+
+	// the sequence is stored as a string, so it is converted to compare the numeric values.
+	var sequence bson.A
+	if s.FromSequence != nil {
+		sequence = append(sequence, bson.D{{Key: "$gte", Value: bson.A{bson.D{{Key: "$toLong", Value: "$sequence"}}, int64(*s.FromSequence)}}})
+	}
+	if s.ToSequence != nil {
+		sequence = append(sequence, bson.D{{Key: "$lte", Value: bson.A{bson.D{{Key: "$toLong", Value: "$sequence"}}, int64(*s.ToSequence)}}})
+	}
+	if len(sequence) > 0 {
+		filter = append(filter, bson.E{Key: "$expr", Value: bson.D{{Key: "$and", Value: sequence}}})
+	}
+	return filter
+}
+
+// Checkpoint is the last published vaa of a job, the job is resumed after it if the pipeline restarts.
+type Checkpoint struct {
+	Timestamp time.Time `bson:"timestamp" json:"timestamp"`
+	ID        string    `bson:"id" json:"id"`
+}
+
+// filterAfter returns the filter of the vaas collection for the vaas of the selector after the checkpoint.
+func (s *Selector) filterAfter(c *Checkpoint) bson.D {
+	filter := s.filter()
+	if c == nil {
+		return filter
+	}
+	// the vaas are sorted by timestamp and id, so the vaas with the same timestamp are not skipped.
+	return append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gt", Value: c.Timestamp}}}},
+		bson.D{{Key: "timestamp", Value: c.Timestamp}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: c.ID}}}},
+	}})
+}
+
+// JobStatus is the status of a reprocessing job.
+type JobStatus string
+
+const (
+	// JobStatusEnqueuing is the status of a job while its events are being published.
+	JobStatusEnqueuing JobStatus = "enqueuing"
+	// JobStatusEnqueued is the status of a job when all its events were published.
+	JobStatusEnqueued JobStatus = "enqueued"
+	// JobStatusCompleted is the status of a job when all the selected stages processed its events.
+	JobStatusCompleted JobStatus = "completed"
+	// JobStatusFailed is the status of a job that could not publish its events.
+	JobStatusFailed JobStatus = "failed"
+)
+
+// StageProgress is the progress of a job in a stage.
+type StageProgress struct {
+	Processed int64  `bson:"processed" json:"processed"`
+	Failed    int64  `bson:"failed" json:"failed"`
+	LastError string `bson:"lastError,omitempty" json:"lastError,omitempty"`
+}
+
+// Job is a document of the reprocessJobs collection.
+type Job struct {
+	ID       string                  `bson:"_id" json:"id"`
+	Reason   string                  `bson:"reason" json:"reason"`
+	Stages   []commonReprocess.Stage `bson:"selectedStages" json:"stages"`
+	Selector Selector                `bson:"selector" json:"selector"`
+	Status   JobStatus               `bson:"status" json:"status"`
+	// Total is the number of vaas that matched the selector when the job was created.
+	Total      int64                                   `bson:"total" json:"total"`
+	Enqueued   int64                                   `bson:"enqueued" json:"enqueued"`
+	Checkpoint *Checkpoint                             `bson:"checkpoint,omitempty" json:"checkpoint,omitempty"`
+	Error      string                                  `bson:"error,omitempty" json:"error,omitempty"`
+	Progress   map[commonReprocess.Stage]StageProgress `bson:"stages,omitempty" json:"progress"`
+	// LeaseUntil is the time until the events of an enqueuing job are published by the pipeline instance that owns it.
+	LeaseUntil *time.Time `bson:"leaseUntil,omitempty" json:"-"`
+	// LeaseOwner is the pipeline instance that owns the lease, only its updates of the progress are saved.
+	LeaseOwner string    `bson:"leaseOwner,omitempty" json:"-"`
+	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// isCompleted returns true if all the events were published and processed by the selected stages.
+func (j *Job) isCompleted() bool {
+	if j.Status != JobStatusEnqueued {
+		return false
+	}
+	for _, stage := range j.Stages {
+		p := j.Progress[stage]
+		if p.Processed+p.Failed < j.Enqueued {
+			return false
+		}
+	}
+	return true
+}
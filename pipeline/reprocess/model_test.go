@@ -0,0 +1,84 @@
+package reprocess
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	commonReprocess "github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSelector_Validate(t *testing.T) {
+	chain := uint16(2)
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	seq := uint64(10)
+	lowerSeq := uint64(5)
+
+	assert.NoError(t, (&Selector{VaaID: "2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585/1"}).Validate())
+	assert.Equal(t, ErrInvalidVaaID, (&Selector{VaaID: "2/abc/1", EmitterChain: &chain}).Validate())
+	assert.Equal(t, ErrInvalidSelector, (&Selector{}).Validate())
+	assert.Equal(t, ErrInvalidSelector, (&Selector{EmitterChain: &chain, From: &from}).Validate())
+	assert.Equal(t, ErrMissingChain, (&Selector{EmitterAddress: "abc"}).Validate())
+	assert.Equal(t, ErrInvalidRange, (&Selector{From: &to, To: &from}).Validate())
+	assert.Equal(t, ErrInvalidSequence, (&Selector{From: &from, To: &to, FromSequence: &seq}).Validate())
+	assert.Equal(t, ErrInvalidSequence, (&Selector{EmitterChain: &chain, EmitterAddress: "abc", FromSequence: &seq, ToSequence: &lowerSeq}).Validate())
+
+	s := Selector{EmitterChain: &chain, EmitterAddress: "0xABC", FromSequence: &lowerSeq}
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, "abc", s.EmitterAddress)
+}
+
+func TestSelector_filter(t *testing.T) {
+	chain := uint16(2)
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	fromSeq := uint64(5)
+
+	s := Selector{EmitterChain: &chain, EmitterAddress: "abc", FromSequence: &fromSeq, From: &from, To: &to}
+	assert.Equal(t, bson.D{
+		{Key: "emitterChain", Value: chain},
+		{Key: "emitterAddr", Value: "abc"},
+		{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+		{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$gte", Value: bson.A{bson.D{{Key: "$toLong", Value: "$sequence"}}, int64(5)}}},
+		}}}},
+	}, s.filter())
+
+	// a vaa id ignores the other fields.
+	s = Selector{VaaID: "2/abc/1"}
+	assert.Equal(t, bson.D{{Key: "_id", Value: "2/abc/1"}}, s.filter())
+}
+
+func TestSelector_filterAfter(t *testing.T) {
+	chain := uint16(2)
+	s := Selector{EmitterChain: &chain}
+	assert.Equal(t, s.filter(), s.filterAfter(nil))
+
+	ts := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	c := Checkpoint{Timestamp: ts, ID: "2/abc/1"}
+	assert.Equal(t, bson.D{
+		{Key: "emitterChain", Value: chain},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gt", Value: ts}}}},
+			bson.D{{Key: "timestamp", Value: ts}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: "2/abc/1"}}}},
+		}},
+	}, s.filterAfter(&c))
+}
+
+func TestJob_isCompleted(t *testing.T) {
+	job := Job{
+		Stages:   []commonReprocess.Stage{"parser", "analytics"},
+		Status:   JobStatusEnqueued,
+		Enqueued: 3,
+		Progress: map[commonReprocess.Stage]StageProgress{"parser": {Processed: 3}, "analytics": {Processed: 2}},
+	}
+	assert.False(t, job.isCompleted())
+
+	job.Progress["analytics"] = StageProgress{Processed: 2, Failed: 1}
+	assert.True(t, job.isCompleted())
+
+	job.Status = JobStatusEnqueuing
+	assert.False(t, job.isCompleted())
+}
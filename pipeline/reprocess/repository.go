@@ -0,0 +1,126 @@
+package reprocess
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	commonReprocess "github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/watcher"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository is the reprocessing jobs data access layer.
+type Repository struct {
+	db          *mongo.Database
+	logger      *zap.Logger
+	collections struct {
+		vaas *mongo.Collection
+		jobs *mongo.Collection
+	}
+}
+
+// NewRepository creates a new reprocessing jobs repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{db, logger.With(zap.String("module", "ReprocessRepository")), struct {
+		vaas *mongo.Collection
+		jobs *mongo.Collection
+	}{
+		vaas: db.Collection("vaas"),
+		jobs: db.Collection(commonReprocess.JobsCollection),
+	}}
+}
+
+// CountVaas returns the number of vaas that match the selector, up to limit.
+func (r *Repository) CountVaas(ctx context.Context, s *Selector, limit int64) (int64, error) {
+	count, err := r.collections.vaas.CountDocuments(ctx, s.filter(), options.Count().SetLimit(limit))
+	return count, errors.WithStack(err)
+}
+
+// FindVaas returns a cursor over the vaas that match the selector after the checkpoint.
+func (r *Repository) FindVaas(ctx context.Context, s *Selector, c *Checkpoint) (*mongo.Cursor, error) {
+	// the vaas are sorted by timestamp so the events of an emitter are published in order.
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := r.collections.vaas.Find(ctx, s.filterAfter(c), opts)
+	return cur, errors.WithStack(err)
+}
+
+// DecodeVaa decodes the current vaa document of the cursor.
+func (r *Repository) DecodeVaa(cur *mongo.Cursor) (*watcher.Event, error) {
+	var e watcher.Event
+	err := cur.Decode(&e)
+	return &e, errors.WithStack(err)
+}
+
+// InsertJob inserts a new reprocessing job.
+func (r *Repository) InsertJob(ctx context.Context, job *Job) error {
+	_, err := r.collections.jobs.InsertOne(ctx, job)
+	return errors.WithStack(err)
+}
+
+// FindJob returns a reprocessing job by id.
+func (r *Repository) FindJob(ctx context.Context, id string) (*Job, error) {
+	var job Job
+	err := r.collections.jobs.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ClaimJob returns an enqueuing job whose lease expired, because the pipeline instance that
+// was publishing its events stopped, and leases it to owner. It returns nil if there is no job to claim.
+func (r *Repository) ClaimJob(ctx context.Context, owner string, now, leaseUntil time.Time) (*Job, error) {
+	filter := bson.D{
+		{Key: "status", Value: JobStatusEnqueuing},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "leaseUntil", Value: bson.D{{Key: "$lt", Value: now}}}},
+		}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "leaseOwner", Value: owner},
+		{Key: "leaseUntil", Value: leaseUntil},
+	}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+	var job Job
+	err := r.collections.jobs.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &job, nil
+}
+
+// UpdateJobEnqueued sets the number of published events and the checkpoint of a job, renewing its lease,
+// and optionally sets its status. It returns ErrLeaseLost if the job was claimed by another pipeline instance.
+func (r *Repository) UpdateJobEnqueued(ctx context.Context, job *Job, leaseUntil time.Time, status JobStatus, jobErr error) error {
+	set := bson.D{
+		{Key: "enqueued", Value: job.Enqueued},
+		{Key: "checkpoint", Value: job.Checkpoint},
+		{Key: "leaseUntil", Value: leaseUntil},
+		{Key: "updatedAt", Value: time.Now()},
+	}
+	if status != "" {
+		set = append(set, bson.E{Key: "status", Value: status})
+	}
+	if jobErr != nil {
+		set = append(set, bson.E{Key: "error", Value: jobErr.Error()})
+	}
+	filter := bson.D{{Key: "_id", Value: job.ID}, {Key: "leaseOwner", Value: job.LeaseOwner}}
+	res, err := r.collections.jobs.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: set}})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package reprocess
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestRepository_UpdateJobEnqueued(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	job := &Job{ID: "job", LeaseOwner: "owner", Enqueued: 500}
+
+	mt.Run("owned lease", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		err := repository.UpdateJobEnqueued(context.Background(), job, time.Now().Add(leaseDuration), "", nil)
+		assert.NoError(t, err)
+
+		// the progress is only saved by the owner of the lease.
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, "owner", update.Lookup("q", "leaseOwner").StringValue())
+		assert.Equal(t, "job", update.Lookup("q", "_id").StringValue())
+	})
+
+	mt.Run("lease claimed by another instance", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}))
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		err := repository.UpdateJobEnqueued(context.Background(), job, time.Now().Add(leaseDuration), "", nil)
+		assert.True(t, errors.Is(err, ErrLeaseLost))
+	})
+}
+
+func TestRepository_ClaimJob(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("claim", func(mt *mtest.T) {
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: "job"},
+				{Key: "status", Value: JobStatusEnqueuing},
+				{Key: "leaseOwner", Value: "owner"},
+			}},
+		})
+		repository := NewRepository(mt.DB, zap.NewNop())
+
+		now := time.Now()
+		job, err := repository.ClaimJob(context.Background(), "owner", now, now.Add(leaseDuration))
+		assert.NoError(t, err)
+		if assert.NotNil(t, job) {
+			assert.Equal(t, "owner", job.LeaseOwner)
+		}
+		update := mt.GetStartedEvent().Command.Lookup("update")
+		assert.Equal(t, "owner", update.Document().Lookup("$set", "leaseOwner").StringValue())
+	})
+}
@@ -0,0 +1,264 @@
+package reprocess
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commonReprocess "github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/pipeline"
+	"github.com/wormhole-foundation/wormhole-explorer/pipeline/topic"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxJobVaas is the max number of vaas of a reprocessing job.
+	maxJobVaas = 100_000
+	// progressInterval is the number of published events between updates of the job progress.
+	progressInterval = 500
+	// leaseDuration is how long an enqueuing job belongs to the pipeline instance that publishes its events,
+	// it is renewed with the progress of the job.
+	leaseDuration = 5 * time.Minute
+	// resumeInterval is the interval to look for enqueuing jobs whose pipeline instance stopped.
+	resumeInterval = time.Minute
+)
+
+// CreateJobRequest is the request to create a reprocessing job.
+type CreateJobRequest struct {
+	Reason string `json:"reason"`
+	// Stages to rerun, all the stages when empty.
+	Stages []string `json:"stages"`
+	Selector
+}
+
+// Service publishes the events of the reprocessing jobs.
+type Service struct {
+	// ctx is the application context, jobs keep publishing events after the request that created them.
+	ctx           context.Context
+	repository    *Repository
+	vaaRepository pipeline.IRepository
+	pushFunc      topic.PushFunc
+	// owner identifies this pipeline instance in the lease of the jobs.
+	owner  string
+	logger *zap.Logger
+}
+
+// NewService creates a new reprocessing jobs service.
+func NewService(ctx context.Context, repository *Repository, vaaRepository pipeline.IRepository, pushFunc topic.PushFunc, logger *zap.Logger) *Service {
+	return &Service{
+		ctx:           ctx,
+		repository:    repository,
+		vaaRepository: vaaRepository,
+		pushFunc:      pushFunc,
+		owner:         primitive.NewObjectID().Hex(),
+		logger:        logger.With(zap.String("module", "ReprocessService")),
+	}
+}
+
+// CreateJob validates the request, creates a job and starts publishing its events.
+func (s *Service) CreateJob(ctx context.Context, req *CreateJobRequest) (*Job, error) {
+	if req.Reason == "" {
+		return nil, ErrMissingReason
+	}
+	stages := commonReprocess.AllStages
+	if len(req.Stages) > 0 {
+		stages = make([]commonReprocess.Stage, 0, len(req.Stages))
+		for _, value := range req.Stages {
+			stage, err := commonReprocess.ParseStage(value)
+			if err != nil {
+				return nil, err
+			}
+			stages = append(stages, stage)
+		}
+	}
+	if err := req.Selector.Validate(); err != nil {
+		return nil, err
+	}
+
+	total, err := s.repository.CountVaas(ctx, &req.Selector, maxJobVaas+1)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, ErrNoVaas
+	}
+	if total > maxJobVaas {
+		return nil, ErrTooManyVaas
+	}
+
+	now := time.Now()
+	leaseUntil := now.Add(leaseDuration)
+	job := Job{
+		ID:         primitive.NewObjectID().Hex(),
+		Reason:     req.Reason,
+		Stages:     stages,
+		Selector:   req.Selector,
+		Status:     JobStatusEnqueuing,
+		Total:      total,
+		LeaseUntil: &leaseUntil,
+		LeaseOwner: s.owner,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repository.InsertJob(ctx, &job); err != nil {
+		return nil, err
+	}
+
+	go s.enqueue(&job)
+	return &job, nil
+}
+
+// GetJob returns a reprocessing job with its progress.
+func (s *Service) GetJob(ctx context.Context, id string) (*Job, error) {
+	job, err := s.repository.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.isCompleted() {
+		job.Status = JobStatusCompleted
+	}
+	return job, nil
+}
+
+// Start resumes the enqueuing jobs of the pipeline instances that stopped, until the context is cancelled.
+func (s *Service) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(resumeInterval)
+		defer ticker.Stop()
+		for {
+			s.resumeJobs(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// resumeJobs claims the enqueuing jobs whose lease expired and resumes them from their checkpoint.
+func (s *Service) resumeJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		job, err := s.repository.ClaimJob(ctx, s.owner, now, now.Add(leaseDuration))
+		if err != nil {
+			s.logger.Error("Error finding reprocessing job to resume", zap.Error(err))
+			return
+		}
+		if job == nil {
+			return
+		}
+		go s.enqueue(job)
+	}
+}
+
+// enqueue publishes an event for each vaa of the job, starting after its checkpoint.
+func (s *Service) enqueue(job *Job) {
+	logger := s.logger.With(zap.String("jobId", job.ID), zap.String("reason", job.Reason))
+	logger.Info("Starting reprocessing job", zap.Int64("total", job.Total), zap.Int64("enqueued", job.Enqueued))
+
+	request := &commonReprocess.Request{JobID: job.ID, Reason: job.Reason, Stages: job.Stages}
+	err := s.publishAll(job, request)
+
+	// the job belongs to another instance, which resumes it from the last checkpoint saved by this one.
+	if errors.Is(err, ErrLeaseLost) {
+		logger.Warn("Reprocessing job claimed by another pipeline instance", zap.Int64("enqueued", job.Enqueued))
+		return
+	}
+
+	// the job is resumed by another instance if the pipeline is stopping, so the lease is released.
+	if s.ctx.Err() != nil {
+		logger.Info("Reprocessing job interrupted", zap.Int64("enqueued", job.Enqueued))
+		if updateErr := s.repository.UpdateJobEnqueued(context.Background(), job, time.Now(), "", nil); updateErr != nil {
+			logger.Error("Error updating reprocessing job", zap.Error(updateErr))
+		}
+		return
+	}
+
+	status := JobStatusEnqueued
+	if err != nil {
+		status = JobStatusFailed
+		logger.Error("Error publishing reprocessing job", zap.Int64("enqueued", job.Enqueued), zap.Error(err))
+	} else {
+		logger.Info("Reprocessing job enqueued", zap.Int64("enqueued", job.Enqueued))
+	}
+	if updateErr := s.repository.UpdateJobEnqueued(s.ctx, job, time.Now(), status, err); updateErr != nil {
+		logger.Error("Error updating reprocessing job", zap.Error(updateErr))
+	}
+}
+
+// publishAll publishes the events of the job after its checkpoint, and updates its checkpoint
+// and the number of published events.
+func (s *Service) publishAll(job *Job, request *commonReprocess.Request) error {
+	cur, err := s.repository.FindVaas(s.ctx, &job.Selector, job.Checkpoint)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(s.ctx)
+
+	for cur.Next(s.ctx) {
+		e, err := s.repository.DecodeVaa(cur)
+		if err != nil {
+			return err
+		}
+
+		event := topic.Event{
+			ID:               e.ID,
+			ChainID:          e.ChainID,
+			EmitterAddress:   e.EmitterAddress,
+			Sequence:         e.Sequence,
+			GuardianSetIndex: e.GuardianSetIndex,
+			Vaa:              e.Vaa,
+			IndexedAt:        e.IndexedAt,
+			Timestamp:        e.Timestamp,
+			UpdatedAt:        e.UpdatedAt,
+			TxHash:           e.TxHash,
+			Version:          e.Version,
+			Revision:         e.Revision,
+			Reprocess:        request,
+		}
+		if event.TxHash == "" && vaa.ChainID(event.ChainID) != vaa.ChainIDPythNet {
+			event.TxHash = s.fixTxHash(event.ID)
+		}
+
+		// each reprocessed vaa starts a new trace.
+		eventCtx, span := telemetry.StartVaaSpan(s.ctx, "pipeline.ReprocessVaa", event.ID)
+		err = s.pushFunc(eventCtx, &event)
+		telemetry.EndSpan(span, err)
+		if err != nil {
+			return err
+		}
+		job.Enqueued++
+		if e.Timestamp != nil {
+			job.Checkpoint = &Checkpoint{Timestamp: *e.Timestamp, ID: e.ID}
+		}
+
+		if job.Enqueued%progressInterval == 0 {
+			err := s.repository.UpdateJobEnqueued(s.ctx, job, time.Now().Add(leaseDuration), "", nil)
+			if errors.Is(err, ErrLeaseLost) {
+				return err
+			}
+			if err != nil {
+				s.logger.Error("Error updating reprocessing job", zap.String("jobId", job.ID), zap.Error(err))
+			}
+		}
+	}
+	return cur.Err()
+}
+
+// fixTxHash tries to complete a vaa without txhash with the txhash of its observations.
+// The event is published without txhash if it is not found.
+func (s *Service) fixTxHash(id string) string {
+	vaaIdTxHash, err := s.vaaRepository.GetVaaIdTxHash(s.ctx, id)
+	if err != nil || vaaIdTxHash.TxHash == "" {
+		s.logger.Warn("Txhash not found for vaa", zap.String("id", id), zap.Error(err))
+		return ""
+	}
+	if err := s.vaaRepository.UpdateVaaDocTxHash(s.ctx, id, vaaIdTxHash.TxHash); err != nil {
+		s.logger.Error("Error updating vaa txhash", zap.String("id", id), zap.Error(err))
+	}
+	return vaaIdTxHash.TxHash
+}
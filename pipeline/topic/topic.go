@@ -3,6 +3,8 @@ package topic
 import (
 	"context"
 	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
 )
 
 // Event represents a vaa data to be handle by the pipeline.
@@ -19,6 +21,11 @@ type Event struct {
 	TxHash           string     `json:"txHash"`
 	Version          uint16     `json:"version"`
 	Revision         uint16     `json:"revision"`
+	// Reprocess is set when the event is published by a reprocessing job.
+	Reprocess *reprocess.Request `json:"reprocess,omitempty"`
+	// TraceContext keeps the trace of the event while it waits for its txHash.
+	// It is propagated to the consumers in the message attributes.
+	TraceContext map[string]string `json:"-"`
 }
 
 // PushFunc is a function to push VAAEvent.
@@ -6,9 +6,9 @@ import (
 	"fmt"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/sns"
 	pipelineAlert "github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
-	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/sns"
 	"go.uber.org/zap"
 )
 
@@ -39,8 +39,13 @@ func (s *SNS) Publish(ctx context.Context, message *Event) error {
 	}
 
 	groupID := fmt.Sprintf("%d/%s", message.ChainID, message.EmitterAddress)
+	// the events of a reprocessing job must not be deduplicated with the original event.
+	deduplicationID := message.ID
+	if message.Reprocess != nil {
+		deduplicationID = fmt.Sprintf("%s/%s", message.Reprocess.JobID, message.ID)
+	}
 	s.logger.Debug("Publishing message", zap.String("groupID", groupID))
-	err = s.producer.SendMessage(ctx, groupID, message.ID, string(body))
+	err = s.producer.SendMessage(ctx, groupID, deduplicationID, string(body))
 	if err == nil {
 		s.metrics.IncVaaSendNotification(message.ChainID)
 	} else {
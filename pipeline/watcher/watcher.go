@@ -6,22 +6,27 @@ import (
 	"time"
 
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/db"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	pipelineAlert "github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/pipeline/internal/metrics"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
 // Watcher represents a listener of database changes.
 type Watcher struct {
-	db          *mongo.Database
-	dbName      string
-	handler     WatcherFunc
-	alertClient alert.AlertClient
-	metrics     metrics.Metrics
-	logger      *zap.Logger
+	db           *mongo.Database
+	dbName       string
+	handler      WatcherFunc
+	resumeID     string
+	saveInterval time.Duration
+	alertClient  alert.AlertClient
+	metrics      metrics.Metrics
+	logger       *zap.Logger
 }
 
 // WatcherFunc is a function to send database changes.
@@ -64,14 +69,17 @@ const queryTemplate = `
 `
 
 // NewWatcher creates a new database event watcher.
-func NewWatcher(ctx context.Context, db *mongo.Database, dbName string, handler WatcherFunc, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) *Watcher {
+// The resumeID identifies the position of the watcher, which is saved every saveInterval to resume from it after a restart.
+func NewWatcher(ctx context.Context, db *mongo.Database, dbName string, handler WatcherFunc, resumeID string, saveInterval time.Duration, alertClient alert.AlertClient, metrics metrics.Metrics, logger *zap.Logger) *Watcher {
 	return &Watcher{
-		db:          db,
-		dbName:      dbName,
-		handler:     handler,
-		metrics:     metrics,
-		alertClient: alertClient,
-		logger:      logger,
+		db:           db,
+		dbName:       dbName,
+		handler:      handler,
+		resumeID:     resumeID,
+		saveInterval: saveInterval,
+		metrics:      metrics,
+		alertClient:  alertClient,
+		logger:       logger,
 	}
 }
 
@@ -84,29 +92,74 @@ func (w *Watcher) Start(ctx context.Context) error {
 		return err
 	}
 
-	stream, err := w.db.Watch(ctx, steps)
-	if err != nil {
-		return err
-	}
-	go func() {
-		for stream.Next(ctx) {
-			var e watchEvent
-			if err := stream.Decode(&e); err != nil {
-				w.logger.Error("Error unmarshalling event", zap.Error(err))
-				alertContext := alert.AlertContext{
-					Details: e.toMapAlertDetail(),
-					Error:   err,
-				}
-				w.alertClient.CreateAndSend(ctx, pipelineAlert.ErrorDecodeWatcherEvent, alertContext)
-				continue
+	stream := db.NewChangeStream(w.db, w.resumeID, steps, w.catchUp, w.saveInterval, w.logger)
+	go stream.Watch(ctx, func(ctx context.Context, raw bson.Raw) {
+		var e watchEvent
+		if err := bson.Unmarshal(raw, &e); err != nil {
+			w.logger.Error("Error unmarshalling event", zap.Error(err))
+			alertContext := alert.AlertContext{
+				Details: e.toMapAlertDetail(),
+				Error:   err,
 			}
-			w.metrics.IncVaaFromMongoStream(e.DbFullDocument.ChainID)
-			w.handler(ctx, &e.DbFullDocument)
+			w.alertClient.CreateAndSend(ctx, pipelineAlert.ErrorDecodeWatcherEvent, alertContext)
+			return
 		}
-	}()
+		w.metrics.IncVaaFromMongoStream(e.DbFullDocument.ChainID)
+		w.handle(ctx, &e.DbFullDocument)
+	})
 	return nil
 }
 
+func (w *Watcher) handle(ctx context.Context, e *Event) {
+	// the trace context is not stored in the vaas collection, so the trace starts here and it is
+	// correlated with the trace of fly by the vaa id.
+	eventCtx, span := telemetry.StartVaaSpan(ctx, "pipeline.PublishVaa", e.ID)
+	w.handler(eventCtx, e)
+	span.End()
+}
+
+// catchUp publishes the vaas indexed since a time, when the change stream can not be resumed.
+func (w *Watcher) catchUp(ctx context.Context, since time.Time) error {
+	alertContext := alert.AlertContext{
+		Details: map[string]string{"since": since.Format(time.RFC3339)},
+	}
+	w.alertClient.CreateAndSend(ctx, pipelineAlert.ErrorWatcherResumeTokenLost, alertContext)
+
+	// the vaas of both watched collections are caught up.
+	count := 0
+	for _, collection := range []string{"vaas", "vaasPythnet"} {
+		n, err := w.catchUpCollection(ctx, collection, since)
+		count += n
+		if err != nil {
+			return err
+		}
+	}
+	w.logger.Info("Caught up vaas", zap.Time("since", since), zap.Int("count", count))
+	return nil
+}
+
+// catchUpCollection publishes the vaas of a collection indexed since a time and returns the number of vaas published.
+func (w *Watcher) catchUpCollection(ctx context.Context, collection string, since time.Time) (int, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "indexedAt", Value: 1}})
+	cur, err := w.db.Collection(collection).Find(ctx, bson.D{{Key: "indexedAt", Value: bson.D{{Key: "$gte", Value: since}}}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cur.Close(ctx)
+
+	count := 0
+	for cur.Next(ctx) {
+		var e Event
+		if err := cur.Decode(&e); err != nil {
+			w.logger.Error("Error decoding vaa to catch up", zap.Error(err), zap.String("collection", collection))
+			continue
+		}
+		w.handle(ctx, &e)
+		count++
+	}
+	return count, cur.Err()
+}
+
 // toAlertDetail returns from the watch event an map with the alert details.
 func (e *watchEvent) toMapAlertDetail() map[string]string {
 	detail := make(map[string]string)
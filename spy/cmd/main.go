@@ -2,16 +2,23 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/certusone/wormhole/node/pkg/supervisor"
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
 	"github.com/wormhole-foundation/wormhole-explorer/spy/config"
 	"github.com/wormhole-foundation/wormhole-explorer/spy/grpc"
 	"github.com/wormhole-foundation/wormhole-explorer/spy/http/infraestructure"
+	spyAlert "github.com/wormhole-foundation/wormhole-explorer/spy/internal/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/spy/internal/metrics"
 	"github.com/wormhole-foundation/wormhole-explorer/spy/storage"
 	"go.uber.org/zap"
 )
@@ -42,12 +49,50 @@ func main() {
 
 	logger.Info("Starting wormhole-explorer-spy ...")
 
-	svs := grpc.NewSignedVaaSubscribers(logger)
-	avs := grpc.NewAllVaaSubscribers(logger)
+	var appEmitters map[string][]string
+	if config.AppEmitters != "" {
+		if err := json.Unmarshal([]byte(config.AppEmitters), &appEmitters); err != nil {
+			logger.Fatal("invalid app emitters", zap.Error(err))
+		}
+	}
+	decoder, err := grpc.NewDecoder(appEmitters, config.DecoderCacheSize)
+	if err != nil {
+		logger.Fatal("failed to create decoder", zap.Error(err))
+	}
+
+	svs := grpc.NewSignedVaaSubscribers(decoder, logger)
+	avs := grpc.NewAllVaaSubscribers(decoder, logger)
 	go svs.Start(rootCtx)
 	go avs.Start(rootCtx)
 
-	handler := grpc.NewHandler(svs, avs, logger)
+	db, err := storage.New(rootCtx, logger, config.MongoURI, config.MongoDatabase)
+	if err != nil {
+		logger.Fatal("failed to connect MongoDB", zap.Error(err))
+	}
+
+	bufferPolicy, err := grpc.ParseBufferPolicy(config.SubscriberBufferPolicy)
+	if err != nil {
+		logger.Fatal("invalid subscriber buffer policy", zap.Error(err))
+	}
+	buffer := grpc.BufferConfig{
+		Size:    config.SubscriberBufferSize,
+		Policy:  bufferPolicy,
+		MaxSize: config.SubscriberMaxBufferSize,
+	}
+
+	var tokens map[string]grpc.TokenConfig
+	if config.SubscriberTokens != "" {
+		if err := json.Unmarshal([]byte(config.SubscriberTokens), &tokens); err != nil {
+			logger.Fatal("invalid subscriber tokens", zap.Error(err))
+		}
+	}
+	auth, err := grpc.NewAuthenticator(tokens, config.SubscriberRequireFilter)
+	if err != nil {
+		logger.Fatal("failed to create authenticator", zap.Error(err))
+	}
+
+	repository := storage.NewRepository(db.Database, logger)
+	handler := grpc.NewHandler(svs, avs, repository, config.ReplayBufferSize, buffer, auth, logger)
 
 	grpcServer, err := grpc.NewServer(handler, logger, config.GrpcAddress)
 	if err != nil {
@@ -67,18 +112,26 @@ func main() {
 
 	publisher := grpc.NewPublisher(svs, avs, logger)
 
-	db, err := storage.New(rootCtx, logger, config.MongoURI, config.MongoDatabase)
+	alertClient, err := newAlertClient(config)
 	if err != nil {
-		logger.Fatal("failed to connect MongoDB", zap.Error(err))
+		logger.Fatal("failed to create alert client", zap.Error(err))
 	}
 
-	watcher := storage.NewWatcher(db.Database, config.MongoDatabase, publisher.Publish, logger)
+	watcher := storage.NewWatcher(db.Database, config.MongoDatabase, publisher.Publish,
+		config.GetWatcherResumeID(), time.Duration(config.WatcherResumeInterval)*time.Second, alertClient, logger)
 	err = watcher.Start(rootCtx)
 	if err != nil {
 		logger.Fatal("failed to watch MongoDB", zap.Error(err))
 	}
 
-	server := infraestructure.NewServer(logger, config.Port, db.Database, config.PprofEnabled)
+	var ws fiber.Handler
+	if config.WebSocketEnabled {
+		ws = handler.WebSocketHandler()
+	}
+	if config.MetricsEnabled {
+		prometheus.MustRegister(metrics.NewPrometheusMetrics(config.Env, auth, svs, avs))
+	}
+	server := infraestructure.NewServer(logger, config.Port, db.Database, config.PprofEnabled, config.MetricsEnabled, config.AdminApiKey, ws, auth, svs, avs)
 	server.Start()
 
 	logger.Info("Started wormhole-explorer-spy")
@@ -104,3 +157,16 @@ func main() {
 	server.Stop()
 	logger.Info("Finished wormhole-explorer-spy")
 }
+
+func newAlertClient(cfg *config.Configuration) (alert.AlertClient, error) {
+	if !cfg.AlertEnabled {
+		return alert.NewDummyClient(), nil
+	}
+
+	alertConfig := alert.AlertConfig{
+		Environment: cfg.Env,
+		ApiKey:      cfg.AlertApiKey,
+		Enabled:     cfg.AlertEnabled,
+	}
+	return alert.NewAlertService(alertConfig, spyAlert.LoadAlerts)
+}
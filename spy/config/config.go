@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"os"
 
 	"github.com/joho/godotenv"
 	"github.com/sethvargo/go-envconfig"
@@ -16,6 +17,37 @@ type Configuration struct {
 	MongoURI      string `env:"MONGODB_URI,required"`
 	MongoDatabase string `env:"MONGODB_DATABASE,required"`
 	PprofEnabled  bool   `env:"PPROF_ENABLED,default=false"`
+	// MetricsEnabled exports the stats of the subscribers and their tokens as Prometheus metrics in the /metrics path of the http server.
+	MetricsEnabled bool `env:"METRICS_ENABLED,default=false"`
+	// WebSocketEnabled serves the subscriptions over websocket in the /ws path of the http server.
+	WebSocketEnabled bool `env:"WEBSOCKET_ENABLED,default=false"`
+	// ReplayBufferSize is the maximum number of live VAAs buffered for a subscriber while the historical VAAs are replayed.
+	ReplayBufferSize int `env:"REPLAY_BUFFER_SIZE,default=10000"`
+	// SubscriberBufferSize is the default number of VAAs buffered for a subscriber, up to SubscriberMaxBufferSize if it requests more.
+	SubscriberBufferSize    int `env:"SUBSCRIBER_BUFFER_SIZE,default=100"`
+	SubscriberMaxBufferSize int `env:"SUBSCRIBER_MAX_BUFFER_SIZE,default=10000"`
+	// SubscriberBufferPolicy is the default policy when the buffer of a subscriber is full: drop-oldest, drop-newest or disconnect.
+	SubscriberBufferPolicy string `env:"SUBSCRIBER_BUFFER_POLICY,default=drop-oldest"`
+	// AppEmitters maps the app IDs matched by the subscription filters to their emitters, encoded as JSON:
+	// {"PORTAL_TOKEN_BRIDGE": ["2/0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585"]}
+	AppEmitters string `env:"APP_EMITTERS"`
+	// DecoderCacheSize is the number of decoded VAAs cached to match the subscription filters.
+	DecoderCacheSize int `env:"DECODER_CACHE_SIZE,default=1024"`
+	// WatcherResumeID identifies the resume token of the watcher, each replica watching the same database must use its own.
+	// When it is empty it is derived from the host name, which is the pod name in kubernetes.
+	WatcherResumeID string `env:"WATCHER_RESUME_ID"`
+	// WatcherResumeInterval is the time in seconds between two saves of the resume token of the watcher.
+	WatcherResumeInterval int `env:"WATCHER_RESUME_INTERVAL,default=5"`
+	// SubscriberTokens maps the tokens of the subscribers to their limits, encoded as JSON. If it is empty the subscribers are anonymous:
+	// {"<token>": {"name": "partner-a", "maxSubscriptions": 5, "maxFilters": 20}}
+	SubscriberTokens string `env:"SUBSCRIBER_TOKENS"`
+	// SubscriberRequireFilter rejects the subscriptions without filters.
+	SubscriberRequireFilter bool `env:"SUBSCRIBER_REQUIRE_FILTER,default=false"`
+	// AdminApiKey enables the admin endpoints, the requests must be authenticated with this key.
+	AdminApiKey string `env:"ADMIN_API_KEY"`
+	// AlertEnabled sends the alerts of the spy, like the loss of the resume token of the watcher.
+	AlertEnabled bool   `env:"ALERT_ENABLED,default=false"`
+	AlertApiKey  string `env:"ALERT_API_KEY"`
 }
 
 // New creates a configuration with the values from .env file and environment variables.
@@ -29,3 +61,15 @@ func New(ctx context.Context) (*Configuration, error) {
 
 	return &configuration, nil
 }
+
+// GetWatcherResumeID returns the id of the resume token of the watcher, unique for each replica unless it is set.
+func (c *Configuration) GetWatcherResumeID() string {
+	if c.WatcherResumeID != "" {
+		return c.WatcherResumeID
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "spy"
+	}
+	return "spy-" + hostname
+}
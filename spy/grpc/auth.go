@@ -0,0 +1,237 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationKey is the metadata key of the token of a subscriber, with the format `Bearer <token>`.
+const authorizationKey = "authorization"
+
+// anonymousToken is the name of the subscribers when the spy does not require tokens.
+const anonymousToken = "anonymous"
+
+// TokenConfig defines the limits of the subscriptions authenticated with a token.
+type TokenConfig struct {
+	// Name identifies the token in the logs and the stats, so the token itself is never exposed.
+	Name string `json:"name"`
+	// MaxSubscriptions is the maximum number of concurrent subscriptions, 0 is unlimited.
+	MaxSubscriptions int `json:"maxSubscriptions"`
+	// MaxFilters is the maximum number of filters of a subscription, 0 is unlimited.
+	MaxFilters int `json:"maxFilters"`
+}
+
+// tokenState is the state of the subscriptions of a token.
+type tokenState struct {
+	TokenConfig
+	token []byte
+	// active is guarded by the mutex of the authenticator, since it is checked against the limit.
+	active    int
+	attempts  atomic.Uint64
+	rejected  atomic.Uint64
+	bytesSent atomic.Uint64
+}
+
+// Authenticator authenticates the subscribers with tokens and enforces their limits.
+//
+// If no token is configured the subscribers are anonymous, and only the filter requirement is enforced.
+type Authenticator struct {
+	mu            sync.Mutex
+	tokens        []*tokenState
+	anonymous     *tokenState
+	requireFilter bool
+}
+
+// NewAuthenticator creates a new Authenticator from the tokens and their limits.
+// If requireFilter is true the subscriptions must have at least one filter.
+func NewAuthenticator(tokens map[string]TokenConfig, requireFilter bool) (*Authenticator, error) {
+	a := Authenticator{
+		anonymous:     &tokenState{TokenConfig: TokenConfig{Name: anonymousToken}},
+		requireFilter: requireFilter,
+	}
+	names := make(map[string]bool, len(tokens))
+	for token, cfg := range tokens {
+		if token == "" {
+			return nil, fmt.Errorf("empty token %s", cfg.Name)
+		}
+		if cfg.Name == "" || names[cfg.Name] {
+			return nil, fmt.Errorf("every token must have a unique name")
+		}
+		if cfg.MaxSubscriptions < 0 || cfg.MaxFilters < 0 {
+			return nil, fmt.Errorf("invalid limits of token %s: must be 0 or greater", cfg.Name)
+		}
+		names[cfg.Name] = true
+		a.tokens = append(a.tokens, &tokenState{TokenConfig: cfg, token: []byte(token)})
+	}
+	sort.Slice(a.tokens, func(i, j int) bool {
+		return a.tokens[i].Name < a.tokens[j].Name
+	})
+	return &a, nil
+}
+
+// tokenSession is a subscription authenticated with a token, which must be released when the subscription ends.
+//
+// The methods of a nil session are no-ops, so the handler works without an authenticator.
+type tokenSession struct {
+	a     *Authenticator
+	state *tokenState
+	once  sync.Once
+}
+
+// identify returns the state of the token of a subscription. The token is identified before the
+// request of the subscription is parsed, so an unauthenticated subscriber gets no feedback about it.
+func (a *Authenticator) identify(token string) (*tokenState, error) {
+	if a == nil {
+		return nil, nil
+	}
+	state, err := a.lookup(token)
+	if err != nil {
+		return nil, err
+	}
+	state.attempts.Add(1)
+	return state, nil
+}
+
+// admit returns a session for a subscription of an identified token with a number of filters.
+func (a *Authenticator) admit(state *tokenState, filters int) (*tokenSession, error) {
+	if a == nil {
+		return nil, nil
+	}
+	if a.requireFilter && filters == 0 {
+		state.rejected.Add(1)
+		return nil, status.Error(codes.InvalidArgument, "at least one filter is required")
+	}
+	if state.MaxFilters > 0 && filters > state.MaxFilters {
+		state.rejected.Add(1)
+		return nil, status.Error(codes.ResourceExhausted,
+			fmt.Sprintf("too many filters: %d, the limit is %d", filters, state.MaxFilters))
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if state.MaxSubscriptions > 0 && state.active >= state.MaxSubscriptions {
+		state.rejected.Add(1)
+		return nil, status.Error(codes.ResourceExhausted,
+			fmt.Sprintf("too many subscriptions, the limit is %d", state.MaxSubscriptions))
+	}
+	state.active++
+	return &tokenSession{a: a, state: state}, nil
+}
+
+// lookup returns the state of a token, or the anonymous state if no token is configured.
+func (a *Authenticator) lookup(token string) (*tokenState, error) {
+	if len(a.tokens) == 0 {
+		return a.anonymous, nil
+	}
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+	for _, state := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), state.token) == 1 {
+			return state, nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "invalid token")
+}
+
+// release ends the subscription of the session.
+func (s *tokenSession) release() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() {
+		s.a.mu.Lock()
+		s.state.active--
+		s.a.mu.Unlock()
+	})
+}
+
+// recordSent records the bytes sent to the subscriber.
+func (s *tokenSession) recordSent(n int) {
+	if s == nil {
+		return
+	}
+	s.state.bytesSent.Add(uint64(n))
+}
+
+// name returns the name of the token of the session.
+func (s *tokenSession) name() string {
+	if s == nil {
+		return anonymousToken
+	}
+	return s.state.Name
+}
+
+// TokenStats are the subscription metrics of a token.
+type TokenStats struct {
+	Name             string `json:"name"`
+	Attempts         uint64 `json:"attempts"`
+	Rejected         uint64 `json:"rejected"`
+	Active           int    `json:"active"`
+	BytesSent        uint64 `json:"bytesSent"`
+	MaxSubscriptions int    `json:"maxSubscriptions"`
+	MaxFilters       int    `json:"maxFilters"`
+}
+
+// Stats returns the subscription metrics of the tokens, sorted by name.
+func (a *Authenticator) Stats() []TokenStats {
+	states := a.tokens
+	if len(states) == 0 {
+		states = []*tokenState{a.anonymous}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := make([]TokenStats, 0, len(states))
+	for _, s := range states {
+		stats = append(stats, TokenStats{
+			Name:             s.Name,
+			Attempts:         s.attempts.Load(),
+			Rejected:         s.rejected.Load(),
+			Active:           s.active,
+			BytesSent:        s.bytesSent.Load(),
+			MaxSubscriptions: s.MaxSubscriptions,
+			MaxFilters:       s.MaxFilters,
+		})
+	}
+	return stats
+}
+
+// bearerToken returns the token of an authorization value, with or without the Bearer prefix.
+func bearerToken(value string) string {
+	return strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+}
+
+// identifyMetadata returns the state of the token in the metadata of a subscription.
+func (h *Handler) identifyMetadata(ctx context.Context) (*tokenState, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationKey); len(values) > 0 {
+			token = bearerToken(values[0])
+		}
+	}
+	return h.auth.identify(token)
+}
+
+// size returns the number of conditions of a filter and its sub filters, which count against the filter limit of a token.
+func (f *filter) size() int {
+	if f == nil {
+		return 0
+	}
+	n := f.conditions()
+	for _, sub := range f.and {
+		n += sub.size()
+	}
+	for _, sub := range f.or {
+		n += sub.size()
+	}
+	return n
+}
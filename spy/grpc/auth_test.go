@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authenticate identifies a token and admits a subscription with a number of filters.
+func authenticate(a *Authenticator, token string, filters int) (*tokenSession, error) {
+	state, err := a.identify(token)
+	if err != nil {
+		return nil, err
+	}
+	return a.admit(state, filters)
+}
+
+func TestAuthenticator_authenticate(t *testing.T) {
+	auth, err := NewAuthenticator(map[string]TokenConfig{
+		"secret-a": {Name: "partner-a", MaxSubscriptions: 1, MaxFilters: 2},
+		"secret-b": {Name: "partner-b"},
+	}, true)
+	assert.Nil(t, err)
+
+	_, err = authenticate(auth, "", 1)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = authenticate(auth, "unknown", 1)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = authenticate(auth, "secret-b", 0)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = authenticate(auth, "secret-a", 3)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	session, err := authenticate(auth, "secret-a", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "partner-a", session.name())
+	_, err = authenticate(auth, "secret-a", 1)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	session.recordSent(100)
+	session.release()
+	session.release()
+	session, err = authenticate(auth, "secret-a", 1)
+	assert.Nil(t, err)
+
+	stats := auth.Stats()
+	assert.Len(t, stats, 2)
+	assert.Equal(t, TokenStats{Name: "partner-a", Attempts: 4, Rejected: 2, Active: 1, BytesSent: 100,
+		MaxSubscriptions: 1, MaxFilters: 2}, stats[0])
+	assert.Equal(t, TokenStats{Name: "partner-b", Attempts: 1, Rejected: 1}, stats[1])
+}
+
+func TestAuthenticator_anonymous(t *testing.T) {
+	auth, err := NewAuthenticator(nil, false)
+	assert.Nil(t, err)
+	session, err := authenticate(auth, "", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, anonymousToken, session.name())
+	assert.Equal(t, []TokenStats{{Name: anonymousToken, Attempts: 1, Active: 1}}, auth.Stats())
+
+	var disabled *Authenticator
+	session, err = authenticate(disabled, "", 0)
+	assert.Nil(t, err)
+	assert.Nil(t, session)
+	session.release()
+}
+
+func TestNewAuthenticator_invalid(t *testing.T) {
+	_, err := NewAuthenticator(map[string]TokenConfig{"secret": {}}, false)
+	assert.NotNil(t, err)
+	_, err = NewAuthenticator(map[string]TokenConfig{"secret": {Name: "a", MaxFilters: -1}}, false)
+	assert.NotNil(t, err)
+}
+
+func TestFilter_size(t *testing.T) {
+	f, err := parseFilter(`{"or": [{"chainId": 2}, {"chainId": 1}], "and": [{"payloadType": 1}]}`)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, f.size())
+	f, err = parseFilter(`{"chainId": 2, "emitterAddress": "0000000000000000000000000000000000000000000000000000000000000001"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, f.size())
+	var none *filter
+	assert.Equal(t, 0, none.size())
+}
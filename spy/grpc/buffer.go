@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BufferPolicy defines what happens when the buffer of a subscriber is full.
+type BufferPolicy string
+
+const (
+	// BufferPolicyDropOldest discards the oldest VAA of the buffer to make room for the new one.
+	BufferPolicyDropOldest BufferPolicy = "drop-oldest"
+	// BufferPolicyDropNewest discards the new VAA.
+	BufferPolicyDropNewest BufferPolicy = "drop-newest"
+	// BufferPolicyDisconnect disconnects the subscriber with an error.
+	BufferPolicyDisconnect BufferPolicy = "disconnect"
+)
+
+// The subscribers choose the buffer of their subscription with metadata,
+// since the requests of the spy service can not be extended.
+const (
+	bufferSizeKey   = "x-buffer-size"
+	bufferPolicyKey = "x-buffer-policy"
+)
+
+// ParseBufferPolicy parses a buffer policy.
+func ParseBufferPolicy(s string) (BufferPolicy, error) {
+	switch p := BufferPolicy(s); p {
+	case BufferPolicyDropOldest, BufferPolicyDropNewest, BufferPolicyDisconnect:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid buffer policy %s: must be %s, %s or %s",
+			s, BufferPolicyDropOldest, BufferPolicyDropNewest, BufferPolicyDisconnect)
+	}
+}
+
+// BufferConfig defines the buffer of a subscriber.
+type BufferConfig struct {
+	Size   int
+	Policy BufferPolicy
+	// MaxSize is the maximum size a subscriber can request.
+	MaxSize int
+}
+
+// withMetadata returns the buffer config requested by a subscriber in the values of the metadata.
+func (c BufferConfig) withMetadata(sizes, policies []string) (BufferConfig, error) {
+	var size int
+	if len(sizes) > 0 {
+		var err error
+		size, err = strconv.Atoi(sizes[0])
+		if err != nil || size <= 0 {
+			return c, fmt.Errorf("invalid %s %s: must be between 1 and %d", bufferSizeKey, sizes[0], c.MaxSize)
+		}
+	}
+	var policy string
+	if len(policies) > 0 {
+		policy = policies[0]
+	}
+	return c.with(size, policy)
+}
+
+// with returns the buffer config requested by a subscriber, the zero values keep the default config.
+func (c BufferConfig) with(size int, policy string) (BufferConfig, error) {
+	if size < 0 || size > c.MaxSize {
+		return c, fmt.Errorf("invalid buffer size %d: must be between 1 and %d", size, c.MaxSize)
+	}
+	if size > 0 {
+		c.Size = size
+	}
+	if policy != "" {
+		p, err := ParseBufferPolicy(policy)
+		if err != nil {
+			return c, err
+		}
+		c.Policy = p
+	}
+	return c, nil
+}
+
+// subscriberStats are the delivery metrics of a subscriber.
+type subscriberStats struct {
+	connectedAt time.Time
+	delivered   atomic.Uint64
+	dropped     atomic.Uint64
+	// lag is the time in nanoseconds between the reception of the last VAA delivered and its delivery.
+	lag    atomic.Int64
+	maxLag atomic.Int64
+}
+
+// recordDelivery records the delivery of a VAA received at a time.
+func (s *subscriberStats) recordDelivery(receivedAt time.Time) {
+	s.delivered.Add(1)
+	lag := int64(time.Since(receivedAt))
+	s.lag.Store(lag)
+	for {
+		max := s.maxLag.Load()
+		if lag <= max || s.maxLag.CompareAndSwap(max, lag) {
+			return
+		}
+	}
+}
+
+// subscriberBuffer is the bounded buffer of the VAAs pending to be sent to a subscriber.
+//
+// The VAAs are pushed by a single goroutine, which also closes the channel.
+type subscriberBuffer[T any] struct {
+	ch     chan T
+	policy BufferPolicy
+	// overflowed is closed when the buffer is full with the disconnect policy.
+	overflowed   chan struct{}
+	overflowOnce sync.Once
+	stats        *subscriberStats
+}
+
+func newSubscriberBuffer[T any](cfg BufferConfig) *subscriberBuffer[T] {
+	size := cfg.Size
+	if size <= 0 {
+		size = 1
+	}
+	policy := cfg.Policy
+	if policy == "" {
+		policy = BufferPolicyDropOldest
+	}
+	return &subscriberBuffer[T]{
+		ch:         make(chan T, size),
+		policy:     policy,
+		overflowed: make(chan struct{}),
+		stats:      &subscriberStats{connectedAt: time.Now()},
+	}
+}
+
+// push adds a VAA to the buffer, applying the policy if it is full.
+func (b *subscriberBuffer[T]) push(v T) {
+	select {
+	case b.ch <- v:
+		return
+	default:
+	}
+
+	switch b.policy {
+	case BufferPolicyDropOldest:
+		// the subscriber may read the buffer meanwhile, so the oldest VAA is only discarded if it is still full.
+		select {
+		case <-b.ch:
+			b.stats.dropped.Add(1)
+		default:
+		}
+		select {
+		case b.ch <- v:
+		default:
+			b.stats.dropped.Add(1)
+		}
+	case BufferPolicyDropNewest:
+		b.stats.dropped.Add(1)
+	case BufferPolicyDisconnect:
+		b.stats.dropped.Add(1)
+		b.overflowOnce.Do(func() { close(b.overflowed) })
+	}
+}
+
+// SubscriberInfo is the state of a subscriber.
+type SubscriberInfo struct {
+	ID          string       `json:"id"`
+	Type        string       `json:"type"`
+	Filters     []string     `json:"filters"`
+	Policy      BufferPolicy `json:"policy"`
+	BufferSize  int          `json:"bufferSize"`
+	Buffered    int          `json:"buffered"`
+	Delivered   uint64       `json:"delivered"`
+	Dropped     uint64       `json:"dropped"`
+	LagMs       int64        `json:"lagMs"`
+	MaxLagMs    int64        `json:"maxLagMs"`
+	ConnectedAt time.Time    `json:"connectedAt"`
+}
+
+func (b *subscriberBuffer[T]) info(id, subscriptionType string, filters []string) SubscriberInfo {
+	return SubscriberInfo{
+		ID:          id,
+		Type:        subscriptionType,
+		Filters:     filters,
+		Policy:      b.policy,
+		BufferSize:  cap(b.ch),
+		Buffered:    len(b.ch),
+		Delivered:   b.stats.delivered.Load(),
+		Dropped:     b.stats.dropped.Load(),
+		LagMs:       time.Duration(b.stats.lag.Load()).Milliseconds(),
+		MaxLagMs:    time.Duration(b.stats.maxLag.Load()).Milliseconds(),
+		ConnectedAt: b.stats.connectedAt,
+	}
+}
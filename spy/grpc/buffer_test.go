@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriberBuffer_push(t *testing.T) {
+
+	t.Run("drop oldest", func(t *testing.T) {
+		b := newSubscriberBuffer[int](BufferConfig{Size: 2, Policy: BufferPolicyDropOldest})
+		for i := 1; i <= 4; i++ {
+			b.push(i)
+		}
+		assert.Equal(t, 3, <-b.ch)
+		assert.Equal(t, 4, <-b.ch)
+		assert.Equal(t, uint64(2), b.stats.dropped.Load())
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		b := newSubscriberBuffer[int](BufferConfig{Size: 2, Policy: BufferPolicyDropNewest})
+		for i := 1; i <= 4; i++ {
+			b.push(i)
+		}
+		assert.Equal(t, 1, <-b.ch)
+		assert.Equal(t, 2, <-b.ch)
+		assert.Equal(t, uint64(2), b.stats.dropped.Load())
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		b := newSubscriberBuffer[int](BufferConfig{Size: 1, Policy: BufferPolicyDisconnect})
+		b.push(1)
+		select {
+		case <-b.overflowed:
+			t.Fatal("unexpected overflow")
+		default:
+		}
+		b.push(2)
+		b.push(3)
+		<-b.overflowed
+		assert.Equal(t, uint64(2), b.stats.dropped.Load())
+	})
+}
+
+func TestBufferConfig_withMetadata(t *testing.T) {
+	cfg := BufferConfig{Size: 100, Policy: BufferPolicyDropOldest, MaxSize: 1000}
+
+	c, err := cfg.withMetadata(nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, cfg, c)
+
+	c, err = cfg.withMetadata([]string{"500"}, []string{"disconnect"})
+	assert.Nil(t, err)
+	assert.Equal(t, 500, c.Size)
+	assert.Equal(t, BufferPolicyDisconnect, c.Policy)
+
+	_, err = cfg.withMetadata([]string{"5000"}, nil)
+	assert.NotNil(t, err)
+	_, err = cfg.withMetadata(nil, []string{"block"})
+	assert.NotNil(t, err)
+}
+
+func TestBufferConfig_with(t *testing.T) {
+	cfg := BufferConfig{Size: 100, Policy: BufferPolicyDropOldest, MaxSize: 1000}
+
+	c, err := cfg.with(0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, cfg, c)
+
+	c, err = cfg.with(10, "drop-newest")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, c.Size)
+	assert.Equal(t, BufferPolicyDropNewest, c.Policy)
+
+	_, err = cfg.with(-1, "")
+	assert.NotNil(t, err)
+	_, err = cfg.with(0, "block")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,293 @@
+package grpc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+// filterKey is the metadata key of the extended filter of a subscription, encoded as JSON.
+// The extended filter is combined with AND with the filters of the request.
+const filterKey = "x-filter"
+
+// Filter is an extended filter of the VAAs of a subscription.
+//
+// The conditions set in a filter are combined with AND, the filters in And are combined with AND
+// and the filters in Or are combined with OR. For example, the token bridge transfers with payload
+// to a contract on Ethereum:
+//
+//	{"or": [{"chainId": 2, "emitterAddress": "0000...3ee18b2214aff97000d974cf647e7c347e8fa585"}, ...],
+//	 "and": [{"payloadType": 3, "targetChain": 2, "recipient": "0000...1234"}]}
+type Filter struct {
+	ChainID        *vaa.ChainID `json:"chainId,omitempty"`
+	EmitterAddress string       `json:"emitterAddress,omitempty"`
+	// PayloadType is the type of a token bridge payload: 1 transfer, 2 attestation or 3 transfer with payload.
+	PayloadType *uint8 `json:"payloadType,omitempty"`
+	// TargetChain is the target chain of a token bridge transfer.
+	TargetChain *vaa.ChainID `json:"targetChain,omitempty"`
+	// Recipient is the recipient address of a token bridge transfer.
+	Recipient string `json:"recipient,omitempty"`
+	// AppID is an app of the emitter, as configured in the spy.
+	AppID string    `json:"appId,omitempty"`
+	And   []*Filter `json:"and,omitempty"`
+	Or    []*Filter `json:"or,omitempty"`
+}
+
+// parseFilter parses an extended filter encoded as JSON.
+func parseFilter(s string) (*filter, error) {
+	var f Filter
+	if err := json.Unmarshal([]byte(s), &f); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", filterKey, err)
+	}
+	c, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+	c.source = s
+	return c, nil
+}
+
+// filter is a Filter with its addresses decoded, ready to be matched.
+type filter struct {
+	chainID     *vaa.ChainID
+	emitter     *vaa.Address
+	payloadType *uint8
+	targetChain *vaa.ChainID
+	recipient   *vaa.Address
+	appID       string
+	and         []*filter
+	or          []*filter
+	// source is the JSON the filter was parsed from.
+	source string
+}
+
+func (f *Filter) compile() (*filter, error) {
+	c := filter{
+		chainID:     f.ChainID,
+		payloadType: f.PayloadType,
+		targetChain: f.TargetChain,
+		appID:       f.AppID,
+	}
+	if f.EmitterAddress != "" {
+		addr, err := vaa.StringToAddress(f.EmitterAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid emitter address %s: %v", f.EmitterAddress, err)
+		}
+		c.emitter = &addr
+	}
+	if f.Recipient != "" {
+		addr, err := vaa.StringToAddress(f.Recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %s: %v", f.Recipient, err)
+		}
+		c.recipient = &addr
+	}
+	for _, sub := range f.And {
+		cs, err := sub.compile()
+		if err != nil {
+			return nil, err
+		}
+		c.and = append(c.and, cs)
+	}
+	for _, sub := range f.Or {
+		cs, err := sub.compile()
+		if err != nil {
+			return nil, err
+		}
+		c.or = append(c.or, cs)
+	}
+	if c.conditions() == 0 && len(c.and) == 0 && len(c.or) == 0 {
+		return nil, fmt.Errorf("invalid %s: a filter must have at least one condition", filterKey)
+	}
+	return &c, nil
+}
+
+// conditions returns the number of conditions set in the filter, without its sub filters.
+func (f *filter) conditions() int {
+	n := 0
+	for _, set := range []bool{f.chainID != nil, f.emitter != nil, f.payloadType != nil,
+		f.targetChain != nil, f.recipient != nil, f.appID != ""} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// match returns whether a decoded VAA satisfies the filter.
+func (f *filter) match(d *decodedVaa) bool {
+	if f.chainID != nil && *f.chainID != d.vaa.EmitterChain {
+		return false
+	}
+	if f.emitter != nil && *f.emitter != d.vaa.EmitterAddress {
+		return false
+	}
+	if f.payloadType != nil && (d.transfer == nil || *f.payloadType != d.transfer.payloadType) {
+		return false
+	}
+	if f.targetChain != nil && (d.transfer == nil || !d.transfer.isTransfer() || *f.targetChain != d.transfer.targetChain) {
+		return false
+	}
+	if f.recipient != nil && (d.transfer == nil || !d.transfer.isTransfer() || *f.recipient != d.transfer.recipient) {
+		return false
+	}
+	if f.appID != "" && !contains(d.appIDs, f.appID) {
+		return false
+	}
+	for _, sub := range f.and {
+		if !sub.match(d) {
+			return false
+		}
+	}
+	if len(f.or) == 0 {
+		return true
+	}
+	for _, sub := range f.or {
+		if sub.match(d) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// transferPayload is the header of a token bridge payload.
+type transferPayload struct {
+	payloadType uint8
+	targetChain vaa.ChainID
+	recipient   vaa.Address
+}
+
+func (t *transferPayload) isTransfer() bool {
+	return t.payloadType == 1 || t.payloadType == 3
+}
+
+// decodeTransferPayload decodes the header of a token bridge payload, or returns nil if the payload is not one.
+//
+// The payload is not checked to come from a token bridge, so the filters on the payload should be combined
+// with the token bridge emitters.
+func decodeTransferPayload(payload []byte) *transferPayload {
+	if len(payload) == 0 {
+		return nil
+	}
+	switch payload[0] {
+	case 1, 3:
+		// type, amount, token address, token chain, recipient, recipient chain, and fee or sender.
+		if len(payload) < 1+32+32+2+32+2+32 || (payload[0] == 1 && len(payload) != 133) {
+			return nil
+		}
+		t := transferPayload{payloadType: payload[0]}
+		copy(t.recipient[:], payload[67:99])
+		t.targetChain = vaa.ChainID(binary.BigEndian.Uint16(payload[99:101]))
+		return &t
+	case 2:
+		// type, token address, token chain, decimals, symbol and name.
+		if len(payload) != 1+32+2+1+32+32 {
+			return nil
+		}
+		return &transferPayload{payloadType: 2}
+	default:
+		return nil
+	}
+}
+
+// decodedVaa is a VAA with the fields its filters are matched on.
+type decodedVaa struct {
+	vaa      *vaa.VAA
+	transfer *transferPayload
+	appIDs   []string
+}
+
+// Decoder decodes the VAAs to match their filters, so every VAA is decoded once for all the subscribers.
+//
+// The last VAAs decoded are cached, since they are dispatched to each subscription type.
+type Decoder struct {
+	mu     sync.Mutex
+	cache  map[[32]byte]*decodedVaa
+	keys   [][32]byte
+	next   int
+	appIDs map[string][]string
+}
+
+// NewDecoder creates a new Decoder that caches the last size VAAs.
+// The appIDs map each app ID to its emitters, with the format chainID/emitterAddress.
+func NewDecoder(appIDs map[string][]string, size int) (*Decoder, error) {
+	if size <= 0 {
+		size = 1
+	}
+	d := Decoder{
+		cache:  make(map[[32]byte]*decodedVaa, size),
+		keys:   make([][32]byte, size),
+		appIDs: make(map[string][]string),
+	}
+	for appID, emitters := range appIDs {
+		for _, e := range emitters {
+			key, err := emitterKey(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid emitter %s of app %s: %v", e, appID, err)
+			}
+			d.appIDs[key] = append(d.appIDs[key], appID)
+		}
+	}
+	return &d, nil
+}
+
+func emitterKey(s string) (string, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("must be chainID/emitterAddress")
+	}
+	chainID, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return "", err
+	}
+	addr, err := vaa.StringToAddress(parts[1])
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%s", chainID, addr), nil
+}
+
+// decode decodes a VAA, or returns it from the cache if it was recently decoded.
+func (d *Decoder) decode(vaaBytes []byte) (*decodedVaa, error) {
+	key := sha256.Sum256(vaaBytes)
+	d.mu.Lock()
+	if decoded, ok := d.cache[key]; ok {
+		d.mu.Unlock()
+		return decoded, nil
+	}
+	d.mu.Unlock()
+
+	v, err := vaa.Unmarshal(vaaBytes)
+	if err != nil {
+		return nil, err
+	}
+	decoded := &decodedVaa{
+		vaa:      v,
+		transfer: decodeTransferPayload(v.Payload),
+		appIDs:   d.appIDs[fmt.Sprintf("%d/%s", v.EmitterChain, v.EmitterAddress)],
+	}
+
+	// the oldest VAA is evicted when the cache is full.
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.cache, d.keys[d.next])
+	d.keys[d.next] = key
+	d.cache[key] = decoded
+	d.next = (d.next + 1) % len(d.keys)
+	return decoded, nil
+}
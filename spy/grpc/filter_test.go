@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+var recipientAddr = vaa.Address{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 9}
+
+// createTransferPayload creates a token bridge transfer payload.
+func createTransferPayload(payloadType uint8, targetChain vaa.ChainID, recipient vaa.Address) []byte {
+	payload := make([]byte, 133)
+	payload[0] = payloadType
+	copy(payload[67:99], recipient[:])
+	binary.BigEndian.PutUint16(payload[99:101], uint16(targetChain))
+	return payload
+}
+
+func decodeTestVaa(t *testing.T, decoder *Decoder, v *vaa.VAA) *decodedVaa {
+	b, err := v.MarshalBinary()
+	assert.Nil(t, err)
+	decoded, err := decoder.decode(b)
+	assert.Nil(t, err)
+	return decoded
+}
+
+func TestFilter_match(t *testing.T) {
+	decoder, err := NewDecoder(map[string][]string{"TEST_APP": {"2/" + emitterAddr.String()}}, 10)
+	assert.Nil(t, err)
+
+	transfer := createVAA(vaa.ChainIDEthereum, emitterAddr)
+	transfer.Payload = createTransferPayload(1, vaa.ChainIDSolana, recipientAddr)
+	decodedTransfer := decodeTestVaa(t, decoder, transfer)
+	other := createVAA(vaa.ChainIDSolana, emitterAddr)
+	decodedOther := decodeTestVaa(t, decoder, other)
+
+	cases := []struct {
+		name     string
+		filter   string
+		transfer bool
+		other    bool
+	}{
+		{"chain", `{"chainId": 2}`, true, false},
+		{"emitter", `{"emitterAddress": "` + emitterAddr.String() + `"}`, true, true},
+		{"payload type", `{"payloadType": 1}`, true, false},
+		{"target chain", `{"targetChain": 1}`, true, false},
+		{"recipient", `{"recipient": "` + recipientAddr.String() + `"}`, true, false},
+		{"other recipient", `{"recipient": "` + emitterAddr.String() + `"}`, false, false},
+		{"app id", `{"appId": "TEST_APP"}`, true, false},
+		{"and", `{"and": [{"chainId": 2}, {"targetChain": 2}]}`, false, false},
+		{"or", `{"or": [{"chainId": 2}, {"chainId": 1}]}`, true, true},
+		{"and or", `{"payloadType": 1, "or": [{"chainId": 1}, {"appId": "TEST_APP"}]}`, true, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := parseFilter(c.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, c.transfer, f.match(decodedTransfer))
+			assert.Equal(t, c.other, f.match(decodedOther))
+		})
+	}
+}
+
+func TestParseFilter_invalid(t *testing.T) {
+	for _, s := range []string{
+		`{"chainId": "ethereum"}`,
+		`{"emitterAddress": "bad-address"}`,
+		`{"or": [{"recipient": "bad-address"}]}`,
+		`{}`,
+		`{"or": [{"chainId": 2}, {}]}`,
+		`{"and": [], "or": []}`,
+	} {
+		_, err := parseFilter(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestNewDecoder_invalid(t *testing.T) {
+	_, err := NewDecoder(map[string][]string{"TEST_APP": {emitterAddr.String()}}, 10)
+	assert.NotNil(t, err)
+}
+
+func TestDecoder_decode(t *testing.T) {
+	decoder, err := NewDecoder(nil, 1)
+	assert.Nil(t, err)
+
+	v1 := createVAA(vaa.ChainIDEthereum, emitterAddr)
+	b1, _ := v1.MarshalBinary()
+	v2 := createVAA(vaa.ChainIDSolana, emitterAddr)
+	b2, _ := v2.MarshalBinary()
+
+	d1, err := decoder.decode(b1)
+	assert.Nil(t, err)
+	cached, _ := decoder.decode(b1)
+	assert.Same(t, d1, cached)
+
+	// the cache holds one VAA, so the first one is evicted.
+	_, _ = decoder.decode(b2)
+	evicted, _ := decoder.decode(b1)
+	assert.NotSame(t, d1, evicted)
+
+	_, err = decoder.decode([]byte{0x0, 0x1})
+	assert.NotNil(t, err)
+}
@@ -1,35 +1,81 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 
 	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
 	"github.com/wormhole-foundation/wormhole/sdk/vaa"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 // Handler represents a GRPC subscription service handler.
 type Handler struct {
 	spyv1.UnimplementedSpyRPCServiceServer
-	svs    *SignedVaaSubscribers
-	avs    *AllVaaSubscribers
-	logger *zap.Logger
+	svs              *SignedVaaSubscribers
+	avs              *AllVaaSubscribers
+	repository       VaaRepository
+	replayBufferSize int
+	buffer           BufferConfig
+	auth             *Authenticator
+	logger           *zap.Logger
 }
 
 // NewHandler creates a new handler of suscriptions.
-func NewHandler(svs *SignedVaaSubscribers, avs *AllVaaSubscribers, logger *zap.Logger) *Handler {
+// The replay of historical VAAs is not supported if the repository is nil.
+// The buffer is the default buffer of the subscribers, which they can override with metadata.
+// The subscribers are not authenticated if auth is nil.
+func NewHandler(svs *SignedVaaSubscribers, avs *AllVaaSubscribers, repository VaaRepository, replayBufferSize int, buffer BufferConfig, auth *Authenticator, logger *zap.Logger) *Handler {
 	return &Handler{
-		svs:    svs,
-		avs:    avs,
-		logger: logger,
+		svs:              svs,
+		avs:              avs,
+		repository:       repository,
+		replayBufferSize: replayBufferSize,
+		buffer:           buffer,
+		auth:             auth,
+		logger:           logger,
 	}
 }
 
+// bufferConfig returns the buffer requested in the metadata of a subscription.
+func (h *Handler) bufferConfig(ctx context.Context) (BufferConfig, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return h.buffer, nil
+	}
+	return h.buffer.withMetadata(md.Get(bufferSizeKey), md.Get(bufferPolicyKey))
+}
+
+// subscriptionFilter returns the extended filter requested in the metadata of a subscription, or nil if it is not requested.
+func subscriptionFilter(ctx context.Context) (*filter, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get(filterKey)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if len(values) > 1 {
+		return nil, fmt.Errorf("%s must be set once", filterKey)
+	}
+	return parseFilter(values[0])
+}
+
+// overflowError is returned when a subscriber is disconnected because its buffer is full.
+var overflowError = status.Error(codes.ResourceExhausted, "subscriber is too slow, the buffer of VAAs is full")
+
 // SubscribeSignedVAA implements the suscriptions of signed VAA.
 func (h *Handler) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, resp spyv1.SpyRPCService_SubscribeSignedVAAServer) error {
 	h.logger.Info("Receiving new subscriber in signed VAA")
+	state, err := h.identifyMetadata(resp.Context())
+	if err != nil {
+		return err
+	}
+
 	var fi []filterSignedVaa
 	if req.Filters != nil {
 		for _, f := range req.Filters {
@@ -51,14 +97,51 @@ func (h *Handler) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, resp
 		}
 	}
 
-	subscriber := h.svs.Register(fi)
+	replay, err := parseReplayQuery(resp.Context(), fi)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	buffer, err := h.bufferConfig(resp.Context())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	f, err := subscriptionFilter(resp.Context())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	session, err := h.auth.admit(state, len(fi)+f.size())
+	if err != nil {
+		return err
+	}
+	defer session.release()
+
+	subscriber := h.svs.Register(fi, f, buffer)
 	defer h.svs.Unregister(subscriber)
+	h.logger.Info("Subscriber authenticated", zap.String("id", subscriber.id), zap.String("token", session.name()))
+
+	if replay != nil {
+		err := h.replaySignedVaas(resp.Context(), subscriber, replay, func(vaaBytes []byte) error {
+			if err := resp.Send(&spyv1.SubscribeSignedVAAResponse{VaaBytes: vaaBytes}); err != nil {
+				return err
+			}
+			session.recordSent(len(vaaBytes))
+			return nil
+		})
+		if err != nil {
+			h.logger.Error("Replaying vaas", zap.String("id", subscriber.id), zap.Error(err))
+			return err
+		}
+	}
 
 	for {
 		select {
 		case <-resp.Context().Done():
 			h.logger.Error("Context done", zap.String("id", subscriber.id), zap.Error(resp.Context().Err()))
 			return resp.Context().Err()
+		case <-subscriber.overflowed:
+			h.logger.Warn("Disconnecting slow subscriber", zap.String("id", subscriber.id))
+			return overflowError
 		case msg := <-subscriber.ch:
 			if err := resp.Send(&spyv1.SubscribeSignedVAAResponse{
 				VaaBytes: msg.vaaBytes,
@@ -66,6 +149,8 @@ func (h *Handler) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, resp
 				h.logger.Error("Sending vaas", zap.String("id", subscriber.id), zap.Error(err))
 				return err
 			}
+			session.recordSent(len(msg.vaaBytes))
+			subscriber.stats.recordDelivery(msg.receivedAt)
 		}
 	}
 }
@@ -73,6 +158,11 @@ func (h *Handler) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, resp
 // SubscribeSignedVAAByType implements the suscriptions of signed VAA by type.
 func (h *Handler) SubscribeSignedVAAByType(req *spyv1.SubscribeSignedVAAByTypeRequest, resp spyv1.SpyRPCService_SubscribeSignedVAAByTypeServer) error {
 	h.logger.Info("Receiving new subscriber in signed VAA by type")
+	state, err := h.identifyMetadata(resp.Context())
+	if err != nil {
+		return err
+	}
+
 	var fi []*spyv1.FilterEntry
 	if req.Filters != nil {
 		for _, f := range req.Filters {
@@ -95,17 +185,38 @@ func (h *Handler) SubscribeSignedVAAByType(req *spyv1.SubscribeSignedVAAByTypeRe
 		}
 	}
 
-	sub := h.avs.Register(fi)
+	buffer, err := h.bufferConfig(resp.Context())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	f, err := subscriptionFilter(resp.Context())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	session, err := h.auth.admit(state, len(fi)+f.size())
+	if err != nil {
+		return err
+	}
+	defer session.release()
+
+	sub := h.avs.Register(fi, f, buffer)
 	defer h.avs.Unregister(sub)
+	h.logger.Info("Subscriber authenticated", zap.String("id", sub.id), zap.String("token", session.name()))
 
 	for {
 		select {
 		case <-resp.Context().Done():
 			return resp.Context().Err()
+		case <-sub.overflowed:
+			h.logger.Warn("Disconnecting slow subscriber", zap.String("id", sub.id))
+			return overflowError
 		case msg := <-sub.ch:
-			if err := resp.Send(msg); err != nil {
+			if err := resp.Send(msg.envelope); err != nil {
 				return err
 			}
+			session.recordSent(len(msg.envelope.GetSignedVaa().GetVaa()))
+			sub.stats.recordDelivery(msg.receivedAt)
 		}
 	}
 }
@@ -44,9 +44,9 @@ func createGRPCServer(handler *Handler, logger *zap.Logger) (context.Context, *g
 
 func TestSubscribeSignedVAA_OK(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	svs := NewSignedVaaSubscribers(logger)
-	avs := NewAllVaaSubscribers(logger)
-	handler := NewHandler(svs, avs, logger)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
 
 	_, _, client := createGRPCServer(handler, logger)
 
@@ -82,9 +82,9 @@ func TestSubscribeSignedVAA_OK(t *testing.T) {
 
 func TestSubscribeSignedVAA_Failed(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	svs := NewSignedVaaSubscribers(logger)
-	avs := NewAllVaaSubscribers(logger)
-	handler := NewHandler(svs, avs, logger)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
 
 	ctx, _, client := createGRPCServer(handler, logger)
 
@@ -128,9 +128,9 @@ func TestSubscribeSignedVAA_Failed(t *testing.T) {
 
 func TestSubscribeSignedVAAByType_OK(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	svs := NewSignedVaaSubscribers(logger)
-	avs := NewAllVaaSubscribers(logger)
-	handler := NewHandler(svs, avs, logger)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
 
 	_, _, client := createGRPCServer(handler, logger)
 
@@ -167,9 +167,9 @@ func TestSubscribeSignedVAAByType_OK(t *testing.T) {
 
 func TestSubscribeSignedVAAByType_Failed(t *testing.T) {
 	logger := zaptest.NewLogger(t)
-	svs := NewSignedVaaSubscribers(logger)
-	avs := NewAllVaaSubscribers(logger)
-	handler := NewHandler(svs, avs, logger)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
 
 	ctx, _, client := createGRPCServer(handler, logger)
 
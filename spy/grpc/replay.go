@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wormhole-foundation/wormhole-explorer/spy/storage"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The subscribers request the replay of the historical VAAs with metadata,
+// since the requests of the spy service can not be extended.
+const (
+	// replayFromTimestampKey replays the VAAs since a timestamp, in RFC3339 format or in unix seconds.
+	replayFromTimestampKey = "x-replay-from-timestamp"
+	// replayFromSequenceKey replays the VAAs of an emitter after its last sequence received,
+	// with the format chainID/emitterAddress/sequence. It can be repeated for several emitters.
+	replayFromSequenceKey = "x-replay-from-sequence"
+)
+
+// VaaRepository is the storage of the historical VAAs replayed to the subscribers.
+type VaaRepository interface {
+	FindVaas(ctx context.Context, q *storage.ReplayQuery, handler func(*storage.Event) error) error
+}
+
+// parseReplayQuery returns the replay requested in the metadata of a subscription, or nil if it is not requested.
+func parseReplayQuery(ctx context.Context, fi []filterSignedVaa) (*storage.ReplayQuery, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return newReplayQuery(fi, md.Get(replayFromTimestampKey), md.Get(replayFromSequenceKey))
+}
+
+// newReplayQuery returns the replay requested from the timestamps and the emitter sequences, or nil if it is not requested.
+func newReplayQuery(fi []filterSignedVaa, timestamps, sequences []string) (*storage.ReplayQuery, error) {
+	if len(timestamps) == 0 && len(sequences) == 0 {
+		return nil, nil
+	}
+
+	var q storage.ReplayQuery
+	for _, f := range fi {
+		q.Emitters = append(q.Emitters, storage.Emitter{ChainID: f.chainId, Address: f.emitterAddr})
+	}
+
+	if len(timestamps) > 1 {
+		return nil, fmt.Errorf("%s must be set once", replayFromTimestampKey)
+	}
+	if len(timestamps) == 1 {
+		from, err := parseReplayTimestamp(timestamps[0])
+		if err != nil {
+			return nil, err
+		}
+		q.From = &from
+	}
+
+	for _, s := range sequences {
+		es, err := parseReplaySequence(s)
+		if err != nil {
+			return nil, err
+		}
+		if len(fi) > 0 && !matchEmitter(fi, es.ChainID, es.Address) {
+			return nil, fmt.Errorf("emitter %d/%s of %s does not match the filters", es.ChainID, es.Address, replayFromSequenceKey)
+		}
+		q.Sequences = append(q.Sequences, *es)
+	}
+	return &q, nil
+}
+
+func parseReplayTimestamp(s string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %s: must be RFC3339 or unix seconds", replayFromTimestampKey, s)
+	}
+	return t, nil
+}
+
+func parseReplaySequence(s string) (*storage.EmitterSequence, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid %s %s: must be chainID/emitterAddress/sequence", replayFromSequenceKey, s)
+	}
+	chainID, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain id %s: %v", parts[0], err)
+	}
+	addr, err := vaa.StringToAddress(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid emitter address %s: %v", parts[1], err)
+	}
+	sequence, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sequence %s: %v", parts[2], err)
+	}
+	return &storage.EmitterSequence{
+		Emitter:  storage.Emitter{ChainID: vaa.ChainID(chainID), Address: addr},
+		Sequence: sequence,
+	}, nil
+}
+
+func matchEmitter(fi []filterSignedVaa, chainID vaa.ChainID, addr vaa.Address) bool {
+	for _, f := range fi {
+		if f.chainId == chainID && f.emitterAddr == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// replaySignedVaas sends the historical VAAs of a replay query, and then the live VAAs received meanwhile.
+//
+// The subscriber must be registered before the replay starts, so every VAA is either replayed or received live.
+// The live VAAs are buffered until the replay finishes, and those already replayed are discarded.
+func (h *Handler) replaySignedVaas(ctx context.Context, sub *subscriptionSignedVaa, q *storage.ReplayQuery, send func([]byte) error) error {
+
+	if h.repository == nil {
+		return status.Error(codes.Unimplemented, "replay is not supported")
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-sub.registered:
+	}
+
+	// the live VAAs are buffered by another goroutine, so they are not discarded while the replay is sent.
+	var mu sync.Mutex
+	var buffered []message
+	overflow := false
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			case msg, ok := <-sub.ch:
+				if !ok {
+					return
+				}
+				mu.Lock()
+				if len(buffered) < h.replayBufferSize {
+					buffered = append(buffered, msg)
+				} else {
+					overflow = true
+				}
+				mu.Unlock()
+			}
+		}
+	}()
+
+	// the ids of the VAAs replayed, the live VAAs are not sent in order of sequence so they are matched by id.
+	replayed := make(map[string]bool)
+	count := 0
+	err := h.repository.FindVaas(ctx, q, func(e *storage.Event) error {
+		mu.Lock()
+		full := overflow
+		mu.Unlock()
+		if full {
+			return status.Error(codes.ResourceExhausted, "too many live VAAs received during the replay")
+		}
+		if v, err := vaa.Unmarshal(e.Vaas); err == nil {
+			replayed[v.MessageID()] = true
+		}
+		if sub.filter != nil {
+			decoded, err := h.svs.decoder.decode(e.Vaas)
+			if err != nil || !sub.filter.match(decoded) {
+				return nil
+			}
+		}
+		count++
+		return send(e.Vaas)
+	})
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		if _, ok := status.FromError(err); !ok {
+			h.logger.Error("Finding vaas to replay", zap.String("id", sub.id), zap.Error(err))
+			return status.Error(codes.Internal, "failed to replay vaas")
+		}
+		return err
+	}
+	if overflow {
+		return status.Error(codes.ResourceExhausted, "too many live VAAs received during the replay")
+	}
+
+	h.logger.Info("Replayed vaas", zap.String("id", sub.id), zap.Int("count", count), zap.Int("buffered", len(buffered)))
+	for _, msg := range buffered {
+		if v, err := vaa.Unmarshal(msg.vaaBytes); err == nil && replayed[v.MessageID()] {
+			continue
+		}
+		if err := send(msg.vaaBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
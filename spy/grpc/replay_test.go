@@ -0,0 +1,109 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/spy/storage"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/metadata"
+)
+
+// fakeRepository replays the events and sends the live VAAs to the subscription during the replay.
+type fakeRepository struct {
+	events []*storage.Event
+	live   [][]byte
+	sub    *subscriptionSignedVaa
+}
+
+func (r *fakeRepository) FindVaas(ctx context.Context, q *storage.ReplayQuery, handler func(*storage.Event) error) error {
+	for _, b := range r.live {
+		r.sub.ch <- message{vaaBytes: b}
+	}
+	// wait for the live VAAs to be buffered.
+	for len(r.sub.ch) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, e := range r.events {
+		if err := handler(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func createEvent(sequence uint64) (*storage.Event, []byte) {
+	v := createVAA(vaa.ChainIDEthereum, emitterAddr)
+	v.Sequence = sequence
+	b, _ := v.MarshalBinary()
+	return &storage.Event{ID: v.MessageID(), Vaas: b, IndexedAt: time.Now()}, b
+}
+
+func TestParseReplayQuery(t *testing.T) {
+	fi := []filterSignedVaa{{chainId: vaa.ChainIDEthereum, emitterAddr: emitterAddr}}
+
+	q, err := parseReplayQuery(context.Background(), fi)
+	assert.Nil(t, err)
+	assert.Nil(t, q)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		replayFromTimestampKey, "1683000000",
+		replayFromSequenceKey, "2/"+emitterAddr.String()+"/10",
+	))
+	q, err = parseReplayQuery(ctx, fi)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1683000000, 0), *q.From)
+	assert.Equal(t, []storage.Emitter{{ChainID: vaa.ChainIDEthereum, Address: emitterAddr}}, q.Emitters)
+	assert.Equal(t, []storage.EmitterSequence{{Emitter: storage.Emitter{ChainID: vaa.ChainIDEthereum, Address: emitterAddr}, Sequence: 10}}, q.Sequences)
+
+	for _, md := range []metadata.MD{
+		metadata.Pairs(replayFromTimestampKey, "yesterday"),
+		metadata.Pairs(replayFromSequenceKey, "2/"+emitterAddr.String()),
+		metadata.Pairs(replayFromSequenceKey, "2/bad-address/10"),
+		// the emitter does not match the filters.
+		metadata.Pairs(replayFromSequenceKey, "1/"+emitterAddr.String()+"/10"),
+	} {
+		_, err = parseReplayQuery(metadata.NewIncomingContext(context.Background(), md), fi)
+		assert.NotNil(t, err, md)
+	}
+}
+
+func TestReplaySignedVaas(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	sub := &subscriptionSignedVaa{subscriberBuffer: newSubscriberBuffer[message](testBuffer), id: "test", registered: make(chan struct{})}
+	close(sub.registered)
+
+	_, b1 := createEvent(1)
+	e2, b2 := createEvent(2)
+	e3, b3 := createEvent(3)
+	_, b4 := createEvent(4)
+	// the VAA 3 is indexed during the replay, so it is both replayed and received live.
+	// It is discarded by its id, even if it was indexed long before the subscription.
+	e3.IndexedAt = time.Now().Add(-time.Hour)
+	// the VAA 1 is received live after the VAAs with higher sequences were replayed, so it is sent.
+	repository := &fakeRepository{events: []*storage.Event{e2, e3}, live: [][]byte{b1, b3, b4}, sub: sub}
+	handler := NewHandler(nil, nil, repository, 10, testBuffer, nil, logger)
+
+	var sent [][]byte
+	err := handler.replaySignedVaas(context.Background(), sub, &storage.ReplayQuery{}, func(b []byte) error {
+		sent = append(sent, b)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]byte{b2, b3, b1, b4}, sent)
+
+	t.Run("buffer overflow", func(t *testing.T) {
+		handler := NewHandler(nil, nil, repository, 1, testBuffer, nil, logger)
+		err := handler.replaySignedVaas(context.Background(), sub, &storage.ReplayQuery{}, func(b []byte) error { return nil })
+		assert.NotNil(t, err)
+	})
+
+	t.Run("replay not supported", func(t *testing.T) {
+		handler := NewHandler(nil, nil, nil, 10, testBuffer, nil, logger)
+		err := handler.replaySignedVaas(context.Background(), sub, &storage.ReplayQuery{}, func(b []byte) error { return nil })
+		assert.NotNil(t, err)
+	})
+}
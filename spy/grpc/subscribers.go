@@ -3,6 +3,8 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	gossipv1 "github.com/certusone/wormhole/node/pkg/proto/gossip/v1"
 	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
@@ -12,7 +14,13 @@ import (
 )
 
 type message struct {
-	vaaBytes []byte
+	vaaBytes   []byte
+	receivedAt time.Time
+}
+
+type envelopeMessage struct {
+	envelope   *spyv1.SubscribeSignedVAAByTypeResponse
+	receivedAt time.Time
 }
 
 type filterSignedVaa struct {
@@ -20,14 +28,20 @@ type filterSignedVaa struct {
 	emitterAddr vaa.Address
 }
 type subscriptionSignedVaa struct {
+	*subscriberBuffer[message]
 	id      string
 	filters []filterSignedVaa
-	ch      chan message
+	// filter is the extended filter of the subscription, if any.
+	filter *filter
+	// registered is closed once the subscriber receives the new VAAs.
+	registered chan struct{}
 }
 type subscriptionAllVaa struct {
+	*subscriberBuffer[envelopeMessage]
 	id      string
 	filters []*spyv1.FilterEntry
-	ch      chan *spyv1.SubscribeSignedVAAByTypeResponse
+	// filter is the extended filter of the subscription, if any.
+	filter *filter
 }
 
 func subscriptionId() string {
@@ -37,19 +51,23 @@ func subscriptionId() string {
 // SignedVaaSubscribers represents signed VAA subscribers.
 type SignedVaaSubscribers struct {
 	source           chan []byte
+	mu               sync.RWMutex
 	subscribers      map[string]*subscriptionSignedVaa
 	addSubscriber    chan *subscriptionSignedVaa
 	removeSubscriber chan *subscriptionSignedVaa
+	decoder          *Decoder
 	logger           *zap.Logger
 }
 
 // NewSignedVaaSubscribers creates a signed VAA subscribers.
-func NewSignedVaaSubscribers(logger *zap.Logger) *SignedVaaSubscribers {
+// The decoder is shared with the other subscription types, so the VAAs are decoded once.
+func NewSignedVaaSubscribers(decoder *Decoder, logger *zap.Logger) *SignedVaaSubscribers {
 	return &SignedVaaSubscribers{
 		subscribers:      make(map[string]*subscriptionSignedVaa),
 		addSubscriber:    make(chan *subscriptionSignedVaa, 1),
 		removeSubscriber: make(chan *subscriptionSignedVaa, 1),
 		source:           make(chan []byte, 1),
+		decoder:          decoder,
 		logger:           logger,
 	}
 }
@@ -57,29 +75,36 @@ func NewSignedVaaSubscribers(logger *zap.Logger) *SignedVaaSubscribers {
 // AllVaaSubscribers represents all VAA subscribers.
 type AllVaaSubscribers struct {
 	source           chan []byte
+	mu               sync.RWMutex
 	subscribers      map[string]*subscriptionAllVaa
 	addSubscriber    chan *subscriptionAllVaa
 	removeSubscriber chan *subscriptionAllVaa
+	decoder          *Decoder
 	logger           *zap.Logger
 }
 
 // NewAllVaaSubscribers creates all VAA subscribers.
-func NewAllVaaSubscribers(logger *zap.Logger) *AllVaaSubscribers {
+// The decoder is shared with the other subscription types, so the VAAs are decoded once.
+func NewAllVaaSubscribers(decoder *Decoder, logger *zap.Logger) *AllVaaSubscribers {
 	return &AllVaaSubscribers{
 		subscribers:      make(map[string]*subscriptionAllVaa),
 		addSubscriber:    make(chan *subscriptionAllVaa, 1),
 		removeSubscriber: make(chan *subscriptionAllVaa, 1),
 		source:           make(chan []byte, 1),
+		decoder:          decoder,
 		logger:           logger,
 	}
 }
 
-// Register registers a new subscriber with a list of filters.
-func (s *SignedVaaSubscribers) Register(fi []filterSignedVaa) *subscriptionSignedVaa {
+// Register registers a new subscriber with a list of filters, an optional extended filter
+// and the buffer of the VAAs pending to be sent.
+func (s *SignedVaaSubscribers) Register(fi []filterSignedVaa, f *filter, buffer BufferConfig) *subscriptionSignedVaa {
 	sub := &subscriptionSignedVaa{
-		id:      subscriptionId(),
-		ch:      make(chan message, 1),
-		filters: fi,
+		subscriberBuffer: newSubscriberBuffer[message](buffer),
+		id:               subscriptionId(),
+		filters:          fi,
+		filter:           f,
+		registered:       make(chan struct{}),
 	}
 	s.logger.Info("Registering subscriber in signed VAAs ...", zap.String("id", sub.id))
 	s.addSubscriber <- sub
@@ -112,58 +137,62 @@ func (s *SignedVaaSubscribers) Start(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case newSubscriber := <-s.addSubscriber:
+			s.mu.Lock()
 			s.subscribers[newSubscriber.id] = newSubscriber
+			s.mu.Unlock()
+			close(newSubscriber.registered)
 			s.logger.Info("New subscriber registered in signed VAAs", zap.String("id", newSubscriber.id))
 		case subscriberToRemove := <-s.removeSubscriber:
 			if subscriber, exists := s.subscribers[subscriberToRemove.id]; exists {
 				close(subscriber.ch)
+				s.mu.Lock()
 				delete(s.subscribers, subscriberToRemove.id)
+				s.mu.Unlock()
 				s.logger.Info("Subscriber unregistered in signed VAAs", zap.String("id", subscriber.id))
 			}
 		case vaas, ok := <-s.source:
 			if !ok {
 				break
 			}
-			var v *vaa.VAA
+			msg := message{vaaBytes: vaas, receivedAt: time.Now()}
 
+			// the VAA is decoded once, only if some subscriber has filters.
+			var decoded *decodedVaa
 			for _, sub := range s.subscribers {
-				if len(sub.filters) == 0 {
-					select {
-					case sub.ch <- message{vaaBytes: vaas}:
-					default:
-					}
+				if len(sub.filters) == 0 && sub.filter == nil {
+					sub.push(msg)
 					continue
 				}
 
-				if v == nil {
+				if decoded == nil {
 					var err error
-					v, err = vaa.Unmarshal(vaas)
+					decoded, err = s.decoder.decode(vaas)
 					if err != nil {
 						s.logger.Error("Unmarshal vaa in signed VAAs", zap.Error(err))
 						break
 					}
 				}
 
-				for _, fi := range sub.filters {
-					if fi.chainId == v.EmitterChain && fi.emitterAddr == v.EmitterAddress {
-						select {
-						case sub.ch <- message{vaaBytes: vaas}:
-						default:
-						}
-					}
+				if len(sub.filters) > 0 && !matchEmitter(sub.filters, decoded.vaa.EmitterChain, decoded.vaa.EmitterAddress) {
+					continue
 				}
-
+				if sub.filter != nil && !sub.filter.match(decoded) {
+					continue
+				}
+				sub.push(msg)
 			}
 		}
 	}
 }
 
-// Register registers a new subscriber with a list of filters.
-func (s *AllVaaSubscribers) Register(fi []*spyv1.FilterEntry) *subscriptionAllVaa {
+// Register registers a new subscriber with a list of filters, an optional extended filter
+// and the buffer of the VAAs pending to be sent.
+func (s *AllVaaSubscribers) Register(fi []*spyv1.FilterEntry, f *filter, buffer BufferConfig) *subscriptionAllVaa {
 	sub := &subscriptionAllVaa{
-		id:      subscriptionId(),
-		ch:      make(chan *spyv1.SubscribeSignedVAAByTypeResponse, 1),
-		filters: fi,
+		subscriberBuffer: newSubscriberBuffer[envelopeMessage](buffer),
+		id:               subscriptionId(),
+		filters:          fi,
+		filter:           f,
 	}
 	s.logger.Info("Registering subscriber in all VAAs ...", zap.String("id", sub.id))
 	s.addSubscriber <- sub
@@ -196,19 +225,23 @@ func (s *AllVaaSubscribers) Start(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case newSubscriber := <-s.addSubscriber:
+			s.mu.Lock()
 			s.subscribers[newSubscriber.id] = newSubscriber
+			s.mu.Unlock()
 			s.logger.Info("New subscriber registered in all VAAs", zap.String("id", newSubscriber.id))
 		case subscriberToRemove := <-s.removeSubscriber:
 			if subscriber, exists := s.subscribers[subscriberToRemove.id]; exists {
 				close(subscriber.ch)
+				s.mu.Lock()
 				delete(s.subscribers, subscriberToRemove.id)
+				s.mu.Unlock()
 				s.logger.Info("Subscriber unregistered in all VAAs", zap.String("id", subscriber.id))
 			}
 		case vaaBytes, ok := <-s.source:
 			if !ok {
 				break
 			}
-			v, err := vaa.Unmarshal(vaaBytes)
+			decoded, err := s.decoder.decode(vaaBytes)
 			if err != nil {
 				s.logger.Error("failed unmarshaing VAA bytes from gossipv1.SignedVAAWithQuorum.", zap.Error(err))
 				continue
@@ -223,39 +256,78 @@ func (s *AllVaaSubscribers) Start(ctx context.Context) {
 			envelope := &spyv1.SubscribeSignedVAAByTypeResponse{
 				VaaType: resType,
 			}
+			msg := envelopeMessage{envelope: envelope, receivedAt: time.Now()}
 
 			// loop through the subscriptions and send responses to everyone that wants this VAA
 			for _, sub := range s.subscribers {
-				if len(sub.filters) == 0 {
-					// this subscription has no filters, send them the VAA.
-					select {
-					case sub.ch <- envelope:
-					default:
-					}
+				if len(sub.filters) > 0 && !s.matchFilterEntries(sub.filters, decoded.vaa) {
+					continue
+				}
+				if sub.filter != nil && !sub.filter.match(decoded) {
 					continue
 				}
+				sub.push(msg)
+			}
+		}
+	}
+}
 
-				// this subscription has filters.
-				for _, filterEntry := range sub.filters {
-					filter := filterEntry.GetFilter()
-					switch t := filter.(type) {
-					case *spyv1.FilterEntry_EmitterFilter:
-						filterAddr := t.EmitterFilter.EmitterAddress
-						filterChain := vaa.ChainID(t.EmitterFilter.ChainId)
+// matchFilterEntries returns whether a VAA matches any of the filters of a subscription.
+func (s *AllVaaSubscribers) matchFilterEntries(filters []*spyv1.FilterEntry, v *vaa.VAA) bool {
+	for _, filterEntry := range filters {
+		filter := filterEntry.GetFilter()
+		switch t := filter.(type) {
+		case *spyv1.FilterEntry_EmitterFilter:
+			filterAddr := t.EmitterFilter.EmitterAddress
+			filterChain := vaa.ChainID(t.EmitterFilter.ChainId)
 
-						if v.EmitterChain == filterChain && v.EmitterAddress.String() == filterAddr {
-							// it is a match, send the response
-							select {
-							case sub.ch <- envelope:
-							default:
-							}
-						}
-					default:
-						s.logger.Error(fmt.Sprintf("Unsupported filter type in subscriptions: %T", filter))
-					}
-				}
+			if v.EmitterChain == filterChain && v.EmitterAddress.String() == filterAddr {
+				return true
+			}
+		default:
+			s.logger.Error(fmt.Sprintf("Unsupported filter type in subscriptions: %T", filter))
+		}
+	}
+	return false
+}
 
+// List returns the state of the signed VAA subscribers.
+func (s *SignedVaaSubscribers) List() []SubscriberInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subscribers := make([]SubscriberInfo, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		filters := make([]string, 0, len(sub.filters))
+		for _, f := range sub.filters {
+			filters = append(filters, fmt.Sprintf("emitter:%d/%s", f.chainId, f.emitterAddr))
+		}
+		if sub.filter != nil {
+			filters = append(filters, "filter:"+sub.filter.source)
+		}
+		subscribers = append(subscribers, sub.info(sub.id, "signedVaa", filters))
+	}
+	return subscribers
+}
+
+// List returns the state of the VAA by type subscribers.
+func (s *AllVaaSubscribers) List() []SubscriberInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	subscribers := make([]SubscriberInfo, 0, len(s.subscribers))
+	for _, sub := range s.subscribers {
+		filters := make([]string, 0, len(sub.filters))
+		for _, f := range sub.filters {
+			switch t := f.GetFilter().(type) {
+			case *spyv1.FilterEntry_EmitterFilter:
+				filters = append(filters, fmt.Sprintf("emitter:%d/%s", t.EmitterFilter.ChainId, t.EmitterFilter.EmitterAddress))
+			default:
+				filters = append(filters, fmt.Sprintf("%T", t))
 			}
 		}
+		if sub.filter != nil {
+			filters = append(filters, "filter:"+sub.filter.source)
+		}
+		subscribers = append(subscribers, sub.info(sub.id, "signedVaaByType", filters))
 	}
+	return subscribers
 }
@@ -10,6 +10,10 @@ import (
 	"go.uber.org/zap/zaptest"
 )
 
+var testBuffer = BufferConfig{Size: 1, Policy: BufferPolicyDropNewest, MaxSize: 10}
+
+var testDecoder, _ = NewDecoder(nil, 10)
+
 var emitterAddr = vaa.Address{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4}
 
 func createVAA(chainID vaa.ChainID, emitterAddr vaa.Address) *vaa.VAA {
@@ -34,8 +38,8 @@ func createVAA(chainID vaa.ChainID, emitterAddr vaa.Address) *vaa.VAA {
 func TestSignedVaaSubscribers_Register(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	var fi []filterSignedVaa
-	svs := NewSignedVaaSubscribers(logger)
-	sub := svs.Register(fi)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	sub := svs.Register(fi, nil, testBuffer)
 	assert.NotNil(t, sub)
 	assert.NotEmpty(t, sub.id)
 }
@@ -43,8 +47,8 @@ func TestSignedVaaSubscribers_Register(t *testing.T) {
 func TestSignedVaaSubscribers_Unregister(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	var fi []filterSignedVaa
-	svs := NewSignedVaaSubscribers(logger)
-	sub := svs.Register(fi)
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	sub := svs.Register(fi, nil, testBuffer)
 	assert.Equal(t, 1, len(svs.addSubscriber))
 	svs.Unregister(sub)
 	assert.Equal(t, 1, len(svs.removeSubscriber))
@@ -55,8 +59,8 @@ func TestSignedVaaSubscribers_HandleVAA(t *testing.T) {
 	t.Run("empty filters", func(t *testing.T) {
 		logger := zaptest.NewLogger(t)
 		var fi []filterSignedVaa
-		svs := NewSignedVaaSubscribers(logger)
-		svs.Register(fi)
+		svs := NewSignedVaaSubscribers(testDecoder, logger)
+		svs.Register(fi, nil, testBuffer)
 
 		vaas := []byte{0x0, 0x1, 0x2, 0x3}
 		err := svs.HandleVAA(vaas)
@@ -73,8 +77,8 @@ func TestSignedVaaSubscribers_HandleVAA(t *testing.T) {
 				emitterAddr: vaa.Address{0x0, 0x1},
 			},
 		}
-		svs := NewSignedVaaSubscribers(logger)
-		_ = svs.Register(fi)
+		svs := NewSignedVaaSubscribers(testDecoder, logger)
+		_ = svs.Register(fi, nil, testBuffer)
 
 		vaas := []byte{0x0, 0x1, 0x2, 0x3}
 		err := svs.HandleVAA(vaas)
@@ -89,8 +93,8 @@ func TestSignedVaaSubscribers_HandleVAA(t *testing.T) {
 				emitterAddr: vaa.Address{0x0, 0x1},
 			},
 		}
-		svs := NewSignedVaaSubscribers(logger)
-		sub := svs.Register(fi)
+		svs := NewSignedVaaSubscribers(testDecoder, logger)
+		sub := svs.Register(fi, nil, testBuffer)
 		vaa := createVAA(vaa.ChainIDEthereum, emitterAddr)
 		vaaBytes, _ := vaa.MarshalBinary()
 		err := svs.HandleVAA(vaaBytes)
@@ -102,9 +106,9 @@ func TestSignedVaaSubscribers_HandleVAA(t *testing.T) {
 func TestAllVaaSubscribers_Register(t *testing.T) {
 	var fi []*spyv1.FilterEntry
 	logger := zaptest.NewLogger(t)
-	avs := NewAllVaaSubscribers(logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
 
-	sub := avs.Register(fi)
+	sub := avs.Register(fi, nil, testBuffer)
 	assert.NotNil(t, sub)
 	assert.NotEmpty(t, sub.id)
 }
@@ -112,9 +116,9 @@ func TestAllVaaSubscribers_Register(t *testing.T) {
 func TestAllVaaSubscribers_Unregister(t *testing.T) {
 	var fi []*spyv1.FilterEntry
 	logger := zaptest.NewLogger(t)
-	avs := NewAllVaaSubscribers(logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
 
-	sub := avs.Register(fi)
+	sub := avs.Register(fi, nil, testBuffer)
 
 	assert.Equal(t, 1, len(avs.addSubscriber))
 	avs.Unregister(sub)
@@ -125,7 +129,7 @@ func TestAllVaaSubscribers_HandleVAA(t *testing.T) {
 
 	t.Run("empty filters", func(t *testing.T) {
 		logger := zaptest.NewLogger(t)
-		avs := NewAllVaaSubscribers(logger)
+		avs := NewAllVaaSubscribers(testDecoder, logger)
 
 		emitterAddr := vaa.Address{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4}
 		vaa := createVAA(vaa.ChainIDEthereum, emitterAddr)
@@ -139,8 +143,8 @@ func TestAllVaaSubscribers_HandleVAA(t *testing.T) {
 	t.Run("invalid vaa", func(t *testing.T) {
 		var fi []*spyv1.FilterEntry
 		logger := zaptest.NewLogger(t)
-		avs := NewAllVaaSubscribers(logger)
-		_ = avs.Register(fi)
+		avs := NewAllVaaSubscribers(testDecoder, logger)
+		_ = avs.Register(fi, nil, testBuffer)
 
 		vaas := []byte{0x0, 0x1, 0x2, 0x3}
 		err := avs.HandleVAA(vaas)
@@ -159,8 +163,8 @@ func TestAllVaaSubscribers_HandleVAA(t *testing.T) {
 			},
 		}
 		logger := zaptest.NewLogger(t)
-		avs := NewAllVaaSubscribers(logger)
-		sub := avs.Register(fi)
+		avs := NewAllVaaSubscribers(testDecoder, logger)
+		sub := avs.Register(fi, nil, testBuffer)
 		emitterAddr := vaa.Address{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4}
 		vaa := createVAA(vaa.ChainIDEthereum, emitterAddr)
 		vaaBytes, _ := vaa.MarshalBinary()
@@ -0,0 +1,278 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	publicrpcv1 "github.com/certusone/wormhole/node/pkg/proto/publicrpc/v1"
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+)
+
+// WebSocket subscription types, the same of the gRPC service.
+const (
+	wsTypeSignedVaa       = "signedVaa"
+	wsTypeSignedVaaByType = "signedVaaByType"
+)
+
+// WebSocket encodings of the VAAs.
+const (
+	// wsEncodingBinary sends every VAA in a binary frame.
+	wsEncodingBinary = "binary"
+	// wsEncodingBase64 sends every VAA in a JSON message, encoded in base64.
+	wsEncodingBase64 = "base64"
+)
+
+// wsSubscribeRequest is the first message of a WebSocket connection, which subscribes to the VAAs.
+//
+// For example, the VAAs of the token bridge on Ethereum encoded in base64:
+//
+//	{"type": "signedVaa", "encoding": "base64",
+//	 "emitters": [{"chainId": 2, "emitterAddress": "0000...3ee18b2214aff97000d974cf647e7c347e8fa585"}]}
+type wsSubscribeRequest struct {
+	Type     string            `json:"type"`
+	Encoding string            `json:"encoding"`
+	Emitters []wsEmitterFilter `json:"emitters"`
+	// Filter is the extended filter of the subscription, see Filter.
+	Filter       json.RawMessage `json:"filter"`
+	BufferSize   int             `json:"bufferSize"`
+	BufferPolicy string          `json:"bufferPolicy"`
+	// The replay of the historical VAAs, only for the signedVaa subscriptions.
+	ReplayFromTimestamp string   `json:"replayFromTimestamp"`
+	ReplayFromSequence  []string `json:"replayFromSequence"`
+}
+
+type wsEmitterFilter struct {
+	ChainID        vaa.ChainID `json:"chainId"`
+	EmitterAddress string      `json:"emitterAddress"`
+}
+
+// wsMessage is a message sent by the spy to a WebSocket subscriber.
+type wsMessage struct {
+	Type  string `json:"type"`
+	ID    string `json:"id,omitempty"`
+	Vaa   []byte `json:"vaa,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// wsTokenLocal is the local of the connection with the token of the subscriber.
+const wsTokenLocal = "token"
+
+// WebSocketHandler returns the handler of the WebSocket subscriptions.
+//
+// The subscriptions share the subscribers of the gRPC service, so a VAA is dispatched once for both transports.
+// The token of the subscriber is sent in the Authorization header or, since browsers can not set it, in the token query parameter.
+func (h *Handler) WebSocketHandler() fiber.Handler {
+	upgrade := websocket.New(h.serveWebSocket)
+	return func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		token := bearerToken(c.Get(fiber.HeaderAuthorization))
+		if token == "" {
+			token = c.Query("token")
+		}
+		c.Locals(wsTokenLocal, token)
+		return upgrade(c)
+	}
+}
+
+func (h *Handler) serveWebSocket(c *websocket.Conn) {
+	defer c.Close()
+
+	var req wsSubscribeRequest
+	if err := c.ReadJSON(&req); err != nil {
+		h.logger.Debug("Reading websocket subscription", zap.Error(err))
+		_ = writeWebSocket(c, func() error {
+			return c.WriteJSON(wsMessage{Type: "error", Error: "invalid subscription request"})
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := h.subscribeWebSocket(ctx, cancel, c, &req); err != nil {
+		_ = writeWebSocket(c, func() error {
+			return c.WriteJSON(wsMessage{Type: "error", Error: status.Convert(err).Message()})
+		})
+	}
+}
+
+func (h *Handler) subscribeWebSocket(ctx context.Context, cancel context.CancelFunc, c *websocket.Conn, req *wsSubscribeRequest) error {
+	token, _ := c.Locals(wsTokenLocal).(string)
+	state, err := h.auth.identify(token)
+	if err != nil {
+		return err
+	}
+
+	if req.Type == "" {
+		req.Type = wsTypeSignedVaa
+	}
+	if req.Encoding == "" {
+		req.Encoding = wsEncodingBinary
+	}
+	if req.Encoding != wsEncodingBinary && req.Encoding != wsEncodingBase64 {
+		return fmt.Errorf("invalid encoding %s: must be %s or %s", req.Encoding, wsEncodingBinary, wsEncodingBase64)
+	}
+
+	var fi []filterSignedVaa
+	for _, e := range req.Emitters {
+		addr, err := vaa.StringToAddress(e.EmitterAddress)
+		if err != nil {
+			return fmt.Errorf("failed to decode emitter address: %v", err)
+		}
+		fi = append(fi, filterSignedVaa{chainId: e.ChainID, emitterAddr: addr})
+	}
+	// a null filter is the same as no filter.
+	var f *filter
+	if len(req.Filter) > 0 && string(req.Filter) != "null" {
+		var err error
+		if f, err = parseFilter(string(req.Filter)); err != nil {
+			return err
+		}
+	}
+	buffer, err := h.buffer.with(req.BufferSize, req.BufferPolicy)
+	if err != nil {
+		return err
+	}
+
+	session, err := h.auth.admit(state, len(fi)+f.size())
+	if err != nil {
+		return err
+	}
+	defer session.release()
+
+	send := func(vaaBytes []byte) error {
+		err := writeWebSocket(c, func() error {
+			if req.Encoding == wsEncodingBinary {
+				return c.WriteMessage(websocket.BinaryMessage, vaaBytes)
+			}
+			return c.WriteJSON(wsMessage{Type: "vaa", Vaa: vaaBytes})
+		})
+		if err == nil {
+			session.recordSent(len(vaaBytes))
+		}
+		return err
+	}
+
+	switch req.Type {
+	case wsTypeSignedVaa:
+		var timestamps []string
+		if req.ReplayFromTimestamp != "" {
+			timestamps = []string{req.ReplayFromTimestamp}
+		}
+		replay, err := newReplayQuery(fi, timestamps, req.ReplayFromSequence)
+		if err != nil {
+			return err
+		}
+
+		sub := h.svs.Register(fi, f, buffer)
+		defer h.svs.Unregister(sub)
+		if err := startWebSocket(cancel, c, sub.id); err != nil {
+			return err
+		}
+		if replay != nil {
+			if err := h.replaySignedVaas(ctx, sub, replay, send); err != nil {
+				h.logger.Error("Replaying vaas", zap.String("id", sub.id), zap.Error(err))
+				return err
+			}
+		}
+		return deliverWebSocket(ctx, h, c, sub.subscriberBuffer, sub.id, func(msg message) ([]byte, time.Time) {
+			return msg.vaaBytes, msg.receivedAt
+		}, send)
+
+	case wsTypeSignedVaaByType:
+		if req.ReplayFromTimestamp != "" || len(req.ReplayFromSequence) > 0 {
+			return fmt.Errorf("replay is only supported in %s subscriptions", wsTypeSignedVaa)
+		}
+		var entries []*spyv1.FilterEntry
+		for _, e := range fi {
+			entries = append(entries, &spyv1.FilterEntry{Filter: &spyv1.FilterEntry_EmitterFilter{
+				EmitterFilter: &spyv1.EmitterFilter{
+					ChainId:        publicrpcv1.ChainID(e.chainId),
+					EmitterAddress: e.emitterAddr.String(),
+				},
+			}})
+		}
+
+		sub := h.avs.Register(entries, f, buffer)
+		defer h.avs.Unregister(sub)
+		if err := startWebSocket(cancel, c, sub.id); err != nil {
+			return err
+		}
+		return deliverWebSocket(ctx, h, c, sub.subscriberBuffer, sub.id, func(msg envelopeMessage) ([]byte, time.Time) {
+			return msg.envelope.GetSignedVaa().GetVaa(), msg.receivedAt
+		}, send)
+
+	default:
+		return fmt.Errorf("invalid type %s: must be %s or %s", req.Type, wsTypeSignedVaa, wsTypeSignedVaaByType)
+	}
+}
+
+// startWebSocket confirms the subscription and reads the connection until it is closed.
+// The subscribers do not send more messages, so they are discarded.
+func startWebSocket(cancel context.CancelFunc, c *websocket.Conn, id string) error {
+	err := writeWebSocket(c, func() error {
+		return c.WriteJSON(wsMessage{Type: "subscribed", ID: id})
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// deliverWebSocket sends the VAAs of a subscription until the connection is closed.
+func deliverWebSocket[T any](ctx context.Context, h *Handler, c *websocket.Conn, sub *subscriberBuffer[T], id string,
+	unwrap func(T) ([]byte, time.Time), send func([]byte) error) error {
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Info("Websocket closed", zap.String("id", id))
+			return nil
+		case <-sub.overflowed:
+			h.logger.Warn("Disconnecting slow subscriber", zap.String("id", id))
+			return overflowError
+		case <-ping.C:
+			if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return err
+			}
+		case msg := <-sub.ch:
+			vaaBytes, receivedAt := unwrap(msg)
+			if err := send(vaaBytes); err != nil {
+				h.logger.Error("Sending vaas", zap.String("id", id), zap.Error(err))
+				return err
+			}
+			sub.stats.recordDelivery(receivedAt)
+		}
+	}
+}
+
+func writeWebSocket(c *websocket.Conn, write func() error) error {
+	if err := c.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return write()
+}
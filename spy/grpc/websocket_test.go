@@ -0,0 +1,146 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// createWebSocketServer serves the websocket subscriptions of the handler and returns its url.
+func createWebSocketServer(t *testing.T, handler *Handler) string {
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/ws", handler.WebSocketHandler())
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	go func() {
+		_ = app.Listener(listen)
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+	return "ws://" + listen.Addr().String() + "/ws"
+}
+
+// subscribeWebSocket connects to the server and waits for the confirmation of the subscription.
+func subscribeWebSocket(t *testing.T, url string, req wsSubscribeRequest) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, conn.WriteJSON(req))
+
+	var msg wsMessage
+	assert.Nil(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	assert.Nil(t, conn.ReadJSON(&msg))
+	assert.Equal(t, "subscribed", msg.Type, msg.Error)
+	assert.NotEmpty(t, msg.ID)
+	return conn
+}
+
+func TestWebSocket_SignedVaa(t *testing.T) {
+	// the connections are served after the test ends, so the logs are discarded.
+	logger := zap.NewNop()
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
+	url := createWebSocketServer(t, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svs.Start(ctx)
+
+	ethereumVaa, _ := createVAA(vaa.ChainIDEthereum, emitterAddr).MarshalBinary()
+	solanaVaa, _ := createVAA(vaa.ChainIDSolana, emitterAddr).MarshalBinary()
+	emitters := []wsEmitterFilter{{ChainID: vaa.ChainIDEthereum, EmitterAddress: emitterAddr.String()}}
+
+	t.Run("binary frames of the matching vaas", func(t *testing.T) {
+		conn := subscribeWebSocket(t, url, wsSubscribeRequest{Emitters: emitters})
+		defer conn.Close()
+
+		assert.Nil(t, svs.HandleVAA(solanaVaa))
+		assert.Nil(t, svs.HandleVAA(ethereumVaa))
+
+		messageType, data, err := conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, websocket.BinaryMessage, messageType)
+		assert.Equal(t, ethereumVaa, data)
+	})
+
+	t.Run("json messages in base64 encoding", func(t *testing.T) {
+		conn := subscribeWebSocket(t, url, wsSubscribeRequest{Encoding: wsEncodingBase64, Emitters: emitters})
+		defer conn.Close()
+
+		assert.Nil(t, svs.HandleVAA(ethereumVaa))
+
+		var msg wsMessage
+		assert.Nil(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "vaa", msg.Type)
+		assert.Equal(t, ethereumVaa, msg.Vaa)
+	})
+
+	t.Run("unregister on disconnect", func(t *testing.T) {
+		conn := subscribeWebSocket(t, url, wsSubscribeRequest{Emitters: emitters})
+		assert.Equal(t, 1, len(handler.svs.List()))
+
+		assert.Nil(t, conn.Close())
+		assert.Eventually(t, func() bool {
+			return len(handler.svs.List()) == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("invalid subscription", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		assert.Nil(t, err)
+		defer conn.Close()
+		assert.Nil(t, conn.WriteJSON(wsSubscribeRequest{Encoding: "hex"}))
+
+		var msg wsMessage
+		assert.Nil(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "error", msg.Type)
+		assert.Contains(t, msg.Error, "invalid encoding hex")
+	})
+}
+
+func TestWebSocket_SignedVaaByType(t *testing.T) {
+	// the connections are served after the test ends, so the logs are discarded.
+	logger := zap.NewNop()
+	svs := NewSignedVaaSubscribers(testDecoder, logger)
+	avs := NewAllVaaSubscribers(testDecoder, logger)
+	handler := NewHandler(svs, avs, nil, 0, testBuffer, nil, logger)
+	url := createWebSocketServer(t, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go avs.Start(ctx)
+
+	ethereumVaa, _ := createVAA(vaa.ChainIDEthereum, emitterAddr).MarshalBinary()
+	solanaVaa, _ := createVAA(vaa.ChainIDSolana, emitterAddr).MarshalBinary()
+
+	conn := subscribeWebSocket(t, url, wsSubscribeRequest{
+		Type:     wsTypeSignedVaaByType,
+		Emitters: []wsEmitterFilter{{ChainID: vaa.ChainIDSolana, EmitterAddress: emitterAddr.String()}},
+	})
+	defer conn.Close()
+
+	assert.Nil(t, avs.HandleVAA(ethereumVaa))
+	assert.Nil(t, avs.HandleVAA(solanaVaa))
+
+	messageType, data, err := conn.ReadMessage()
+	assert.Nil(t, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+	assert.Equal(t, solanaVaa, data)
+
+	// replays are only supported in the signedVaa subscriptions.
+	other, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Nil(t, err)
+	defer other.Close()
+	assert.Nil(t, other.WriteJSON(wsSubscribeRequest{Type: wsTypeSignedVaaByType, ReplayFromTimestamp: "2023-01-01T00:00:00Z"}))
+	var msg wsMessage
+	assert.Nil(t, other.ReadJSON(&msg))
+	assert.Equal(t, "error", msg.Type)
+}
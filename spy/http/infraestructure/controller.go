@@ -1,6 +1,9 @@
 package infraestructure
 
 import (
+	"crypto/subtle"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
@@ -8,12 +11,35 @@ import (
 // Controller definition.
 type Controller struct {
 	srv    *Service
+	apiKey string
 	logger *zap.Logger
 }
 
 // NewController creates a Controller instance.
-func NewController(serv *Service, logger *zap.Logger) *Controller {
-	return &Controller{srv: serv, logger: logger}
+// The admin endpoints require the header `Authorization: Bearer <apiKey>`.
+func NewController(serv *Service, apiKey string, logger *zap.Logger) *Controller {
+	return &Controller{srv: serv, apiKey: apiKey, logger: logger}
+}
+
+// Authenticate is a middleware that validates the admin api key.
+func (c *Controller) Authenticate(ctx *fiber.Ctx) error {
+	token := strings.TrimPrefix(ctx.Get(fiber.HeaderAuthorization), "Bearer ")
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(c.apiKey)) != 1 {
+		return ctx.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
+	}
+	return ctx.Next()
+}
+
+// ListSubscribers handler for the endpoint /admin/subscribers.
+// It returns the active subscribers with their filters, buffered VAAs, drops and lag.
+func (c *Controller) ListSubscribers(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.srv.ListSubscribers())
+}
+
+// ListTokens handler for the endpoint /admin/tokens.
+// It returns the subscription attempts, active subscriptions and bytes sent of every token.
+func (c *Controller) ListTokens(ctx *fiber.Ctx) error {
+	return ctx.JSON(c.srv.ListTokens())
 }
 
 // HealthCheck handler for the endpoint /health.
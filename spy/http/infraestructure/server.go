@@ -2,7 +2,9 @@ package infraestructure
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/pprof"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
@@ -13,10 +15,12 @@ type Server struct {
 	logger *zap.Logger
 }
 
-func NewServer(logger *zap.Logger, port string, db *mongo.Database, pprofEnabled bool) *Server {
+// NewServer creates the http server of the spy.
+// The admin endpoints are registered only if adminApiKey is not empty, and the websocket subscriptions only if ws is not nil.
+func NewServer(logger *zap.Logger, port string, db *mongo.Database, pprofEnabled, metricsEnabled bool, adminApiKey string, ws fiber.Handler, tokens TokenStatsLister, subscribers ...SubscriberLister) *Server {
 	repository := NewRepository(db, logger)
-	service := NewService(repository, logger)
-	ctrl := NewController(service, logger)
+	service := NewService(repository, tokens, subscribers, logger)
+	ctrl := NewController(service, adminApiKey, logger)
 	app := fiber.New(fiber.Config{DisableStartupMessage: true})
 	if pprofEnabled {
 		app.Use(pprof.New())
@@ -25,6 +29,17 @@ func NewServer(logger *zap.Logger, port string, db *mongo.Database, pprofEnabled
 	api := app.Group("/api")
 	api.Get("/health", ctrl.HealthCheck)
 	api.Get("/ready", ctrl.ReadyCheck)
+	if ws != nil {
+		app.Get("/ws", ws)
+	}
+	if metricsEnabled {
+		app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
+	}
+	if adminApiKey != "" {
+		admin := api.Group("/admin", ctrl.Authenticate)
+		admin.Get("/subscribers", ctrl.ListSubscribers)
+		admin.Get("/tokens", ctrl.ListTokens)
+	}
 	return &Server{
 		app:    app,
 		port:   port,
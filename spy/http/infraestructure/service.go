@@ -3,18 +3,49 @@ package infraestructure
 import (
 	"context"
 	"fmt"
+	"sort"
 
+	"github.com/wormhole-foundation/wormhole-explorer/spy/grpc"
 	"go.uber.org/zap"
 )
 
 type Service struct {
-	repo   *Repository
-	logger *zap.Logger
+	repo        *Repository
+	tokens      TokenStatsLister
+	subscribers []SubscriberLister
+	logger      *zap.Logger
+}
+
+// SubscriberLister lists the state of the subscribers of a subscription type.
+type SubscriberLister interface {
+	List() []grpc.SubscriberInfo
+}
+
+// TokenStatsLister lists the subscription metrics of the tokens of the subscribers.
+type TokenStatsLister interface {
+	Stats() []grpc.TokenStats
 }
 
 // NewService create a new governor.Service.
-func NewService(dao *Repository, logger *zap.Logger) *Service {
-	return &Service{repo: dao, logger: logger.With(zap.String("module", "Infraestructureervice"))}
+func NewService(dao *Repository, tokens TokenStatsLister, subscribers []SubscriberLister, logger *zap.Logger) *Service {
+	return &Service{repo: dao, tokens: tokens, subscribers: subscribers, logger: logger.With(zap.String("module", "Infraestructureervice"))}
+}
+
+// ListSubscribers returns the state of the active subscribers, sorted by connection time.
+func (s *Service) ListSubscribers() []grpc.SubscriberInfo {
+	subscribers := make([]grpc.SubscriberInfo, 0)
+	for _, l := range s.subscribers {
+		subscribers = append(subscribers, l.List()...)
+	}
+	sort.Slice(subscribers, func(i, j int) bool {
+		return subscribers[i].ConnectedAt.Before(subscribers[j].ConnectedAt)
+	})
+	return subscribers
+}
+
+// ListTokens returns the subscription attempts, active subscriptions and bytes sent of every token.
+func (s *Service) ListTokens() []grpc.TokenStats {
+	return s.tokens.Stats()
 }
 
 // CheckMongoServerStatus
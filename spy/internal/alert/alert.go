@@ -0,0 +1,29 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+)
+
+// alert key constants definition.
+const (
+	ErrorWatcherResumeTokenLost = "ERROR_WATCHER_RESUME_TOKEN_LOST"
+)
+
+func LoadAlerts(cfg alert.AlertConfig) map[string]alert.Alert {
+	alerts := make(map[string]alert.Alert)
+
+	// Alert resume token of the watcher lost.
+	alerts[ErrorWatcherResumeTokenLost] = alert.Alert{
+		Alias:       "Spy watcher resume token lost",
+		Message:     fmt.Sprintf("[%s] %s", cfg.Environment, "Spy watcher resume token lost"),
+		Description: "The resume token of the spy watcher is no longer in the oplog, the vaas inserted meanwhile are sent with a catch-up query",
+		Actions:     []string{""},
+		Tags:        []string{cfg.Environment, "spy", "watcher", "mongo"},
+		Entity:      "spy",
+		Priority:    alert.HIGH,
+	}
+
+	return alerts
+}
@@ -0,0 +1,15 @@
+package metrics
+
+import "github.com/wormhole-foundation/wormhole-explorer/spy/grpc"
+
+const serviceName = "wormscan-spy"
+
+// SubscriberLister lists the state of the subscribers of a subscription type.
+type SubscriberLister interface {
+	List() []grpc.SubscriberInfo
+}
+
+// TokenStatsLister lists the subscription metrics of the tokens of the subscribers.
+type TokenStatsLister interface {
+	Stats() []grpc.TokenStats
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// PrometheusMetrics exports the stats of the subscribers and their tokens to Prometheus.
+//
+// The stats are read when the metrics are collected, so the subscribers disconnected are no longer exported.
+type PrometheusMetrics struct {
+	tokens      TokenStatsLister
+	subscribers []SubscriberLister
+
+	subscriberDelivered  *prometheus.Desc
+	subscriberDropped    *prometheus.Desc
+	subscriberBuffered   *prometheus.Desc
+	subscriberBufferSize *prometheus.Desc
+	subscriberLag        *prometheus.Desc
+	subscriberMaxLag     *prometheus.Desc
+	tokenAttempts        *prometheus.Desc
+	tokenRejected        *prometheus.Desc
+	tokenActive          *prometheus.Desc
+	tokenBytesSent       *prometheus.Desc
+}
+
+// NewPrometheusMetrics creates a new PrometheusMetrics.
+func NewPrometheusMetrics(environment string, tokens TokenStatsLister, subscribers ...SubscriberLister) *PrometheusMetrics {
+	constLabels := prometheus.Labels{
+		"environment": environment,
+		"service":     serviceName,
+	}
+	subscriberLabels := []string{"id", "type", "policy"}
+	tokenLabels := []string{"token"}
+
+	return &PrometheusMetrics{
+		tokens:      tokens,
+		subscribers: subscribers,
+		subscriberDelivered: prometheus.NewDesc("spy_subscriber_delivered_total",
+			"Total number of vaa delivered to a subscriber", subscriberLabels, constLabels),
+		subscriberDropped: prometheus.NewDesc("spy_subscriber_dropped_total",
+			"Total number of vaa dropped because the buffer of a subscriber was full", subscriberLabels, constLabels),
+		subscriberBuffered: prometheus.NewDesc("spy_subscriber_buffered",
+			"Number of vaa pending to be sent to a subscriber", subscriberLabels, constLabels),
+		subscriberBufferSize: prometheus.NewDesc("spy_subscriber_buffer_size",
+			"Size of the buffer of a subscriber", subscriberLabels, constLabels),
+		subscriberLag: prometheus.NewDesc("spy_subscriber_lag_seconds",
+			"Time between the reception and the delivery of the last vaa sent to a subscriber", subscriberLabels, constLabels),
+		subscriberMaxLag: prometheus.NewDesc("spy_subscriber_max_lag_seconds",
+			"Maximum time between the reception and the delivery of a vaa sent to a subscriber", subscriberLabels, constLabels),
+		tokenAttempts: prometheus.NewDesc("spy_token_subscription_attempts_total",
+			"Total number of subscriptions attempted with a token", tokenLabels, constLabels),
+		tokenRejected: prometheus.NewDesc("spy_token_subscription_rejected_total",
+			"Total number of subscriptions rejected with a token", tokenLabels, constLabels),
+		tokenActive: prometheus.NewDesc("spy_token_active_subscriptions",
+			"Number of active subscriptions of a token", tokenLabels, constLabels),
+		tokenBytesSent: prometheus.NewDesc("spy_token_sent_bytes_total",
+			"Total number of bytes sent to the subscriptions of a token", tokenLabels, constLabels),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *PrometheusMetrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- m.subscriberDelivered
+	ch <- m.subscriberDropped
+	ch <- m.subscriberBuffered
+	ch <- m.subscriberBufferSize
+	ch <- m.subscriberLag
+	ch <- m.subscriberMaxLag
+	ch <- m.tokenAttempts
+	ch <- m.tokenRejected
+	ch <- m.tokenActive
+	ch <- m.tokenBytesSent
+}
+
+// Collect implements prometheus.Collector.
+func (m *PrometheusMetrics) Collect(ch chan<- prometheus.Metric) {
+	for _, l := range m.subscribers {
+		for _, s := range l.List() {
+			labels := []string{s.ID, s.Type, string(s.Policy)}
+			ch <- prometheus.MustNewConstMetric(m.subscriberDelivered, prometheus.CounterValue, float64(s.Delivered), labels...)
+			ch <- prometheus.MustNewConstMetric(m.subscriberDropped, prometheus.CounterValue, float64(s.Dropped), labels...)
+			ch <- prometheus.MustNewConstMetric(m.subscriberBuffered, prometheus.GaugeValue, float64(s.Buffered), labels...)
+			ch <- prometheus.MustNewConstMetric(m.subscriberBufferSize, prometheus.GaugeValue, float64(s.BufferSize), labels...)
+			ch <- prometheus.MustNewConstMetric(m.subscriberLag, prometheus.GaugeValue, float64(s.LagMs)/1000, labels...)
+			ch <- prometheus.MustNewConstMetric(m.subscriberMaxLag, prometheus.GaugeValue, float64(s.MaxLagMs)/1000, labels...)
+		}
+	}
+
+	if m.tokens == nil {
+		return
+	}
+	for _, t := range m.tokens.Stats() {
+		ch <- prometheus.MustNewConstMetric(m.tokenAttempts, prometheus.CounterValue, float64(t.Attempts), t.Name)
+		ch <- prometheus.MustNewConstMetric(m.tokenRejected, prometheus.CounterValue, float64(t.Rejected), t.Name)
+		ch <- prometheus.MustNewConstMetric(m.tokenActive, prometheus.GaugeValue, float64(t.Active), t.Name)
+		ch <- prometheus.MustNewConstMetric(m.tokenBytesSent, prometheus.CounterValue, float64(t.BytesSent), t.Name)
+	}
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/spy/grpc"
+)
+
+type fakeSubscribers []grpc.SubscriberInfo
+
+func (f fakeSubscribers) List() []grpc.SubscriberInfo { return f }
+
+type fakeTokens []grpc.TokenStats
+
+func (f fakeTokens) Stats() []grpc.TokenStats { return f }
+
+func TestPrometheusMetrics_Collect(t *testing.T) {
+	subscribers := fakeSubscribers{{
+		ID: "sub-1", Type: "signedVaa", Policy: grpc.BufferPolicyDropOldest,
+		BufferSize: 100, Buffered: 3, Delivered: 10, Dropped: 2, LagMs: 1500, MaxLagMs: 2000,
+	}}
+	tokens := fakeTokens{{Name: "partner-a", Attempts: 4, Rejected: 1, Active: 1, BytesSent: 1024}}
+	m := NewPrometheusMetrics("test", tokens, subscribers)
+
+	expected := `
+# HELP spy_subscriber_dropped_total Total number of vaa dropped because the buffer of a subscriber was full
+# TYPE spy_subscriber_dropped_total counter
+spy_subscriber_dropped_total{environment="test",id="sub-1",policy="drop-oldest",service="wormscan-spy",type="signedVaa"} 2
+# HELP spy_subscriber_lag_seconds Time between the reception and the delivery of the last vaa sent to a subscriber
+# TYPE spy_subscriber_lag_seconds gauge
+spy_subscriber_lag_seconds{environment="test",id="sub-1",policy="drop-oldest",service="wormscan-spy",type="signedVaa"} 1.5
+# HELP spy_token_sent_bytes_total Total number of bytes sent to the subscriptions of a token
+# TYPE spy_token_sent_bytes_total counter
+spy_token_sent_bytes_total{environment="test",service="wormscan-spy",token="partner-a"} 1024
+`
+	err := testutil.CollectAndCompare(m, strings.NewReader(expected),
+		"spy_subscriber_dropped_total", "spy_subscriber_lag_seconds", "spy_token_sent_bytes_total")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, testutil.CollectAndCount(m))
+
+	// the subscribers disconnected are no longer exported.
+	m = NewPrometheusMetrics("test", nil, fakeSubscribers{})
+	assert.Equal(t, 0, testutil.CollectAndCount(m))
+}
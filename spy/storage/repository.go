@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Repository reads the historical VAAs replayed to the subscribers.
+type Repository struct {
+	db     *mongo.Database
+	logger *zap.Logger
+}
+
+// NewRepository creates a new Repository.
+func NewRepository(db *mongo.Database, logger *zap.Logger) *Repository {
+	return &Repository{
+		db:     db,
+		logger: logger.With(zap.String("module", "SpyRepository")),
+	}
+}
+
+// Emitter identifies the emitter of a VAA.
+type Emitter struct {
+	ChainID vaa.ChainID
+	Address vaa.Address
+}
+
+// EmitterSequence is the last sequence of an emitter received by a subscriber.
+type EmitterSequence struct {
+	Emitter
+	Sequence uint64
+}
+
+// ReplayQuery defines the historical VAAs to replay.
+type ReplayQuery struct {
+	// From replays the VAAs with a timestamp greater or equal than it, if it is set.
+	From *time.Time
+	// Emitters limits the VAAs replayed from a timestamp to the emitters, all the emitters if it is empty.
+	Emitters []Emitter
+	// Sequences replays the VAAs of each emitter with a sequence greater than the last one received.
+	Sequences []EmitterSequence
+}
+
+func (q *ReplayQuery) toBSON() bson.D {
+	var branches bson.A
+	withSequence := make(map[Emitter]bool, len(q.Sequences))
+	for _, s := range q.Sequences {
+		withSequence[s.Emitter] = true
+		branches = append(branches, bson.D{
+			{Key: "emitterChain", Value: s.ChainID},
+			{Key: "emitterAddr", Value: s.Address.String()},
+			// the sequence is stored as a string, so it is converted to be compared numerically.
+			{Key: "$expr", Value: bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$toLong", Value: "$sequence"}}, int64(s.Sequence)}}}},
+		})
+	}
+
+	if q.From != nil {
+		byTimestamp := bson.D{{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: *q.From}}}}
+		var emitters bson.A
+		for _, e := range q.Emitters {
+			if !withSequence[e] {
+				emitters = append(emitters, bson.D{{Key: "emitterChain", Value: e.ChainID}, {Key: "emitterAddr", Value: e.Address.String()}})
+			}
+		}
+		if len(emitters) > 0 {
+			byTimestamp = append(byTimestamp, bson.E{Key: "$or", Value: emitters})
+		}
+		// without filters, the emitters replayed from a sequence are excluded from the timestamp.
+		if len(q.Emitters) == 0 && len(q.Sequences) > 0 {
+			var excluded bson.A
+			for _, s := range q.Sequences {
+				excluded = append(excluded, bson.D{{Key: "emitterChain", Value: s.ChainID}, {Key: "emitterAddr", Value: s.Address.String()}})
+			}
+			byTimestamp = append(byTimestamp, bson.E{Key: "$nor", Value: excluded})
+		}
+		if len(q.Emitters) == 0 || len(emitters) > 0 {
+			branches = append(branches, byTimestamp)
+		}
+	}
+
+	return bson.D{{Key: "$or", Value: branches}}
+}
+
+// FindVaas calls the handler with each VAA that matches the query, in timestamp order.
+// The VAAs of pythnet are not replayed.
+func (r *Repository) FindVaas(ctx context.Context, q *ReplayQuery, handler func(*Event) error) error {
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "indexedAt", Value: 1}}).
+		SetProjection(bson.D{{Key: "vaas", Value: 1}, {Key: "indexedAt", Value: 1}})
+	cur, err := r.db.Collection("vaas").Find(ctx, q.toBSON(), opts)
+	if err != nil {
+		r.logger.Error("failed to find vaas to replay", zap.Error(err))
+		return errors.WithStack(err)
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var e Event
+		if err := cur.Decode(&e); err != nil {
+			r.logger.Error("failed to decode vaa to replay", zap.Error(err))
+			return errors.WithStack(err)
+		}
+		if err := handler(&e); err != nil {
+			return err
+		}
+	}
+	return errors.WithStack(cur.Err())
+}
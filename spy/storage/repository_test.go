@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReplayQuery_ToBSON(t *testing.T) {
+	from := time.Unix(1683000000, 0)
+	addr, _ := vaa.StringToAddress("0000000000000000000000003ee18b2214aff97000d974cf647e7c347e8fa585")
+	emitter := Emitter{ChainID: vaa.ChainIDEthereum, Address: addr}
+	byEmitter := bson.D{{Key: "emitterChain", Value: vaa.ChainIDEthereum}, {Key: "emitterAddr", Value: addr.String()}}
+	bySequence := bson.D{
+		{Key: "emitterChain", Value: vaa.ChainIDEthereum},
+		{Key: "emitterAddr", Value: addr.String()},
+		{Key: "$expr", Value: bson.D{{Key: "$gt", Value: bson.A{bson.D{{Key: "$toLong", Value: "$sequence"}}, int64(10)}}}},
+	}
+
+	// without filters, the emitter replayed from a sequence is excluded from the timestamp.
+	q := ReplayQuery{From: &from, Sequences: []EmitterSequence{{Emitter: emitter, Sequence: 10}}}
+	assert.Equal(t, bson.D{{Key: "$or", Value: bson.A{
+		bySequence,
+		bson.D{
+			{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: from}}},
+			{Key: "$nor", Value: bson.A{byEmitter}},
+		},
+	}}}, q.toBSON())
+
+	// the only emitter of the filters is replayed from a sequence.
+	q.Emitters = []Emitter{emitter}
+	assert.Equal(t, bson.D{{Key: "$or", Value: bson.A{bySequence}}}, q.toBSON())
+}
@@ -3,18 +3,28 @@ package storage
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/db"
+	spyAlert "github.com/wormhole-foundation/wormhole-explorer/spy/internal/alert"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
 // Watcher represents a listener of database changes.
 type Watcher struct {
-	db      *mongo.Database
-	dbName  string
-	handler WatcherFunc
-	logger  *zap.Logger
+	db           *mongo.Database
+	dbName       string
+	handler      WatcherFunc
+	resumeID     string
+	saveInterval time.Duration
+	alertClient  alert.AlertClient
+	// startedAt is the start time of the watcher, the subscribers are connected after it.
+	startedAt time.Time
+	logger    *zap.Logger
 }
 
 // WatcherFunc is a function to send database changes.
@@ -31,8 +41,9 @@ type documentKey struct {
 
 // Event represents a database change.
 type Event struct {
-	ID   string `bson:"_id"`
-	Vaas []byte
+	ID        string `bson:"_id"`
+	Vaas      []byte
+	IndexedAt time.Time `bson:"indexedAt"`
 }
 
 const queryTemplate = `
@@ -47,12 +58,17 @@ const queryTemplate = `
 `
 
 // NewWatcher creates a new database event watcher.
-func NewWatcher(db *mongo.Database, dbName string, handler WatcherFunc, logger *zap.Logger) *Watcher {
+// The resumeID identifies the position of the watcher, which is saved every saveInterval to resume from it after a restart.
+func NewWatcher(db *mongo.Database, dbName string, handler WatcherFunc, resumeID string, saveInterval time.Duration,
+	alertClient alert.AlertClient, logger *zap.Logger) *Watcher {
 	return &Watcher{
-		db:      db,
-		dbName:  dbName,
-		handler: handler,
-		logger:  logger,
+		db:           db,
+		dbName:       dbName,
+		handler:      handler,
+		resumeID:     resumeID,
+		saveInterval: saveInterval,
+		alertClient:  alertClient,
+		logger:       logger,
 	}
 }
 
@@ -65,22 +81,76 @@ func (w *Watcher) Start(ctx context.Context) error {
 		return err
 	}
 
-	stream, err := w.db.Watch(ctx, steps)
-	if err != nil {
-		return err
+	w.startedAt = time.Now()
+	stream := db.NewChangeStream(w.db, w.resumeID, steps, w.catchUp, w.saveInterval, w.logger)
+	go stream.Watch(ctx, func(ctx context.Context, raw bson.Raw) {
+		var e watchEvent
+		if err := bson.Unmarshal(raw, &e); err != nil {
+			w.logger.Error("Error unmarshalling event", zap.Error(err))
+			return
+		}
+		w.publish(&Event{
+			ID:        e.DbFullDocument.ID,
+			Vaas:      e.DbFullDocument.Vaas,
+			IndexedAt: e.DbFullDocument.IndexedAt,
+		})
+	})
+	return nil
+}
+
+// publish sends a vaa to the live subscribers. The vaas indexed before the watcher started, that are received
+// again when the change stream is resumed or caught up after a restart, are skipped since the subscribers
+// connected after them. They are still sent when the stream is resumed after an error while the spy is running.
+func (w *Watcher) publish(e *Event) bool {
+	if e.IndexedAt.Before(w.startedAt) {
+		return false
 	}
-	go func() {
-		for stream.Next(ctx) {
-			var e watchEvent
-			if err := stream.Decode(&e); err != nil {
-				w.logger.Error("Error unmarshalling event", zap.Error(err))
-				continue
-			}
-			w.handler(&Event{
-				ID:   e.DbFullDocument.ID,
-				Vaas: e.DbFullDocument.Vaas,
-			})
+	w.handler(e)
+	return true
+}
+
+// catchUp sends the vaas indexed since a time, when the change stream can not be resumed.
+func (w *Watcher) catchUp(ctx context.Context, since time.Time) error {
+	alertContext := alert.AlertContext{
+		Details: map[string]string{"since": since.Format(time.RFC3339)},
+	}
+	w.alertClient.CreateAndSend(ctx, spyAlert.ErrorWatcherResumeTokenLost, alertContext)
+
+	// the vaas of both watched collections are caught up.
+	count, skipped := 0, 0
+	for _, collection := range []string{"vaas", "vaasPythnet"} {
+		published, notPublished, err := w.catchUpCollection(ctx, collection, since)
+		count += published
+		skipped += notPublished
+		if err != nil {
+			return err
 		}
-	}()
+	}
+	w.logger.Info("Caught up vaas", zap.Time("since", since), zap.Int("count", count), zap.Int("skipped", skipped))
 	return nil
 }
+
+// catchUpCollection sends the vaas of a collection indexed since a time and returns the number of vaas sent and skipped.
+func (w *Watcher) catchUpCollection(ctx context.Context, collection string, since time.Time) (int, int, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "indexedAt", Value: 1}})
+	cur, err := w.db.Collection(collection).Find(ctx, bson.D{{Key: "indexedAt", Value: bson.D{{Key: "$gte", Value: since}}}}, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cur.Close(ctx)
+
+	count, skipped := 0, 0
+	for cur.Next(ctx) {
+		var e Event
+		if err := cur.Decode(&e); err != nil {
+			w.logger.Error("Error decoding vaa to catch up", zap.Error(err), zap.String("collection", collection))
+			continue
+		}
+		if w.publish(&e) {
+			count++
+		} else {
+			skipped++
+		}
+	}
+	return count, skipped, cur.Err()
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wormhole-foundation/wormhole-explorer/common/client/alert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.uber.org/zap"
+)
+
+func TestWatcher_CatchUp(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("vaas and pyth vaas", func(mt *mtest.T) {
+		startedAt := time.Now()
+		since := startedAt.Add(-time.Minute)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "wormscan.vaas", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "2/emitter/1"}, {Key: "indexedAt", Value: since}},
+				bson.D{{Key: "_id", Value: "2/emitter/2"}, {Key: "indexedAt", Value: startedAt.Add(time.Second)}},
+			),
+			mtest.CreateCursorResponse(0, "wormscan.vaasPythnet", mtest.FirstBatch,
+				bson.D{{Key: "_id", Value: "26/emitter/1"}, {Key: "indexedAt", Value: startedAt.Add(time.Second)}},
+			),
+		)
+
+		var published []string
+		handler := func(e *Event) { published = append(published, e.ID) }
+		w := NewWatcher(mt.DB, "wormscan", handler, "test", time.Second, alert.NewDummyClient(), zap.NewNop())
+		w.startedAt = startedAt
+
+		err := w.catchUp(context.Background(), since)
+		assert.NoError(t, err)
+
+		// the vaas indexed before the watcher started are skipped.
+		assert.Equal(t, []string{"2/emitter/2", "26/emitter/1"}, published)
+		var collections []string
+		for _, e := range mt.GetAllStartedEvents() {
+			collections = append(collections, e.Command.Lookup("find").StringValue())
+		}
+		assert.Equal(t, []string{"vaas", "vaasPythnet"}, collections)
+	})
+}
@@ -17,6 +17,8 @@ import (
 	"github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
 	"github.com/wormhole-foundation/wormhole-explorer/common/health"
 	"github.com/wormhole-foundation/wormhole-explorer/common/logger"
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/config"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/consumer"
@@ -41,13 +43,20 @@ func main() {
 	// build logger
 	logger := logger.New("wormhole-explorer-tx-tracker", logger.WithLevel(cfg.LogLevel))
 
+	// Set up tracing, it is a no-op when no OTLP endpoint is configured.
+	shutdownTracing, err := telemetry.Init(rootCtx, "wormhole-explorer-tx-tracker")
+	if err != nil {
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
 	logger.Info("Starting wormhole-explorer-tx-tracker ...")
 
 	// initialize rate limiters
 	chains.Initialize(&cfg.RpcProviderSettings)
 
 	// initialize the database client
-	cli, err := mongo.Connect(rootCtx, options.Client().ApplyURI(cfg.MongodbUri))
+	cli, err := mongo.Connect(rootCtx, options.Client().ApplyURI(cfg.MongodbUri).SetMonitor(telemetry.NewMongoMonitor()))
 	if err != nil {
 		log.Fatal("Failed to initialize MongoDB client: ", err)
 	}
@@ -69,7 +78,8 @@ func main() {
 	// create and start a consumer.
 	vaaConsumeFunc := newVAAConsumeFunc(rootCtx, cfg, metrics, logger)
 	repository := consumer.NewRepository(logger, db)
-	consumer := consumer.New(vaaConsumeFunc, &cfg.RpcProviderSettings, rootCtx, logger, repository, metrics)
+	tracker := reprocess.NewTracker(db, logger)
+	consumer := consumer.New(vaaConsumeFunc, &cfg.RpcProviderSettings, rootCtx, logger, repository, metrics, tracker)
 	consumer.Start(rootCtx)
 
 	logger.Info("Started wormhole-explorer-tx-tracker")
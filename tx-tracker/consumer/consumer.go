@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/chains"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/config"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
@@ -19,6 +21,7 @@ type Consumer struct {
 	logger              *zap.Logger
 	repository          *Repository
 	metrics             metrics.Metrics
+	tracker             *reprocess.Tracker
 }
 
 // New creates a new vaa consumer.
@@ -29,6 +32,7 @@ func New(
 	logger *zap.Logger,
 	repository *Repository,
 	metrics metrics.Metrics,
+	tracker *reprocess.Tracker,
 ) *Consumer {
 
 	c := Consumer{
@@ -37,6 +41,7 @@ func New(
 		logger:              logger,
 		repository:          repository,
 		metrics:             metrics,
+		tracker:             tracker,
 	}
 
 	return &c
@@ -63,9 +68,15 @@ func (c *Consumer) process(ctx context.Context, msg queue.ConsumerMessage) {
 
 	event := msg.Data()
 
+	// Skip the events of reprocessing jobs that do not include the tx-tracker
+	if !event.Reprocess.Includes(reprocess.StageTxTracker) {
+		return
+	}
+
 	// Do not process messages from PythNet
 	if event.ChainID == sdk.ChainIDPythNet {
 		c.logger.Debug("Skipping expired PythNet message", zap.String("vaaId", event.ID))
+		c.tracker.Track(ctx, event.Reprocess, reprocess.StageTxTracker, event.ID, nil)
 		return
 	}
 
@@ -79,9 +90,23 @@ func (c *Consumer) process(ctx context.Context, msg queue.ConsumerMessage) {
 		Emitter:   event.EmitterAddress,
 		Sequence:  event.Sequence,
 		TxHash:    event.TxHash,
-		Overwrite: false, // avoid processing the same transaction twice
+		// avoid processing the same transaction twice, unless it is explicitly reprocessed.
+		Overwrite: event.Reprocess != nil,
+	}
+	msgCtx, span := telemetry.StartVaaSpan(msg.Context(), "txtracker.ProcessSourceTx", event.ID)
+	err := ProcessSourceTx(msgCtx, c.logger, c.rpcProviderSettings, c.repository, &p)
+	if errors.Is(err, chains.ErrChainNotSupported) || errors.Is(err, ErrAlreadyProcessed) {
+		telemetry.EndSpan(span, nil)
+	} else {
+		telemetry.EndSpan(span, err)
+	}
+
+	// Unsupported chains and already processed transactions are not a failure of the reprocessing job
+	if errors.Is(err, chains.ErrChainNotSupported) || errors.Is(err, ErrAlreadyProcessed) {
+		c.tracker.Track(ctx, event.Reprocess, reprocess.StageTxTracker, event.ID, nil)
+	} else {
+		c.tracker.Track(ctx, event.Reprocess, reprocess.StageTxTracker, event.ID, err)
 	}
-	err := ProcessSourceTx(ctx, c.logger, c.rpcProviderSettings, c.repository, &p)
 
 	// Log a message informing the processing status
 	if errors.Is(err, chains.ErrChainNotSupported) {
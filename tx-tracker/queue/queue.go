@@ -4,12 +4,15 @@ import (
 	"context"
 	"time"
 
+	"github.com/wormhole-foundation/wormhole-explorer/common/reprocess"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	sdk "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
 type sqsEvent struct {
-	MessageID string `json:"MessageId"`
-	Message   string `json:"Message"`
+	MessageID         string                           `json:"MessageId"`
+	Message           string                           `json:"Message"`
+	MessageAttributes telemetry.NotificationAttributes `json:"MessageAttributes"`
 }
 
 // VaaEvent represents a vaa data to be handle by the pipeline.
@@ -26,11 +29,15 @@ type VaaEvent struct {
 	TxHash           string      `json:"txHash"`
 	Version          uint16      `json:"version"`
 	Revision         uint16      `json:"revision"`
+	// Reprocess is set when the event is published by a reprocessing job.
+	Reprocess *reprocess.Request `json:"reprocess,omitempty"`
 }
 
 // ConsumerMessage defition.
 type ConsumerMessage interface {
 	Data() *VaaEvent
+	// Context returns the context of the message, with the trace context propagated by the pipeline.
+	Context() context.Context
 	Done()
 	Failed()
 	IsExpired() bool
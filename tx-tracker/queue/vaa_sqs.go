@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	sqs_client "github.com/wormhole-foundation/wormhole-explorer/common/client/sqs"
+	"github.com/wormhole-foundation/wormhole-explorer/common/telemetry"
 	"github.com/wormhole-foundation/wormhole-explorer/txtracker/internal/metrics"
 )
 
@@ -85,7 +86,7 @@ func (q *SQS) Consume(ctx context.Context) <-chan ConsumerMessage {
 					logger:    q.logger,
 					consumer:  q.consumer,
 					expiredAt: expiredAt,
-					ctx:       ctx,
+					ctx:       telemetry.Extract(ctx, sqsEvent.MessageAttributes.Carrier()),
 				}
 			}
 			q.wg.Wait()
@@ -114,6 +115,10 @@ func (m *sqsConsumerMessage) Data() *VaaEvent {
 	return m.data
 }
 
+func (m *sqsConsumerMessage) Context() context.Context {
+	return m.ctx
+}
+
 func (m *sqsConsumerMessage) Done() {
 	if err := m.consumer.DeleteMessage(m.ctx, m.id); err != nil {
 		m.logger.Error("Error deleting message from SQS",